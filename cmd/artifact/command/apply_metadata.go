@@ -0,0 +1,370 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/harness/harness-cli/cmd/cmdutils"
+	"github.com/harness/harness-cli/config"
+	"github.com/harness/harness-cli/internal/api/ar_v2"
+	"github.com/harness/harness-cli/module/ar/migrate/util"
+	"github.com/harness/harness-cli/util/common/printer"
+	"github.com/harness/harness-cli/util/common/progress"
+
+	"github.com/gobwas/glob"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// metadataManifest is the file read by metadata apply (YAML or JSON).
+// Example:
+//
+//	registry: docker-prod          # default registry of the entries
+//	entries:
+//	  - package: "payments-*"      # * and ? wildcards
+//	    metadata:
+//	      owner: "team-a, team-b"  # values may hold commas and colons
+//	  - package: payments-api
+//	    version: "1.4.*"           # without a version the package metadata is set
+//	    metadata:
+//	      approved: true
+//	      docs: https://docs.example.com:8443/payments
+//
+// When several entries select the same package or version their metadata is
+// merged, later entries winning.
+type metadataManifest struct {
+	Registry string          `yaml:"registry"`
+	Entries  []metadataEntry `yaml:"entries"`
+}
+
+type metadataEntry struct {
+	Registry string            `yaml:"registry"`
+	Package  string            `yaml:"package"`
+	Version  string            `yaml:"version"`
+	Metadata map[string]string `yaml:"metadata"`
+}
+
+// metadataChange is a metadata key added, changed or removed on a package
+// or version by metadata apply
+type metadataChange struct {
+	Registry string `json:"registry"`
+	Package  string `json:"package"`
+	Version  string `json:"version,omitempty"`
+	valueChange
+}
+
+// metadataTarget is a package (empty Version) or version selected by the
+// manifest, with the metadata it should carry
+type metadataTarget struct {
+	versionRef
+	metadata map[string]string
+	changes  []valueChange
+}
+
+// NewMetadataApplyCmd creates a new cobra.Command for applying the metadata
+// of a manifest to many packages and versions.
+// command example: hc artifact metadata apply -f metadata.yaml --dry-run
+func NewMetadataApplyCmd(f *cmdutils.Factory) *cobra.Command {
+	var manifestFile string
+	var dryRun, prune bool
+
+	cmd := &cobra.Command{
+		Use:   "apply -f <metadata.yaml>",
+		Short: "Apply metadata from a YAML or JSON file to many packages and versions",
+		Long: `Apply the metadata of a manifest (YAML or JSON) to the packages and versions
+selected by its entries. Package and version selectors accept * and ?
+wildcards; an entry without a version sets package metadata. Values are read
+as they are written in the file, so they may contain commas and colons.
+
+The current metadata of every selected package or version is fetched and the
+changes are printed; with --dry-run nothing is updated. With --prune, keys
+that are not listed for a package or version are removed.`,
+		Example: `  registry: docker-prod
+  entries:
+    - package: "payments-*"
+      metadata:
+        owner: "team-a, team-b"
+    - package: payments-api
+      version: "1.4.*"
+      metadata:
+        approved: true
+        docs: https://docs.example.com:8443/payments
+
+  hc artifact metadata apply -f metadata.yaml --dry-run
+  hc artifact metadata apply -f metadata.yaml --prune`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			p := progress.NewConsoleReporter()
+
+			p.Start(fmt.Sprintf("Reading %s", manifestFile))
+			manifest, err := loadMetadataManifest(manifestFile)
+			if err != nil {
+				p.Error("Invalid manifest")
+				return err
+			}
+			targets, err := resolveMetadataTargets(ctx, f, manifest)
+			if err != nil {
+				p.Error("Failed to resolve the entries")
+				return err
+			}
+			p.Success(fmt.Sprintf("%d package(s)/version(s) selected", len(targets)))
+
+			changes := []metadataChange{}
+			pending := 0
+			for _, target := range targets {
+				current, err := versionMetadata(ctx, f.RegistryV2HttpClient(), target.versionRef)
+				if err != nil {
+					return err
+				}
+				target.changes = diffValues(current, desiredMetadata(current, target.metadata, prune))
+				for _, change := range target.changes {
+					changes = append(changes, metadataChange{Registry: target.Registry, Package: target.Artifact,
+						Version: target.Version, valueChange: change})
+				}
+				if len(target.changes) > 0 {
+					pending++
+				}
+			}
+			if err := printer.Print(changes, 0, 1, int64(len(changes)), false, [][]string{
+				{"registry", "Registry"},
+				{"package", "Package"},
+				{"version", "Version"},
+				{"key", "Key"},
+				{"change", "Change"},
+				{"from", "From"},
+				{"to", "To"},
+			}); err != nil {
+				return err
+			}
+			if dryRun || pending == 0 {
+				p.Success(fmt.Sprintf("%d package(s)/version(s) to update", pending))
+				return nil
+			}
+
+			failed := 0
+			for _, target := range targets {
+				if len(target.changes) == 0 {
+					continue
+				}
+				if err := applyMetadataChanges(ctx, f, target); err != nil {
+					failed++
+					p.Error(fmt.Sprintf("%s: %v", target.versionRef, err))
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d package(s)/version(s) failed to update", failed, pending)
+			}
+			p.Success(fmt.Sprintf("Updated %d package(s)/version(s)", pending))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&manifestFile, "file", "f", "", "Metadata manifest (YAML or JSON)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the changes without applying them")
+	cmd.Flags().BoolVar(&prune, "prune", false, "Remove metadata keys that are not listed in the manifest")
+	cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+// loadMetadataManifest reads a manifest, rejecting unknown fields, entries
+// without a registry or package and empty keys or values
+func loadMetadataManifest(file string) (*metadataManifest, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var manifest metadataManifest
+	if err := dec.Decode(&manifest); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", file, err)
+	}
+	if len(manifest.Entries) == 0 {
+		return nil, fmt.Errorf("manifest %s has no entries", file)
+	}
+	var problems []string
+	for i := range manifest.Entries {
+		entry := &manifest.Entries[i]
+		if entry.Registry == "" {
+			entry.Registry = manifest.Registry
+		}
+		if err := validateMetadataEntry(entry); err != nil {
+			problems = append(problems, fmt.Sprintf("  - entry %d (%s): %v", i+1, entry.Package, err))
+		}
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%d of %d manifest entries are invalid:\n%s", len(problems),
+			len(manifest.Entries), strings.Join(problems, "\n"))
+	}
+	return &manifest, nil
+}
+
+func validateMetadataEntry(entry *metadataEntry) error {
+	switch {
+	case entry.Registry == "":
+		return fmt.Errorf("no registry")
+	case entry.Package == "":
+		return fmt.Errorf("no package")
+	case len(entry.Metadata) == 0:
+		return fmt.Errorf("no metadata")
+	}
+	for _, pattern := range []string{entry.Package, entry.Version} {
+		if _, err := util.IsWildCardExpression(pattern); err != nil {
+			return err
+		}
+	}
+	for k, v := range entry.Metadata {
+		if strings.TrimSpace(k) == "" {
+			return fmt.Errorf("empty metadata key")
+		}
+		if strings.TrimSpace(v) == "" {
+			return fmt.Errorf("empty value for metadata key %q", k)
+		}
+	}
+	return nil
+}
+
+// resolveMetadataTargets expands the package and version selectors of the
+// entries, merging the metadata of entries selecting the same target
+func resolveMetadataTargets(ctx context.Context, f *cmdutils.Factory,
+	manifest *metadataManifest) ([]*metadataTarget, error) {
+	var targets []*metadataTarget
+	byRef := map[versionRef]*metadataTarget{}
+	for i, entry := range manifest.Entries {
+		refs, err := resolveMetadataEntry(ctx, f, entry)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d (%s): %w", i+1, entry.Package, err)
+		}
+		for _, ref := range refs {
+			target, ok := byRef[ref]
+			if !ok {
+				target = &metadataTarget{versionRef: ref, metadata: map[string]string{}}
+				byRef[ref] = target
+				targets = append(targets, target)
+			}
+			for k, v := range entry.Metadata {
+				target.metadata[k] = v
+			}
+		}
+	}
+	return targets, nil
+}
+
+// resolveMetadataEntry lists the packages, or versions, an entry selects;
+// selectors without wildcards are used as they are
+func resolveMetadataEntry(ctx context.Context, f *cmdutils.Factory, entry metadataEntry) ([]versionRef, error) {
+	client := f.RegistryHttpClient()
+	packages := []string{entry.Package}
+	if wildcard, _ := util.IsWildCardExpression(entry.Package); wildcard {
+		match := glob.MustCompile(entry.Package)
+		artifacts, err := listRegistryArtifacts(ctx, client, entry.Registry)
+		if err != nil {
+			return nil, err
+		}
+		packages = nil
+		for _, a := range artifacts {
+			if match.Match(a.Name) {
+				packages = append(packages, a.Name)
+			}
+		}
+	}
+
+	var refs []versionRef
+	for _, pkg := range packages {
+		wildcard, _ := util.IsWildCardExpression(entry.Version)
+		if !wildcard {
+			refs = append(refs, versionRef{Registry: entry.Registry, Artifact: pkg, Version: entry.Version})
+			continue
+		}
+		match := glob.MustCompile(entry.Version)
+		versions, err := listVersionUsage(ctx, client, artifactRegistryRef(entry.Registry), pkg, "")
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range versions {
+			if match.Match(v.Version) {
+				refs = append(refs, versionRef{Registry: entry.Registry, Artifact: pkg, Version: v.Version})
+			}
+		}
+	}
+	return refs, nil
+}
+
+// desiredMetadata is the metadata a target should end up with: the current
+// metadata with the manifest values applied, limited to the manifest keys
+// when pruning
+func desiredMetadata(current, manifest map[string]string, prune bool) map[string]string {
+	desired := map[string]string{}
+	if !prune {
+		for k, v := range current {
+			desired[k] = v
+		}
+	}
+	for k, v := range manifest {
+		desired[k] = v
+	}
+	return desired
+}
+
+// applyMetadataChanges sets the added and changed keys of a target and
+// deletes its removed keys
+func applyMetadataChanges(ctx context.Context, f *cmdutils.Factory, target *metadataTarget) error {
+	set, removed := map[string]string{}, map[string]string{}
+	for _, c := range target.changes {
+		if c.Change == changeRemoved {
+			removed[c.Key] = c.From
+		} else {
+			set[c.Key] = c.To
+		}
+	}
+	if len(set) > 0 {
+		if err := updateArtifactMetadata(ctx, f, target.Registry, target.Artifact, target.Version, set); err != nil {
+			return err
+		}
+	}
+	if len(removed) > 0 {
+		return deleteArtifactMetadata(ctx, f, target.Registry, target.Artifact, target.Version, removed)
+	}
+	return nil
+}
+
+// deleteArtifactMetadata removes metadata from a package, or from one of its
+// versions when version is set
+func deleteArtifactMetadata(ctx context.Context, f *cmdutils.Factory, registry, pkg, version string,
+	metadata map[string]string) error {
+	keys := make([]string, 0, len(metadata))
+	for k := range metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	items := make([]ar_v2.MetadataItemInput, 0, len(keys))
+	for _, k := range keys {
+		items = append(items, ar_v2.MetadataItemInput{Key: k, Value: metadata[k]})
+	}
+	body := ar_v2.DeleteMetadataJSONRequestBody{
+		RegistryIdentifier: registry,
+		Package:            &pkg,
+		Metadata:           items,
+	}
+	if version != "" {
+		body.Version = &version
+	}
+	response, err := f.RegistryV2HttpClient().DeleteMetadataWithResponse(ctx,
+		&ar_v2.DeleteMetadataParams{AccountIdentifier: config.Global.AccountID}, body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode() >= 400 {
+		return fmt.Errorf("request failed with status %d: %s", response.StatusCode(),
+			bytes.TrimSpace(response.Body))
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package command
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/harness/harness-cli/cmd/cmdutils"
+	"github.com/harness/harness-cli/internal/api/ar"
+	"github.com/harness/harness-cli/internal/api/ar_v2"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// metadataServer serves the packages of registry reg, with versions and
+// metadata keyed by "<package>" or "<package>:<version>"; updates and deletes
+// are applied to the metadata and recorded as "<METHOD> <target> <keys>"
+func metadataServer(t *testing.T, versions map[string][]string,
+	metadata map[string]map[string]string) (*[]string, *cmdutils.Factory) {
+	t.Helper()
+	withPullConfig(t)
+	var calls []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var body any
+		switch {
+		case r.URL.Path == "/registry/acct/reg/+/artifacts":
+			var artifacts []ar.RegistryArtifactMetadata
+			for _, name := range []string{"payments-api", "payments-web", "ledger"} {
+				artifacts = append(artifacts, ar.RegistryArtifactMetadata{Name: name})
+			}
+			body = ar.ListRegistryArtifactResponse{Data: ar.ListRegistryArtifact{Artifacts: artifacts}}
+		case strings.HasSuffix(r.URL.Path, "/+/versions"):
+			name := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/registry/acct/reg/+/artifact/"), "/+/versions")
+			var items []ar.ArtifactVersionMetadata
+			for _, v := range versions[name] {
+				items = append(items, ar.ArtifactVersionMetadata{Name: v})
+			}
+			body = ar.ListArtifactVersionResponse{Data: ar.ListArtifactVersion{ArtifactVersions: &items}}
+		case r.URL.Path == "/metadata" && r.Method == http.MethodGet:
+			q := r.URL.Query()
+			target := q.Get("package")
+			if q.Has("version") {
+				target += ":" + q.Get("version")
+			}
+			var items []ar_v2.MetadataItemOutput
+			for k, v := range metadata[target] {
+				items = append(items, ar_v2.MetadataItemOutput{Key: k, Value: v})
+			}
+			body = ar_v2.MetadataResponse{Data: ar_v2.MetadataOutput{Metadata: items}}
+		case r.URL.Path == "/metadata":
+			var input ar_v2.MetadataInput
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&input))
+			assert.Equal(t, "reg", input.RegistryIdentifier)
+			target := *input.Package
+			if input.Version != nil {
+				target += ":" + *input.Version
+			}
+			if metadata[target] == nil {
+				metadata[target] = map[string]string{}
+			}
+			var keys []string
+			for _, item := range input.Metadata {
+				keys = append(keys, item.Key+"="+item.Value)
+				if r.Method == http.MethodDelete {
+					delete(metadata[target], item.Key)
+				} else {
+					metadata[target][item.Key] = item.Value
+				}
+			}
+			calls = append(calls, r.Method+" "+target+" "+strings.Join(keys, ","))
+			body = map[string]string{"status": "SUCCESS"}
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(ts.Close)
+
+	client, err := ar.NewClientWithResponses(ts.URL)
+	require.NoError(t, err)
+	v2Client, err := ar_v2.NewClientWithResponses(ts.URL)
+	require.NoError(t, err)
+	return &calls, &cmdutils.Factory{
+		RegistryHttpClient:   func() *ar.ClientWithResponses { return client },
+		RegistryV2HttpClient: func() *ar_v2.ClientWithResponses { return v2Client },
+	}
+}
+
+const testMetadataManifest = `
+registry: reg
+entries:
+  - package: "payments-*"
+    metadata:
+      owner: "team-a, team-b"
+  - package: payments-api
+    version: "1.4.*"
+    metadata:
+      approved: true
+      docs: https://docs.example.com:8443/payments
+`
+
+func TestMetadataApplySetsMetadataOfSelectedTargets(t *testing.T) {
+	calls, f := metadataServer(t, map[string][]string{"payments-api": {"1.3.0", "1.4.0", "1.4.1"}},
+		map[string]map[string]string{
+			"payments-web":       {"owner": "team-a, team-b", "tier": "2"},
+			"payments-api:1.4.1": {"approved": "false"},
+		})
+	manifest := writeTestFile(t, "metadata.yaml", testMetadataManifest)
+
+	require.NoError(t, runCobraCmd(t, NewMetadataApplyCmd(f), "-f", manifest, "--dry-run"))
+	assert.Empty(t, *calls)
+
+	require.NoError(t, runCobraCmd(t, NewMetadataApplyCmd(f), "-f", manifest))
+	assert.Equal(t, []string{
+		"POST payments-api owner=team-a, team-b",
+		"POST payments-api:1.4.0 approved=true,docs=https://docs.example.com:8443/payments",
+		"POST payments-api:1.4.1 approved=true,docs=https://docs.example.com:8443/payments",
+	}, *calls)
+
+	// applying again changes nothing
+	*calls = nil
+	require.NoError(t, runCobraCmd(t, NewMetadataApplyCmd(f), "-f", manifest))
+	assert.Empty(t, *calls)
+}
+
+func TestMetadataApplyPrunesUnlistedKeys(t *testing.T) {
+	calls, f := metadataServer(t, nil, map[string]map[string]string{
+		"ledger": {"owner": "old", "tier": "2", "qa": "passed"},
+	})
+	manifest := writeTestFile(t, "metadata.json",
+		`{"entries": [{"registry": "reg", "package": "ledger", "metadata": {"owner": "team-c", "qa": "passed"}}]}`)
+
+	require.NoError(t, runCobraCmd(t, NewMetadataApplyCmd(f), "-f", manifest, "--prune"))
+	assert.Equal(t, []string{"POST ledger owner=team-c", "DELETE ledger tier=2"}, *calls)
+}
+
+func TestDesiredMetadata(t *testing.T) {
+	current := map[string]string{"owner": "old", "tier": "2"}
+	manifest := map[string]string{"owner": "new", "qa": "passed"}
+	assert.Equal(t, []valueChange{
+		{Key: "owner", Change: changeChanged, From: "old", To: "new"},
+		{Key: "qa", Change: changeAdded, To: "passed"},
+	}, diffValues(current, desiredMetadata(current, manifest, false)))
+	assert.Equal(t, []valueChange{
+		{Key: "owner", Change: changeChanged, From: "old", To: "new"},
+		{Key: "qa", Change: changeAdded, To: "passed"},
+		{Key: "tier", Change: changeRemoved, From: "2"},
+	}, diffValues(current, desiredMetadata(current, manifest, true)))
+}
+
+func TestLoadMetadataManifestRejectsInvalidEntries(t *testing.T) {
+	_, err := loadMetadataManifest(writeTestFile(t, "m.yaml", `
+entries:
+  - package: api
+    metadata: {owner: a}
+  - registry: reg
+    package: "api-[0-9]"
+    metadata: {owner: a}
+  - registry: reg
+    package: api
+    metadata: {owner: ""}
+`))
+	require.ErrorContains(t, err, "3 of 3 manifest entries are invalid")
+	require.ErrorContains(t, err, "entry 1 (api): no registry")
+	require.ErrorContains(t, err, "unsupported wildcard character")
+	require.ErrorContains(t, err, `empty value for metadata key "owner"`)
+
+	_, err = loadMetadataManifest(writeTestFile(t, "m.yaml", "entries:\n  - pkg: api\n"))
+	require.ErrorContains(t, err, "field pkg not found")
+}
@@ -0,0 +1,496 @@
+package command
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/harness/harness-cli/cmd/cmdutils"
+	"github.com/harness/harness-cli/config"
+	"github.com/harness/harness-cli/internal/api/ar"
+	"github.com/harness/harness-cli/internal/api/ar_v3"
+	"github.com/harness/harness-cli/module/ar/migrate/util"
+	"github.com/harness/harness-cli/util/common/pagination"
+	"github.com/harness/harness-cli/util/common/printer"
+	"github.com/harness/harness-cli/util/common/progress"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/gobwas/glob"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	cleanupPlanned = "planned"
+	cleanupDeleted = "deleted"
+	cleanupFailed  = "failed"
+)
+
+// cleanupPolicy is the file read by cleanup --policy. Example:
+//
+//	registry: docker-dev          # used when --registry is not set
+//	rules:
+//	  - packages: "payments-*"    # package pattern, * and ? wildcards
+//	    keepLast: 10              # always keep the 10 newest versions
+//	    olderThanDays: 90         # only delete versions older than 90 days that were never downloaded
+//	    exemptReleases: true      # never delete semver releases such as 1.4.0
+//	    skip:
+//	      labels: [retain]        # packages carrying one of these labels
+//	      metadata:               # versions carrying this metadata
+//	        keep: "true"
+//	        release-ticket: "*"   # any value
+//
+// A package is governed by the first rule whose pattern matches it.
+type cleanupPolicy struct {
+	Registry string        `yaml:"registry"`
+	Rules    []cleanupRule `yaml:"rules"`
+}
+
+type cleanupRule struct {
+	Packages       string `yaml:"packages"`
+	KeepLast       int    `yaml:"keepLast"`
+	OlderThanDays  int    `yaml:"olderThanDays"`
+	ExemptReleases bool   `yaml:"exemptReleases"`
+	Skip           struct {
+		Labels   []string          `yaml:"labels"`
+		Metadata map[string]string `yaml:"metadata"`
+	} `yaml:"skip"`
+
+	match glob.Glob
+}
+
+// cleanupCandidate is a version selected for deletion by a cleanup policy
+type cleanupCandidate struct {
+	Package      string `json:"package"`
+	Version      string `json:"version"`
+	LastModified string `json:"lastModified,omitempty"`
+	Downloads    int64  `json:"downloads"`
+	Reason       string `json:"reason"`
+	Status       string `json:"status"`
+	Error        string `json:"error,omitempty"`
+}
+
+// NewCleanupArtifactCmd creates a new cobra.Command for deleting the package
+// versions selected by a retention policy.
+// command example: hc artifact cleanup --policy cleanup.yaml --registry docker-dev
+//
+// The versions to delete are previewed first; once confirmed they are deleted
+// in batches and a report of every version is printed.
+func NewCleanupArtifactCmd(c *cmdutils.Factory) *cobra.Command {
+	var policyFile, registry string
+	var batchSize int
+	var dryRun, force, yes bool
+	cmd := &cobra.Command{
+		Use:   "cleanup --policy <cleanup.yaml>",
+		Short: "Delete package versions selected by a retention policy",
+		Long: `Delete the package versions of a registry selected by the rules of a retention
+policy. A rule applies to the packages matching its pattern and can keep the
+newest versions of each package, delete only versions older than a number of
+days that were never downloaded, skip packages carrying labels or versions
+carrying metadata, and exempt semver releases. A package is governed by the
+first rule matching it.
+
+The versions to delete are previewed and must be confirmed (or --yes given);
+they are then deleted in batches and a report of every version is printed.
+
+Policy file:
+  registry: docker-dev
+  rules:
+    - packages: "payments-*"
+      keepLast: 10
+      olderThanDays: 90
+      exemptReleases: true
+      skip:
+        labels: [retain]
+        metadata:
+          keep: "true"`,
+		Example: `  hc artifact cleanup --policy cleanup.yaml --dry-run
+  hc artifact cleanup --policy cleanup.yaml --registry docker-dev --yes --batch-size 20`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			p := progress.NewConsoleReporter()
+			if batchSize < 1 {
+				return fmt.Errorf("--batch-size must be at least 1")
+			}
+
+			policy, err := loadCleanupPolicy(policyFile)
+			if err != nil {
+				return err
+			}
+			if registry == "" {
+				registry = policy.Registry
+			}
+			if registry == "" {
+				return fmt.Errorf("no registry: set --registry or registry in %s", policyFile)
+			}
+
+			p.Start(fmt.Sprintf("Selecting versions of %s to clean up", registry))
+			candidates, err := planCleanup(ctx, c, registry, policy, time.Now(), p)
+			if err != nil {
+				p.Error("Failed to select versions")
+				return err
+			}
+			p.Success(fmt.Sprintf("%d version(s) selected for deletion", len(candidates)))
+
+			patterns := make([]string, 0, len(policy.Rules))
+			for _, rule := range policy.Rules {
+				patterns = append(patterns, rule.Packages)
+			}
+			preview := &bulkDeleteDryRunResponse{
+				DryRun:          true,
+				Force:           force,
+				Message:         fmt.Sprintf("Cleanup policy %s", policyFile),
+				Pattern:         strings.Join(patterns, ", "),
+				Registry:        registry,
+				Success:         len(candidates),
+				Total:           len(candidates),
+				VersionPattern:  "selected by the policy rules",
+				SuccessPackages: make([]string, 0, len(candidates)),
+			}
+			for _, candidate := range candidates {
+				preview.SuccessPackages = append(preview.SuccessPackages,
+					fmt.Sprintf("%s:%s (%s)", candidate.Package, candidate.Version, candidate.Reason))
+			}
+			if err := printBulkDeleteResponse(preview, p, "Versions"); err != nil {
+				return err
+			}
+			if dryRun || len(candidates) == 0 {
+				return nil
+			}
+
+			if !yes {
+				fmt.Printf("Above %d version(s) will be deleted. Do you want to proceed? (y/N): ", len(candidates))
+				response, err := bufio.NewReader(stdinReader).ReadString('\n')
+				if err != nil {
+					p.Error("Failed to read confirmation input")
+					return fmt.Errorf("failed to read confirmation: %w", err)
+				}
+				if response = strings.TrimSpace(response); response != "y" && response != "Y" {
+					p.Error("Cleanup cancelled by user")
+					return fmt.Errorf("cleanup cancelled by user")
+				}
+			}
+
+			failed := executeCleanup(ctx, c, registry, candidates, batchSize, force, p)
+			if err := printer.Print(candidates, 0, 1, int64(len(candidates)), false, [][]string{
+				{"package", "Package"},
+				{"version", "Version"},
+				{"lastModified", "Last Modified"},
+				{"reason", "Reason"},
+				{"status", "Status"},
+				{"error", "Error"},
+			}); err != nil {
+				return err
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d version(s) failed to delete", failed, len(candidates))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&policyFile, "policy", "", "Retention policy file (YAML)")
+	cmd.Flags().StringVar(&registry, "registry", "", "Registry to clean up (default: the registry of the policy)")
+	cmd.Flags().IntVar(&batchSize, "batch-size", 10, "Number of versions deleted in parallel per batch")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview the versions to delete without deleting them")
+	cmd.Flags().BoolVar(&force, "force", false, "Hard delete the versions, even if they are in use")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Delete without asking for confirmation")
+	cmd.MarkFlagRequired("policy")
+
+	return cmd
+}
+
+// loadCleanupPolicy reads a policy file, rejecting unknown fields and rules
+// that would delete every version of a package
+func loadCleanupPolicy(file string) (*cleanupPolicy, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy: %w", err)
+	}
+	dec := yaml.NewDecoder(strings.NewReader(string(data)))
+	dec.KnownFields(true)
+	policy := &cleanupPolicy{}
+	if err := dec.Decode(policy); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse policy %s: %w", file, err)
+	}
+	if len(policy.Rules) == 0 {
+		return nil, fmt.Errorf("policy %s has no rules", file)
+	}
+	for i := range policy.Rules {
+		rule := &policy.Rules[i]
+		if rule.Packages == "" {
+			rule.Packages = "*"
+		}
+		if _, err := util.IsWildCardExpression(rule.Packages); err != nil {
+			return nil, fmt.Errorf("rule %d of policy %s: %w", i+1, file, err)
+		}
+		if rule.match, err = glob.Compile(rule.Packages); err != nil {
+			return nil, fmt.Errorf("rule %d of policy %s: invalid pattern %q: %w", i+1, file, rule.Packages, err)
+		}
+		if rule.KeepLast < 0 || rule.OlderThanDays < 0 {
+			return nil, fmt.Errorf("rule %d of policy %s: keepLast and olderThanDays cannot be negative", i+1, file)
+		}
+		if rule.KeepLast == 0 && rule.OlderThanDays == 0 {
+			return nil, fmt.Errorf("rule %d of policy %s: set keepLast or olderThanDays", i+1, file)
+		}
+	}
+	return policy, nil
+}
+
+// planCleanup selects the versions of a registry to delete, listing the
+// packages of the registry and the versions of every package a rule applies to
+func planCleanup(ctx context.Context, c *cmdutils.Factory, registry string, policy *cleanupPolicy, now time.Time,
+	p *progress.ConsoleReporter) ([]cleanupCandidate, error) {
+	client := c.RegistryHttpClient()
+	artifacts, err := listRegistryArtifacts(ctx, client, registry)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []cleanupCandidate
+	for _, artifact := range artifacts {
+		i := slices.IndexFunc(policy.Rules, func(r cleanupRule) bool { return r.match.Match(artifact.Name) })
+		if i < 0 {
+			continue
+		}
+		rule := &policy.Rules[i]
+		var labels []string
+		if artifact.Labels != nil {
+			labels = *artifact.Labels
+		}
+		if label := matchingLabel(labels, rule.Skip.Labels); label != "" {
+			p.Step(fmt.Sprintf("Skipping %s: labelled %s", artifact.Name, label))
+			continue
+		}
+
+		versions, err := listVersionUsage(ctx, client, artifactRegistryRef(registry), artifact.Name, "")
+		if err != nil {
+			return nil, err
+		}
+		for _, candidate := range selectCleanupVersions(rule, artifact.Name, versions, now) {
+			if len(rule.Skip.Metadata) > 0 {
+				metadata, err := versionMetadata(ctx, c.RegistryV2HttpClient(),
+					versionRef{Registry: registry, Artifact: artifact.Name, Version: candidate.Version})
+				if err != nil {
+					return nil, err
+				}
+				if key := matchingMetadata(metadata, rule.Skip.Metadata); key != "" {
+					p.Step(fmt.Sprintf("Skipping %s:%s: metadata %s=%s", artifact.Name, candidate.Version, key,
+						metadata[key]))
+					continue
+				}
+			}
+			candidates = append(candidates, candidate)
+		}
+	}
+	return candidates, nil
+}
+
+// selectCleanupVersions applies the age, count and release rules to the
+// versions of a package; versions without a known modification date are
+// treated as the newest so they are never deleted by age
+func selectCleanupVersions(rule *cleanupRule, pkg string, versions []versionUsage,
+	now time.Time) []cleanupCandidate {
+	type datedVersion struct {
+		versionUsage
+		modified time.Time
+	}
+	dated := make([]datedVersion, 0, len(versions))
+	for _, v := range versions {
+		modified, _ := time.Parse(time.RFC3339, v.LastModified)
+		dated = append(dated, datedVersion{versionUsage: v, modified: modified})
+	}
+	sort.SliceStable(dated, func(i, j int) bool {
+		if dated[i].modified.IsZero() != dated[j].modified.IsZero() {
+			return dated[i].modified.IsZero()
+		}
+		return dated[i].modified.After(dated[j].modified)
+	})
+
+	var candidates []cleanupCandidate
+	for i, v := range dated {
+		if i < rule.KeepLast || (rule.ExemptReleases && isSemverRelease(v.Version)) {
+			continue
+		}
+		var reasons []string
+		if rule.KeepLast > 0 {
+			reasons = append(reasons, fmt.Sprintf("not in the last %d", rule.KeepLast))
+		}
+		if rule.OlderThanDays > 0 {
+			age := int(now.Sub(v.modified).Hours() / 24)
+			if v.modified.IsZero() || v.Downloads > 0 || age < rule.OlderThanDays {
+				continue
+			}
+			reasons = append(reasons, fmt.Sprintf("%d days old, never downloaded", age))
+		}
+		candidates = append(candidates, cleanupCandidate{
+			Package:      pkg,
+			Version:      v.Version,
+			LastModified: v.LastModified,
+			Downloads:    v.Downloads,
+			Reason:       strings.Join(reasons, ", "),
+			Status:       cleanupPlanned,
+		})
+	}
+	return candidates
+}
+
+// isSemverRelease reports whether a version is a full semantic version
+// without a pre-release part, such as 1.4.0 or v2.0.1; dates and build
+// numbers such as 20240101 and partial versions such as 1.2 are not
+func isSemverRelease(version string) bool {
+	v, err := semver.StrictNewVersion(strings.TrimPrefix(version, "v"))
+	return err == nil && v.Prerelease() == ""
+}
+
+// matchingLabel returns the first skip label carried by a package; a skip
+// label without a value matches the key of key=value labels
+func matchingLabel(labels, skip []string) string {
+	values := labelValues(labels)
+	for _, s := range skip {
+		key, want, hasValue := strings.Cut(s, "=")
+		if got, ok := values[key]; ok && (!hasValue || got == want) {
+			return s
+		}
+	}
+	return ""
+}
+
+// matchingMetadata returns the first skip key whose value the metadata
+// carries, "*" matching any value
+func matchingMetadata(metadata, skip map[string]string) string {
+	keys := make([]string, 0, len(skip))
+	for k := range skip {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if got, ok := metadata[key]; ok && (skip[key] == "*" || got == skip[key]) {
+			return key
+		}
+	}
+	return ""
+}
+
+// listRegistryArtifacts returns the packages of a registry, following
+// pagination
+func listRegistryArtifacts(ctx context.Context, client *ar.ClientWithResponses,
+	registry string) ([]ar.RegistryArtifactMetadata, error) {
+	size := int64(pagination.DefaultPageSize)
+	fetch := func(ctx context.Context, page int64) (pagination.Page[ar.RegistryArtifactMetadata], error) {
+		resp, err := client.GetAllArtifactsByRegistryWithResponse(ctx, artifactRegistryRef(registry),
+			&ar.GetAllArtifactsByRegistryParams{Page: &page, Size: &size})
+		if err != nil {
+			return pagination.Page[ar.RegistryArtifactMetadata]{}, fmt.Errorf("failed to list artifacts: %w", err)
+		}
+		if resp.JSON200 == nil {
+			return pagination.Page[ar.RegistryArtifactMetadata]{}, fmt.Errorf(
+				"failed to list artifacts of registry %s: %s %s", registry, resp.Status(), string(resp.Body))
+		}
+		data := resp.JSON200.Data
+		return pagination.Page[ar.RegistryArtifactMetadata]{Items: data.Artifacts,
+			PageCount: derefInt64(data.PageCount)}, nil
+	}
+	return pagination.Collect(ctx, fetch, pagination.Options{Concurrency: pagination.DefaultConcurrency})
+}
+
+// executeCleanup deletes the candidates in batches, the versions of a batch
+// in parallel, recording the outcome in each candidate; it returns the
+// number of failed deletions
+func executeCleanup(ctx context.Context, c *cmdutils.Factory, registry string, candidates []cleanupCandidate,
+	batchSize int, force bool, p *progress.ConsoleReporter) int {
+	params := &ar_v3.BulkDeleteArtifactsParams{AccountIdentifier: config.Global.AccountID}
+	if org := config.Global.OrgID; org != "" {
+		params.OrgIdentifier = &org
+	}
+	if project := config.Global.ProjectID; project != "" {
+		params.ProjectIdentifier = &project
+	}
+
+	failed := 0
+	batches := (len(candidates) + batchSize - 1) / batchSize
+	for b := 0; b < batches; b++ {
+		batch := candidates[b*batchSize : min((b+1)*batchSize, len(candidates))]
+		var wg sync.WaitGroup
+		for i := range batch {
+			wg.Add(1)
+			go func(candidate *cleanupCandidate) {
+				defer wg.Done()
+				if err := deleteVersion(ctx, c, params, registry, candidate.Package, candidate.Version,
+					force); err != nil {
+					candidate.Status, candidate.Error = cleanupFailed, err.Error()
+					return
+				}
+				candidate.Status = cleanupDeleted
+			}(&batch[i])
+		}
+		wg.Wait()
+
+		batchFailed := 0
+		for _, candidate := range batch {
+			if candidate.Status == cleanupFailed {
+				batchFailed++
+			}
+		}
+		failed += batchFailed
+		msg := fmt.Sprintf("Batch %d/%d: %d deleted, %d failed", b+1, batches, len(batch)-batchFailed, batchFailed)
+		if batchFailed > 0 {
+			p.Error(msg)
+		} else {
+			p.Step(msg)
+		}
+	}
+	return failed
+}
+
+// deleteVersion deletes a single version through the bulk delete API
+func deleteVersion(ctx context.Context, c *cmdutils.Factory, params *ar_v3.BulkDeleteArtifactsParams,
+	registry, pkg, version string, force bool) error {
+	// the bulk delete API takes patterns, so a name with wildcards would
+	// match other versions
+	for _, name := range []string{pkg, version} {
+		if wildcard, err := util.IsWildCardExpression(name); wildcard || err != nil {
+			return fmt.Errorf("%q contains wildcard characters, delete it with hc artifact delete", name)
+		}
+	}
+	dryRun := false
+	resp, err := c.RegistryV3HttpClient().BulkDeleteArtifactsWithResponse(ctx, params,
+		ar_v3.BulkDeleteArtifactsJSONRequestBody{
+			Packages: pkg,
+			Versions: version,
+			Registry: registry,
+			Force:    &force,
+			DryRun:   &dryRun,
+		})
+	if err != nil {
+		return fmt.Errorf("bulk delete execution failed: %w", err)
+	}
+	if resp.StatusCode() != 200 {
+		if resp.JSONDefault != nil && resp.JSONDefault.Error.Message != nil {
+			return errors.New(*resp.JSONDefault.Error.Message)
+		}
+		return fmt.Errorf("bulk delete failed with status %d", resp.StatusCode())
+	}
+	var result bulkDeleteDryRunResponse
+	if err := json.Unmarshal(resp.Body, &result); err != nil {
+		return fmt.Errorf("failed to parse bulk delete response: %w", err)
+	}
+	if result.Failed > 0 || result.Success == 0 {
+		reason := result.Message
+		if len(result.FailedPackages) > 0 {
+			reason = strings.Join(result.FailedPackages, ", ")
+		}
+		return fmt.Errorf("not deleted: %s", reason)
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package command
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/harness/harness-cli/cmd/cmdutils"
+	"github.com/harness/harness-cli/internal/api/ar"
+	"github.com/harness/harness-cli/internal/api/ar_v2"
+	"github.com/harness/harness-cli/internal/api/ar_v3"
+	"github.com/harness/harness-cli/util/common/progress"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var cleanupNow = time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+// cleanupVersion is a version served by cleanupServer
+type cleanupVersion struct {
+	name      string
+	age       int // in days before cleanupNow
+	downloads int64
+	metadata  map[string]string
+}
+
+// cleanupServer serves the packages of registry reg and records the
+// deleted "<package>:<version>"; deleting a version listed in failing fails
+type cleanupServer struct {
+	mu      sync.Mutex
+	deleted []string
+	failing map[string]bool
+}
+
+func newCleanupServer(t *testing.T, labels map[string][]string,
+	versions map[string][]cleanupVersion) (*cleanupServer, *cmdutils.Factory) {
+	t.Helper()
+	withPullConfig(t)
+	s := &cleanupServer{failing: map[string]bool{}}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		q := r.URL.Query()
+		var body any
+		switch {
+		case r.URL.Path == "/registry/acct/reg/+/artifacts":
+			var artifacts []ar.RegistryArtifactMetadata
+			for name := range versions {
+				l := labels[name]
+				artifacts = append(artifacts, ar.RegistryArtifactMetadata{Name: name, Labels: &l})
+			}
+			sort.Slice(artifacts, func(i, j int) bool { return artifacts[i].Name < artifacts[j].Name })
+			body = ar.ListRegistryArtifactResponse{Data: ar.ListRegistryArtifact{Artifacts: artifacts}}
+		case strings.HasSuffix(r.URL.Path, "/+/versions"):
+			name := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/registry/acct/reg/+/artifact/"), "/+/versions")
+			var items []ar.ArtifactVersionMetadata
+			for _, v := range versions[name] {
+				modified := strconv.FormatInt(cleanupNow.AddDate(0, 0, -v.age).UnixMilli(), 10)
+				items = append(items, ar.ArtifactVersionMetadata{Name: v.name, LastModified: &modified,
+					DownloadsCount: &v.downloads})
+			}
+			body = ar.ListArtifactVersionResponse{Data: ar.ListArtifactVersion{ArtifactVersions: &items}}
+		case r.URL.Path == "/metadata":
+			var items []ar_v2.MetadataItemOutput
+			for _, v := range versions[q.Get("package")] {
+				if v.name == q.Get("version") {
+					for k, val := range v.metadata {
+						items = append(items, ar_v2.MetadataItemOutput{Key: k, Value: val})
+					}
+				}
+			}
+			body = ar_v2.MetadataResponse{Data: ar_v2.MetadataOutput{Metadata: items}}
+		case r.URL.Path == "/bulkdelete":
+			var req ar_v3.BulkDeleteRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, "reg", req.Registry)
+			assert.False(t, *req.DryRun)
+			name := req.Packages + ":" + req.Versions
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.failing[name] {
+				body = bulkDeleteDryRunResponse{Registry: "reg", Total: 1, Failed: 1, FailedPackages: []string{name}}
+				break
+			}
+			s.deleted = append(s.deleted, name)
+			body = bulkDeleteDryRunResponse{Registry: "reg", Total: 1, Success: 1, SuccessPackages: []string{name}}
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(ts.Close)
+
+	client, err := ar.NewClientWithResponses(ts.URL)
+	require.NoError(t, err)
+	v2Client, err := ar_v2.NewClientWithResponses(ts.URL)
+	require.NoError(t, err)
+	v3Client, err := ar_v3.NewClientWithResponses(ts.URL)
+	require.NoError(t, err)
+	return s, &cmdutils.Factory{
+		RegistryHttpClient:   func() *ar.ClientWithResponses { return client },
+		RegistryV2HttpClient: func() *ar_v2.ClientWithResponses { return v2Client },
+		RegistryV3HttpClient: func() *ar_v3.ClientWithResponses { return v3Client },
+	}
+}
+
+func mustLoadCleanupPolicy(t *testing.T, policy string) *cleanupPolicy {
+	t.Helper()
+	p, err := loadCleanupPolicy(writeTestFile(t, "cleanup.yaml", policy))
+	require.NoError(t, err)
+	return p
+}
+
+func candidateNames(candidates []cleanupCandidate) []string {
+	names := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		names = append(names, c.Package+":"+c.Version)
+	}
+	return names
+}
+
+func TestSelectCleanupVersions(t *testing.T) {
+	versions := []versionUsage{
+		{Version: "1.0.0", LastModified: "2026-01-01T00:00:00Z"},
+		// dates, build numbers and partial versions are not releases
+		{Version: "20240101", LastModified: "2026-01-02T00:00:00Z"},
+		{Version: "1234567", LastModified: "2026-01-03T00:00:00Z"},
+		{Version: "1.2", LastModified: "2026-01-04T00:00:00Z"},
+		{Version: "1.1.0-rc.1", LastModified: "2026-02-01T00:00:00Z"},
+		{Version: "1.1.0", LastModified: "2026-02-02T00:00:00Z", Downloads: 7},
+		{Version: "nightly-42", LastModified: "2026-05-20T00:00:00Z"},
+		{Version: "nightly-43", LastModified: "2026-05-30T00:00:00Z"},
+		// without a date the version counts as the newest
+		{Version: "unknown"},
+	}
+	tests := []struct {
+		name string
+		rule cleanupRule
+		want []string
+	}{
+		{"keep last", cleanupRule{KeepLast: 3}, []string{"app:1.1.0", "app:1.1.0-rc.1", "app:1.2", "app:1234567",
+			"app:20240101", "app:1.0.0"}},
+		{"older than", cleanupRule{OlderThanDays: 30}, []string{"app:1.1.0-rc.1", "app:1.2", "app:1234567",
+			"app:20240101", "app:1.0.0"}},
+		{"keep last and older than", cleanupRule{KeepLast: 5, OlderThanDays: 30}, []string{"app:1.2", "app:1234567",
+			"app:20240101", "app:1.0.0"}},
+		{"exempt releases", cleanupRule{KeepLast: 1, ExemptReleases: true},
+			[]string{"app:nightly-43", "app:nightly-42", "app:1.1.0-rc.1", "app:1.2", "app:1234567", "app:20240101"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, candidateNames(selectCleanupVersions(&tt.rule, "app", versions, cleanupNow)))
+		})
+	}
+
+	candidates := selectCleanupVersions(&cleanupRule{KeepLast: 5, OlderThanDays: 30}, "app", versions, cleanupNow)
+	assert.Equal(t, "not in the last 5, 148 days old, never downloaded", candidates[0].Reason)
+	assert.Equal(t, cleanupPlanned, candidates[0].Status)
+}
+
+func TestPlanCleanupSkipsLabelsAndMetadata(t *testing.T) {
+	_, f := newCleanupServer(t, map[string][]string{"core-lib": {"retain=forever"}}, map[string][]cleanupVersion{
+		"api":      {{name: "1", age: 100}, {name: "2", age: 90, metadata: map[string]string{"keep": "yes"}}},
+		"core-lib": {{name: "1", age: 100}},
+		"web":      {{name: "1", age: 100}, {name: "2", age: 1}},
+		"tools":    {{name: "1", age: 100}},
+	})
+	policy := mustLoadCleanupPolicy(t, `
+rules:
+  - packages: tools
+    keepLast: 1
+  - olderThanDays: 30
+    skip:
+      labels: [retain]
+      metadata:
+        keep: "*"
+`)
+
+	candidates, err := planCleanup(t.Context(), f, "reg", policy, cleanupNow, progress.NewConsoleReporter())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api:1", "web:1"}, candidateNames(candidates))
+}
+
+func TestCleanupDeletesInBatches(t *testing.T) {
+	s, f := newCleanupServer(t, nil, map[string][]cleanupVersion{
+		"api": {{name: "1", age: 100}, {name: "2", age: 90}, {name: "3", age: 80}, {name: "4", age: 1}},
+	})
+	s.failing["api:2"] = true
+	policy := writeTestFile(t, "cleanup.yaml", "registry: reg\nrules:\n  - keepLast: 1\n")
+
+	require.NoError(t, runCobraCmd(t, NewCleanupArtifactCmd(f), "--policy", policy, "--dry-run"))
+	assert.Empty(t, s.deleted)
+
+	withStdin(t, strings.NewReader("n\n"))
+	err := runCobraCmd(t, NewCleanupArtifactCmd(f), "--policy", policy)
+	require.ErrorContains(t, err, "cleanup cancelled by user")
+	assert.Empty(t, s.deleted)
+
+	err = runCobraCmd(t, NewCleanupArtifactCmd(f), "--policy", policy, "--yes", "--batch-size", "2")
+	require.ErrorContains(t, err, "1 of 3 version(s) failed to delete")
+	sort.Strings(s.deleted)
+	assert.Equal(t, []string{"api:1", "api:3"}, s.deleted)
+}
+
+func TestLoadCleanupPolicyRejectsInvalidPolicies(t *testing.T) {
+	_, err := loadCleanupPolicy(writeTestFile(t, "cleanup.yaml", "rules:\n  - packages: api\n"))
+	require.ErrorContains(t, err, "rule 1 of policy")
+	require.ErrorContains(t, err, "set keepLast or olderThanDays")
+	_, err = loadCleanupPolicy(writeTestFile(t, "cleanup.yaml", "rules:\n  - keepLast: 1\n    olderThan: 30\n"))
+	require.ErrorContains(t, err, "field olderThan not found")
+	_, err = loadCleanupPolicy(writeTestFile(t, "cleanup.yaml", "rules:\n  - packages: \"api-[0-9]\"\n    keepLast: 1\n"))
+	require.ErrorContains(t, err, "unsupported wildcard character")
+	_, err = loadCleanupPolicy(writeTestFile(t, "cleanup.yaml", "registry: reg\n"))
+	require.ErrorContains(t, err, "has no rules")
+}
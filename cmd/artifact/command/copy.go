@@ -89,19 +89,9 @@ func NewCopyArtifactCmd(c *cmdutils.Factory) *cobra.Command {
 
 			progress.Step(fmt.Sprintf("copying package from %s to %s", srcPackagePath, targetRegistryIdentifier))
 
-			resp, err := c.RegistryV2HttpClient().CopyRegistryPackageWithResponse(
-				context.Background(),
-				copyReqParam,
-			)
-
-			if err != nil {
-				progress.Error("Failed to upload package")
-				return err
-			}
-			// Check response
-			if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusCreated {
+			if err := copyRegistryPackage(context.Background(), c, copyReqParam); err != nil {
 				progress.Error("Copy failed")
-				return fmt.Errorf("failed to copy package: %s \n response: %s", resp.Status(), resp.Body)
+				return err
 			}
 
 			progress.Success(fmt.Sprintf("Successfully Copied package from  %s to %s", srcPackagePath, targetRegistryIdentifier))
@@ -152,6 +142,18 @@ func parsePackagePath(input string) ([]string, error) {
 	return []string{registry, artifact, version}, nil
 }
 
+// copyRegistryPackage copies a package version to another registry
+func copyRegistryPackage(ctx context.Context, c *cmdutils.Factory, params *v2client.CopyRegistryPackageParams) error {
+	resp, err := c.RegistryV2HttpClient().CopyRegistryPackageWithResponse(ctx, params)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusCreated {
+		return fmt.Errorf("failed to copy package: %s \n response: %s", resp.Status(), resp.Body)
+	}
+	return nil
+}
+
 func validateCopyRegistryPackageParams(r *v2client.CopyRegistryPackageParams) error {
 	if r == nil {
 		return fmt.Errorf("params cannot be nil")
@@ -200,47 +200,12 @@ func executeWithDryRunResponse(
 		return fmt.Errorf("failed to parse bulk delete response: %w", err)
 	}
 
-	if parsed.Message != "" {
-		fmt.Println(parsed.Message)
+	if err := printBulkDeleteResponse(&parsed, p, impactType); err != nil {
+		return err
 	}
-
-	fmt.Printf("Registry        : %s\n", parsed.Registry)
-	fmt.Printf("Version pattern : %s\n", parsed.VersionPattern)
-	fmt.Printf("Dry-run         : %t\n", parsed.DryRun)
-	fmt.Printf("Force           : %t\n", parsed.Force)
-	fmt.Printf("Total impacted  : %d (success: %d, failed: %d)\n",
-		parsed.Total, parsed.Success, parsed.Failed)
-
 	if len(parsed.SuccessPackages) == 0 {
-		fmt.Println("No package/Version found to be deleted matching given pattern")
 		return nil
 	}
-	p.Step("Printing impacted packages/version")
-	err := printOutPut(parsed.SuccessPackages)
-	if err != nil {
-		return err
-	}
-
-	extra := parsed.Success - len(parsed.SuccessPackages)
-	if extra > 0 {
-
-		extraMessage := fmt.Sprintf("... and %d more %s, will be impacted (not listed above)\n", extra, impactType)
-		if !parsed.DryRun {
-			// Already a real-run response; change to final message
-			extraMessage = fmt.Sprintf("... and %d more %s, is deleted \n", extra, impactType)
-		}
-		fmt.Println(extraMessage)
-	}
-
-	p.Step("Printing complete")
-
-	if len(parsed.FailedPackages) > 0 {
-
-		p.Step(fmt.Sprintf("Printing faliure : "))
-		for _, pkg := range parsed.FailedPackages {
-			p.Step(fmt.Sprintf("%s \n", pkg))
-		}
-	}
 
 	if !parsed.DryRun {
 		// Already a real-run response; nothing more to do.
@@ -294,6 +259,53 @@ func executeWithDryRunResponse(
 	return nil
 }
 
+// printBulkDeleteResponse renders the summary and impacted packages/versions
+// of a bulk delete response
+func printBulkDeleteResponse(parsed *bulkDeleteDryRunResponse, p *progress.ConsoleReporter, impactType string) error {
+	if parsed.Message != "" {
+		fmt.Println(parsed.Message)
+	}
+
+	fmt.Printf("Registry        : %s\n", parsed.Registry)
+	fmt.Printf("Version pattern : %s\n", parsed.VersionPattern)
+	fmt.Printf("Dry-run         : %t\n", parsed.DryRun)
+	fmt.Printf("Force           : %t\n", parsed.Force)
+	fmt.Printf("Total impacted  : %d (success: %d, failed: %d)\n",
+		parsed.Total, parsed.Success, parsed.Failed)
+
+	if len(parsed.SuccessPackages) == 0 {
+		fmt.Println("No package/Version found to be deleted matching given pattern")
+		return nil
+	}
+	p.Step("Printing impacted packages/version")
+	err := printOutPut(parsed.SuccessPackages)
+	if err != nil {
+		return err
+	}
+
+	extra := parsed.Success - len(parsed.SuccessPackages)
+	if extra > 0 {
+
+		extraMessage := fmt.Sprintf("... and %d more %s, will be impacted (not listed above)\n", extra, impactType)
+		if !parsed.DryRun {
+			// Already a real-run response; change to final message
+			extraMessage = fmt.Sprintf("... and %d more %s, is deleted \n", extra, impactType)
+		}
+		fmt.Println(extraMessage)
+	}
+
+	p.Step("Printing complete")
+
+	if len(parsed.FailedPackages) > 0 {
+
+		p.Step(fmt.Sprintf("Printing faliure : "))
+		for _, pkg := range parsed.FailedPackages {
+			p.Step(fmt.Sprintf("%s \n", pkg))
+		}
+	}
+	return nil
+}
+
 func printOutPut(filteredSlice []string) error {
 	fmt.Println("Impacted package/Version")
 	for _, pkg := range filteredSlice {
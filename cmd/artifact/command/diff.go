@@ -0,0 +1,450 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/harness/harness-cli/cmd/cmdutils"
+	"github.com/harness/harness-cli/config"
+	"github.com/harness/harness-cli/internal/api/ar"
+	"github.com/harness/harness-cli/internal/api/ar_v2"
+	"github.com/harness/harness-cli/util/common/printer"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	changeAdded   = "added"
+	changeRemoved = "removed"
+	changeChanged = "changed"
+)
+
+// versionRef identifies a version of an artifact in a registry
+type versionRef struct {
+	Registry string `json:"registry"`
+	Artifact string `json:"artifact"`
+	Version  string `json:"version"`
+}
+
+func (v versionRef) String() string {
+	if v.Version == "" {
+		return v.Registry + "/" + v.Artifact
+	}
+	return v.Registry + "/" + v.Artifact + ":" + v.Version
+}
+
+// versionDiff lists what changed from one artifact version to another
+type versionDiff struct {
+	From        versionRef    `json:"from"`
+	To          versionRef    `json:"to"`
+	PackageType string        `json:"packageType"`
+	Files       []fileChange  `json:"files"`
+	Metadata    []valueChange `json:"metadata"`
+	Labels      []valueChange `json:"labels"`
+	Platforms   []valueChange `json:"platforms,omitempty"`
+	Layers      []layerChange `json:"layers,omitempty"`
+}
+
+type fileChange struct {
+	Name         string `json:"name"`
+	Change       string `json:"change"`
+	FromSize     string `json:"fromSize,omitempty"`
+	ToSize       string `json:"toSize,omitempty"`
+	FromChecksum string `json:"fromChecksum,omitempty"`
+	ToChecksum   string `json:"toChecksum,omitempty"`
+}
+
+// valueChange is a changed key of a metadata, label or platform list; for a
+// platform the values are the manifest digests
+type valueChange struct {
+	Key    string `json:"key"`
+	Change string `json:"change"`
+	From   string `json:"from,omitempty"`
+	To     string `json:"to,omitempty"`
+}
+
+type layerChange struct {
+	Platform string `json:"platform"`
+	Change   string `json:"change"`
+	Digest   string `json:"digest"`
+	Size     int64  `json:"size"`
+}
+
+func (d *versionDiff) empty() bool {
+	return len(d.Files)+len(d.Metadata)+len(d.Labels)+len(d.Platforms)+len(d.Layers) == 0
+}
+
+// NewDiffArtifactCmd creates a new cobra.Command for comparing two versions of
+// an artifact.
+// command example: hc artifact diff <registry_name>/<artifact> <version1> <version2>
+//
+// Files are compared by name, size and checksum; docker images are compared
+// by platform and layers instead. Labels belong to the artifact rather than
+// to a version, so they only differ when --compare-to reads the second version
+// from another artifact.
+func NewDiffArtifactCmd(c *cmdutils.Factory) *cobra.Command {
+	const expectedNumberOfArgument = 3
+	var compareTo string
+	cmd := &cobra.Command{
+		Use:   "diff <registry_name>/<artifact> <version1> <version2>",
+		Short: "Compare two versions of an artifact",
+		Long: `List the files added, removed and changed (by size or checksum) between two
+versions of an artifact, along with the differences of their metadata and labels.
+Docker images are compared by platform and layer.`,
+		Example: `  hc artifact diff generic-prod/payments 1.4.2 1.5.0
+  hc artifact diff docker-prod/payments-api 1.4.2 1.5.0 --format json
+  hc artifact diff docker-dev/payments-api 1.5.0 1.5.0 --compare-to docker-prod/payments-api`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != expectedNumberOfArgument {
+				return fmt.Errorf(
+					"Error: Invalid number of argument,  accepts %d arg(s), received %d  \nUsage :\n %s",
+					expectedNumberOfArgument, len(args), cmd.UseLine(),
+				)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			registryName, artifact, err := parseArtifactRef(args[0])
+			if err != nil {
+				return err
+			}
+			from := versionRef{Registry: registryName, Artifact: artifact, Version: args[1]}
+			to := versionRef{Registry: registryName, Artifact: artifact, Version: args[2]}
+			if compareTo != "" {
+				if to.Registry, to.Artifact, err = parseArtifactRef(compareTo); err != nil {
+					return err
+				}
+			}
+
+			result, err := diffArtifactVersions(cmd.Context(), c, from, to)
+			if err != nil {
+				return err
+			}
+
+			if config.Global.Format == "json" {
+				options := printer.DefaultJsonOptions()
+				options.ShowPagination = false
+				return printer.PrintJsonWithOptions(result, options)
+			}
+			return printVersionDiff(result)
+		},
+	}
+
+	cmd.Flags().StringVar(&compareTo, "compare-to", "",
+		"Read the second version from this artifact (<registry_name>/<artifact>) instead")
+
+	return cmd
+}
+
+func diffArtifactVersions(ctx context.Context, c *cmdutils.Factory, from, to versionRef) (*versionDiff, error) {
+	client := c.RegistryHttpClient()
+	result := &versionDiff{From: from, To: to}
+
+	fromSummary, err := getVersionSummary(ctx, client, from)
+	if err != nil {
+		return nil, err
+	}
+	toSummary, err := getVersionSummary(ctx, client, to)
+	if err != nil {
+		return nil, err
+	}
+	fromType, toType := fromSummary.PackageType, toSummary.PackageType
+	if fromType != toType {
+		return nil, fmt.Errorf("cannot compare a %s package with a %s package", fromType, toType)
+	}
+	result.PackageType = string(fromType)
+
+	if fromType == ar.DOCKER {
+		if err := diffDockerVersions(ctx, client, result); err != nil {
+			return nil, err
+		}
+	} else {
+		fromFiles, err := listVersionFiles(ctx, client, from.Registry, from.Artifact, from.Version)
+		if err != nil {
+			return nil, err
+		}
+		toFiles, err := listVersionFiles(ctx, client, to.Registry, to.Artifact, to.Version)
+		if err != nil {
+			return nil, err
+		}
+		result.Files = diffFiles(fromFiles, toFiles)
+	}
+
+	fromMetadata, err := versionMetadata(ctx, c.RegistryV2HttpClient(), from)
+	if err != nil {
+		return nil, err
+	}
+	toMetadata, err := versionMetadata(ctx, c.RegistryV2HttpClient(), to)
+	if err != nil {
+		return nil, err
+	}
+	result.Metadata = diffValues(fromMetadata, toMetadata)
+
+	fromLabels, err := getArtifactLabels(ctx, c, from.Registry, from.Artifact)
+	if err != nil {
+		return nil, err
+	}
+	toLabels := fromLabels
+	if to.Registry != from.Registry || to.Artifact != from.Artifact {
+		if toLabels, err = getArtifactLabels(ctx, c, to.Registry, to.Artifact); err != nil {
+			return nil, err
+		}
+	}
+	result.Labels = diffValues(labelValues(fromLabels), labelValues(toLabels))
+	return result, nil
+}
+
+func getVersionSummary(ctx context.Context, client *ar.ClientWithResponses,
+	v versionRef) (*ar.ArtifactVersionSummary, error) {
+	resp, err := client.GetArtifactVersionSummaryWithResponse(ctx, artifactRegistryRef(v.Registry), v.Artifact,
+		v.Version, &ar.GetArtifactVersionSummaryParams{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch version: %w", err)
+	}
+	if resp.JSON200 == nil {
+		return nil, fmt.Errorf("failed to fetch version %s: %s %s", v, resp.Status(), string(resp.Body))
+	}
+	return &resp.JSON200.Data, nil
+}
+
+// versionMetadata returns the metadata of a version by key, or of the
+// package when the version is empty
+func versionMetadata(ctx context.Context, client *ar_v2.ClientWithResponses, v versionRef) (map[string]string,
+	error) {
+	params := &ar_v2.GetMetadataParams{
+		AccountIdentifier:  config.Global.AccountID,
+		RegistryIdentifier: v.Registry,
+		Package:            &v.Artifact,
+	}
+	if v.Version != "" {
+		params.Version = &v.Version
+	}
+	resp, err := client.GetMetadataWithResponse(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch metadata: %w", err)
+	}
+	if resp.JSON200 == nil {
+		return nil, fmt.Errorf("failed to fetch metadata of %s: %s %s", v, resp.Status(), string(resp.Body))
+	}
+	metadata := map[string]string{}
+	for _, m := range resp.JSON200.Data.Metadata {
+		metadata[m.Key] = m.Value
+	}
+	return metadata, nil
+}
+
+// labelValues maps labels to their values by key; labels that are not of the
+// form key=value have an empty value
+func labelValues(labels []string) map[string]string {
+	values := map[string]string{}
+	for _, label := range labels {
+		key, value, _ := strings.Cut(label, "=")
+		values[key] = value
+	}
+	return values
+}
+
+// diffValues lists the keys added, removed or changed from one map to the
+// other, sorted by key
+func diffValues(from, to map[string]string) []valueChange {
+	changes := []valueChange{}
+	for key, value := range from {
+		other, ok := to[key]
+		switch {
+		case !ok:
+			changes = append(changes, valueChange{Key: key, Change: changeRemoved, From: value})
+		case other != value:
+			changes = append(changes, valueChange{Key: key, Change: changeChanged, From: value, To: other})
+		}
+	}
+	for key, value := range to {
+		if _, ok := from[key]; !ok {
+			changes = append(changes, valueChange{Key: key, Change: changeAdded, To: value})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
+
+// diffFiles lists the files added, removed, or changed in size or checksum,
+// sorted by name
+func diffFiles(from, to []ar.FileDetail) []fileChange {
+	toByName := make(map[string]ar.FileDetail, len(to))
+	for _, f := range to {
+		toByName[f.Name] = f
+	}
+	changes := []fileChange{}
+	seen := map[string]bool{}
+	for _, f := range from {
+		seen[f.Name] = true
+		other, ok := toByName[f.Name]
+		if !ok {
+			changes = append(changes, fileChange{Name: f.Name, Change: changeRemoved, FromSize: f.Size,
+				FromChecksum: fileChecksum(f)})
+			continue
+		}
+		fromChecksum, toChecksum, differ := compareChecksums(f, other)
+		if f.Size != other.Size || differ {
+			changes = append(changes, fileChange{Name: f.Name, Change: changeChanged, FromSize: f.Size,
+				ToSize: other.Size, FromChecksum: fromChecksum, ToChecksum: toChecksum})
+		}
+	}
+	for _, f := range to {
+		if !seen[f.Name] {
+			changes = append(changes, fileChange{Name: f.Name, Change: changeAdded, ToSize: f.Size,
+				ToChecksum: fileChecksum(f)})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	return changes
+}
+
+// checksumAlgorithms lists the checksum algorithms from strongest to weakest
+func checksumAlgorithms(f ar.FileDetail) [][2]string {
+	c := parseFileChecksums(f.Checksums)
+	return [][2]string{{"sha512", c.SHA512}, {"sha256", c.SHA256}, {"sha1", c.SHA1}, {"md5", c.MD5}}
+}
+
+// fileChecksum returns the strongest checksum of a file as "<algorithm>:<hex>"
+func fileChecksum(f ar.FileDetail) string {
+	for _, c := range checksumAlgorithms(f) {
+		if c[1] != "" {
+			return c[0] + ":" + c[1]
+		}
+	}
+	return ""
+}
+
+// compareChecksums returns the strongest checksum listed for both files and
+// whether it differs; files without a common algorithm are compared by size
+func compareChecksums(from, to ar.FileDetail) (string, string, bool) {
+	toChecksums := checksumAlgorithms(to)
+	for i, c := range checksumAlgorithms(from) {
+		if c[1] != "" && toChecksums[i][1] != "" {
+			return c[0] + ":" + c[1], c[0] + ":" + toChecksums[i][1], c[1] != toChecksums[i][1]
+		}
+	}
+	return fileChecksum(from), fileChecksum(to), false
+}
+
+// diffDockerVersions compares the platforms of two docker image versions and
+// the layers of the platforms whose manifest changed
+func diffDockerVersions(ctx context.Context, client *ar.ClientWithResponses, result *versionDiff) error {
+	from, to := result.From, result.To
+	fromPlatforms, err := listDockerPlatforms(ctx, client, artifactRegistryRef(from.Registry), from.Artifact,
+		from.Version)
+	if err != nil {
+		return err
+	}
+	toPlatforms, err := listDockerPlatforms(ctx, client, artifactRegistryRef(to.Registry), to.Artifact, to.Version)
+	if err != nil {
+		return err
+	}
+	digests := func(platforms []dockerPlatform) map[string]string {
+		m := make(map[string]string, len(platforms))
+		for _, p := range platforms {
+			m[p.Platform] = p.Digest
+		}
+		return m
+	}
+	result.Platforms = diffValues(digests(fromPlatforms), digests(toPlatforms))
+	result.Layers = []layerChange{}
+
+	for _, p := range result.Platforms {
+		if p.Change != changeChanged {
+			continue
+		}
+		fromManifest, err := fetchDockerManifest(ctx, client, artifactRegistryRef(from.Registry), from.Artifact,
+			from.Version, p.From)
+		if err != nil {
+			return err
+		}
+		toManifest, err := fetchDockerManifest(ctx, client, artifactRegistryRef(to.Registry), to.Artifact,
+			to.Version, p.To)
+		if err != nil {
+			return err
+		}
+		fromLayers, toLayers := manifestLayers(fromManifest), manifestLayers(toManifest)
+		hasLayer := func(layers []dockerLayer, digest string) bool {
+			return slices.ContainsFunc(layers, func(l dockerLayer) bool { return l.Digest == digest })
+		}
+		for _, l := range fromLayers {
+			if !hasLayer(toLayers, l.Digest) {
+				result.Layers = append(result.Layers, layerChange{Platform: p.Key, Change: changeRemoved,
+					Digest: l.Digest, Size: l.Size})
+			}
+		}
+		for _, l := range toLayers {
+			if !hasLayer(fromLayers, l.Digest) {
+				result.Layers = append(result.Layers, layerChange{Platform: p.Key, Change: changeAdded,
+					Digest: l.Digest, Size: l.Size})
+			}
+		}
+	}
+	return nil
+}
+
+func printVersionDiff(result *versionDiff) error {
+	if err := printInspectSummary([][2]string{
+		{"From", result.From.String()},
+		{"To", result.To.String()},
+		{"Package Type", result.PackageType},
+	}); err != nil {
+		return err
+	}
+	if result.empty() {
+		fmt.Println("\nNo differences")
+		return nil
+	}
+
+	if err := printInspectSection("Files", result.Files, len(result.Files), [][]string{
+		{"change", "Change"},
+		{"name", "Name"},
+		{"fromSize", "Old Size"},
+		{"toSize", "New Size"},
+		{"fromChecksum", "Old Checksum"},
+		{"toChecksum", "New Checksum"},
+	}); err != nil {
+		return err
+	}
+	if err := printInspectSection("Platforms", result.Platforms, len(result.Platforms), [][]string{
+		{"change", "Change"},
+		{"key", "Platform"},
+		{"from", "Old Digest"},
+		{"to", "New Digest"},
+	}); err != nil {
+		return err
+	}
+	// sizes are formatted here, the table would print large numbers in
+	// e-notation
+	layers := make([]map[string]string, 0, len(result.Layers))
+	for _, l := range result.Layers {
+		layers = append(layers, map[string]string{
+			"change":   l.Change,
+			"platform": l.Platform,
+			"digest":   l.Digest,
+			"size":     formatBytes(l.Size),
+		})
+	}
+	if err := printInspectSection("Layers", layers, len(layers), [][]string{
+		{"change", "Change"},
+		{"platform", "Platform"},
+		{"digest", "Digest"},
+		{"size", "Size"},
+	}); err != nil {
+		return err
+	}
+	valueMappings := [][]string{
+		{"change", "Change"},
+		{"key", "Key"},
+		{"from", "Old Value"},
+		{"to", "New Value"},
+	}
+	if err := printInspectSection("Metadata", result.Metadata, len(result.Metadata), valueMappings); err != nil {
+		return err
+	}
+	return printInspectSection("Labels", result.Labels, len(result.Labels), valueMappings)
+}
@@ -0,0 +1,214 @@
+package command
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/harness/harness-cli/cmd/cmdutils"
+	"github.com/harness/harness-cli/internal/api/ar"
+	"github.com/harness/harness-cli/internal/api/ar_v2"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// diffVersion is a version served by diffServer
+type diffVersion struct {
+	packageType ar.PackageType
+	files       []ar.FileDetail
+	metadata    map[string]string
+	// platforms maps os/arch to the layer digests of its manifest
+	platforms map[string][]string
+}
+
+// diffServer serves versions by "<registry>/<artifact>/<version>" and
+// artifact labels by "<registry>/<artifact>"
+func diffServer(t *testing.T, versions map[string]diffVersion, labels map[string][]string) *cmdutils.Factory {
+	t.Helper()
+	withPullConfig(t)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/metadata" {
+			q := r.URL.Query()
+			v := versions[q.Get("registry_identifier")+"/"+q.Get("package")+"/"+q.Get("version")]
+			var items []ar_v2.MetadataItemOutput
+			for k, val := range v.metadata {
+				items = append(items, ar_v2.MetadataItemOutput{Key: k, Value: val})
+			}
+			_ = json.NewEncoder(w).Encode(ar_v2.MetadataResponse{Data: ar_v2.MetadataOutput{Metadata: items}})
+			return
+		}
+
+		// /registry/acct/<registry>/+/artifact/<artifact>/+/[version/<version>/]<endpoint>
+		parts := strings.Split(r.URL.Path, "/+/")
+		registry := strings.TrimPrefix(parts[0], "/registry/acct/")
+		artifact := strings.TrimPrefix(parts[1], "artifact/")
+		if parts[2] == "summary" {
+			l := labels[registry+"/"+artifact]
+			_ = json.NewEncoder(w).Encode(ar.ArtifactSummaryResponse{Data: ar.ArtifactSummary{ImageName: artifact,
+				Labels: &l}})
+			return
+		}
+		version, endpoint, _ := strings.Cut(strings.TrimPrefix(parts[2], "version/"), "/")
+		v, ok := versions[registry+"/"+artifact+"/"+version]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var body any
+		switch endpoint {
+		case "summary":
+			body = ar.ArtifactVersionSummaryResponse{Data: ar.ArtifactVersionSummary{ImageName: artifact,
+				Version: version, PackageType: v.packageType}}
+		case "files":
+			body = ar.FileDetailResponse{Data: ar.ListFileDetail{Files: v.files}}
+		case "docker/manifests":
+			var manifests []ar.DockerManifestDetails
+			for platform := range v.platforms {
+				manifests = append(manifests, ar.DockerManifestDetails{OsArch: platform,
+					Digest: manifestDigest(v.platforms[platform])})
+			}
+			body = ar.DockerManifestsResponse{Data: ar.DockerManifests{Manifests: &manifests}}
+		case "docker/manifest":
+			for _, layers := range v.platforms {
+				if manifestDigest(layers) == r.URL.Query().Get("digest") {
+					body = ar.DockerArtifactManifestResponse{Data: ar.DockerArtifactManifest{
+						Manifest: testManifest(layers)}}
+				}
+			}
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(ts.Close)
+
+	client, err := ar.NewClientWithResponses(ts.URL)
+	require.NoError(t, err)
+	v2Client, err := ar_v2.NewClientWithResponses(ts.URL)
+	require.NoError(t, err)
+	return &cmdutils.Factory{
+		RegistryHttpClient:   func() *ar.ClientWithResponses { return client },
+		RegistryV2HttpClient: func() *ar_v2.ClientWithResponses { return v2Client },
+	}
+}
+
+// manifestDigest derives a fake manifest digest from the layers of a manifest
+func manifestDigest(layers []string) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(strings.Join(layers, ","))))
+}
+
+func testManifest(layers []string) string {
+	var l []string
+	for _, digest := range layers {
+		l = append(l, fmt.Sprintf(`{"mediaType":"application/vnd.oci.image.layer.v1.tar+gzip","size":10,"digest":%q}`,
+			digest))
+	}
+	return `{"schemaVersion":2,"mediaType":"application/vnd.oci.image.manifest.v1+json",
+		"config":{"mediaType":"application/vnd.oci.image.config.v1+json","size":1,
+		"digest":"sha256:cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc"},
+		"layers":[` + strings.Join(l, ",") + `]}`
+}
+
+func TestDiffGenericVersions(t *testing.T) {
+	f := diffServer(t, map[string]diffVersion{
+		"reg/app/1.4.2": {packageType: ar.GENERIC, metadata: map[string]string{"commit": "abc", "owner": "pay"},
+			files: []ar.FileDetail{
+				{Name: "app.tar.gz", Size: "10 MB", Checksums: []string{"SHA-256: AAA", "MD5: m1"}},
+				{Name: "README.md", Size: "1 KB", Checksums: []string{"SHA-256: bbb"}},
+				{Name: "legacy.jar", Size: "2 MB", Checksums: []string{"SHA-256: ccc"}},
+				{Name: "notes.txt", Size: "1 KB", Checksums: []string{"MD5: n1"}},
+			}},
+		"reg/app/1.5.0": {packageType: ar.GENERIC, metadata: map[string]string{"commit": "def", "tier": "1"},
+			files: []ar.FileDetail{
+				{Name: "app.tar.gz", Size: "10 MB", Checksums: []string{"SHA-256: aaa2", "MD5: m1"}},
+				{Name: "README.md", Size: "1 KB", Checksums: []string{"SHA-256: bbb"}},
+				{Name: "app.sbom.json", Size: "5 KB", Checksums: []string{"SHA-512: ddd"}},
+				// without a common checksum algorithm only the sizes are compared
+				{Name: "notes.txt", Size: "1 KB", Checksums: []string{"SHA-1: n2"}},
+			}},
+	}, map[string][]string{"reg/app": {"team=payments"}})
+
+	result, err := diffArtifactVersions(t.Context(), f,
+		versionRef{Registry: "reg", Artifact: "app", Version: "1.4.2"},
+		versionRef{Registry: "reg", Artifact: "app", Version: "1.5.0"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "GENERIC", result.PackageType)
+	assert.Equal(t, []fileChange{
+		{Name: "app.sbom.json", Change: changeAdded, ToSize: "5 KB", ToChecksum: "sha512:ddd"},
+		{Name: "app.tar.gz", Change: changeChanged, FromSize: "10 MB", ToSize: "10 MB",
+			FromChecksum: "sha256:aaa", ToChecksum: "sha256:aaa2"},
+		{Name: "legacy.jar", Change: changeRemoved, FromSize: "2 MB", FromChecksum: "sha256:ccc"},
+	}, result.Files)
+	assert.Equal(t, []valueChange{
+		{Key: "commit", Change: changeChanged, From: "abc", To: "def"},
+		{Key: "owner", Change: changeRemoved, From: "pay"},
+		{Key: "tier", Change: changeAdded, To: "1"},
+	}, result.Metadata)
+	assert.Empty(t, result.Labels)
+	assert.Nil(t, result.Platforms)
+}
+
+func TestDiffDockerVersions(t *testing.T) {
+	layer := func(c string) string { return "sha256:" + strings.Repeat(c, 64) }
+	f := diffServer(t, map[string]diffVersion{
+		"reg/library/app/1.0": {packageType: ar.DOCKER, platforms: map[string][]string{
+			"linux/amd64": {layer("a"), layer("b")},
+			"linux/arm64": {layer("a"), layer("c")},
+		}},
+		"reg/library/app/2.0": {packageType: ar.DOCKER, platforms: map[string][]string{
+			"linux/amd64": {layer("a"), layer("d"), layer("e")},
+			"linux/s390x": {layer("f")},
+		}},
+	}, nil)
+
+	result, err := diffArtifactVersions(t.Context(), f,
+		versionRef{Registry: "reg", Artifact: "library/app", Version: "1.0"},
+		versionRef{Registry: "reg", Artifact: "library/app", Version: "2.0"})
+	require.NoError(t, err)
+
+	require.Len(t, result.Platforms, 3)
+	assert.Equal(t, valueChange{Key: "linux/amd64", Change: changeChanged,
+		From: manifestDigest([]string{layer("a"), layer("b")}),
+		To:   manifestDigest([]string{layer("a"), layer("d"), layer("e")})}, result.Platforms[0])
+	assert.Equal(t, changeRemoved, result.Platforms[1].Change)
+	assert.Equal(t, "linux/s390x", result.Platforms[2].Key)
+	assert.Equal(t, changeAdded, result.Platforms[2].Change)
+	assert.Equal(t, []layerChange{
+		{Platform: "linux/amd64", Change: changeRemoved, Digest: layer("b"), Size: 10},
+		{Platform: "linux/amd64", Change: changeAdded, Digest: layer("d"), Size: 10},
+		{Platform: "linux/amd64", Change: changeAdded, Digest: layer("e"), Size: 10},
+	}, result.Layers)
+	assert.Empty(t, result.Files)
+}
+
+func TestDiffComparesLabelsAcrossArtifacts(t *testing.T) {
+	f := diffServer(t, map[string]diffVersion{
+		"dev/app/1.0":    {packageType: ar.GENERIC},
+		"prod/app/1.0":   {packageType: ar.GENERIC},
+		"prod/chart/1.0": {packageType: ar.HELM},
+	}, map[string][]string{
+		"dev/app":  {"team=payments", "stage=dev", "experimental"},
+		"prod/app": {"team=payments", "stage=prod"},
+	})
+
+	result, err := diffArtifactVersions(t.Context(), f,
+		versionRef{Registry: "dev", Artifact: "app", Version: "1.0"},
+		versionRef{Registry: "prod", Artifact: "app", Version: "1.0"})
+	require.NoError(t, err)
+	assert.Equal(t, []valueChange{
+		{Key: "experimental", Change: changeRemoved},
+		{Key: "stage", Change: changeChanged, From: "dev", To: "prod"},
+	}, result.Labels)
+
+	_, err = diffArtifactVersions(t.Context(), f,
+		versionRef{Registry: "dev", Artifact: "app", Version: "1.0"},
+		versionRef{Registry: "prod", Artifact: "chart", Version: "1.0"})
+	require.ErrorContains(t, err, "cannot compare a GENERIC package with a HELM package")
+}
@@ -0,0 +1,123 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/harness/harness-cli/cmd/cmdutils"
+	"github.com/harness/harness-cli/config"
+	"github.com/harness/harness-cli/module/ar/migrate/adapter/har"
+	client2 "github.com/harness/harness-cli/util/client"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// dockerRegistry is where the images of a HAR Docker registry live
+type dockerRegistry struct {
+	// prefix is "<host>/<path>" images are pushed under, e.g.
+	// pkg.harness.io/<account>/<registry>
+	prefix   string
+	insecure bool
+	options  []remote.Option
+}
+
+// resolveDockerRegistry looks up the URL of a HAR registry and returns the
+// image prefix with remote options that authenticate with the stored
+// Harness credentials through the HAR keychain the migration uses
+func resolveDockerRegistry(ctx context.Context, c *cmdutils.Factory, registryName string) (dockerRegistry, error) {
+	registryRef := client2.GetRef(config.Global.AccountID, config.Global.OrgID, config.Global.ProjectID,
+		registryName)
+	resp, err := c.RegistryHttpClient().GetRegistryWithResponse(ctx, registryRef)
+	if err != nil {
+		return dockerRegistry{}, fmt.Errorf("failed to fetch registry details: %w", err)
+	}
+	if resp.JSON200 == nil {
+		return dockerRegistry{}, fmt.Errorf("registry '%s' not found (status: %d)", registryName, resp.StatusCode())
+	}
+	u, err := url.Parse(resp.JSON200.Data.Url)
+	if err != nil || u.Host == "" {
+		return dockerRegistry{}, fmt.Errorf("registry '%s' has no usable URL %q", registryName,
+			resp.JSON200.Data.Url)
+	}
+
+	return dockerRegistry{
+		prefix:   strings.TrimSuffix(u.Host+u.Path, "/"),
+		insecure: u.Scheme == "http",
+		options: []remote.Option{
+			remote.WithContext(ctx),
+			remote.WithAuthFromKeychain(har.NewHarKeychain("", config.Global.AuthToken, u.Hostname())),
+			remote.WithUserAgent(config.UserAgent()),
+		},
+	}, nil
+}
+
+// reference parses "<repository>[:<tag>|@<digest>]" relative to the
+// registry; a missing tag means latest
+func (r dockerRegistry) reference(image string) (name.Reference, error) {
+	var opts []name.Option
+	if r.insecure {
+		opts = append(opts, name.Insecure)
+	}
+	ref, err := name.ParseReference(r.prefix+"/"+strings.TrimPrefix(image, "/"), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image reference %q: %w", image, err)
+	}
+	return ref, nil
+}
+
+// selectPlatformImage returns the image of an index matching the platform,
+// descending into nested indexes
+func selectPlatformImage(index v1.ImageIndex, platform v1.Platform) (v1.Image, error) {
+	manifest, err := index.IndexManifest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index: %w", err)
+	}
+	for _, desc := range manifest.Manifests {
+		switch {
+		case desc.MediaType.IsIndex():
+			child, err := index.ImageIndex(desc.Digest)
+			if err != nil {
+				return nil, err
+			}
+			if img, err := selectPlatformImage(child, platform); err == nil {
+				return img, nil
+			}
+		case desc.MediaType.IsImage():
+			if desc.Platform != nil {
+				if desc.Platform.Satisfies(platform) {
+					return index.Image(desc.Digest)
+				}
+				continue
+			}
+			// descriptors written without a platform, as single-image
+			// layouts often are, are matched on the image config
+			img, err := index.Image(desc.Digest)
+			if err != nil {
+				return nil, err
+			}
+			if ok, err := imageMatchesPlatform(img, platform); err != nil {
+				return nil, err
+			} else if ok {
+				return img, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no image for platform %s", platform)
+}
+
+// imageMatchesPlatform reports whether an image's config satisfies the platform
+func imageMatchesPlatform(img v1.Image, platform v1.Platform) (bool, error) {
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		return false, fmt.Errorf("failed to read image config: %w", err)
+	}
+	have := cfg.Platform()
+	if have == nil {
+		return false, nil
+	}
+	return have.Satisfies(platform), nil
+}
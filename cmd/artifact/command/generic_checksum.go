@@ -0,0 +1,131 @@
+package command
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/harness/harness-cli/cmd/artifact/command/utils"
+	"github.com/harness/harness-cli/config"
+	"github.com/harness/harness-cli/internal/api/ar_pkg"
+	"github.com/harness/harness-cli/util/common/upload"
+)
+
+// genericChangeDetector finds the generic upload jobs whose file the registry
+// already holds. headChangeDetector asks about one file at a time; a
+// server-side bulk checksum check implements the same interface and replaces
+// it without touching the push and sync commands.
+type genericChangeDetector interface {
+	// Unchanged returns the DestPath of every job whose registry copy has the
+	// size and checksums of the local file
+	Unchanged(ctx context.Context, jobs []*upload.GenericUploadJob) (map[string]bool, error)
+}
+
+// headChangeDetector compares each job against the size and X-Checksum-*
+// headers HeadGenericFileAtPath returns for its DestPath
+type headChangeDetector struct {
+	client   *ar_pkg.ClientWithResponses
+	registry string
+	workers  int
+}
+
+func newHeadChangeDetector(client *ar_pkg.ClientWithResponses, registry string, workers int) *headChangeDetector {
+	if workers <= 0 {
+		workers = upload.DefaultUploadWorker
+	}
+	return &headChangeDetector{client: client, registry: registry, workers: workers}
+}
+
+// Unchanged issues the HEAD requests concurrently. A file the registry does
+// not have, or any failed request, counts as changed so it gets uploaded.
+func (d *headChangeDetector) Unchanged(ctx context.Context, jobs []*upload.GenericUploadJob) (map[string]bool, error) {
+	unchanged := make(map[string]bool)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	queue := make(chan *upload.GenericUploadJob)
+
+	for i := 0; i < d.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				resp, err := d.client.HeadGenericFileAtPath(ctx, config.Global.AccountID, d.registry, job.DestPath,
+					keepPathSlashes)
+				if err != nil {
+					continue
+				}
+				resp.Body.Close()
+				if resp.StatusCode == http.StatusOK && sameGenericFile(resp, job) {
+					mu.Lock()
+					unchanged[job.DestPath] = true
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, job := range jobs {
+		queue <- job
+	}
+	close(queue)
+	wg.Wait()
+	return unchanged, ctx.Err()
+}
+
+// sameGenericFile reports whether a HEAD response describes the job's file:
+// the sizes agree and every checksum the registry returns matches, with at
+// least one checksum to compare
+func sameGenericFile(resp *http.Response, job *upload.GenericUploadJob) bool {
+	if resp.ContentLength >= 0 && resp.ContentLength != job.FileSize {
+		return false
+	}
+	local := http.Header{}
+	utils.SetChecksumHeaders(local, job.Checksums)
+	compared := 0
+	for name := range local {
+		value := resp.Header.Get(name)
+		if value == "" {
+			continue
+		}
+		if !strings.EqualFold(value, local.Get(name)) {
+			return false
+		}
+		compared++
+	}
+	return compared > 0
+}
+
+// filterUnchangedGenericJobs drops the jobs the detector reports unchanged
+// and returns the remaining jobs with the number dropped
+func filterUnchangedGenericJobs(
+	ctx context.Context,
+	detector genericChangeDetector,
+	jobs []upload.FileUploadJob,
+) ([]upload.FileUploadJob, int, error) {
+	generic := make([]*upload.GenericUploadJob, 0, len(jobs))
+	for _, job := range jobs {
+		if gj, ok := job.(*upload.GenericUploadJob); ok {
+			generic = append(generic, gj)
+		}
+	}
+	unchanged, err := detector.Unchanged(ctx, generic)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	changed := make([]upload.FileUploadJob, 0, len(jobs))
+	for _, job := range jobs {
+		if gj, ok := job.(*upload.GenericUploadJob); ok && unchanged[gj.DestPath] {
+			continue
+		}
+		changed = append(changed, job)
+	}
+	return changed, len(jobs) - len(changed), nil
+}
+
+// keepPathSlashes sends a nested file path with its slashes intact; the
+// generated package client escapes them as %2F
+func keepPathSlashes(_ context.Context, req *http.Request) error {
+	req.URL.RawPath = ""
+	return nil
+}
@@ -0,0 +1,158 @@
+package command
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/harness/harness-cli/cmd/artifact/command/utils"
+	"github.com/harness/harness-cli/config"
+	"github.com/harness/harness-cli/util/common/upload"
+)
+
+// withStoredGenericFiles stands up a registry that already holds the given
+// local files under <pkg>/<version>/<name>, answering HEAD with their size and
+// checksums, and counts the files PUT to it
+func withStoredGenericFiles(t *testing.T, stored map[string]string) *[]string {
+	t.Helper()
+	var mu sync.Mutex
+	var puts []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.EscapedPath(), "/pkg/test-account/myreg/files/")
+		switch r.Method {
+		case http.MethodHead:
+			path, ok := stored[name]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			checksums, err := utils.ComputeFileChecksums(path)
+			if err != nil {
+				t.Errorf("checksum %s: %v", path, err)
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Errorf("stat %s: %v", path, err)
+				return
+			}
+			utils.SetChecksumHeaders(w.Header(), checksums)
+			w.Header().Set("Content-Length", strconv.FormatInt(info.Size(), 10))
+			w.WriteHeader(http.StatusOK)
+		case http.MethodPut:
+			mu.Lock()
+			puts = append(puts, name)
+			mu.Unlock()
+			w.WriteHeader(http.StatusCreated)
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	orig := config.Global
+	config.Global.Registry.PkgURL = srv.URL
+	config.Global.AccountID = "test-account"
+	config.Global.AuthToken = "pat.test-account.aaa.bbb"
+	t.Cleanup(func() { config.Global = orig })
+	return &puts
+}
+
+func TestNewPushGenericCmd_SkipsUnchangedFiles(t *testing.T) {
+	root := makeTree(t, map[string]string{
+		"index.html":       "home",
+		"assets/main.css":  "body {}",
+		"assets/new.js":    "console.log(1)",
+		"assets/edited.js": "v2",
+	})
+	stale := writeFile(t, t.TempDir(), "edited.js", "v1")
+	base := filepath.Base(root)
+	puts := withStoredGenericFiles(t, map[string]string{
+		"site/1.0.0/" + base + "/index.html":       filepath.Join(root, "index.html"),
+		"site/1.0.0/" + base + "/assets/main.css":  filepath.Join(root, "assets", "main.css"),
+		"site/1.0.0/" + base + "/assets/edited.js": stale,
+	})
+
+	stdout, err := runGenericCmd(t, "myreg", root, "--name", "site")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "skipping 2 unchanged files") {
+		t.Errorf("expected skip summary, got: %s", stdout)
+	}
+	got := append([]string(nil), *puts...)
+	sort.Strings(got)
+	want := []string{"site/1.0.0/" + base + "/assets/edited.js", "site/1.0.0/" + base + "/assets/new.js"}
+	if !equal(got, want) {
+		t.Errorf("uploaded %v, want %v", got, want)
+	}
+}
+
+func TestNewPushGenericCmd_AllFilesUnchanged(t *testing.T) {
+	dir := t.TempDir()
+	file := writeFile(t, dir, "blob.bin", "data")
+	puts := withStoredGenericFiles(t, map[string]string{"web/1.0.0/blob.bin": file})
+
+	stdout, err := runGenericCmd(t, "myreg", file, "--name", "web")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(*puts) != 0 {
+		t.Errorf("expected no uploads, got %v", *puts)
+	}
+	if !strings.Contains(stdout, "skipping 1 unchanged files") || !strings.Contains(stdout, "already up to date") {
+		t.Errorf("expected up-to-date summary, got: %s", stdout)
+	}
+}
+
+// stubDetector stands in for a bulk checksum check
+type stubDetector map[string]bool
+
+func (s stubDetector) Unchanged(context.Context, []*upload.GenericUploadJob) (map[string]bool, error) {
+	return s, nil
+}
+
+func TestFilterUnchangedGenericJobs(t *testing.T) {
+	jobs := []upload.FileUploadJob{
+		upload.NewGenericUploadJob("a", "/tmp/a", "p/1/a", "reg", "p", "1", 1, utils.FileChecksums{}, "", nil),
+		upload.NewGenericUploadJob("b", "/tmp/b", "p/1/b", "reg", "p", "1", 1, utils.FileChecksums{}, "", nil),
+	}
+	changed, skipped, err := filterUnchangedGenericJobs(context.Background(), stubDetector{"p/1/a": true}, jobs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if skipped != 1 || len(changed) != 1 || changed[0].GetID() != "b" {
+		t.Errorf("changed=%v skipped=%d, want only b", destPathsOf(changed), skipped)
+	}
+}
+
+func TestSameGenericFile(t *testing.T) {
+	job := upload.NewGenericUploadJob("a", "/tmp/a", "p/1/a", "reg", "p", "1", 4,
+		utils.FileChecksums{SHA256: "abcd", MD5: "ef"}, "", nil)
+	response := func(size int64, headers map[string]string) *http.Response {
+		resp := &http.Response{ContentLength: size, Header: http.Header{}}
+		for k, v := range headers {
+			resp.Header.Set(k, v)
+		}
+		return resp
+	}
+
+	if !sameGenericFile(response(4, map[string]string{"X-Checksum-Sha256": "ABCD"}), job) {
+		t.Error("matching size and checksum should be unchanged")
+	}
+	if sameGenericFile(response(5, map[string]string{"X-Checksum-Sha256": "abcd"}), job) {
+		t.Error("a different size must count as changed")
+	}
+	if sameGenericFile(response(4, map[string]string{"X-Checksum-Sha256": "abcd", "X-Checksum-Md5": "00"}), job) {
+		t.Error("any mismatching checksum must count as changed")
+	}
+	if sameGenericFile(response(4, nil), job) {
+		t.Error("a response without checksums must count as changed")
+	}
+}
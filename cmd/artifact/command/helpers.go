@@ -0,0 +1,33 @@
+package command
+
+import (
+	"github.com/harness/harness-cli/config"
+	client2 "github.com/harness/harness-cli/util/client"
+)
+
+// artifactRegistryRef scopes a registry name to the configured account, org
+// and project, as the registry API expects in its path
+func artifactRegistryRef(registry string) string {
+	return client2.GetRef(config.Global.AccountID, config.Global.OrgID, config.Global.ProjectID, registry)
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func derefInt(i *int) int {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
+
+func derefInt64(i *int64) int64 {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
@@ -0,0 +1,51 @@
+package command
+
+import (
+	"io"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/harness/harness-cli/cmd/cmdutils"
+	"github.com/harness/harness-cli/config"
+	"github.com/harness/harness-cli/internal/api/ar"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+// runCobraCmd executes cmd with args, discarding its usage and error output
+func runCobraCmd(t *testing.T, cmd *cobra.Command, args ...string) error {
+	t.Helper()
+	cmd.SetArgs(args)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	return cmd.Execute()
+}
+
+// writeTestFile writes content to a file name in a new temporary directory
+// and returns its path
+func writeTestFile(t *testing.T, name, content string) string {
+	t.Helper()
+	dir := makeTree(t, map[string]string{name: content})
+	return filepath.Join(dir, name)
+}
+
+// withPullConfig sets account "acct" and API token "token" for the test
+func withPullConfig(t *testing.T) {
+	t.Helper()
+	withGlobalConfig(t, "acct", "", "")
+	origToken := config.Global.AuthToken
+	config.Global.AuthToken = "token"
+	t.Cleanup(func() { config.Global.AuthToken = origToken })
+}
+
+// newPullTestFactory returns a factory whose registry client talks to ts
+func newPullTestFactory(t *testing.T, ts *httptest.Server) *cmdutils.Factory {
+	t.Helper()
+	client, err := ar.NewClientWithResponses(ts.URL)
+	require.NoError(t, err)
+	return &cmdutils.Factory{
+		RegistryHttpClient: func() *ar.ClientWithResponses { return client },
+	}
+}
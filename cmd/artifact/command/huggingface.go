@@ -0,0 +1,550 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/harness/harness-cli/config"
+)
+
+// Repository types of a HuggingFace registry, as accepted by --artifact-type
+const (
+	hfModel   = "model"
+	hfDataset = "dataset"
+)
+
+// hfSampleSize is how much of each file the pre-upload check looks at to
+// decide between a regular and an LFS upload
+const hfSampleSize = 512
+
+// hfRepo addresses a model or dataset repository of a HAR HuggingFace
+// registry, which speaks the HuggingFace Hub API under
+// <pkg-url>/pkg/<account>/<registry>/huggingface
+type hfRepo struct {
+	base     string
+	kind     string
+	name     string
+	revision string
+	client   *http.Client
+}
+
+// newHFRepo addresses the repository name of kind in a registry, at the
+// package URL of u
+func newHFRepo(u pkgUploader, registryName, kind, name, revision string) (*hfRepo, error) {
+	if kind != hfModel && kind != hfDataset {
+		return nil, fmt.Errorf("invalid artifact type %q (expected %s or %s)", kind, hfModel, hfDataset)
+	}
+	if org, model, ok := strings.Cut(name, "/"); !ok || org == "" || model == "" || strings.Contains(model, "/") {
+		return nil, fmt.Errorf("invalid repository name %q (expected format: <org>/<name>)", name)
+	}
+	if u.pkgURL == "" {
+		return nil, fmt.Errorf("pkg-url must be set")
+	}
+	return &hfRepo{
+		base: fmt.Sprintf("%s/pkg/%s/%s/huggingface", strings.TrimRight(u.pkgURL, "/"),
+			config.Global.AccountID, registryName),
+		kind:     kind,
+		name:     name,
+		revision: revision,
+		client:   u.httpClient(0, ""),
+	}, nil
+}
+
+// apiURL returns <base>/api/{models|datasets}/<repo>/<op>/<revision>
+func (r *hfRepo) apiURL(op string) string {
+	return fmt.Sprintf("%s/api/%ss/%s/%s/%s", r.base, r.kind, r.name, op, url.PathEscape(r.revision))
+}
+
+// repoPath is the repository path outside the API, where datasets carry a
+// "datasets/" prefix and models none
+func (r *hfRepo) repoPath() string {
+	if r.kind == hfDataset {
+		return "datasets/" + r.name
+	}
+	return r.name
+}
+
+// uploadStateDir returns where the parts of multipart uploads to the
+// revision sent so far are recorded: under the user cache directory, keyed by
+// registry, repository and revision, so the pushed directory is left as is.
+// It is empty when there is no cache directory.
+func (r *hfRepo) uploadStateDir() string {
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	registry := sha256.Sum256([]byte(r.base))
+	return filepath.Join(cache, "hc", "huggingface", "upload", hex.EncodeToString(registry[:8]),
+		filepath.FromSlash(r.repoPath()), url.PathEscape(r.revision))
+}
+
+// resolveURL returns the download URL of a file at the revision
+func (r *hfRepo) resolveURL(file string) string {
+	segments := strings.Split(file, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return fmt.Sprintf("%s/%s/resolve/%s/%s", r.base, r.repoPath(), url.PathEscape(r.revision),
+		strings.Join(segments, "/"))
+}
+
+// do sends a JSON request and decodes a JSON response into out, if set
+func (r *hfRepo) do(ctx context.Context, method, rawURL, contentType string, body io.Reader, out any) error {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	setPkgAuthHeaders(req)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %w", method, rawURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("%s %s: server returned %s: %s", method, rawURL, resp.Status,
+			strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse response of %s: %w", rawURL, err)
+	}
+	return nil
+}
+
+// hfFile is a local file of a repository being pushed
+type hfFile struct {
+	path   string // path within the repository, slash separated
+	local  string
+	size   int64
+	sha256 string
+	sample []byte
+	lfs    bool
+	state  string // where the parts of a multipart upload are recorded
+}
+
+// collectHFFiles lists the files of a repository directory, skipping hidden
+// files and directories such as .git and .cache, with their upload state
+// recorded under stateDir
+func collectHFFiles(dir, stateDir string) ([]*hfFile, error) {
+	var files []*hfFile
+	err := filepath.WalkDir(dir, func(local string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if local != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, local)
+		if err != nil {
+			return err
+		}
+		f, err := describeHFFile(local)
+		if err != nil {
+			return err
+		}
+		f.path = filepath.ToSlash(rel)
+		if stateDir != "" {
+			f.state = filepath.Join(stateDir, rel+".json")
+		}
+		files = append(files, f)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+	return files, nil
+}
+
+// describeHFFile hashes a file and keeps the sample the pre-upload check needs
+func describeHFFile(local string) (*hfFile, error) {
+	file, err := os.Open(local)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	h := sha256.New()
+	var sample bytes.Buffer
+	size, err := io.Copy(io.MultiWriter(h, &limitedWriter{w: &sample, n: hfSampleSize}), file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", local, err)
+	}
+	return &hfFile{local: local, size: size, sha256: hex.EncodeToString(h.Sum(nil)), sample: sample.Bytes()}, nil
+}
+
+// limitedWriter keeps the first n bytes written to it and discards the rest
+type limitedWriter struct {
+	w io.Writer
+	n int
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if l.n > 0 {
+		keep := p
+		if len(keep) > l.n {
+			keep = keep[:l.n]
+		}
+		if _, err := l.w.Write(keep); err != nil {
+			return 0, err
+		}
+		l.n -= len(keep)
+	}
+	return len(p), nil
+}
+
+// preUpload asks the registry which files are stored through LFS, marking
+// them on the files
+func (r *hfRepo) preUpload(ctx context.Context, files []*hfFile) error {
+	type preUploadFile struct {
+		Path       string `json:"path"`
+		Sample     string `json:"sample,omitempty"`
+		Size       int64  `json:"size"`
+		UploadMode string `json:"uploadMode,omitempty"`
+	}
+	var req, resp struct {
+		Files []preUploadFile `json:"files"`
+	}
+	byPath := map[string]*hfFile{}
+	for _, f := range files {
+		req.Files = append(req.Files, preUploadFile{
+			Path: f.path, Sample: base64.StdEncoding.EncodeToString(f.sample), Size: f.size,
+		})
+		byPath[f.path] = f
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	if err := r.do(ctx, http.MethodPost, r.apiURL("preupload"), "application/json", bytes.NewReader(body),
+		&resp); err != nil {
+		return fmt.Errorf("pre-upload check failed: %w", err)
+	}
+	for _, f := range resp.Files {
+		if file, ok := byPath[f.Path]; ok {
+			file.lfs = f.UploadMode == "lfs"
+		}
+	}
+	return nil
+}
+
+// lfsAction is an upload or verify step the LFS batch API hands out
+type lfsAction struct {
+	Href   string            `json:"href"`
+	Header map[string]string `json:"header,omitempty"`
+}
+
+type lfsObject struct {
+	Oid     string               `json:"oid"`
+	Size    int64                `json:"size"`
+	Actions map[string]lfsAction `json:"actions,omitempty"`
+	Error   *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// lfsBatch requests upload actions for LFS files. Objects the registry
+// already stores come back without an upload action, which is what lets an
+// interrupted push resume with the files not uploaded yet.
+func (r *hfRepo) lfsBatch(ctx context.Context, files []*hfFile) (string, map[string]lfsObject, error) {
+	req := struct {
+		Operation string      `json:"operation"`
+		Transfers []string    `json:"transfers"`
+		Objects   []lfsObject `json:"objects"`
+		HashAlgo  string      `json:"hash_algo"`
+		Ref       struct {
+			Name string `json:"name"`
+		} `json:"ref"`
+	}{Operation: "upload", Transfers: []string{"basic", "multipart"}, HashAlgo: "sha256"}
+	req.Ref.Name = r.revision
+	for _, f := range files {
+		req.Objects = append(req.Objects, lfsObject{Oid: f.sha256, Size: f.size})
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", nil, err
+	}
+	var resp struct {
+		Transfer string      `json:"transfer"`
+		Objects  []lfsObject `json:"objects"`
+	}
+	batchURL := fmt.Sprintf("%s/%s.git/info/lfs/objects/batch", r.base, r.repoPath())
+	if err := r.do(ctx, http.MethodPost, batchURL, "application/vnd.git-lfs+json", bytes.NewReader(body),
+		&resp); err != nil {
+		return "", nil, fmt.Errorf("LFS batch request failed: %w", err)
+	}
+	objects := make(map[string]lfsObject, len(resp.Objects))
+	for _, o := range resp.Objects {
+		if o.Error != nil {
+			return "", nil, fmt.Errorf("LFS object %s rejected: %s", o.Oid, o.Error.Message)
+		}
+		objects[o.Oid] = o
+	}
+	return resp.Transfer, objects, nil
+}
+
+// uploadLFS uploads one LFS object. A multipart transfer uploads the file in
+// the chunks the registry asked for, each retried on its own and recorded
+// once sent, and completes the upload with the parts' ETags.
+func (r *hfRepo) uploadLFS(ctx context.Context, transfer string, f *hfFile, obj lfsObject) error {
+	upload, ok := obj.Actions["upload"]
+	if !ok {
+		return nil
+	}
+	file, err := os.Open(f.local)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if chunkSize, _ := strconv.ParseInt(upload.Header["chunk_size"], 10, 64); transfer == "multipart" && chunkSize > 0 {
+		if err := r.uploadChunks(ctx, file, f, upload, chunkSize); err != nil {
+			return err
+		}
+	} else if err := r.put(ctx, upload.Href, upload.Header, file, f.size); err != nil {
+		return err
+	}
+
+	if verify, ok := obj.Actions["verify"]; ok {
+		body, _ := json.Marshal(lfsObject{Oid: f.sha256, Size: f.size})
+		if err := r.do(ctx, http.MethodPost, verify.Href, "application/vnd.git-lfs+json", bytes.NewReader(body),
+			nil); err != nil {
+			return fmt.Errorf("failed to verify %s: %w", f.path, err)
+		}
+	}
+	return nil
+}
+
+// hfUploadState records the parts of a multipart upload that were sent, so
+// a push interrupted mid-file only sends the rest once the registry hands out
+// the same upload again
+type hfUploadState struct {
+	Oid       string         `json:"oid"`
+	Upload    string         `json:"upload"`
+	ChunkSize int64          `json:"chunkSize"`
+	Parts     map[int]string `json:"parts"` // ETags by part number
+}
+
+// loadUploadState returns the recorded parts of the upload, or an empty state
+// when none were recorded for it
+func loadUploadState(f *hfFile, upload lfsAction, chunkSize int64) *hfUploadState {
+	fresh := &hfUploadState{Oid: f.sha256, Upload: upload.Href, ChunkSize: chunkSize, Parts: map[int]string{}}
+	data, err := os.ReadFile(f.state)
+	if err != nil {
+		return fresh
+	}
+	var state hfUploadState
+	if json.Unmarshal(data, &state) != nil || state.Oid != fresh.Oid || state.Upload != fresh.Upload ||
+		state.ChunkSize != chunkSize || state.Parts == nil {
+		return fresh
+	}
+	return &state
+}
+
+// save records the state. It is best effort: a directory that cannot be
+// written only means an interrupted upload starts over.
+func (s *hfUploadState) save(path string) {
+	if path == "" {
+		return
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return
+	}
+	_ = os.Rename(tmp, path)
+}
+
+func (r *hfRepo) uploadChunks(ctx context.Context, file *os.File, f *hfFile, upload lfsAction, chunkSize int64) error {
+	type part struct {
+		PartNumber int    `json:"partNumber"`
+		ETag       string `json:"etag"`
+	}
+	// part URLs are keyed by their zero-padded part number
+	var numbers []int
+	for key := range upload.Header {
+		if n, err := strconv.Atoi(key); err == nil && n > 0 {
+			numbers = append(numbers, n)
+		}
+	}
+	sort.Ints(numbers)
+	if want := int((f.size + chunkSize - 1) / chunkSize); len(numbers) != want {
+		return fmt.Errorf("registry handed out %d part URL(s) for %s, expected %d", len(numbers), f.path, want)
+	}
+	state := loadUploadState(f, upload, chunkSize)
+	parts := make([]part, 0, len(numbers))
+	for i, n := range numbers {
+		if etag, ok := state.Parts[n]; ok {
+			parts = append(parts, part{PartNumber: n, ETag: etag})
+			continue
+		}
+		offset := int64(i) * chunkSize
+		size := min(chunkSize, f.size-offset)
+		partURL := upload.Header[fmt.Sprintf("%05d", n)]
+		if partURL == "" {
+			partURL = upload.Header[strconv.Itoa(n)]
+		}
+		etag, err := r.putChunk(ctx, partURL, io.NewSectionReader(file, offset, size), size)
+		if err != nil {
+			return fmt.Errorf("failed to upload part %d of %s: %w", n, f.path, err)
+		}
+		parts = append(parts, part{PartNumber: n, ETag: etag})
+		state.Parts[n] = etag
+		state.save(f.state)
+	}
+	body, err := json.Marshal(struct {
+		Oid   string `json:"oid"`
+		Parts []part `json:"parts"`
+	}{Oid: f.sha256, Parts: parts})
+	if err != nil {
+		return err
+	}
+	if err := r.do(ctx, http.MethodPost, upload.Href, "application/json", bytes.NewReader(body), nil); err != nil {
+		return fmt.Errorf("failed to complete upload of %s: %w", f.path, err)
+	}
+	if f.state != "" {
+		_ = os.Remove(f.state)
+	}
+	return nil
+}
+
+// put uploads a whole file to an LFS upload URL
+func (r *hfRepo) put(ctx context.Context, rawURL string, header map[string]string, body io.Reader, size int64) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, rawURL, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	// an upload URL on another host, e.g. object storage, is presigned
+	if target, err := url.Parse(rawURL); err == nil && strings.HasPrefix(r.base, target.Scheme+"://"+target.Host+"/") {
+		setPkgAuthHeaders(req)
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("upload failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("upload failed: server returned %s", resp.Status)
+	}
+	return nil
+}
+
+// putChunk uploads one part to its presigned URL and returns its ETag. The
+// URL carries its own credentials, so no auth headers are sent.
+func (r *hfRepo) putChunk(ctx context.Context, rawURL string, body io.Reader, size int64) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, rawURL, body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.ContentLength = size
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("server returned %s", resp.Status)
+	}
+	return resp.Header.Get("ETag"), nil
+}
+
+// commit records the files at the revision. Regular files are sent inline;
+// LFS files by their pointer.
+func (r *hfRepo) commit(ctx context.Context, files []*hfFile, summary string) (string, error) {
+	type op struct {
+		Key   string `json:"key"`
+		Value any    `json:"value"`
+	}
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	if err := enc.Encode(op{Key: "header", Value: map[string]string{"summary": summary, "description": ""}}); err != nil {
+		return "", err
+	}
+	for _, f := range files {
+		var line op
+		if f.lfs {
+			line = op{Key: "lfsFile", Value: map[string]any{
+				"path": f.path, "algo": "sha256", "oid": f.sha256, "size": f.size,
+			}}
+		} else {
+			content, err := os.ReadFile(f.local)
+			if err != nil {
+				return "", err
+			}
+			line = op{Key: "file", Value: map[string]string{
+				"path": f.path, "encoding": "base64", "content": base64.StdEncoding.EncodeToString(content),
+			}}
+		}
+		if err := enc.Encode(line); err != nil {
+			return "", err
+		}
+	}
+	var resp struct {
+		CommitOid string `json:"commitOid"`
+		CommitURL string `json:"commitUrl"`
+	}
+	if err := r.do(ctx, http.MethodPost, r.apiURL("commit"), "application/x-ndjson", &body, &resp); err != nil {
+		return "", fmt.Errorf("commit failed: %w", err)
+	}
+	return resp.CommitOid, nil
+}
+
+// hfSibling is a file listed in a revision
+type hfSibling struct {
+	Name string `json:"rfilename"`
+	Size int64  `json:"size,omitempty"`
+	LFS  *struct {
+		SHA256 string `json:"sha256"`
+		Size   int64  `json:"size"`
+	} `json:"lfs,omitempty"`
+}
+
+// revisionInfo lists the files of the revision and returns its commit sha
+func (r *hfRepo) revisionInfo(ctx context.Context) (string, []hfSibling, error) {
+	var info struct {
+		SHA      string      `json:"sha"`
+		Siblings []hfSibling `json:"siblings"`
+	}
+	rawURL := r.apiURL("revision") + "?blobs=true"
+	if err := r.do(ctx, http.MethodGet, rawURL, "", nil, &info); err != nil {
+		return "", nil, fmt.Errorf("failed to read revision %s of %s: %w", r.revision, r.name, err)
+	}
+	return info.SHA, info.Siblings, nil
+}
@@ -0,0 +1,69 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/harness/harness-cli/cmd/cmdutils"
+	"github.com/harness/harness-cli/util/common/printer"
+
+	"github.com/spf13/cobra"
+)
+
+// NewInspectArtifactCmd creates a new cobra.Command for inspecting artifacts
+func NewInspectArtifactCmd(c *cmdutils.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inspect",
+		Short: "Inspect artifacts in Harness Artifact Registry",
+		Long:  `Show the package-specific details of an artifact version without pulling it`,
+	}
+
+	// Add subcommands for different package types
+	cmd.AddCommand(NewInspectDockerCmd(c))
+	cmd.AddCommand(NewInspectHelmCmd(c))
+
+	return cmd
+}
+
+// inspectField is a row of a two-column table of an inspect command
+type inspectField struct {
+	Field string `json:"field"`
+	Value string `json:"value"`
+}
+
+// printInspectSection prints a titled table; empty sections are skipped
+func printInspectSection(title string, rows any, count int, mappings [][]string) error {
+	if count == 0 {
+		return nil
+	}
+	fmt.Println()
+	fmt.Println(title)
+	return printer.Print(rows, 0, 1, int64(count), false, mappings)
+}
+
+// printInspectSummary prints the non-empty fields as a two-column table
+func printInspectSummary(fields [][2]string) error {
+	rows := make([]inspectField, 0, len(fields))
+	for _, f := range fields {
+		if f[1] != "" {
+			rows = append(rows, inspectField{Field: f[0], Value: f[1]})
+		}
+	}
+	return printer.Print(rows, 0, 1, int64(len(rows)), false, [][]string{
+		{"field", "Field"},
+		{"value", "Value"},
+	})
+}
+
+// formatBytes renders a size with a binary unit, e.g. 3.2 MiB
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
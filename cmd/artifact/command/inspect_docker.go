@@ -0,0 +1,328 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/harness/harness-cli/cmd/cmdutils"
+	"github.com/harness/harness-cli/config"
+	"github.com/harness/harness-cli/internal/api/ar"
+	"github.com/harness/harness-cli/util/common/printer"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/spf13/cobra"
+)
+
+// dockerInspection describes a docker image version; the manifest fields are
+// set when a single platform is selected
+type dockerInspection struct {
+	Image          string           `json:"image"`
+	Version        string           `json:"version"`
+	Platforms      []dockerPlatform `json:"platforms"`
+	Digest         string           `json:"digest,omitempty"`
+	Platform       string           `json:"platform,omitempty"`
+	MediaType      string           `json:"mediaType,omitempty"`
+	CreatedAt      string           `json:"createdAt,omitempty"`
+	PullCommand    string           `json:"pullCommand,omitempty"`
+	CompressedSize int64            `json:"compressedSize,omitempty"`
+	Layers         []dockerLayer    `json:"layers,omitempty"`
+	History        []dockerHistory  `json:"history,omitempty"`
+}
+
+type dockerPlatform struct {
+	Platform  string `json:"platform"`
+	Digest    string `json:"digest"`
+	Size      string `json:"size,omitempty"`
+	CreatedAt string `json:"createdAt,omitempty"`
+}
+
+type dockerLayer struct {
+	Digest    string `json:"digest"`
+	MediaType string `json:"mediaType"`
+	Size      int64  `json:"size"`
+}
+
+type dockerHistory struct {
+	CreatedBy string `json:"createdBy"`
+	Size      string `json:"size,omitempty"`
+}
+
+// NewInspectDockerCmd creates a new cobra.Command for inspecting a docker
+// image version.
+// command example: hc artifact inspect docker <registry_name>/<image>:<tag> --platform linux/amd64
+//
+// For a multi-arch image the platforms of the index are listed; the layers,
+// history and compressed size are shown for the single platform of an image,
+// or the one selected with --platform.
+func NewInspectDockerCmd(c *cmdutils.Factory) *cobra.Command {
+	const expectedNumberOfArgument = 1
+	var platform string
+	cmd := &cobra.Command{
+		Use:   "docker <registry_name>/<image>[:<tag>|@<digest>]",
+		Short: "Inspect Docker Images",
+		Long: `Show the platforms of a docker image, and for one platform its manifest layers
+with digests and sizes, the build history and the total compressed size.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != expectedNumberOfArgument {
+				return fmt.Errorf(
+					"Error: Invalid number of argument,  accepts %d arg(s), received %d  \nUsage :\n %s",
+					expectedNumberOfArgument, len(args), cmd.UseLine(),
+				)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			registryName, image, version, digest, err := parseDockerVersionRef(args[0])
+			if err != nil {
+				return err
+			}
+			var want *v1.Platform
+			if platform != "" {
+				if want, err = v1.ParsePlatform(platform); err != nil {
+					return fmt.Errorf("invalid platform %q: %w", platform, err)
+				}
+			}
+
+			result, err := inspectDockerImage(cmd.Context(), c.RegistryHttpClient(), registryName, image, version,
+				digest, want)
+			if err != nil {
+				return err
+			}
+
+			if config.Global.Format == "json" {
+				options := printer.DefaultJsonOptions()
+				options.ShowPagination = false
+				return printer.PrintJsonWithOptions(result, options)
+			}
+			return printDockerInspection(result)
+		},
+	}
+
+	cmd.Flags().StringVar(&platform, "platform", "", "Platform (os/arch[/variant]) of a multi-arch image to inspect")
+
+	return cmd
+}
+
+// parseDockerVersionRef splits "<registry>/<image>[:<tag>|@<digest>]"; the
+// version is the tag, or the digest when no tag is given, and defaults to latest
+func parseDockerVersionRef(ref string) (registry, image, version, digest string, err error) {
+	registry, image, _ = strings.Cut(ref, "/")
+	if registry == "" || image == "" {
+		return "", "", "", "", fmt.Errorf("invalid image %q (expected format: <registry_name>/<image>:<tag>)", ref)
+	}
+	if i := strings.Index(image, "@"); i >= 0 {
+		image, digest = image[:i], image[i+1:]
+		if _, err := v1.NewHash(digest); err != nil {
+			return "", "", "", "", fmt.Errorf("invalid digest in %q: %w", ref, err)
+		}
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image, version = image[:i], image[i+1:]
+	}
+	switch {
+	case version != "":
+	case digest != "":
+		version = digest
+	default:
+		version = "latest"
+	}
+	if image == "" {
+		return "", "", "", "", fmt.Errorf("invalid image %q (expected format: <registry_name>/<image>:<tag>)", ref)
+	}
+	return registry, image, version, digest, nil
+}
+
+func inspectDockerImage(ctx context.Context, client *ar.ClientWithResponses, registryName, image, version,
+	digest string, want *v1.Platform) (*dockerInspection, error) {
+	registryRef := artifactRegistryRef(registryName)
+	result := &dockerInspection{Image: registryName + "/" + image, Version: version}
+
+	platforms, err := listDockerPlatforms(ctx, client, registryRef, image, version)
+	if err != nil {
+		return nil, err
+	}
+	result.Platforms = platforms
+
+	selected, err := selectDockerPlatform(result.Platforms, digest, want)
+	if err != nil || selected == nil {
+		return result, err
+	}
+	result.Digest, result.Platform = selected.Digest, selected.Platform
+
+	parsed, err := fetchDockerManifest(ctx, client, registryRef, image, version, selected.Digest)
+	if err != nil {
+		return nil, err
+	}
+	result.MediaType = string(parsed.MediaType)
+	result.CompressedSize = parsed.Config.Size
+	result.Layers = manifestLayers(parsed)
+	for _, l := range result.Layers {
+		result.CompressedSize += l.Size
+	}
+
+	layers, err := client.GetDockerArtifactLayersWithResponse(ctx, registryRef, image, version,
+		&ar.GetDockerArtifactLayersParams{Digest: selected.Digest})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch history: %w", err)
+	}
+	if layers.JSON200 == nil {
+		return nil, fmt.Errorf("failed to fetch history of %s: %s %s", selected.Digest, layers.Status(),
+			string(layers.Body))
+	}
+	if layers.JSON200.Data.Layers != nil {
+		for _, l := range *layers.JSON200.Data.Layers {
+			result.History = append(result.History, dockerHistory{CreatedBy: l.Command, Size: derefString(l.Size)})
+		}
+	}
+
+	details, err := client.GetDockerArtifactDetailsWithResponse(ctx, registryRef, image, version,
+		&ar.GetDockerArtifactDetailsParams{Digest: selected.Digest})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch image details: %w", err)
+	}
+	if details.JSON200 != nil {
+		result.CreatedAt = derefString(details.JSON200.Data.CreatedAt)
+		result.PullCommand = derefString(details.JSON200.Data.PullCommand)
+	}
+	return result, nil
+}
+
+// listDockerPlatforms lists the manifests of a docker image version, one per
+// platform for a multi-arch image
+func listDockerPlatforms(ctx context.Context, client *ar.ClientWithResponses, registryRef, image,
+	version string) ([]dockerPlatform, error) {
+	manifests, err := client.GetDockerArtifactManifestsWithResponse(ctx, registryRef, image, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list manifests: %w", err)
+	}
+	if manifests.JSON200 == nil {
+		return nil, fmt.Errorf("failed to list manifests of %s:%s: %s %s", image, version,
+			manifests.Status(), string(manifests.Body))
+	}
+	var platforms []dockerPlatform
+	if manifests.JSON200.Data.Manifests != nil {
+		for _, m := range *manifests.JSON200.Data.Manifests {
+			platforms = append(platforms, dockerPlatform{
+				Platform:  m.OsArch,
+				Digest:    m.Digest,
+				Size:      derefString(m.Size),
+				CreatedAt: derefString(m.CreatedAt),
+			})
+		}
+	}
+	return platforms, nil
+}
+
+// fetchDockerManifest fetches and parses the manifest of one platform of a
+// docker image version
+func fetchDockerManifest(ctx context.Context, client *ar.ClientWithResponses, registryRef, image, version,
+	digest string) (*v1.Manifest, error) {
+	manifest, err := client.GetDockerArtifactManifestWithResponse(ctx, registryRef, image, version,
+		&ar.GetDockerArtifactManifestParams{Digest: digest})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch manifest: %w", err)
+	}
+	if manifest.JSON200 == nil {
+		return nil, fmt.Errorf("failed to fetch manifest %s: %s %s", digest, manifest.Status(),
+			string(manifest.Body))
+	}
+	parsed, err := v1.ParseManifest(strings.NewReader(manifest.JSON200.Data.Manifest))
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", digest, err)
+	}
+	return parsed, nil
+}
+
+func manifestLayers(m *v1.Manifest) []dockerLayer {
+	layers := make([]dockerLayer, 0, len(m.Layers))
+	for _, l := range m.Layers {
+		layers = append(layers, dockerLayer{
+			Digest:    l.Digest.String(),
+			MediaType: string(l.MediaType),
+			Size:      l.Size,
+		})
+	}
+	return layers
+}
+
+// selectDockerPlatform picks the manifest to inspect: the one of the digest,
+// the one matching the wanted platform, or the only one. Nil means an index
+// whose platforms are listed without inspecting one.
+func selectDockerPlatform(platforms []dockerPlatform, digest string, want *v1.Platform) (*dockerPlatform, error) {
+	if len(platforms) == 0 {
+		return nil, fmt.Errorf("image has no manifests")
+	}
+	if want == nil {
+		for i := range platforms {
+			if digest != "" && platforms[i].Digest == digest {
+				return &platforms[i], nil
+			}
+		}
+		if len(platforms) == 1 {
+			return &platforms[0], nil
+		}
+		return nil, nil
+	}
+
+	var available []string
+	for i := range platforms {
+		available = append(available, platforms[i].Platform)
+		got, err := v1.ParsePlatform(platforms[i].Platform)
+		if err != nil {
+			continue
+		}
+		if got.OS == want.OS && got.Architecture == want.Architecture &&
+			(want.Variant == "" || got.Variant == want.Variant) {
+			return &platforms[i], nil
+		}
+	}
+	return nil, fmt.Errorf("image has no platform %s (available: %s)", want, strings.Join(available, ", "))
+}
+
+func printDockerInspection(result *dockerInspection) error {
+	fields := [][2]string{
+		{"Image", result.Image},
+		{"Version", result.Version},
+		{"Digest", result.Digest},
+		{"Platform", result.Platform},
+		{"Media Type", result.MediaType},
+		{"Created", result.CreatedAt},
+		{"Pull Command", result.PullCommand},
+	}
+	if result.Digest != "" {
+		fields = append(fields,
+			[2]string{"Layers", fmt.Sprint(len(result.Layers))},
+			[2]string{"Compressed Size", formatBytes(result.CompressedSize)})
+	}
+	if err := printInspectSummary(fields); err != nil {
+		return err
+	}
+	if err := printInspectSection("Platforms", result.Platforms, len(result.Platforms), [][]string{
+		{"platform", "Platform"},
+		{"digest", "Digest"},
+		{"size", "Size"},
+		{"createdAt", "Created"},
+	}); err != nil {
+		return err
+	}
+	if result.Digest == "" {
+		fmt.Println("\nUse --platform to inspect the layers of one platform")
+		return nil
+	}
+	// sizes are formatted here, the table would print large numbers in e-notation
+	layers := make([]inspectField, 0, len(result.Layers))
+	for _, l := range result.Layers {
+		layers = append(layers, inspectField{Field: l.Digest, Value: formatBytes(l.Size)})
+	}
+	if err := printInspectSection("Layers", layers, len(layers), [][]string{
+		{"field", "Digest"},
+		{"value", "Size"},
+	}); err != nil {
+		return err
+	}
+	return printInspectSection("History", result.History, len(result.History), [][]string{
+		{"createdBy", "Created By"},
+		{"size", "Size"},
+	})
+}
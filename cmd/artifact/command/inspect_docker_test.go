@@ -0,0 +1,133 @@
+package command
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/harness/harness-cli/internal/api/ar"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	amd64Digest = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	arm64Digest = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+)
+
+// dockerInspectServer serves the docker endpoints of reg/library/app:1.0, a
+// two-platform image
+func dockerInspectServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	withPullConfig(t)
+	manifest := `{
+		"schemaVersion": 2,
+		"mediaType": "application/vnd.oci.image.manifest.v1+json",
+		"config": {"mediaType": "application/vnd.oci.image.config.v1+json", "size": 1000,
+			"digest": "sha256:cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc"},
+		"layers": [
+			{"mediaType": "application/vnd.oci.image.layer.v1.tar+gzip", "size": 3000000,
+				"digest": "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"},
+			{"mediaType": "application/vnd.oci.image.layer.v1.tar+gzip", "size": 24,
+				"digest": "sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"}
+		]
+	}`
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const prefix = "/registry/acct/reg/+/artifact/library/app/+/version/1.0/docker/"
+		if !strings.HasPrefix(r.URL.Path, prefix) {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		digest := r.URL.Query().Get("digest")
+		w.Header().Set("Content-Type", "application/json")
+		var body any
+		switch strings.TrimPrefix(r.URL.Path, prefix) {
+		case "manifests":
+			body = ar.DockerManifestsResponse{Data: ar.DockerManifests{ImageName: "library/app", Version: "1.0",
+				Manifests: &[]ar.DockerManifestDetails{
+					{Digest: amd64Digest, OsArch: "linux/amd64"},
+					{Digest: arm64Digest, OsArch: "linux/arm64/v8"},
+				}}}
+		case "manifest":
+			assert.Equal(t, arm64Digest, digest)
+			body = ar.DockerArtifactManifestResponse{Data: ar.DockerArtifactManifest{Manifest: manifest}}
+		case "layers":
+			size := "2.9 MB"
+			body = ar.DockerLayersResponse{Data: ar.DockerLayersSummary{Digest: digest, Layers: &[]ar.DockerLayerEntry{
+				{Command: "ADD rootfs.tar.gz /", Size: &size},
+				{Command: `CMD ["/app"]`},
+			}}}
+		case "details":
+			pull := "docker pull pkg.harness.io/acct/reg/library/app:1.0"
+			body = ar.DockerArtifactDetailResponse{Data: ar.DockerArtifactDetail{ImageName: "library/app",
+				Version: "1.0", PullCommand: &pull}}
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestInspectDockerSelectsPlatform(t *testing.T) {
+	ts := dockerInspectServer(t)
+	client, err := ar.NewClientWithResponses(ts.URL)
+	require.NoError(t, err)
+
+	want, err := v1.ParsePlatform("linux/arm64")
+	require.NoError(t, err)
+	result, err := inspectDockerImage(t.Context(), client, "reg", "library/app", "1.0", "", want)
+	require.NoError(t, err)
+
+	assert.Equal(t, arm64Digest, result.Digest)
+	assert.Equal(t, "linux/arm64/v8", result.Platform)
+	assert.Len(t, result.Platforms, 2)
+	assert.Equal(t, int64(3001024), result.CompressedSize)
+	require.Len(t, result.Layers, 2)
+	assert.Equal(t, int64(3000000), result.Layers[0].Size)
+	assert.Equal(t, []dockerHistory{{CreatedBy: "ADD rootfs.tar.gz /", Size: "2.9 MB"}, {CreatedBy: `CMD ["/app"]`}},
+		result.History)
+	assert.Contains(t, result.PullCommand, "docker pull")
+
+	// the table output renders without error
+	require.NoError(t, printDockerInspection(result))
+}
+
+func TestInspectDockerIndexListsPlatforms(t *testing.T) {
+	ts := dockerInspectServer(t)
+	f := newPullTestFactory(t, ts)
+
+	// without --platform only the platforms of the index are listed
+	require.NoError(t, runCobraCmd(t, NewInspectDockerCmd(f), "reg/library/app:1.0"))
+
+	err := runCobraCmd(t, NewInspectDockerCmd(f), "reg/library/app:1.0", "--platform", "windows/amd64")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "available: linux/amd64, linux/arm64/v8")
+}
+
+func TestParseDockerVersionRef(t *testing.T) {
+	tests := []struct {
+		ref                              string
+		registry, image, version, digest string
+	}{
+		{"reg/app", "reg", "app", "latest", ""},
+		{"reg/library/app:1.0", "reg", "library/app", "1.0", ""},
+		{"reg/app@" + amd64Digest, "reg", "app", amd64Digest, amd64Digest},
+		{"reg/app:1.0@" + amd64Digest, "reg", "app", "1.0", amd64Digest},
+	}
+	for _, tt := range tests {
+		registry, image, version, digest, err := parseDockerVersionRef(tt.ref)
+		require.NoError(t, err, tt.ref)
+		assert.Equal(t, []string{tt.registry, tt.image, tt.version, tt.digest},
+			[]string{registry, image, version, digest}, tt.ref)
+	}
+
+	for _, ref := range []string{"app:1.0", "reg/", "reg/app@sha256:short"} {
+		_, _, _, _, err := parseDockerVersionRef(ref)
+		assert.Error(t, err, ref)
+	}
+}
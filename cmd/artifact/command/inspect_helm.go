@@ -0,0 +1,252 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/harness/harness-cli/cmd/cmdutils"
+	"github.com/harness/harness-cli/config"
+	"github.com/harness/harness-cli/module/ar/migrate/util"
+	"github.com/harness/harness-cli/util/common/printer"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+// maxChartConfigSize bounds the config blob read for the chart metadata
+const maxChartConfigSize = 4 << 20
+
+// helmInspection describes a helm chart version
+type helmInspection struct {
+	Chart        string              `json:"chart"`
+	Version      string              `json:"version"`
+	AppVersion   string              `json:"appVersion,omitempty"`
+	Description  string              `json:"description,omitempty"`
+	APIVersion   string              `json:"apiVersion,omitempty"`
+	Type         string              `json:"type,omitempty"`
+	KubeVersion  string              `json:"kubeVersion,omitempty"`
+	Dependencies []*chart.Dependency `json:"dependencies,omitempty"`
+	Maintainers  []*chart.Maintainer `json:"maintainers,omitempty"`
+	Digest       string              `json:"digest,omitempty"`
+	CreatedAt    string              `json:"createdAt,omitempty"`
+	PullCommand  string              `json:"pullCommand,omitempty"`
+	Provenance   bool                `json:"provenance"`
+	Layers       []dockerLayer       `json:"layers"`
+	// Note explains chart metadata missing from the chart config
+	Note string `json:"note,omitempty"`
+}
+
+// NewInspectHelmCmd creates a new cobra.Command for inspecting a helm chart
+// version.
+// command example: hc artifact inspect helm <registry_name>/<chart>:<version>
+//
+// The chart metadata is read from the config of the chart's OCI manifest, so
+// the chart archive itself is not downloaded.
+func NewInspectHelmCmd(c *cmdutils.Factory) *cobra.Command {
+	const expectedNumberOfArgument = 1
+	cmd := &cobra.Command{
+		Use:   "helm <registry_name>/<chart>:<version>",
+		Short: "Inspect Helm Charts",
+		Long: `Show the metadata of a helm chart version (appVersion, kubeVersion, dependencies
+and maintainers), its manifest layers and whether a provenance file is attached,
+without pulling the chart.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != expectedNumberOfArgument {
+				return fmt.Errorf(
+					"Error: Invalid number of argument,  accepts %d arg(s), received %d  \nUsage :\n %s",
+					expectedNumberOfArgument, len(args), cmd.UseLine(),
+				)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			registryName, chartName, ok := strings.Cut(args[0], "/")
+			i := strings.LastIndex(chartName, ":")
+			if !ok || registryName == "" || i <= 0 || i == len(chartName)-1 {
+				return fmt.Errorf("invalid chart %q (expected format: <registry_name>/<chart>:<version>)", args[0])
+			}
+			chartName, version := chartName[:i], chartName[i+1:]
+
+			result, err := inspectHelmChart(cmd.Context(), c, registryName, chartName, version)
+			if err != nil {
+				return err
+			}
+
+			if config.Global.Format == "json" {
+				options := printer.DefaultJsonOptions()
+				options.ShowPagination = false
+				return printer.PrintJsonWithOptions(result, options)
+			}
+			return printHelmInspection(result)
+		},
+	}
+
+	return cmd
+}
+
+func inspectHelmChart(ctx context.Context, c *cmdutils.Factory, registryName, chartName,
+	version string) (*helmInspection, error) {
+	client := c.RegistryHttpClient()
+	registryRef := artifactRegistryRef(registryName)
+	result := &helmInspection{Chart: chartName, Version: version, Layers: []dockerLayer{}}
+
+	details, err := client.GetHelmArtifactDetailsWithResponse(ctx, registryRef, chartName, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch chart details: %w", err)
+	}
+	if details.JSON200 == nil {
+		return nil, fmt.Errorf("failed to fetch chart %s/%s:%s: %s %s", registryName, chartName, version,
+			details.Status(), string(details.Body))
+	}
+	result.CreatedAt = derefString(details.JSON200.Data.CreatedAt)
+	result.PullCommand = derefString(details.JSON200.Data.PullCommand)
+
+	manifest, err := client.GetHelmArtifactManifestWithResponse(ctx, registryRef, chartName, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch chart manifest: %w", err)
+	}
+	if manifest.JSON200 == nil {
+		return nil, fmt.Errorf("failed to fetch manifest of %s:%s: %s %s", chartName, version, manifest.Status(),
+			string(manifest.Body))
+	}
+	parsed, err := v1.ParseManifest(strings.NewReader(manifest.JSON200.Data.Manifest))
+	if err != nil {
+		return nil, fmt.Errorf("invalid chart manifest: %w", err)
+	}
+	digest, _, err := v1.SHA256(strings.NewReader(manifest.JSON200.Data.Manifest))
+	if err != nil {
+		return nil, err
+	}
+	result.Digest = digest.String()
+	for _, l := range parsed.Layers {
+		result.Layers = append(result.Layers, dockerLayer{
+			Digest:    l.Digest.String(),
+			MediaType: string(l.MediaType),
+			Size:      l.Size,
+		})
+		if l.MediaType == util.HelmChartProvenanceMediaType {
+			result.Provenance = true
+		}
+	}
+
+	meta, err := fetchChartMetadata(ctx, c, registryName, chartName, parsed.Config.Digest)
+	if errors.Is(err, errNoChartMetadata) {
+		// charts written by the migration have an empty config, so only the
+		// manifest annotations describe them
+		meta = &chart.Metadata{
+			Name:        parsed.Annotations["org.opencontainers.image.title"],
+			Version:     parsed.Annotations["org.opencontainers.image.version"],
+			Description: parsed.Annotations["org.opencontainers.image.description"],
+		}
+		result.Note = "no chart metadata in the chart config, as for migrated charts; " +
+			"the description is read from the manifest annotations"
+	} else if err != nil {
+		return nil, err
+	}
+	result.AppVersion = meta.AppVersion
+	result.Description = meta.Description
+	result.APIVersion = meta.APIVersion
+	result.Type = meta.Type
+	result.KubeVersion = meta.KubeVersion
+	result.Dependencies = meta.Dependencies
+	result.Maintainers = meta.Maintainers
+	return result, nil
+}
+
+// fetchChartMetadata reads the config blob of a chart from the registry
+func fetchChartMetadata(ctx context.Context, c *cmdutils.Factory, registryName, chartName string,
+	configDigest v1.Hash) (*chart.Metadata, error) {
+	reg, err := resolveDockerRegistry(ctx, c, registryName)
+	if err != nil {
+		return nil, err
+	}
+	ref, err := reg.reference(chartName)
+	if err != nil {
+		return nil, err
+	}
+	blob, err := remote.Layer(ref.Context().Digest(configDigest.String()), reg.options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch chart config: %w", err)
+	}
+	rc, err := blob.Compressed()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch chart config: %w", err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, maxChartConfigSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chart config: %w", err)
+	}
+	return parseChartConfig(data)
+}
+
+// errNoChartMetadata is returned for a chart config that does not hold the
+// chart metadata, such as the empty config written by the migration
+var errNoChartMetadata = errors.New("chart config has no chart metadata")
+
+// parseChartConfig reads chart metadata from a chart config blob: the
+// Chart.yaml fields as JSON, as written by helm push
+func parseChartConfig(data []byte) (*chart.Metadata, error) {
+	var meta chart.Metadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("invalid chart config: %w", err)
+	}
+	if meta.Name == "" {
+		return nil, errNoChartMetadata
+	}
+	return &meta, nil
+}
+
+func printHelmInspection(result *helmInspection) error {
+	provenance := "no"
+	if result.Provenance {
+		provenance = "yes"
+	}
+	if err := printInspectSummary([][2]string{
+		{"Chart", result.Chart},
+		{"Version", result.Version},
+		{"App Version", result.AppVersion},
+		{"Description", result.Description},
+		{"API Version", result.APIVersion},
+		{"Type", result.Type},
+		{"Kube Version", result.KubeVersion},
+		{"Digest", result.Digest},
+		{"Created", result.CreatedAt},
+		{"Pull Command", result.PullCommand},
+		{"Provenance", provenance},
+	}); err != nil {
+		return err
+	}
+	if result.Note != "" {
+		fmt.Printf("\nNote: %s\n", result.Note)
+	}
+	if err := printInspectSection("Dependencies", result.Dependencies, len(result.Dependencies), [][]string{
+		{"name", "Name"},
+		{"version", "Version"},
+		{"repository", "Repository"},
+		{"condition", "Condition"},
+	}); err != nil {
+		return err
+	}
+	if err := printInspectSection("Maintainers", result.Maintainers, len(result.Maintainers), [][]string{
+		{"name", "Name"},
+		{"email", "Email"},
+		{"url", "URL"},
+	}); err != nil {
+		return err
+	}
+	layers := make([]inspectField, 0, len(result.Layers))
+	for _, l := range result.Layers {
+		layers = append(layers, inspectField{Field: l.MediaType, Value: formatBytes(l.Size)})
+	}
+	return printInspectSection("Layers", layers, len(layers), [][]string{
+		{"field", "Media Type"},
+		{"value", "Size"},
+	})
+}
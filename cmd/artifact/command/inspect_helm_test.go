@@ -0,0 +1,145 @@
+package command
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/harness/harness-cli/internal/api/ar"
+	"github.com/harness/harness-cli/module/ar/migrate/util"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+// helmInspectServer is a HAR helm registry "charts" holding img as
+// demo:0.1.0, serving the OCI distribution API and the helm endpoints
+func helmInspectServer(t *testing.T, img v1.Image) *httptest.Server {
+	t.Helper()
+	withPullConfig(t)
+	reg := registry.New(registry.Logger(log.New(io.Discard, "", 0)))
+	var ts *httptest.Server
+	var manifest []byte
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var body any
+		switch path := r.URL.Path; {
+		case strings.HasPrefix(path, "/v2/"):
+			reg.ServeHTTP(w, r)
+			return
+		case path == "/registry/acct/charts/+":
+			body = ar.RegistryResponse{Data: ar.Registry{Identifier: "charts", PackageType: ar.HELM,
+				Url: ts.URL + "/acct/charts"}}
+		case path == "/registry/acct/charts/+/artifact/demo/+/version/0.1.0/helm/details":
+			pull := "helm pull oci://pkg.harness.io/acct/charts/demo --version 0.1.0"
+			body = ar.HelmArtifactDetailResponse{Data: ar.HelmArtifactDetail{Version: "0.1.0", PullCommand: &pull}}
+		case path == "/registry/acct/charts/+/artifact/demo/+/version/0.1.0/helm/manifest":
+			body = ar.HelmArtifactManifestResponse{Data: ar.HelmArtifactManifest{Manifest: string(manifest)}}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"code":"404","message":"not found"}`)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(ts.Close)
+
+	require.NoError(t, remote.Write(mustRef(t, strings.TrimPrefix(ts.URL, "http://")+"/acct/charts/demo:0.1.0"), img))
+	var err error
+	manifest, err = img.RawManifest()
+	require.NoError(t, err)
+	return ts
+}
+
+// helmPushedChart is a chart as helm push writes it, with a provenance file
+func helmPushedChart(t *testing.T, meta *chart.Metadata) v1.Image {
+	t.Helper()
+	img, err := util.ChartImage(meta, []byte("chart archive"), []byte("-----BEGIN PGP SIGNED MESSAGE-----\n"))
+	require.NoError(t, err)
+	return img
+}
+
+func TestInspectHelmChart(t *testing.T) {
+	ts := helmInspectServer(t, helmPushedChart(t, &chart.Metadata{
+		APIVersion:  "v2",
+		Name:        "demo",
+		Version:     "0.1.0",
+		AppVersion:  "1.2.3",
+		KubeVersion: ">=1.27.0",
+		Maintainers: []*chart.Maintainer{{Name: "Platform", Email: "platform@example.com"}},
+		Dependencies: []*chart.Dependency{
+			{Name: "redis", Version: "19.x", Repository: "oci://registry-1.docker.io/bitnamicharts"},
+		},
+	}))
+	f := newPullTestFactory(t, ts)
+
+	result, err := inspectHelmChart(t.Context(), f, "charts", "demo", "0.1.0")
+	require.NoError(t, err)
+
+	assert.Equal(t, "1.2.3", result.AppVersion)
+	assert.Equal(t, ">=1.27.0", result.KubeVersion)
+	require.Len(t, result.Dependencies, 1)
+	assert.Equal(t, "redis", result.Dependencies[0].Name)
+	require.Len(t, result.Maintainers, 1)
+	assert.Equal(t, "platform@example.com", result.Maintainers[0].Email)
+	assert.True(t, result.Provenance)
+	require.Len(t, result.Layers, 2)
+	assert.Equal(t, util.HelmChartContentMediaType, result.Layers[0].MediaType)
+	assert.True(t, strings.HasPrefix(result.Digest, "sha256:"))
+	assert.Empty(t, result.Note)
+
+	require.NoError(t, printHelmInspection(result))
+}
+
+func TestInspectMigratedHelmChart(t *testing.T) {
+	img, err := util.MigratedChartImage(&chart.Metadata{Name: "demo", Version: "0.1.0",
+		Description: "A demo chart", AppVersion: "1.2.3"}, []byte("chart archive"))
+	require.NoError(t, err)
+	f := newPullTestFactory(t, helmInspectServer(t, img))
+
+	result, err := inspectHelmChart(t.Context(), f, "charts", "demo", "0.1.0")
+	require.NoError(t, err)
+
+	// the empty config holds no chart metadata, the manifest annotations do
+	assert.Equal(t, "A demo chart", result.Description)
+	assert.Empty(t, result.AppVersion)
+	assert.Contains(t, result.Note, "no chart metadata")
+	assert.False(t, result.Provenance)
+	require.Len(t, result.Layers, 1)
+
+	require.NoError(t, printHelmInspection(result))
+}
+
+func TestInspectHelmCmdErrors(t *testing.T) {
+	ts := helmInspectServer(t, helmPushedChart(t, &chart.Metadata{APIVersion: "v2", Name: "demo", Version: "0.1.0"}))
+	f := newPullTestFactory(t, ts)
+
+	err := runCobraCmd(t, NewInspectHelmCmd(f), "charts/demo")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected format: <registry_name>/<chart>:<version>")
+
+	err = runCobraCmd(t, NewInspectHelmCmd(f), "charts/demo:9.9.9")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestParseChartConfig(t *testing.T) {
+	// helm push stores Chart.yaml as the config
+	meta, err := parseChartConfig([]byte(`{"name":"demo","version":"0.1.0","appVersion":"2.0",
+		"dependencies":[{"name":"redis","repository":"https://charts.example.com"}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, "2.0", meta.AppVersion)
+	assert.Len(t, meta.Dependencies, 1)
+
+	// the migration writes an empty image config
+	_, err = parseChartConfig([]byte(`{"architecture":"","os":"","rootfs":{"type":"layers"},"config":{}}`))
+	assert.ErrorIs(t, err, errNoChartMetadata)
+}
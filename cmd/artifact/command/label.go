@@ -0,0 +1,244 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/harness/harness-cli/cmd/cmdutils"
+	"github.com/harness/harness-cli/internal/api/ar"
+	"github.com/harness/harness-cli/util/common/pagination"
+	"github.com/harness/harness-cli/util/common/printer"
+
+	"github.com/spf13/cobra"
+)
+
+// labelRow is a label in list output, split into key and value for labels
+// of the form key=value
+type labelRow struct {
+	Label string `json:"label"`
+	Key   string `json:"key"`
+	Value string `json:"value,omitempty"`
+}
+
+// NewLabelCmd wires up:
+//
+//	hc artifact label list|add|remove|set <registry>/<artifact>
+func NewLabelCmd(f *cmdutils.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "label",
+		Short: "Manage artifact labels",
+		Long: `Commands to manage the labels of artifacts in Harness Artifact Registry.
+
+Labels are free-form strings; labels of the form key=value (team=payments,
+eol=2026-01) are matched by key, so adding team=search replaces team=payments
+and removing team removes it.`,
+	}
+
+	cmd.AddCommand(NewLabelListCmd(f))
+	cmd.AddCommand(NewLabelAddCmd(f))
+	cmd.AddCommand(NewLabelRemoveCmd(f))
+	cmd.AddCommand(NewLabelSetCmd(f))
+
+	return cmd
+}
+
+// NewLabelListCmd lists the labels of an artifact, or every label used in a
+// registry when no artifact is given
+func NewLabelListCmd(c *cmdutils.Factory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list <registry>[/<artifact>]",
+		Short: "List labels of an artifact or a registry",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			registry, artifact, _ := strings.Cut(args[0], "/")
+			if registry == "" {
+				return fmt.Errorf("invalid reference %q, expected <registry>[/<artifact>]", args[0])
+			}
+
+			var labels []string
+			var err error
+			if artifact == "" {
+				labels, err = listRegistryLabels(ctx, c, registry)
+			} else {
+				labels, err = getArtifactLabels(ctx, c, registry, artifact)
+			}
+			if err != nil {
+				return err
+			}
+			return printLabels(labels)
+		},
+	}
+}
+
+// NewLabelAddCmd adds labels to an artifact, replacing labels with the same key
+func NewLabelAddCmd(c *cmdutils.Factory) *cobra.Command {
+	return &cobra.Command{
+		Use:     "add <registry>/<artifact> <label>...",
+		Short:   "Add labels to an artifact",
+		Example: "  hc artifact label add docker-prod/payments-api team=payments tier=1",
+		Args:    cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return changeArtifactLabels(cmd.Context(), c, args[0], func(current []string) []string {
+				return addLabels(current, args[1:])
+			})
+		},
+	}
+}
+
+// NewLabelRemoveCmd removes labels from an artifact by label or by key
+func NewLabelRemoveCmd(c *cmdutils.Factory) *cobra.Command {
+	return &cobra.Command{
+		Use:     "remove <registry>/<artifact> <label|key>...",
+		Short:   "Remove labels from an artifact",
+		Example: "  hc artifact label remove docker-prod/payments-api eol",
+		Args:    cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return changeArtifactLabels(cmd.Context(), c, args[0], func(current []string) []string {
+				return removeLabels(current, args[1:])
+			})
+		},
+	}
+}
+
+// NewLabelSetCmd replaces every label of an artifact
+func NewLabelSetCmd(c *cmdutils.Factory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <registry>/<artifact> [label]...",
+		Short: "Replace the labels of an artifact",
+		Long:  "Replaces the labels of an artifact with the given ones; without labels, every label is removed",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return changeArtifactLabels(cmd.Context(), c, args[0], func([]string) []string {
+				return addLabels(nil, args[1:])
+			})
+		},
+	}
+}
+
+// parseArtifactRef splits "<registry>/<artifact>"; the artifact may contain
+// slashes, as docker images do
+func parseArtifactRef(ref string) (string, string, error) {
+	registry, artifact, _ := strings.Cut(ref, "/")
+	if registry == "" || artifact == "" {
+		return "", "", fmt.Errorf("invalid artifact reference %q, expected <registry>/<artifact>", ref)
+	}
+	return registry, artifact, nil
+}
+
+// changeArtifactLabels applies change to the current labels of an artifact
+// and stores the result, printing the labels the artifact ends up with
+func changeArtifactLabels(ctx context.Context, c *cmdutils.Factory, ref string,
+	change func(current []string) []string) error {
+	registry, artifact, err := parseArtifactRef(ref)
+	if err != nil {
+		return err
+	}
+	current, err := getArtifactLabels(ctx, c, registry, artifact)
+	if err != nil {
+		return err
+	}
+	labels := change(current)
+	if slices.Equal(labels, current) {
+		return printLabels(current)
+	}
+
+	resp, err := c.RegistryHttpClient().UpdateArtifactLabelsWithResponse(ctx, artifactRegistryRef(registry), artifact,
+		ar.UpdateArtifactLabelsJSONRequestBody{Labels: labels})
+	if err != nil {
+		return fmt.Errorf("failed to update labels: %w", err)
+	}
+	if resp.JSON200 == nil {
+		return fmt.Errorf("failed to update labels of %s: %s %s", ref, resp.Status(), string(resp.Body))
+	}
+	if resp.JSON200.Data.Labels != nil {
+		labels = *resp.JSON200.Data.Labels
+	}
+	return printLabels(labels)
+}
+
+func getArtifactLabels(ctx context.Context, c *cmdutils.Factory, registry, artifact string) ([]string, error) {
+	resp, err := c.RegistryHttpClient().GetArtifactSummaryWithResponse(ctx, artifactRegistryRef(registry), artifact)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch artifact: %w", err)
+	}
+	if resp.JSON200 == nil {
+		return nil, fmt.Errorf("failed to fetch artifact %s/%s: %s %s", registry, artifact, resp.Status(),
+			string(resp.Body))
+	}
+	if resp.JSON200.Data.Labels == nil {
+		return nil, nil
+	}
+	return *resp.JSON200.Data.Labels, nil
+}
+
+// listRegistryLabels returns every label used by artifacts of a registry,
+// following pagination
+func listRegistryLabels(ctx context.Context, c *cmdutils.Factory, registry string) ([]string, error) {
+	size := int64(pagination.DefaultPageSize)
+	fetch := func(ctx context.Context, page int64) (pagination.Page[string], error) {
+		resp, err := c.RegistryHttpClient().ListArtifactLabelsWithResponse(ctx, artifactRegistryRef(registry),
+			&ar.ListArtifactLabelsParams{Page: &page, Size: &size})
+		if err != nil {
+			return pagination.Page[string]{}, fmt.Errorf("failed to list labels: %w", err)
+		}
+		if resp.JSON200 == nil {
+			return pagination.Page[string]{}, fmt.Errorf("failed to list labels of %s: %s %s", registry,
+				resp.Status(), string(resp.Body))
+		}
+		data := resp.JSON200.Data
+		return pagination.Page[string]{Items: data.Labels, PageCount: derefInt64(data.PageCount)}, nil
+	}
+	return pagination.Collect(ctx, fetch, pagination.Options{Concurrency: pagination.DefaultConcurrency})
+}
+
+func labelKey(label string) string {
+	key, _, _ := strings.Cut(label, "=")
+	return key
+}
+
+// addLabels appends labels to current, dropping duplicates and labels with
+// the key of an added key=value label
+func addLabels(current, add []string) []string {
+	labels := []string{}
+	for _, label := range current {
+		if !slices.ContainsFunc(add, func(a string) bool {
+			return a != label && strings.Contains(a, "=") && labelKey(a) == labelKey(label)
+		}) {
+			labels = append(labels, label)
+		}
+	}
+	for _, label := range add {
+		if label != "" && !slices.Contains(labels, label) {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// removeLabels drops the labels matching one of remove, either exactly or by
+// the key of a key=value label
+func removeLabels(current, remove []string) []string {
+	labels := []string{}
+	for _, label := range current {
+		if !slices.Contains(remove, label) && !slices.Contains(remove, labelKey(label)) {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+func printLabels(labels []string) error {
+	rows := make([]labelRow, 0, len(labels))
+	for _, label := range labels {
+		key, value, _ := strings.Cut(label, "=")
+		rows = append(rows, labelRow{Label: label, Key: key, Value: value})
+	}
+	return printer.Print(rows, 0, 1, int64(len(rows)), false, [][]string{
+		{"label", "Label"},
+		{"key", "Key"},
+		{"value", "Value"},
+	})
+}
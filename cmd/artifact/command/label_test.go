@@ -0,0 +1,123 @@
+package command
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/harness/harness-cli/internal/api/ar"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// labelServer holds the labels of artifacts of registry "reg"
+type labelServer struct {
+	mu      sync.Mutex
+	labels  map[string][]string
+	updates int
+	query   string
+}
+
+func newLabelServer(t *testing.T, labels map[string][]string) (*labelServer, *httptest.Server) {
+	t.Helper()
+	withPullConfig(t)
+	s := &labelServer{labels: labels}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		const prefix = "/registry/acct/reg/+/artifact/"
+		path := r.URL.Path
+		switch {
+		case r.Method == http.MethodGet && path == "/registry/acct/reg/+/artifacts/labels":
+			var all []string
+			for _, l := range s.labels {
+				all = append(all, l...)
+			}
+			_ = json.NewEncoder(w).Encode(ar.ListArtifactLabelResponse{Data: ar.ListArtifactLabel{Labels: all}})
+		case r.Method == http.MethodGet && path == "/registry/acct/reg/+/artifacts":
+			s.query = r.URL.RawQuery
+			_ = json.NewEncoder(w).Encode(ar.ListRegistryArtifactResponse{Data: ar.ListRegistryArtifact{
+				Artifacts: []ar.RegistryArtifactMetadata{{Name: "api", RegistryIdentifier: "reg"}},
+			}})
+		case r.Method == http.MethodGet && strings.HasPrefix(path, prefix):
+			name := strings.TrimSuffix(strings.TrimPrefix(path, prefix), "/+/summary")
+			labels, ok := s.labels[name]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = io.WriteString(w, `{"code":"404","message":"artifact not found"}`)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(ar.ArtifactSummaryResponse{Data: ar.ArtifactSummary{ImageName: name,
+				Labels: &labels}})
+		case r.Method == http.MethodPut && strings.HasPrefix(path, prefix):
+			name := strings.TrimSuffix(strings.TrimPrefix(path, prefix), "/+/labels")
+			var body ar.ArtifactLabelRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			s.labels[name] = body.Labels
+			s.updates++
+			_ = json.NewEncoder(w).Encode(ar.ArtifactLabelResponse{Data: ar.ArtifactSummary{ImageName: name,
+				Labels: &body.Labels}})
+		default:
+			t.Errorf("unexpected %s %s", r.Method, path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return s, ts
+}
+
+func TestLabelAddReplacesSameKey(t *testing.T) {
+	s, ts := newLabelServer(t, map[string][]string{"api": {"team=payments", "tier=2", "legacy"}})
+	f := newPullTestFactory(t, ts)
+
+	require.NoError(t, runCobraCmd(t, NewLabelCmd(f), "add", "reg/api", "tier=1", "eol=2027-01"))
+
+	assert.Equal(t, []string{"team=payments", "legacy", "tier=1", "eol=2027-01"}, s.labels["api"])
+
+	// adding labels the artifact already has is a no-op
+	require.NoError(t, runCobraCmd(t, NewLabelCmd(f), "add", "reg/api", "legacy", "tier=1"))
+	assert.Equal(t, 1, s.updates)
+}
+
+func TestLabelRemoveByKeyAndSet(t *testing.T) {
+	s, ts := newLabelServer(t, map[string][]string{"org/api": {"team=payments", "eol=2027-01", "legacy"}})
+	f := newPullTestFactory(t, ts)
+
+	require.NoError(t, runCobraCmd(t, NewLabelCmd(f), "remove", "reg/org/api", "eol", "legacy"))
+	assert.Equal(t, []string{"team=payments"}, s.labels["org/api"])
+
+	require.NoError(t, runCobraCmd(t, NewLabelCmd(f), "set", "reg/org/api"))
+	assert.Equal(t, []string{}, s.labels["org/api"])
+}
+
+func TestLabelErrors(t *testing.T) {
+	_, ts := newLabelServer(t, map[string][]string{})
+	f := newPullTestFactory(t, ts)
+
+	err := runCobraCmd(t, NewLabelCmd(f), "add", "reg", "team=payments")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected <registry>/<artifact>")
+
+	err = runCobraCmd(t, NewLabelCmd(f), "add", "reg/missing", "team=payments")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "artifact not found")
+}
+
+func TestListArtifactsByLabel(t *testing.T) {
+	s, ts := newLabelServer(t, map[string][]string{})
+	f := newPullTestFactory(t, ts)
+
+	err := runCobraCmd(t, NewListArtifactCmd(f), "--label", "team=payments")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--label requires --registry")
+
+	require.NoError(t, runCobraCmd(t, NewListArtifactCmd(f), "--registry", "reg", "--label", "team=payments",
+		"--label", "tier=1"))
+	assert.Contains(t, s.query, "label=team%3Dpayments&label=tier%3D1")
+}
@@ -7,24 +7,80 @@ import (
 	"github.com/harness/harness-cli/cmd/cmdutils"
 	client "github.com/harness/harness-cli/internal/api/ar"
 	client2 "github.com/harness/harness-cli/util/client"
+	"github.com/harness/harness-cli/util/common/pagination"
 	"github.com/harness/harness-cli/util/common/printer"
 
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
 
+var artifactListColumns = [][]string{
+	{"name", "Artifact"},
+	{"version", "Version"},
+	{"artifactKey", "Artifact Key"},
+	{"packageType", "Package Type"},
+	{"registryIdentifier", "Registry"},
+	{"downloadsCount", "Download Count"},
+}
+
+var registryArtifactListColumns = [][]string{
+	{"name", "Artifact"},
+	{"latestVersion", "Latest Version"},
+	{"packageType", "Package Type"},
+	{"registryIdentifier", "Registry"},
+	{"labels", "Labels"},
+	{"downloadsCount", "Download Count"},
+}
+
 // NewListArtifactCmd wires up:
 //
 //	hc artifact list
 func NewListArtifactCmd(c *cmdutils.Factory) *cobra.Command {
 	var registry string
+	var labels []string
 	var pageSize int32
 	var pageIndex int32
+	var all bool
+	var limit, concurrency int
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all artifacts",
-		Long:  "Lists all artifacts in the Harness Artifact Registry",
+		Long: `Lists all artifacts in the Harness Artifact Registry
+
+With --all every page is fetched, a few pages at a time, and printed as a
+single table or JSON document; with --format ndjson each artifact is printed
+as a JSON line as soon as its page is fetched. --limit caps the number of
+artifacts and implies --all.`,
+		Example: `  hc artifact list --registry docker-prod --page-size 20 --page 1
+  hc artifact list --all --format ndjson | jq -r .name
+  hc artifact list --registry docker-prod --label team=payments --all --limit 500`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(labels) > 0 && registry == "" {
+				return fmt.Errorf("--label requires --registry")
+			}
+			if limit > 0 {
+				all = true
+			}
+			if all {
+				if cmd.Flags().Changed("page") {
+					return fmt.Errorf("--page cannot be used with --all or --limit")
+				}
+				size := int64(pagination.DefaultPageSize)
+				if cmd.Flags().Changed("page-size") {
+					size = int64(pageSize)
+				}
+				opts := pagination.Options{Concurrency: concurrency, Limit: limit}
+				if len(labels) > 0 {
+					return pagination.Print(cmd.Context(), registryArtifactPages(c, registry, labels, size), opts,
+						registryArtifactListColumns)
+				}
+				return pagination.Print(cmd.Context(), artifactPages(c, registry, size), opts, artifactListColumns)
+			}
+
+			if len(labels) > 0 {
+				return listArtifactsByLabel(c, registry, labels, pageSize, pageIndex)
+			}
+
 			params := client.GetAllHarnessArtifactsParams{}
 			if len(registry) > 0 {
 				params.RegIdentifier = &[]string{registry}
@@ -52,22 +108,105 @@ func NewListArtifactCmd(c *cmdutils.Factory) *cobra.Command {
 			}
 
 			err = printer.Print(response.JSON200.Data.Artifacts, *response.JSON200.Data.PageIndex,
-				*response.JSON200.Data.PageCount, *response.JSON200.Data.ItemCount, true, [][]string{
-					{"name", "Artifact"},
-					{"version", "Version"},
-					{"artifactKey", "Artifact Key"},
-					{"packageType", "Package Type"},
-					{"registryIdentifier", "Registry"},
-					{"downloadsCount", "Download Count"},
-				})
+				*response.JSON200.Data.PageCount, *response.JSON200.Data.ItemCount, true, artifactListColumns)
 
 			return err
 		},
 	}
 
 	cmd.Flags().StringVar(&registry, "registry", "", "name of the registry")
+	cmd.Flags().StringSliceVar(&labels, "label", nil, "only list artifacts with the label, repeatable (requires --registry)")
 	cmd.Flags().Int32Var(&pageSize, "page-size", 10, "number of items per page")
 	cmd.Flags().Int32Var(&pageIndex, "page", 0, "page number (zero-indexed)")
+	cmd.Flags().BoolVar(&all, "all", false, "fetch every page")
+	cmd.Flags().IntVar(&limit, "limit", 0, "maximum number of artifacts to list (implies --all)")
+	cmd.Flags().IntVar(&concurrency, "concurrency", pagination.DefaultConcurrency,
+		"maximum number of pages fetched at once with --all")
 
 	return cmd
 }
+
+// artifactPages fetches the pages of the artifacts of every registry, or of
+// one registry
+func artifactPages(c *cmdutils.Factory, registry string, size int64) pagination.Fetcher[client.ArtifactMetadata] {
+	return func(ctx context.Context, page int64) (pagination.Page[client.ArtifactMetadata], error) {
+		params := client.GetAllHarnessArtifactsParams{Page: &page, Size: &size}
+		if len(registry) > 0 {
+			params.RegIdentifier = &[]string{registry}
+		}
+		response, err := c.RegistryHttpClient().GetAllHarnessArtifactsWithResponse(ctx, client2.GetScopeRef(), &params)
+		if err != nil {
+			return pagination.Page[client.ArtifactMetadata]{}, err
+		}
+		if response.JSON200 == nil {
+			log.Debug().Msgf("Unable to list artifacts: %s", string(response.Body))
+			return pagination.Page[client.ArtifactMetadata]{}, fmt.Errorf("unable to list artifacts: %s",
+				response.Status())
+		}
+		data := response.JSON200.Data
+		return pagination.Page[client.ArtifactMetadata]{Items: data.Artifacts, PageCount: derefInt64(data.PageCount)},
+			nil
+	}
+}
+
+// registryArtifactPages fetches the pages of the artifacts of a registry
+// carrying the labels
+func registryArtifactPages(c *cmdutils.Factory, registry string, labels []string,
+	size int64) pagination.Fetcher[client.RegistryArtifactMetadata] {
+	return func(ctx context.Context, page int64) (pagination.Page[client.RegistryArtifactMetadata], error) {
+		params := client.GetAllArtifactsByRegistryParams{Label: &labels, Page: &page, Size: &size}
+		response, err := c.RegistryHttpClient().GetAllArtifactsByRegistryWithResponse(ctx,
+			client2.GetRef(client2.GetScopeRef(), registry), &params)
+		if err != nil {
+			return pagination.Page[client.RegistryArtifactMetadata]{}, err
+		}
+		if response.JSON200 == nil {
+			log.Debug().Msgf("Unable to list artifacts: %s", string(response.Body))
+			return pagination.Page[client.RegistryArtifactMetadata]{},
+				fmt.Errorf("unable to list artifacts of registry %s: %s", registry, response.Status())
+		}
+		data := response.JSON200.Data
+		return pagination.Page[client.RegistryArtifactMetadata]{Items: data.Artifacts,
+			PageCount: derefInt64(data.PageCount)}, nil
+	}
+}
+
+// listArtifactsByLabel lists the artifacts of a registry carrying the labels;
+// the label filter is only available on the registry-scoped artifact list
+func listArtifactsByLabel(c *cmdutils.Factory, registry string, labels []string, pageSize, pageIndex int32) error {
+	if registry == "" {
+		return fmt.Errorf("--label requires --registry")
+	}
+	params := client.GetAllArtifactsByRegistryParams{Label: &labels}
+	if pageSize > 0 {
+		size := int64(pageSize)
+		params.Size = &size
+	}
+	if pageIndex > 0 {
+		page := int64(pageIndex)
+		params.Page = &page
+	}
+
+	response, err := c.RegistryHttpClient().GetAllArtifactsByRegistryWithResponse(context.Background(),
+		client2.GetRef(client2.GetScopeRef(), registry), &params)
+	if err != nil {
+		return err
+	}
+	if response.JSON200 == nil {
+		log.Debug().Msgf("Unable to list artifacts: %s", string(response.Body))
+		return fmt.Errorf("unable to list artifacts of registry %s: %s", registry, response.Status())
+	}
+
+	data := response.JSON200.Data
+	var pageIdx, pageCount, itemCount int64
+	if data.PageIndex != nil {
+		pageIdx = *data.PageIndex
+	}
+	if data.PageCount != nil {
+		pageCount = *data.PageCount
+	}
+	if data.ItemCount != nil {
+		itemCount = *data.ItemCount
+	}
+	return printer.Print(data.Artifacts, pageIdx, pageCount, itemCount, true, registryArtifactListColumns)
+}
@@ -0,0 +1,78 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/harness/harness-cli/cmd/cmdutils"
+	"github.com/harness/harness-cli/config"
+	"github.com/harness/harness-cli/internal/api/ar"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runListCmd executes the artifact list command with the output format and
+// returns what it printed
+func runListCmd(t *testing.T, f *cmdutils.Factory, format string, args ...string) (string, error) {
+	t.Helper()
+	origFormat := config.Global.Format
+	config.Global.Format = format
+	t.Cleanup(func() { config.Global.Format = origFormat })
+
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	origStdout := os.Stdout
+	os.Stdout = w
+	doneCh := make(chan string, 1)
+	go func() {
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, r)
+		doneCh <- buf.String()
+	}()
+
+	runErr := runCobraCmd(t, NewListArtifactCmd(f), args...)
+
+	_ = w.Close()
+	os.Stdout = origStdout
+	return <-doneCh, runErr
+}
+
+func TestListAllStreamsEveryPage(t *testing.T) {
+	var artifacts []ar.ArtifactMetadata
+	for i := 0; i < 25; i++ {
+		artifacts = append(artifacts, searchArtifact(fmt.Sprintf("app-%02d", i), "1.0", "GENERIC", "reg", 0))
+	}
+	var queries []url.Values
+	client := searchServer(t, artifacts, &queries)
+	f := &cmdutils.Factory{RegistryHttpClient: func() *ar.ClientWithResponses { return client }}
+
+	out, err := runListCmd(t, f, "ndjson", "--all", "--page-size", "10")
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 25)
+	for i, line := range lines {
+		var a ar.ArtifactMetadata
+		require.NoError(t, json.Unmarshal([]byte(line), &a))
+		assert.Equal(t, fmt.Sprintf("app-%02d", i), a.Name)
+	}
+	assert.Len(t, queries, 3)
+
+	// --limit implies --all and merges the pages into one JSON document
+	queries = nil
+	out, err = runListCmd(t, f, "json", "--limit", "12", "--page-size", "10")
+	require.NoError(t, err)
+	var merged []ar.ArtifactMetadata
+	require.NoError(t, json.Unmarshal([]byte(out), &merged))
+	assert.Len(t, merged, 12)
+	assert.Equal(t, "app-11", merged[11].Name)
+
+	_, err = runListCmd(t, f, "json", "--all", "--page", "2")
+	require.ErrorContains(t, err, "--page cannot be used with --all")
+}
@@ -16,6 +16,7 @@ func NewMetadataCmd(f *cmdutils.Factory) *cobra.Command {
 	cmd.AddCommand(NewMetadataGetCmd(f))
 	cmd.AddCommand(NewMetadataSetCmd(f))
 	cmd.AddCommand(NewMetadataDeleteCmd(f))
+	cmd.AddCommand(NewMetadataApplyCmd(f))
 
 	return cmd
 }
@@ -0,0 +1,370 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/harness/harness-cli/cmd/cmdutils"
+	"github.com/harness/harness-cli/config"
+	"github.com/harness/harness-cli/internal/api/ar_v2"
+	"github.com/harness/harness-cli/internal/api/ar_v3"
+	"github.com/harness/harness-cli/util/common/printer"
+	p "github.com/harness/harness-cli/util/common/progress"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	gatePassed = "passed"
+	gateFailed = "failed"
+)
+
+// promotionPolicy is the file read by promote --policy. Example:
+//
+//	targets: [docker-prod]       # registries the version may be promoted to
+//	scan:
+//	  block: [BLOCKED, WARN]     # scan statuses that stop the promotion
+//	  requireScan: false         # whether a version without a scan may be promoted
+//	allowQuarantined: false      # whether a quarantined version may be promoted
+//	metadata:                    # metadata the version must carry
+//	  qa: passed
+//	  change-ticket: "*"         # any value
+type promotionPolicy struct {
+	Targets []string `yaml:"targets"`
+	Scan    struct {
+		Block       []string `yaml:"block"`
+		RequireScan bool     `yaml:"requireScan"`
+	} `yaml:"scan"`
+	AllowQuarantined bool              `yaml:"allowQuarantined"`
+	Metadata         map[string]string `yaml:"metadata"`
+}
+
+// defaultPromotionPolicy blocks versions whose scan failed a policy set or
+// that were never scanned, and quarantined versions
+func defaultPromotionPolicy() *promotionPolicy {
+	policy := &promotionPolicy{}
+	policy.Scan.Block = []string{string(ar_v3.ArtifactScanScanStatusBLOCKED)}
+	policy.Scan.RequireScan = true
+	return policy
+}
+
+// gateResult is the outcome of one gate of a promotion policy
+type gateResult struct {
+	Gate   string `json:"gate"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// NewPromoteArtifactCmd creates a new cobra.Command for promoting a package
+// version to another registry.
+// command example: hc artifact promote <registry>/<package>/<version> --to <registry> --policy promote.yaml
+//
+// The version is only copied once every gate of the policy passes; the copy
+// is then stamped with promoted_from, promoted_by and promoted_at metadata.
+func NewPromoteArtifactCmd(c *cmdutils.Factory) *cobra.Command {
+	const expectedArgumentCount = 1
+	var target, policyFile, promotedBy string
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "promote <SRC_REGISTRY>/<PACKAGE_NAME>/<VERSION> --to <DEST_REGISTRY>",
+		Short: "Promote a package version to another registry after checking policy gates",
+		Long: `Promote a package version to another registry. The version is checked against the
+gates of a policy file first: the target registry must be allowed, its scan
+status must not be blocked, it must not be quarantined by the firewall and it
+must carry the required metadata. Only when every gate passes is the version
+copied; the copy is then stamped with promoted_from, promoted_by and
+promoted_at metadata. Without a policy, versions whose scan is BLOCKED,
+versions without a scan and quarantined versions are refused.
+
+Policy file:
+  targets: [docker-prod]
+  scan:
+    block: [BLOCKED, WARN]
+    requireScan: true     # set to false to promote versions without a scan
+  allowQuarantined: false
+  metadata:
+    qa: passed
+    change-ticket: "*"`,
+		Example: `  hc artifact promote docker-stage/payments-api/1.5.0 --to docker-prod --policy promote.yaml
+  hc artifact promote docker-stage/payments-api/1.5.0 --to docker-prod --policy promote.yaml --dry-run`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != expectedArgumentCount {
+				return fmt.Errorf(
+					"Error: Invalid number of argument,  accepts %d arg(s), received %d  \nUsage :\n %s",
+					expectedArgumentCount, len(args), cmd.UseLine(),
+				)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			progress := p.NewConsoleReporter()
+
+			parts, err := parsePackagePath(args[0])
+			if err != nil {
+				return err
+			}
+			src := versionRef{Registry: parts[0], Artifact: parts[1], Version: parts[2]}
+			policy := defaultPromotionPolicy()
+			if policyFile != "" {
+				if policy, err = loadPromotionPolicy(policyFile); err != nil {
+					return err
+				}
+			}
+			if promotedBy == "" {
+				promotedBy = defaultPromotedBy()
+			}
+
+			progress.Start(fmt.Sprintf("Checking promotion gates of %s", src))
+			gates, err := checkPromotionGates(ctx, c, src, target, policy)
+			if err != nil {
+				progress.Error("Failed to check promotion gates")
+				return err
+			}
+			failed := 0
+			for _, g := range gates {
+				if g.Status == gateFailed {
+					failed++
+				}
+			}
+			if failed > 0 {
+				progress.Error(fmt.Sprintf("%d of %d gate(s) failed", failed, len(gates)))
+			} else {
+				progress.Success(fmt.Sprintf("All %d gate(s) passed", len(gates)))
+			}
+			if err := printer.Print(gates, 0, 1, int64(len(gates)), false, [][]string{
+				{"gate", "Gate"},
+				{"status", "Status"},
+				{"detail", "Detail"},
+			}); err != nil {
+				return err
+			}
+			if failed > 0 {
+				return fmt.Errorf("promotion of %s to %s blocked: %d gate(s) failed", src, target, failed)
+			}
+			if dryRun {
+				return nil
+			}
+
+			progress.Step(fmt.Sprintf("Copying %s to %s", src, target))
+			if err := copyRegistryPackage(ctx, c, &ar_v2.CopyRegistryPackageParams{
+				AccountIdentifier:        config.Global.AccountID,
+				SrcRegistryIdentifier:    src.Registry,
+				TargetRegistryIdentifier: target,
+				SrcArtifact:              src.Artifact,
+				SrcVersion:               src.Version,
+			}); err != nil {
+				progress.Error("Copy failed")
+				return err
+			}
+
+			if err := updateArtifactMetadata(ctx, c, target, src.Artifact, src.Version, map[string]string{
+				"promoted_from": src.Registry,
+				"promoted_by":   promotedBy,
+				"promoted_at":   time.Now().UTC().Format(time.RFC3339),
+			}); err != nil {
+				progress.Error("Failed to stamp promotion metadata")
+				return fmt.Errorf("copied, but setting promotion metadata failed: %w", err)
+			}
+			progress.Success(fmt.Sprintf("Promoted %s to %s", src, target))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&target, "to", "", "Registry to promote the version to (required)")
+	cmd.Flags().StringVar(&policyFile, "policy", "", "Promotion policy file (YAML) with the gates to check")
+	cmd.Flags().StringVar(&promotedBy, "promoted-by", "",
+		"Value of the promoted_by metadata (default: the pipeline execution, or the current user)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Check the gates without promoting")
+	cmd.MarkFlagRequired("to")
+
+	return cmd
+}
+
+// loadPromotionPolicy reads a policy file, rejecting unknown fields so a
+// misspelled gate is not silently skipped
+func loadPromotionPolicy(file string) (*promotionPolicy, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy: %w", err)
+	}
+	dec := yaml.NewDecoder(strings.NewReader(string(data)))
+	dec.KnownFields(true)
+	policy := defaultPromotionPolicy()
+	if err := dec.Decode(policy); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse policy %s: %w", file, err)
+	}
+	for i, status := range policy.Scan.Block {
+		status = strings.ToUpper(status)
+		switch ar_v3.ArtifactScanScanStatus(status) {
+		case ar_v3.ArtifactScanScanStatusBLOCKED, ar_v3.ArtifactScanScanStatusWARN:
+			policy.Scan.Block[i] = status
+		default:
+			return nil, fmt.Errorf("invalid scan status %q in policy %s (expected BLOCKED or WARN)", status, file)
+		}
+	}
+	return policy, nil
+}
+
+// defaultPromotedBy names the pipeline execution running the promotion, or
+// the current user outside of a pipeline
+func defaultPromotedBy() string {
+	if pipeline, execution := os.Getenv("HARNESS_PIPELINE_ID"), os.Getenv("HARNESS_EXECUTION_ID"); pipeline != "" &&
+		execution != "" {
+		return "pipeline:" + pipeline + "/" + execution
+	}
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return "unknown"
+}
+
+// checkPromotionGates evaluates every gate of the policy; a failed gate is a
+// result, while an error means a gate could not be evaluated
+func checkPromotionGates(ctx context.Context, c *cmdutils.Factory, src versionRef, target string,
+	policy *promotionPolicy) ([]gateResult, error) {
+	var gates []gateResult
+	gate := func(name string, passed bool, detail string) {
+		status := gatePassed
+		if !passed {
+			status = gateFailed
+		}
+		gates = append(gates, gateResult{Gate: name, Status: status, Detail: detail})
+	}
+
+	if target == src.Registry {
+		return nil, fmt.Errorf("%s is already in registry %s", src, target)
+	}
+	if len(policy.Targets) > 0 {
+		gate("target", slices.Contains(policy.Targets, target),
+			fmt.Sprintf("%s (allowed: %s)", target, strings.Join(policy.Targets, ", ")))
+	}
+
+	summary, err := getVersionSummary(ctx, c.RegistryHttpClient(), src)
+	if err != nil {
+		return nil, err
+	}
+	quarantined := summary.IsQuarantined != nil && *summary.IsQuarantined
+	detail := "not quarantined"
+	if quarantined {
+		detail = "quarantined"
+		if reason := derefString(summary.QuarantineReason); reason != "" {
+			detail += ": " + reason
+		}
+	}
+	gate("firewall", !quarantined || policy.AllowQuarantined, detail)
+
+	if len(policy.Scan.Block) > 0 || policy.Scan.RequireScan {
+		status, err := versionScanStatus(ctx, c.RegistryV3HttpClient(), summary.RegistryUUID, src)
+		if err != nil {
+			return nil, err
+		}
+		if status == "" {
+			// an unscanned version only passes when the policy allows it
+			gate("scan", !policy.Scan.RequireScan, "no scan found")
+		} else {
+			gate("scan", !slices.Contains(policy.Scan.Block, status),
+				fmt.Sprintf("scan status %s (blocking: %s)", status, strings.Join(policy.Scan.Block, ", ")))
+		}
+	}
+
+	if len(policy.Metadata) > 0 {
+		metadata, err := versionMetadata(ctx, c.RegistryV2HttpClient(), src)
+		if err != nil {
+			return nil, err
+		}
+		keys := make([]string, 0, len(policy.Metadata))
+		for k := range policy.Metadata {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			want := policy.Metadata[key]
+			got, ok := metadata[key]
+			switch {
+			case !ok:
+				gate("metadata "+key, false, "not set")
+			case want == "*" || got == want:
+				gate("metadata "+key, true, key+"="+got)
+			default:
+				gate("metadata "+key, false, fmt.Sprintf("%s=%s, expected %s", key, got, want))
+			}
+		}
+	}
+	return gates, nil
+}
+
+// versionScanStatus returns the status of the most recently evaluated scan of
+// a version, or "" when it has none
+func versionScanStatus(ctx context.Context, client *ar_v3.ClientWithResponses, registryUUID string,
+	v versionRef) (string, error) {
+	registryID, err := uuid.Parse(registryUUID)
+	if err != nil {
+		return "", fmt.Errorf("invalid registry UUID %q: %w", registryUUID, err)
+	}
+	page, size := int64(0), int64(100)
+	params := &ar_v3.GetArtifactScansParams{
+		AccountIdentifier: config.Global.AccountID,
+		RegistryId:        &ar_v3.RegistryIdListParam{registryID},
+		SearchTerm:        &v.Artifact,
+		Page:              &page,
+		Size:              &size,
+	}
+	if config.Global.OrgID != "" {
+		params.OrgIdentifier = &config.Global.OrgID
+	}
+	if config.Global.ProjectID != "" {
+		params.ProjectIdentifier = &config.Global.ProjectID
+	}
+	// the listing order is not a time order, so every scan of the version is
+	// collected and the latest evaluation wins
+	var scans []ar_v3.ArtifactScan
+	for {
+		resp, err := client.GetArtifactScansWithResponse(ctx, params)
+		if err != nil {
+			return "", fmt.Errorf("failed to list scans: %w", err)
+		}
+		if resp.JSON200 == nil {
+			return "", fmt.Errorf("failed to list scans of %s: %s %s", v, resp.Status(), string(resp.Body))
+		}
+		for _, scan := range resp.JSON200.Data {
+			if scan.PackageName == v.Artifact && scan.Version == v.Version {
+				scans = append(scans, scan)
+			}
+		}
+		if len(resp.JSON200.Data) < int(size) || resp.JSON200.PageIndex+1 >= resp.JSON200.PageCount {
+			break
+		}
+		page++
+	}
+	if len(scans) == 0 {
+		return "", nil
+	}
+	latest := slices.MaxFunc(scans, func(a, b ar_v3.ArtifactScan) int {
+		return scanEvaluatedAt(a).Compare(scanEvaluatedAt(b))
+	})
+	return string(latest.ScanStatus), nil
+}
+
+// scanEvaluatedAt parses the evaluation time of a scan, sent as epoch
+// milliseconds or RFC 3339; a scan without one sorts first
+func scanEvaluatedAt(scan ar_v3.ArtifactScan) time.Time {
+	at := derefString(scan.LastEvaluatedAt)
+	if ms, err := strconv.ParseInt(at, 10, 64); err == nil {
+		return time.UnixMilli(ms)
+	}
+	if t, err := time.Parse(time.RFC3339, at); err == nil {
+		return t
+	}
+	return time.Time{}
+}
@@ -0,0 +1,197 @@
+package command
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/harness/harness-cli/cmd/cmdutils"
+	"github.com/harness/harness-cli/internal/api/ar"
+	"github.com/harness/harness-cli/internal/api/ar_v2"
+	"github.com/harness/harness-cli/internal/api/ar_v3"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const promoteRegistryUUID = "5f0e8f4e-3c52-4c0a-9d0e-6a4f4c1d2b3a"
+
+// promoteServer serves version 1.5.0 of stage/api and records the copies and
+// metadata updates
+type promoteServer struct {
+	mu          sync.Mutex
+	quarantined bool
+	scanStatus  string
+	metadata    map[string]string
+	copies      []string
+	updates     []ar_v2.MetadataInput
+}
+
+func newPromoteServer(t *testing.T) (*promoteServer, *cmdutils.Factory) {
+	t.Helper()
+	withPullConfig(t)
+	s := &promoteServer{metadata: map[string]string{"qa": "passed", "ticket": "REL-12"}}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		q := r.URL.Query()
+		var body any
+		switch {
+		case r.URL.Path == "/registry/acct/stage/+/artifact/api/+/version/1.5.0/summary":
+			reason := "critical CVE"
+			body = ar.ArtifactVersionSummaryResponse{Data: ar.ArtifactVersionSummary{ImageName: "api",
+				Version: "1.5.0", PackageType: ar.DOCKER, RegistryUUID: promoteRegistryUUID,
+				IsQuarantined: &s.quarantined, QuarantineReason: &reason}}
+		case r.URL.Path == "/scans":
+			assert.Equal(t, promoteRegistryUUID, q.Get("registry_id"))
+			var scans []ar_v3.ArtifactScan
+			if s.scanStatus != "" {
+				older, newer := "1700000000000", "1700000600000"
+				// the older BLOCKED scan of 1.5.0 is listed before the latest one
+				scans = append(scans,
+					ar_v3.ArtifactScan{PackageName: "api", Version: "1.4.0", ScanStatus: "BLOCKED"},
+					ar_v3.ArtifactScan{PackageName: "api", Version: "1.5.0", ScanStatus: "BLOCKED",
+						LastEvaluatedAt: &older},
+					ar_v3.ArtifactScan{PackageName: "api", Version: "1.5.0",
+						ScanStatus: ar_v3.ArtifactScanScanStatus(s.scanStatus), LastEvaluatedAt: &newer})
+			}
+			body = ar_v3.ListArtifactScanResponse{Data: scans, PageCount: 1}
+		case r.URL.Path == "/metadata" && r.Method == http.MethodGet:
+			var items []ar_v2.MetadataItemOutput
+			for k, v := range s.metadata {
+				items = append(items, ar_v2.MetadataItemOutput{Key: k, Value: v})
+			}
+			body = ar_v2.MetadataResponse{Data: ar_v2.MetadataOutput{Metadata: items}}
+		case r.URL.Path == "/metadata":
+			var update ar_v2.MetadataInput
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&update))
+			s.updates = append(s.updates, update)
+			body = map[string]string{"status": "SUCCESS"}
+		case r.URL.Path == "/copy":
+			s.copies = append(s.copies, q.Get("src_registry_identifier")+"/"+q.Get("src_artifact")+"/"+
+				q.Get("src_version")+" -> "+q.Get("target_registry_identifier"))
+			body = map[string]string{"status": "SUCCESS"}
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(ts.Close)
+
+	client, err := ar.NewClientWithResponses(ts.URL)
+	require.NoError(t, err)
+	v2Client, err := ar_v2.NewClientWithResponses(ts.URL)
+	require.NoError(t, err)
+	v3Client, err := ar_v3.NewClientWithResponses(ts.URL)
+	require.NoError(t, err)
+	return s, &cmdutils.Factory{
+		RegistryHttpClient:   func() *ar.ClientWithResponses { return client },
+		RegistryV2HttpClient: func() *ar_v2.ClientWithResponses { return v2Client },
+		RegistryV3HttpClient: func() *ar_v3.ClientWithResponses { return v3Client },
+	}
+}
+
+const testPolicy = `
+targets: [prod]
+scan:
+  block: [BLOCKED, warn]
+  requireScan: false
+metadata:
+  qa: passed
+  ticket: "*"
+`
+
+func TestPromoteCopiesAndStampsMetadata(t *testing.T) {
+	s, f := newPromoteServer(t)
+
+	require.NoError(t, runCobraCmd(t, NewPromoteArtifactCmd(f), "stage/api/1.5.0", "--to", "prod",
+		"--policy", writeTestFile(t, "promote.yaml", testPolicy), "--promoted-by", "release-bot"))
+
+	assert.Equal(t, []string{"stage/api/1.5.0 -> prod"}, s.copies)
+	require.Len(t, s.updates, 1)
+	update := s.updates[0]
+	assert.Equal(t, "prod", update.RegistryIdentifier)
+	assert.Equal(t, "api", *update.Package)
+	assert.Equal(t, "1.5.0", *update.Version)
+	require.Len(t, update.Metadata, 3)
+	assert.Equal(t, ar_v2.MetadataItemInput{Key: "promoted_by", Value: "release-bot"}, update.Metadata[1])
+	assert.Equal(t, ar_v2.MetadataItemInput{Key: "promoted_from", Value: "stage"}, update.Metadata[2])
+	assert.Equal(t, "promoted_at", update.Metadata[0].Key)
+}
+
+func TestPromoteGatesBlockCopy(t *testing.T) {
+	s, f := newPromoteServer(t)
+	s.quarantined = true
+	s.scanStatus = "WARN"
+	s.metadata = map[string]string{"qa": "failed"}
+
+	gates, err := checkPromotionGates(t.Context(), f, versionRef{Registry: "stage", Artifact: "api",
+		Version: "1.5.0"}, "qa", mustLoadPolicy(t, testPolicy))
+	require.NoError(t, err)
+	assert.Equal(t, []gateResult{
+		{Gate: "target", Status: gateFailed, Detail: "qa (allowed: prod)"},
+		{Gate: "firewall", Status: gateFailed, Detail: "quarantined: critical CVE"},
+		{Gate: "scan", Status: gateFailed, Detail: "scan status WARN (blocking: BLOCKED, WARN)"},
+		{Gate: "metadata qa", Status: gateFailed, Detail: "qa=failed, expected passed"},
+		{Gate: "metadata ticket", Status: gateFailed, Detail: "not set"},
+	}, gates)
+
+	err = runCobraCmd(t, NewPromoteArtifactCmd(f), "stage/api/1.5.0", "--to", "prod",
+		"--policy", writeTestFile(t, "promote.yaml", testPolicy))
+	require.ErrorContains(t, err, "promotion of stage/api:1.5.0 to prod blocked: 4 gate(s) failed")
+	assert.Empty(t, s.copies)
+	assert.Empty(t, s.updates)
+}
+
+func TestPromoteDefaultPolicyAndDryRun(t *testing.T) {
+	s, f := newPromoteServer(t)
+	s.scanStatus = "WARN"
+
+	// without a policy only BLOCKED scans and quarantined versions are refused
+	require.NoError(t, runCobraCmd(t, NewPromoteArtifactCmd(f), "stage/api/1.5.0", "--to", "prod",
+		"--dry-run"))
+	assert.Empty(t, s.copies)
+
+	s.scanStatus = "BLOCKED"
+	err := runCobraCmd(t, NewPromoteArtifactCmd(f), "stage/api/1.5.0", "--to", "prod")
+	require.ErrorContains(t, err, "1 gate(s) failed")
+	assert.Empty(t, s.copies)
+}
+
+func TestPromoteRequiresAScan(t *testing.T) {
+	s, f := newPromoteServer(t)
+	src := versionRef{Registry: "stage", Artifact: "api", Version: "1.5.0"}
+
+	// the default policy refuses a version that was never scanned
+	gates, err := checkPromotionGates(t.Context(), f, src, "prod", defaultPromotionPolicy())
+	require.NoError(t, err)
+	assert.Contains(t, gates, gateResult{Gate: "scan", Status: gateFailed, Detail: "no scan found"})
+	err = runCobraCmd(t, NewPromoteArtifactCmd(f), "stage/api/1.5.0", "--to", "prod")
+	require.ErrorContains(t, err, "1 gate(s) failed")
+	assert.Empty(t, s.copies)
+
+	// unless the policy allows it explicitly
+	gates, err = checkPromotionGates(t.Context(), f, src, "prod",
+		mustLoadPolicy(t, "scan:\n  requireScan: false\n"))
+	require.NoError(t, err)
+	assert.Contains(t, gates, gateResult{Gate: "scan", Status: gatePassed, Detail: "no scan found"})
+}
+
+func TestLoadPromotionPolicyRejectsInvalidPolicies(t *testing.T) {
+	_, err := loadPromotionPolicy(writeTestFile(t, "promote.yaml", "scan:\n  block: [FAILED]\n"))
+	require.ErrorContains(t, err, `invalid scan status "FAILED"`)
+	_, err = loadPromotionPolicy(writeTestFile(t, "promote.yaml", "metdata:\n  qa: passed\n"))
+	require.ErrorContains(t, err, "field metdata not found")
+}
+
+func mustLoadPolicy(t *testing.T, policy string) *promotionPolicy {
+	t.Helper()
+	p, err := loadPromotionPolicy(writeTestFile(t, "promote.yaml", policy))
+	require.NoError(t, err)
+	return p
+}
@@ -0,0 +1,725 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/harness/harness-cli/cmd/cmdutils"
+	"github.com/harness/harness-cli/config"
+	"github.com/harness/harness-cli/internal/api/ar_v2"
+	"github.com/harness/harness-cli/util"
+	"github.com/harness/harness-cli/util/common/httpclient"
+	"github.com/harness/harness-cli/util/common/printer"
+	p "github.com/harness/harness-cli/util/common/progress"
+	"github.com/harness/harness-cli/util/common/upload"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// releaseManifest is the file read by publish. Example:
+//
+//	registry: releases            # default registry of the entries
+//	artifacts:
+//	  - type: generic
+//	    path: dist/app-linux-amd64
+//	    name: app
+//	    version: 1.4.0
+//	    metadata: {commit: "${GIT_SHA}"}
+//	  - type: debian
+//	    registry: apt
+//	    path: dist/*.deb
+//	    options: {distribution: stable, component: main}
+type releaseManifest struct {
+	Registry  string         `yaml:"registry"`
+	Artifacts []releaseEntry `yaml:"artifacts"`
+}
+
+// releaseEntry is one artifact of a release manifest. It is pushed with
+// `hc artifact push <type> <registry> <path> [args...]`; name and version
+// fill the --name and --version flags of types that have them, and options
+// hold any other flag of the push command.
+type releaseEntry struct {
+	Type     string            `yaml:"type"`
+	Registry string            `yaml:"registry"`
+	Path     string            `yaml:"path"`
+	Args     []string          `yaml:"args"`
+	Name     string            `yaml:"name"`
+	Version  string            `yaml:"version"`
+	Options  map[string]any    `yaml:"options"`
+	Metadata map[string]string `yaml:"metadata"`
+}
+
+// publishJob is one artifact path of a manifest entry. Its uploads run on
+// the engine shared by the whole manifest, then finish and the entry's
+// metadata are applied once they all succeeded.
+type publishJob struct {
+	index    int
+	entry    releaseEntry
+	registry string
+	path     string
+	uploads  []upload.FileUploadJob
+	// skipUnchanged drops the generic files the registry already holds
+	skipUnchanged bool
+	skipped       int
+	finish        func(ctx context.Context) error
+}
+
+// complete runs the steps that follow the uploads of the job
+func (j *publishJob) complete(ctx context.Context, f *cmdutils.Factory) error {
+	if j.finish != nil {
+		if err := j.finish(ctx); err != nil {
+			return err
+		}
+	}
+	if len(j.entry.Metadata) == 0 {
+		return nil
+	}
+	if err := updateArtifactMetadata(ctx, f, j.registry, j.entry.Name, j.entry.Version,
+		j.entry.Metadata); err != nil {
+		return fmt.Errorf("pushed, but setting metadata failed: %w", err)
+	}
+	return nil
+}
+
+// pushJob pushes an artifact of a type without upload jobs of its own, as
+// one job of the engine
+type pushJob struct {
+	upload.BaseFileUploadJob
+	push func(ctx context.Context) error
+}
+
+// Upload pushes the job's artifact
+func (j *pushJob) Upload(ctx context.Context) error {
+	return j.push(ctx)
+}
+
+// publishUpload gives an upload an ID unique across the manifest, as two
+// entries may push files of the same name
+type publishUpload struct {
+	upload.FileUploadJob
+	id string
+}
+
+func (u *publishUpload) GetID() string { return u.id }
+
+// publishResult is one row of the publish report
+type publishResult struct {
+	Entry    int    `json:"entry"`
+	Type     string `json:"type"`
+	Registry string `json:"registry"`
+	Path     string `json:"path"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+}
+
+// NewPublishArtifactCmd creates a new cobra.Command for publishing every
+// artifact of a release manifest.
+// command example: hc artifact publish -f release.yaml
+//
+// All entries are validated before anything is uploaded; the pushes then
+// share one upload worker pool and a single report and exit status cover
+// the whole release.
+func NewPublishArtifactCmd(f *cmdutils.Factory) *cobra.Command {
+	var manifestFile, pkgURL string
+	var maxConcurrentUploads int
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "publish -f <release.yaml>",
+		Short: "Publish the artifacts of a release manifest",
+		Long: `Publish every artifact listed in a release manifest (YAML or JSON). Each entry
+names the artifact type, registry and path, plus the options of its
+'hc artifact push <type>' command (e.g. distribution and component for Debian)
+and metadata to set on the pushed version. Paths are relative to the manifest
+and may be globs; ${VAR} references in values are expanded from the
+environment, and an unset variable fails the publish.
+
+Every entry is validated before anything is uploaded. The files of all
+entries are then uploaded in parallel on one worker pool and a report of all
+entries is printed; the command fails if any entry failed.`,
+		Example: `  registry: releases
+  artifacts:
+    - type: maven
+      path: target/app-1.4.0.jar
+      options: {pom-file: pom.xml}
+    - type: python
+      path: dist/*.whl
+    - type: debian
+      registry: apt
+      path: dist/app_1.4.0_amd64.deb
+      options: {distribution: stable, component: main}
+    - type: generic
+      path: dist/app-linux-amd64
+      name: app
+      version: 1.4.0
+      metadata: {commit: "${GIT_SHA}"}`,
+		Args: cobra.NoArgs,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			if pkgURL != "" {
+				config.Global.Registry.PkgURL = util.GetPkgUrl(pkgURL)
+			}
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			progress := p.NewConsoleReporter()
+			progress.Start(fmt.Sprintf("Validating %s", manifestFile))
+			manifest, err := loadReleaseManifest(manifestFile)
+			if err != nil {
+				progress.Error("Invalid manifest")
+				return err
+			}
+			u := quietPkgUploader(config.Global.Registry.PkgURL)
+			jobs, err := planPublish(f, u, manifest, filepath.Dir(manifestFile))
+			if err != nil {
+				progress.Error("Invalid manifest")
+				return err
+			}
+			progress.Success(fmt.Sprintf("%d artifact(s) to publish", len(jobs)))
+
+			if dryRun {
+				rows := make([]publishResult, 0, len(jobs))
+				for _, job := range jobs {
+					rows = append(rows, job.result("planned", nil))
+				}
+				return printPublishReport(rows)
+			}
+
+			rows, failed, err := executePublish(cmd.Context(), f, jobs, maxConcurrentUploads, progress)
+			if err != nil {
+				return err
+			}
+			if err := printPublishReport(rows); err != nil {
+				return err
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d artifact(s) failed to publish", failed, len(jobs))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&manifestFile, "file", "f", "", "Release manifest (YAML or JSON)")
+	cmd.Flags().StringVar(&pkgURL, "pkg-url", "", "Base URL for the Packages")
+	cmd.Flags().IntVar(&maxConcurrentUploads, "max-concurrent-uploads", upload.DefaultUploadWorker,
+		"Maximum number of files to upload in parallel, 1 for sequential")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Validate the manifest and list the artifacts without pushing")
+	cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+// executePublish uploads the files of every job on one engine, reporting its
+// progress, and returns a report row per job
+func executePublish(ctx context.Context, f *cmdutils.Factory, jobs []*publishJob,
+	maxConcurrentUploads int, progress p.Reporter) ([]publishResult, int, error) {
+	var uploads []upload.FileUploadJob
+	owners := map[string]*publishJob{}
+	for i, job := range jobs {
+		if job.skipUnchanged {
+			detector := newHeadChangeDetector(f.PkgHttpClient(), job.registry, maxConcurrentUploads)
+			changed, skipped, err := filterUnchangedGenericJobs(ctx, detector, job.uploads)
+			if err != nil {
+				return nil, 0, err
+			}
+			job.uploads, job.skipped = changed, skipped
+		}
+		for _, u := range job.uploads {
+			id := fmt.Sprintf("%d:%s", i, u.GetID())
+			uploads = append(uploads, &publishUpload{FileUploadJob: u, id: id})
+			owners[id] = job
+		}
+	}
+
+	engine := upload.NewFileUploadEngine(maxConcurrentUploads, progress)
+	results := engine.Execute(ctx, uploads)
+	errs := map[*publishJob]error{}
+	for _, r := range results {
+		if job := owners[r.JobID]; !r.Success && errs[job] == nil {
+			errs[job] = fmt.Errorf("%s: %w", r.FilePath, r.Error)
+		}
+	}
+	for _, job := range jobs {
+		if errs[job] == nil {
+			if err := job.complete(ctx, f); err != nil {
+				errs[job] = err
+			}
+		}
+	}
+
+	rows := make([]publishResult, 0, len(jobs))
+	for _, job := range jobs {
+		switch {
+		case errs[job] != nil:
+			rows = append(rows, job.result("failed", errs[job]))
+		case len(job.uploads) == 0 && job.skipped > 0:
+			rows = append(rows, job.result("unchanged", nil))
+		default:
+			rows = append(rows, job.result("published", nil))
+		}
+	}
+	return rows, len(errs), nil
+}
+
+func (j *publishJob) result(status string, err error) publishResult {
+	r := publishResult{Entry: j.index, Type: j.entry.Type, Registry: j.registry, Path: j.path,
+		Status: status}
+	if err != nil {
+		r.Error = err.Error()
+	}
+	return r
+}
+
+func printPublishReport(rows []publishResult) error {
+	return printer.Print(rows, 0, 1, int64(len(rows)), false, [][]string{
+		{"entry", "#"},
+		{"type", "Type"},
+		{"registry", "Registry"},
+		{"path", "Path"},
+		{"status", "Status"},
+		{"error", "Error"},
+	})
+}
+
+// loadReleaseManifest reads a manifest, expanding ${VAR} references from the
+// environment and rejecting unknown fields so typos are caught up front
+func loadReleaseManifest(file string) (*releaseManifest, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var manifest releaseManifest
+	if err := dec.Decode(&manifest); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", file, err)
+	}
+	if len(manifest.Artifacts) == 0 {
+		return nil, fmt.Errorf("manifest %s lists no artifacts", file)
+	}
+	if err := manifest.expandEnv(); err != nil {
+		return nil, fmt.Errorf("failed to expand manifest %s: %w", file, err)
+	}
+	return &manifest, nil
+}
+
+var manifestEnvRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandManifestEnv replaces the ${VAR} references of a manifest value;
+// an unset variable is an error rather than an empty string
+func expandManifestEnv(s string) (string, error) {
+	var missing string
+	out := manifestEnvRef.ReplaceAllStringFunc(s, func(ref string) string {
+		name := manifestEnvRef.FindStringSubmatch(ref)[1]
+		value, ok := os.LookupEnv(name)
+		if !ok && missing == "" {
+			missing = name
+		}
+		return value
+	})
+	if missing != "" {
+		return "", fmt.Errorf("environment variable %s is not set", missing)
+	}
+	return out, nil
+}
+
+// expandEnv expands the ${VAR} references of every string of the decoded
+// manifest, so the YAML structure itself is never rewritten
+func (m *releaseManifest) expandEnv() error {
+	var err error
+	expand := func(s *string) {
+		if err == nil {
+			*s, err = expandManifestEnv(*s)
+		}
+	}
+	expandAny := func(v any) any {
+		if s, ok := v.(string); ok {
+			expand(&s)
+			return s
+		}
+		return v
+	}
+	expand(&m.Registry)
+	for i := range m.Artifacts {
+		e := &m.Artifacts[i]
+		for _, s := range []*string{&e.Type, &e.Registry, &e.Path, &e.Name, &e.Version} {
+			expand(s)
+		}
+		for j := range e.Args {
+			expand(&e.Args[j])
+		}
+		for k, v := range e.Options {
+			if list, ok := v.([]any); ok {
+				for j := range list {
+					list[j] = expandAny(list[j])
+				}
+				continue
+			}
+			e.Options[k] = expandAny(v)
+		}
+		for k, v := range e.Metadata {
+			expand(&v)
+			e.Metadata[k] = v
+		}
+		if err != nil {
+			return fmt.Errorf("entry %d: %w", i+1, err)
+		}
+	}
+	return err
+}
+
+// planPublish validates every entry against its push command and returns
+// one job per artifact file, uploading with u. All problems are reported
+// together.
+func planPublish(f *cmdutils.Factory, u pkgUploader, manifest *releaseManifest,
+	baseDir string) ([]*publishJob, error) {
+	var jobs []*publishJob
+	var problems []string
+	for i, entry := range manifest.Artifacts {
+		entryJobs, err := planPublishEntry(f, u, i+1, entry, manifest.Registry, baseDir)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("  - entry %d (%s %s): %v", i+1, entry.Type, entry.Path, err))
+			continue
+		}
+		jobs = append(jobs, entryJobs...)
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%d of %d manifest entries are invalid:\n%s", len(problems),
+			len(manifest.Artifacts), strings.Join(problems, "\n"))
+	}
+	return jobs, nil
+}
+
+func planPublishEntry(f *cmdutils.Factory, u pkgUploader, index int, entry releaseEntry, defaultRegistry,
+	baseDir string) ([]*publishJob, error) {
+	registry := entry.Registry
+	if registry == "" {
+		registry = defaultRegistry
+	}
+	switch {
+	case entry.Type == "":
+		return nil, fmt.Errorf("type is required")
+	case registry == "":
+		return nil, fmt.Errorf("registry is required")
+	case entry.Path == "":
+		return nil, fmt.Errorf("path is required")
+	case len(entry.Metadata) > 0 && entry.Name == "":
+		return nil, fmt.Errorf("name is required to set metadata")
+	}
+
+	push := NewPushArtifactCmd(f)
+	sub, _, err := push.Find([]string{entry.Type})
+	if err != nil || sub == push {
+		return nil, fmt.Errorf("unknown type %q", entry.Type)
+	}
+	flagArgs, err := publishFlagArgs(sub, entry, baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := expandPublishPath(baseDir, entry.Path)
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]*publishJob, 0, len(paths))
+	for _, path := range paths {
+		positional := append([]string{registry, path}, entry.Args...)
+		// parse on a fresh command so the checks match what the push will see
+		check, _, _ := NewPushArtifactCmd(f).Find([]string{entry.Type})
+		if err := check.ParseFlags(flagArgs); err != nil {
+			return nil, err
+		}
+		if err := check.ValidateArgs(positional); err != nil {
+			return nil, errors.New(firstLine(err.Error()))
+		}
+		if err := check.ValidateRequiredFlags(); err != nil {
+			return nil, err
+		}
+		if err := check.ValidateFlagGroups(); err != nil {
+			return nil, err
+		}
+		job := &publishJob{index: index, entry: entry, registry: registry, path: path}
+		if job.uploads, job.finish, err = publishUploads(f, u, check, entry.Type, positional); err != nil {
+			return nil, err
+		}
+		job.skipUnchanged = entry.Type == "generic"
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// publishUploads builds the upload jobs of an artifact file. The files of
+// generic, python and maven pushes are jobs of their own, and for maven the
+// update of maven-metadata.xml runs after the uploads; the other types push
+// with their own requests, as one job.
+func publishUploads(f *cmdutils.Factory, u pkgUploader, push *cobra.Command, artifactType string,
+	positional []string) ([]upload.FileUploadJob, func(context.Context) error, error) {
+	registry, path := positional[0], positional[1]
+	flags := push.Flags()
+	switch artifactType {
+	case "generic":
+		name, _ := flags.GetString("name")
+		version, _ := flags.GetString("version")
+		if version == "" {
+			version = "1.0.0"
+		}
+		includeHidden, _ := flags.GetBool("include-hidden")
+		jobs, _, err := collectGenericUploadJobs(positional[1:], registry, name, version, includeHidden,
+			u.pkgURL, httpclient.NewRetryClientWithoutProgress())
+		if err == nil && len(jobs) == 0 {
+			err = errors.New("no files to upload")
+		}
+		return jobs, nil, err
+	case "python":
+		jobs, err := pythonUploadJobs(f, registry, path, u.progress)
+		return jobs, nil, err
+	case "maven":
+		var opts mavenPushOptions
+		opts.pomPath, _ = flags.GetString("pom-file")
+		opts.groupID, _ = flags.GetString("group-id")
+		opts.artifactID, _ = flags.GetString("artifact-id")
+		opts.version, _ = flags.GetString("version")
+		opts.packaging, _ = flags.GetString("packaging")
+		opts.classifier, _ = flags.GetString("classifier")
+		opts.sourcesPath, _ = flags.GetString("sources")
+		opts.javadocPath, _ = flags.GetString("javadoc")
+		coords, jobs, err := planMavenPush(f, registry, path, opts, u.progress)
+		if err != nil {
+			return nil, nil, err
+		}
+		return jobs, func(context.Context) error {
+			return updateMavenMetadata(f.PkgHttpClient(), registry, coords, u.progress)
+		}, nil
+	}
+
+	pushArtifact, err := publishPush(f, u, push, artifactType, positional)
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return []upload.FileUploadJob{&pushJob{
+		BaseFileUploadJob: upload.BaseFileUploadJob{ID: path, FilePath: path, FileSize: info.Size()},
+		push:              pushArtifact,
+	}}, nil, nil
+}
+
+// publishPush returns the push of an artifact of a type without upload jobs
+// of its own, with the options parsed by its push command
+func publishPush(f *cmdutils.Factory, u pkgUploader, push *cobra.Command, artifactType string,
+	positional []string) (func(context.Context) error, error) {
+	registry, path := positional[0], positional[1]
+	flags := push.Flags()
+	str := func(name string) string {
+		value, _ := flags.GetString(name)
+		return value
+	}
+	switch artifactType {
+	case "cargo":
+		return func(ctx context.Context) error { return pushCargo(ctx, u, registry, path, u.progress) }, nil
+	case "composer":
+		return func(ctx context.Context) error { return pushComposer(ctx, u, registry, path, u.progress) }, nil
+	case "conda":
+		return func(ctx context.Context) error { return pushConda(ctx, u, registry, path, u.progress) }, nil
+	case "rpm":
+		return func(ctx context.Context) error { return pushRpm(ctx, u, registry, path, u.progress) }, nil
+	case "npm":
+		return func(ctx context.Context) error {
+			return pushNpm(ctx, u, f.PkgHttpClient(), registry, path, u.progress)
+		}, nil
+	case "dart":
+		return func(ctx context.Context) error { return pushDart(ctx, u, registry, path, u.progress) }, nil
+	case "puppet":
+		return func(ctx context.Context) error { return pushPuppet(ctx, u, registry, path, u.progress) }, nil
+	case "nuget":
+		nestedPath := str("path")
+		return func(ctx context.Context) error {
+			return pushNuget(ctx, u, registry, path, nestedPath, u.progress)
+		}, nil
+	case "swift":
+		target, metadataPath := positional[2], str("metadata-path")
+		return func(ctx context.Context) error {
+			return pushSwift(ctx, u, registry, path, target, metadataPath, u.progress)
+		}, nil
+	case "go":
+		version := str("version")
+		return func(ctx context.Context) error {
+			// pushes of several modules must not share the generated files
+			output, err := os.MkdirTemp("", "hc-go-*")
+			if err != nil {
+				return err
+			}
+			defer os.RemoveAll(output)
+			return pushGo(ctx, u, registry, path, version, output, u.progress)
+		}, nil
+	case "debian":
+		opts := debianPushOptions{distribution: str("distribution"), component: str("component"),
+			sourceFile: str("source-file"), originSourceFile: str("origin-source-file")}
+		return func(ctx context.Context) error { return pushDebian(ctx, u, registry, path, opts, u.progress) }, nil
+	case "conan":
+		opts := conanPushOptions{recipeRevision: str("recipe-revision"), packageDir: str("package-dir"),
+			packageID: str("package-id"), packageRevision: str("package-revision")}
+		recipeDir := positional[2]
+		return func(ctx context.Context) error {
+			return pushConan(ctx, u, registry, path, recipeDir, opts, u.progress)
+		}, nil
+	case "docker":
+		tags, _ := flags.GetStringArray("tag")
+		platform := str("platform")
+		return func(ctx context.Context) error {
+			_, err := pushDockerImage(ctx, f, registry, path, tags, platform, u.progress)
+			return err
+		}, nil
+	case "helm":
+		opts := helmPackageOptions{destination: str("destination"), key: str("key"), keyring: str("keyring"),
+			passphraseFile: str("passphrase-file")}
+		opts.skipDependencyUpdate, _ = flags.GetBool("skip-dependency-update")
+		opts.sign, _ = flags.GetBool("sign")
+		return func(ctx context.Context) error {
+			_, err := pushHelmChart(ctx, f, registry, path, opts, u.progress)
+			return err
+		}, nil
+	case "huggingface":
+		opts := hfPushOptions{name: str("name"), revision: str("revision"), artifactType: str("artifact-type"),
+			message: str("message")}
+		opts.maxConcurrentUploads, _ = flags.GetInt("max-concurrent-uploads")
+		return func(ctx context.Context) error {
+			_, err := pushHuggingFace(ctx, u, registry, path, opts, u.progress)
+			return err
+		}, nil
+	}
+	return nil, fmt.Errorf("type %q cannot be published", artifactType)
+}
+
+// publishPathOptions are the push options naming local files or directories,
+// which are relative to the manifest like the entry path
+var publishPathOptions = map[string]bool{
+	"pom-file":           true,
+	"sources":            true,
+	"javadoc":            true,
+	"source-file":        true,
+	"origin-source-file": true,
+	"package-dir":        true,
+	"metadata-path":      true,
+	"destination":        true,
+	"keyring":            true,
+	"passphrase-file":    true,
+}
+
+// publishFlagArgs turns an entry's name, version and options into flags of
+// its push command; list options repeat the flag
+func publishFlagArgs(sub *cobra.Command, entry releaseEntry, baseDir string) ([]string, error) {
+	options := map[string]any{}
+	for k, v := range entry.Options {
+		options[k] = v
+	}
+	for flag, value := range map[string]string{"name": entry.Name, "version": entry.Version} {
+		// types without the flag only use name and version to address metadata
+		if _, set := options[flag]; value == "" || set || sub.Flags().Lookup(flag) == nil {
+			continue
+		}
+		options[flag] = value
+	}
+
+	keys := make([]string, 0, len(options))
+	for k := range options {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var args []string
+	for _, k := range keys {
+		if k == "pkg-url" || k == "max-concurrent-uploads" {
+			return nil, fmt.Errorf("%s applies to the whole manifest: pass --%s to publish", k, k)
+		}
+		if sub.Flags().Lookup(k) == nil {
+			return nil, fmt.Errorf("push %s has no option %q", entry.Type, k)
+		}
+		value := func(v any) string {
+			s := fmt.Sprint(v)
+			// "-" reads stdin
+			if publishPathOptions[k] && s != "-" && !filepath.IsAbs(s) {
+				return filepath.Join(baseDir, s)
+			}
+			return s
+		}
+		switch v := options[k].(type) {
+		case []any:
+			for _, item := range v {
+				args = append(args, fmt.Sprintf("--%s=%s", k, value(item)))
+			}
+		case nil:
+			args = append(args, "--"+k)
+		default:
+			args = append(args, fmt.Sprintf("--%s=%s", k, value(v)))
+		}
+	}
+	return args, nil
+}
+
+// expandPublishPath resolves an entry path relative to the manifest,
+// expanding globs
+func expandPublishPath(baseDir, path string) ([]string, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	if !isGlobPattern(path) {
+		if _, err := os.Stat(path); err != nil {
+			return nil, err
+		}
+		return []string{path}, nil
+	}
+	matches, err := filepath.Glob(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", path, err)
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no file matches %s", path)
+	}
+	return matches, nil
+}
+
+// updateArtifactMetadata sets metadata on a package, or on one of its
+// versions when version is set
+func updateArtifactMetadata(ctx context.Context, f *cmdutils.Factory, registry, pkg, version string,
+	metadata map[string]string) error {
+	keys := make([]string, 0, len(metadata))
+	for k := range metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	items := make([]ar_v2.MetadataItemInput, 0, len(keys))
+	for _, k := range keys {
+		items = append(items, ar_v2.MetadataItemInput{Key: k, Value: metadata[k]})
+	}
+	body := ar_v2.UpdateMetadataJSONRequestBody{
+		RegistryIdentifier: registry,
+		Package:            &pkg,
+		Metadata:           items,
+	}
+	if version != "" {
+		body.Version = &version
+	}
+	response, err := f.RegistryV2HttpClient().UpdateMetadataWithResponse(ctx,
+		&ar_v2.UpdateMetadataParams{AccountIdentifier: config.Global.AccountID}, body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode() >= 400 {
+		return fmt.Errorf("request failed with status %d: %s", response.StatusCode(),
+			bytes.TrimSpace(response.Body))
+	}
+	return nil
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return strings.TrimSpace(line)
+}
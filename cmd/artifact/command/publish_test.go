@@ -0,0 +1,311 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/harness/harness-cli/cmd/cmdutils"
+	"github.com/harness/harness-cli/config"
+	"github.com/harness/harness-cli/internal/api/ar_pkg"
+	"github.com/harness/harness-cli/internal/api/ar_v2"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// publishServer accepts generic uploads and metadata updates, recording the
+// uploaded paths and the metadata requests
+type publishServer struct {
+	mu       sync.Mutex
+	uploaded []string
+	metadata []ar_v2.MetadataInput
+	requests int
+	// metadataStatus is the status metadata updates are answered with
+	metadataStatus int
+}
+
+func newPublishServer(t *testing.T) (*publishServer, *cmdutils.Factory) {
+	t.Helper()
+	s := &publishServer{metadataStatus: http.StatusOK}
+	srv := withGenericServer(t, func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.requests++
+		switch {
+		case r.Method == http.MethodPut:
+			s.uploaded = append(s.uploaded, strings.TrimPrefix(r.URL.EscapedPath(), "/pkg/test-account/"))
+			w.WriteHeader(http.StatusCreated)
+		case r.URL.Path == "/metadata":
+			var body ar_v2.MetadataInput
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			s.metadata = append(s.metadata, body)
+			w.WriteHeader(s.metadataStatus)
+			_, _ = io.WriteString(w, `{"status":"SUCCESS"}`)
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+	})
+	pkgClient, err := ar_pkg.NewClientWithResponses(srv.URL)
+	require.NoError(t, err)
+	v2Client, err := ar_v2.NewClientWithResponses(srv.URL)
+	require.NoError(t, err)
+	return s, &cmdutils.Factory{
+		PkgHttpClient:        func() *ar_pkg.ClientWithResponses { return pkgClient },
+		RegistryV2HttpClient: func() *ar_v2.ClientWithResponses { return v2Client },
+	}
+}
+
+func runPublish(t *testing.T, f *cmdutils.Factory, manifest string, extra ...string) error {
+	t.Helper()
+	dir := makeTree(t, map[string]string{
+		"release.yaml":           manifest,
+		"dist/app-linux-amd64":   "linux binary",
+		"dist/app-darwin-arm64":  "darwin binary",
+		"dist/checksums.txt":     "sums",
+		"dist/app_1.4.0_all.deb": "deb",
+	})
+	cmd := NewPublishArtifactCmd(f)
+	cmd.SetArgs(append([]string{"-f", filepath.Join(dir, "release.yaml")}, extra...))
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	return cmd.Execute()
+}
+
+func TestPublishManifest(t *testing.T) {
+	s, f := newPublishServer(t)
+	t.Setenv("RELEASE_SHA", "abc123")
+
+	err := runPublish(t, f, `
+registry: bin
+artifacts:
+  - type: generic
+    path: dist/app-*
+    name: app
+    version: 1.4.0
+    metadata: {commit: "${RELEASE_SHA}"}
+  - type: generic
+    registry: misc
+    path: dist/checksums.txt
+    name: sums
+    version: 1.4.0
+`)
+	require.NoError(t, err)
+
+	sort.Strings(s.uploaded)
+	assert.Equal(t, []string{
+		"bin/files/app/1.4.0/app-darwin-arm64",
+		"bin/files/app/1.4.0/app-linux-amd64",
+		"misc/files/sums/1.4.0/checksums.txt",
+	}, s.uploaded)
+	// one metadata update per file pushed by the entry
+	require.Len(t, s.metadata, 2)
+	assert.Equal(t, "bin", s.metadata[0].RegistryIdentifier)
+	assert.Equal(t, "app", *s.metadata[0].Package)
+	assert.Equal(t, "1.4.0", *s.metadata[0].Version)
+	assert.Equal(t, []ar_v2.MetadataItemInput{{Key: "commit", Value: "abc123"}}, s.metadata[0].Metadata)
+}
+
+func TestPublishPushesOtherTypesDirectly(t *testing.T) {
+	s, f := newPublishServer(t)
+	dir := makeTree(t, map[string]string{
+		"release.yaml": `
+registry: rpms
+artifacts:
+  - type: rpm
+    path: dist/*.rpm
+`,
+		"dist/app-1.4.0.x86_64.rpm": "rpm",
+		"dist/app-1.4.0.noarch.rpm": "rpm",
+	})
+	cmd := NewPublishArtifactCmd(f)
+	cmd.SetArgs([]string{"-f", filepath.Join(dir, "release.yaml")})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	require.NoError(t, cmd.Execute())
+
+	// one push per package, without running the rpm push command
+	assert.Equal(t, []string{"rpms/rpm", "rpms/rpm"}, s.uploaded)
+}
+
+func TestPublishExpandsOnlyDecodedStrings(t *testing.T) {
+	s, f := newPublishServer(t)
+	// a value that would change the YAML structure if spliced into the text
+	t.Setenv("RELEASE_SHA", "abc: 123\n  - x")
+
+	require.NoError(t, runPublish(t, f, `
+artifacts:
+  - type: generic
+    registry: bin
+    path: dist/checksums.txt
+    name: sums
+    version: 1.4.0
+    metadata: {commit: "${RELEASE_SHA}", note: "costs $5"}
+`))
+	require.Len(t, s.metadata, 1)
+	assert.ElementsMatch(t, []ar_v2.MetadataItemInput{
+		{Key: "commit", Value: "abc: 123\n  - x"},
+		{Key: "note", Value: "costs $5"},
+	}, s.metadata[0].Metadata)
+
+	err := runPublish(t, f, `
+artifacts:
+  - type: generic
+    registry: bin
+    path: dist/checksums.txt
+    name: sums
+    version: ${RELEASE_VERSION_UNSET}
+`)
+	require.ErrorContains(t, err, "environment variable RELEASE_VERSION_UNSET is not set")
+}
+
+func TestPublishValidatesEveryEntryBeforeUploading(t *testing.T) {
+	s, f := newPublishServer(t)
+
+	err := runPublish(t, f, `
+registry: bin
+artifacts:
+  - type: generic
+    path: dist/checksums.txt
+    name: sums
+  - type: rubygems
+    path: dist/checksums.txt
+  - type: generic
+    path: dist/missing.bin
+    name: missing
+  - type: debian
+    registry: apt
+    path: dist/app_1.4.0_all.deb
+    options: {distribution: stable}
+  - type: debian
+    registry: apt
+    path: dist/app_1.4.0_all.deb
+    options: {distribution: stable, component: main, arch: amd64}
+  - type: generic
+    path: dist/checksums.txt
+    metadata: {a: b}
+`)
+	require.Error(t, err)
+	msg := err.Error()
+	assert.Contains(t, msg, "5 of 6 manifest entries are invalid")
+	assert.Contains(t, msg, `entry 2 (rubygems dist/checksums.txt): unknown type "rubygems"`)
+	assert.Contains(t, msg, "entry 3 (generic dist/missing.bin)")
+	assert.Contains(t, msg, `entry 4 (debian dist/app_1.4.0_all.deb): required flag(s) "component" not set`)
+	assert.Contains(t, msg, `entry 5 (debian dist/app_1.4.0_all.deb): push debian has no option "arch"`)
+	assert.Contains(t, msg, "entry 6 (generic dist/checksums.txt): name is required to set metadata")
+	assert.Zero(t, s.requests)
+}
+
+func TestPublishReportsFailedEntries(t *testing.T) {
+	s, f := newPublishServer(t)
+	s.metadataStatus = http.StatusBadRequest
+
+	err := runPublish(t, f, `
+artifacts:
+  - type: generic
+    registry: bin
+    path: dist/checksums.txt
+    name: sums
+    metadata: {a: b}
+  - type: generic
+    registry: bin
+    path: dist/app-linux-amd64
+    name: app
+`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 of 2 artifact(s) failed to publish")
+	assert.Len(t, s.uploaded, 2)
+}
+
+func TestPublishDryRun(t *testing.T) {
+	s, f := newPublishServer(t)
+
+	require.NoError(t, runPublish(t, f, `
+artifacts:
+  - type: generic
+    registry: bin
+    path: dist/app-*
+    name: app
+`, "--dry-run"))
+	assert.Zero(t, s.requests)
+}
+
+func TestPublishPrintsOnlyTheReport(t *testing.T) {
+	s, f := newPublishServer(t)
+	origFormat := config.Global.Format
+	config.Global.Format = "json"
+	t.Cleanup(func() { config.Global.Format = origFormat })
+
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	origStdout := os.Stdout
+	os.Stdout = w
+	doneCh := make(chan string, 1)
+	go func() {
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, r)
+		doneCh <- buf.String()
+	}()
+	err = runPublish(t, f, `
+registry: bin
+artifacts:
+  - type: generic
+    path: dist
+    name: app
+    version: 1.4.0
+`, "--max-concurrent-uploads", "2")
+	_ = w.Close()
+	os.Stdout = origStdout
+	out := <-doneCh
+	require.NoError(t, err)
+
+	// every file of the directory is a job of the shared engine
+	assert.Len(t, s.uploaded, 4)
+	// the validation and upload progress, then the report alone
+	_, report, ok := strings.Cut(out, "1 artifact(s) to publish\n")
+	require.True(t, ok, out)
+	_, report, ok = strings.Cut(report, "Successfully uploaded 4 files")
+	require.True(t, ok, out)
+	report = report[strings.Index(report, "\n")+1:]
+	var rows []publishResult
+	require.NoError(t, json.Unmarshal([]byte(report), &rows), report)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "published", rows[0].Status)
+}
+
+func TestPublishFlagArgsResolvesFileOptions(t *testing.T) {
+	push := NewPushArtifactCmd(&cmdutils.Factory{})
+	maven, _, err := push.Find([]string{"maven"})
+	require.NoError(t, err)
+
+	args, err := publishFlagArgs(maven, releaseEntry{
+		Type: "maven",
+		Options: map[string]any{
+			"pom-file":   "build/pom.xml",
+			"sources":    "/abs/app-sources.jar",
+			"classifier": "linux",
+		},
+	}, "/release")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"--classifier=linux",
+		"--pom-file=/release/build/pom.xml",
+		"--sources=/abs/app-sources.jar",
+	}, args)
+
+	helm, _, err := push.Find([]string{"helm"})
+	require.NoError(t, err)
+	args, err = publishFlagArgs(helm, releaseEntry{
+		Type:    "helm",
+		Options: map[string]any{"passphrase-file": "-"},
+	}, "/release")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"--passphrase-file=-"}, args)
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/harness/harness-cli/util/common"
 	"github.com/harness/harness-cli/util/common/printer"
 	"github.com/harness/harness-cli/util/common/progress"
+	"github.com/harness/harness-cli/util/common/upload"
 
 	"github.com/spf13/cobra"
 )
@@ -34,13 +35,26 @@ func printReadCloser(rc io.ReadCloser) {
 
 // NewPullGenericCmd creates a new cobra.Command for pulling generic artifacts from the registry.
 // command example: hc ar pull generic <registry_name> <package_path> <destination_path>
+//
+// package_path is one of:
+//
+//   - <package>/<version>/<filename>: a single file.
+//   - <package>/<version>: every file of the version.
+//   - <package>/<version>/<pattern>: every file matching a glob such as
+//     build/**/*.so, or under a directory such as build/.
+//
+// Multiple files are downloaded concurrently into destination_path, keeping
+// the directory layout they were pushed with.
 func NewPullGenericCmd(c *cmdutils.Factory) *cobra.Command {
 	var pkgURL string
+	var maxConcurrentDownloads int
 	cmd := &cobra.Command{
 		Use:   "generic <registry_name> <package_path> <destination_path>",
 		Short: "Pull Generic Artifacts",
-		Long:  "Pull Generic Artifacts from Harness Artifact Registry",
-		Args:  cobra.ExactArgs(3),
+		Long: "Pull Generic Artifacts from Harness Artifact Registry. package_path is " +
+			"<package>/<version>/<filename> for a single file, <package>/<version> for every file of the " +
+			"version, or <package>/<version>/<glob> (e.g. build/**/*.so) for the matching files.",
+		Args: cobra.ExactArgs(3),
 		PreRun: func(cmd *cobra.Command, args []string) {
 			if pkgURL != "" {
 				config.Global.Registry.PkgURL = util.GetPkgUrl(pkgURL)
@@ -51,12 +65,29 @@ func NewPullGenericCmd(c *cmdutils.Factory) *cobra.Command {
 			packagePath := args[1]
 			destinationPath := args[2]
 
-			// Parse package path: <package_name>/<version>/<filename>
+			// Parse package path: <package_name>/<version>[/<filename or pattern>]
 			splits := strings.SplitN(packagePath, "/", 3)
-			if len(splits) != 3 {
+			if len(splits) < 2 || splits[0] == "" || splits[1] == "" {
 				return fmt.Errorf("invalid package path format: %s (expected format: <package_name>/<version>/<filename>)",
 					packagePath)
 			}
+			if len(splits) == 2 || splits[2] == "" || isGlobPattern(splits[2]) || strings.HasSuffix(splits[2], "/") {
+				pattern := ""
+				if len(splits) == 3 {
+					pattern = splits[2]
+					if strings.HasSuffix(pattern, "/") {
+						pattern += "**"
+					}
+				}
+				pulled, err := pullGenericFiles(cmd.Context(), c.RegistryHttpClient(), registryName,
+					splits[0], splits[1], pattern, destinationPath, maxConcurrentDownloads)
+				if err != nil {
+					return err
+				}
+				options := printer.DefaultJsonOptions()
+				options.ShowPagination = false
+				return printer.PrintJsonWithOptions(pulled, options)
+			}
 
 			packageName := splits[0]
 			packageVersion := splits[1]
@@ -164,6 +195,8 @@ func NewPullGenericCmd(c *cmdutils.Factory) *cobra.Command {
 
 	// Add flags
 	cmd.Flags().StringVar(&pkgURL, "pkg-url", "", "Base URL for the Packages")
+	cmd.Flags().IntVar(&maxConcurrentDownloads, "max-concurrent-downloads", upload.DefaultUploadWorker,
+		"Maximum number of concurrent file downloads when pulling several files, 1 for sequential")
 
 	return cmd
 }
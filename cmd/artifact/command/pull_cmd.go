@@ -16,6 +16,11 @@ func NewPullArtifactCmd(c *cmdutils.Factory) *cobra.Command {
 
 	// Add subcommands for different package types
 	cmd.AddCommand(NewPullGenericCmd(c))
+	cmd.AddCommand(NewPullDockerCmd(c))
+	cmd.AddCommand(NewPullHuggingFaceCmd(c))
+	for _, format := range pullFormats {
+		cmd.AddCommand(NewPullPackageCmd(c, format))
+	}
 
 	return cmd
 }
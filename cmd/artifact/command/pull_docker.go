@@ -0,0 +1,199 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/harness/harness-cli/cmd/cmdutils"
+	"github.com/harness/harness-cli/util/common/printer"
+	p "github.com/harness/harness-cli/util/common/progress"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/spf13/cobra"
+)
+
+// defaultPullPlatform is the image pulled from a multi-arch index when no
+// platform is requested, matching what docker pulls on a typical host
+const defaultPullPlatform = "linux/amd64"
+
+// ociRefNameAnnotation names an image within an OCI layout
+const ociRefNameAnnotation = "org.opencontainers.image.ref.name"
+
+// pulledImage describes what pull docker wrote
+type pulledImage struct {
+	Reference string   `json:"reference"`
+	Digest    string   `json:"digest"`
+	Platforms []string `json:"platforms"`
+	Format    string   `json:"format"`
+	Path      string   `json:"path"`
+}
+
+// NewPullDockerCmd creates a new cobra.Command for pulling container images
+// without a Docker daemon.
+// command example: hc artifact pull docker <registry_name>/<image>:<tag> --output image.tar
+func NewPullDockerCmd(c *cmdutils.Factory) *cobra.Command {
+	const expectedNumberOfArgument = 1
+	var output, ociLayout, platform string
+	var allPlatforms bool
+	cmd := &cobra.Command{
+		Use:   "docker <registry_name>/<image>[:<tag>|@<digest>]",
+		Short: "Pull Docker Images",
+		Long: `Pull a container image from a Harness Docker registry into a docker-loadable
+tarball (--output) or an OCI image layout directory (--oci-layout), using the
+stored Harness credentials. From a multi-arch image one platform is pulled,
+` + defaultPullPlatform + ` unless --platform is set; --all-platforms keeps the whole index
+in an OCI layout.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != expectedNumberOfArgument {
+				return fmt.Errorf(
+					"Error: Invalid number of argument,  accepts %d arg(s), received %d  \nUsage :\n %s",
+					expectedNumberOfArgument, len(args), cmd.UseLine(),
+				)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			registryName, image, ok := strings.Cut(args[0], "/")
+			if !ok || registryName == "" || image == "" {
+				return fmt.Errorf("invalid image %q (expected format: <registry_name>/<image>:<tag>)", args[0])
+			}
+			if allPlatforms && output != "" {
+				return fmt.Errorf("--all-platforms needs --oci-layout: a docker tarball holds a single platform")
+			}
+			if allPlatforms && platform != "" {
+				return fmt.Errorf("--platform and --all-platforms cannot be combined")
+			}
+			if platform == "" {
+				platform = defaultPullPlatform
+			}
+			want, err := v1.ParsePlatform(platform)
+			if err != nil {
+				return fmt.Errorf("invalid platform %q: %w", platform, err)
+			}
+			progress := p.NewConsoleReporter()
+
+			reg, err := resolveDockerRegistry(cmd.Context(), c, registryName)
+			if err != nil {
+				return err
+			}
+			ref, err := reg.reference(image)
+			if err != nil {
+				return err
+			}
+
+			progress.Start(fmt.Sprintf("Pulling %s", ref))
+			desc, err := remote.Get(ref, append(reg.options, remote.WithPlatform(*want))...)
+			if err != nil {
+				progress.Error("Failed to fetch image")
+				return fmt.Errorf("failed to fetch %s: %w", ref, err)
+			}
+
+			result := pulledImage{Reference: ref.String()}
+			if allPlatforms && desc.MediaType.IsIndex() {
+				index, err := desc.ImageIndex()
+				if err != nil {
+					return err
+				}
+				if result.Platforms, err = indexPlatforms(index); err != nil {
+					return err
+				}
+				result.Digest = desc.Digest.String()
+				result.Format, result.Path = "oci-layout", ociLayout
+				progress.Step(fmt.Sprintf("Writing %d platform(s) to OCI layout %s", len(result.Platforms), ociLayout))
+				if err := writeOCILayout(ociLayout, ref, nil, index); err != nil {
+					progress.Error("Failed to write OCI layout")
+					return err
+				}
+			} else {
+				// Image resolves an index to the requested platform
+				img, err := desc.Image()
+				if err != nil {
+					progress.Error("Failed to resolve image")
+					return fmt.Errorf("%s: %w", ref, err)
+				}
+				digest, err := img.Digest()
+				if err != nil {
+					return err
+				}
+				result.Digest = digest.String()
+				if cfg, err := img.ConfigFile(); err == nil && cfg.Platform() != nil {
+					result.Platforms = []string{cfg.Platform().String()}
+				}
+				if output != "" {
+					result.Format, result.Path = "docker-archive", output
+					progress.Step(fmt.Sprintf("Writing docker tarball %s", output))
+					if err := tarball.WriteToFile(output, ref, img); err != nil {
+						os.Remove(output)
+						progress.Error("Failed to write tarball")
+						return fmt.Errorf("failed to write %s: %w", output, err)
+					}
+				} else {
+					result.Format, result.Path = "oci-layout", ociLayout
+					progress.Step(fmt.Sprintf("Writing OCI layout %s", ociLayout))
+					if err := writeOCILayout(ociLayout, ref, img, nil); err != nil {
+						progress.Error("Failed to write OCI layout")
+						return err
+					}
+				}
+			}
+			progress.Success(fmt.Sprintf("Pulled %s (%s)", ref, result.Digest))
+
+			options := printer.DefaultJsonOptions()
+			options.ShowPagination = false
+			return printer.PrintJsonWithOptions(result, options)
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write a docker-loadable tarball to this file")
+	cmd.Flags().StringVar(&ociLayout, "oci-layout", "", "Write the image to this OCI image layout directory")
+	cmd.Flags().StringVar(&platform, "platform", "",
+		"Platform (os/arch[/variant]) to pull from a multi-arch image (default "+defaultPullPlatform+")")
+	cmd.Flags().BoolVar(&allPlatforms, "all-platforms", false,
+		"Pull every platform of a multi-arch image (requires --oci-layout)")
+	cmd.MarkFlagsOneRequired("output", "oci-layout")
+	cmd.MarkFlagsMutuallyExclusive("output", "oci-layout")
+
+	return cmd
+}
+
+// indexPlatforms lists the platforms of an index's images
+func indexPlatforms(index v1.ImageIndex) ([]string, error) {
+	manifest, err := index.IndexManifest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index: %w", err)
+	}
+	var platforms []string
+	for _, desc := range manifest.Manifests {
+		if desc.Platform != nil {
+			platforms = append(platforms, desc.Platform.String())
+		}
+	}
+	return platforms, nil
+}
+
+// writeOCILayout appends an image or index to the OCI layout at dir, creating
+// the layout if needed, and names it after the reference
+func writeOCILayout(dir string, ref name.Reference, img v1.Image, index v1.ImageIndex) error {
+	l, err := layout.FromPath(dir)
+	if err != nil {
+		if l, err = layout.Write(dir, empty.Index); err != nil {
+			return fmt.Errorf("failed to create OCI layout %s: %w", dir, err)
+		}
+	}
+	annotations := layout.WithAnnotations(map[string]string{ociRefNameAnnotation: ref.Identifier()})
+	if index != nil {
+		err = l.AppendIndex(index, annotations)
+	} else {
+		err = l.AppendImage(img, annotations)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write OCI layout %s: %w", dir, err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package command
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/harness/harness-cli/cmd/cmdutils"
+
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runPullDocker(t *testing.T, f *cmdutils.Factory, args ...string) error {
+	t.Helper()
+	cmd := NewPullDockerCmd(f)
+	cmd.SetArgs(args)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	return cmd.Execute()
+}
+
+func TestPullDockerTarballPlatform(t *testing.T) {
+	f, prefix := dockerRegistryServer(t)
+	_, idx, arm := multiArchLayout(t)
+	require.NoError(t, remote.WriteIndex(mustRef(t, prefix+"/app:1.0"), idx))
+
+	out := filepath.Join(t.TempDir(), "app.tar")
+	require.NoError(t, runPullDocker(t, f, "docker-reg/app:1.0", "--output", out, "--platform", "linux/arm64"))
+
+	img, err := tarball.ImageFromPath(out, nil)
+	require.NoError(t, err)
+	got, err := img.ConfigName()
+	require.NoError(t, err)
+	want, _ := arm.ConfigName()
+	assert.Equal(t, want, got)
+
+	manifest, err := tarball.LoadManifest(func() (io.ReadCloser, error) { return os.Open(out) })
+	require.NoError(t, err)
+	assert.Equal(t, []string{prefix + "/app:1.0"}, manifest[0].RepoTags)
+}
+
+func TestPullDockerAllPlatformsLayout(t *testing.T) {
+	f, prefix := dockerRegistryServer(t)
+	_, idx, _ := multiArchLayout(t)
+	require.NoError(t, remote.WriteIndex(mustRef(t, prefix+"/app:1.0"), idx))
+
+	dir := filepath.Join(t.TempDir(), "layout")
+	require.NoError(t, runPullDocker(t, f, "docker-reg/app:1.0", "--oci-layout", dir, "--all-platforms"))
+
+	index, err := layout.ImageIndexFromPath(dir)
+	require.NoError(t, err)
+	manifest, err := index.IndexManifest()
+	require.NoError(t, err)
+	require.Len(t, manifest.Manifests, 1)
+	want, _ := idx.Digest()
+	assert.Equal(t, want, manifest.Manifests[0].Digest)
+	assert.Equal(t, "1.0", manifest.Manifests[0].Annotations[ociRefNameAnnotation])
+
+	// the layout round-trips through push docker
+	require.NoError(t, runPushDocker(t, f, "docker-reg", dir, "--tag", "app:copy"))
+	copied, err := remote.Index(mustRef(t, prefix+"/app:copy"))
+	require.NoError(t, err)
+	got, _ := copied.Digest()
+	assert.Equal(t, want, got)
+}
+
+func TestPullDockerAllPlatformsNeedsLayout(t *testing.T) {
+	f, _ := dockerRegistryServer(t)
+	err := runPullDocker(t, f, "docker-reg/app:1.0", "--output", filepath.Join(t.TempDir(), "a.tar"), "--all-platforms")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--all-platforms needs --oci-layout")
+}
@@ -0,0 +1,316 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/harness/harness-cli/cmd/artifact/command/utils"
+	"github.com/harness/harness-cli/config"
+	ar "github.com/harness/harness-cli/internal/api/ar"
+	"github.com/harness/harness-cli/util/common"
+	"github.com/harness/harness-cli/util/common/httpclient"
+	p "github.com/harness/harness-cli/util/common/progress"
+
+	"github.com/pterm/pterm"
+)
+
+// partialSuffix marks a file whose download has not completed yet; a later
+// pull resumes it with a range request
+const partialSuffix = ".part"
+
+// isGlobPattern reports whether a generic file path selects files by pattern
+func isGlobPattern(s string) bool {
+	return strings.ContainsAny(s, "*?[")
+}
+
+// matchGenericPath matches a slash-separated file path against a pattern
+// where "**" matches any number of directories and every other segment
+// follows path.Match
+func matchGenericPath(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// genericFilePath is the path of a listed generic file relative to its version
+func genericFilePath(file ar.FileDetail) string {
+	return strings.TrimPrefix(file.Name, "/")
+}
+
+// genericDownload is one file of a recursive generic pull
+type genericDownload struct {
+	file   ar.FileDetail
+	rel    string
+	url    string
+	target string
+}
+
+// genericDownloadResult reports the outcome of one genericDownload
+type genericDownloadResult struct {
+	pulledFile
+	err     error
+	skipped bool
+}
+
+// pullGenericFiles pulls every file of a generic version whose path matches
+// pattern ("" for all files) into destination, keeping the directory layout
+// the files were pushed with
+func pullGenericFiles(
+	ctx context.Context,
+	client *ar.ClientWithResponses,
+	registryName, packageName, version, pattern, destination string,
+	workers int,
+) ([]pulledFile, error) {
+	progress := p.NewConsoleReporter()
+	progress.Start(fmt.Sprintf("Listing files of %s/%s in registry '%s'", packageName, version, registryName))
+	files, err := listVersionFiles(ctx, client, registryName, packageName, version)
+	if err != nil {
+		progress.Error("Failed to list files")
+		return nil, err
+	}
+
+	var downloads []genericDownload
+	for _, file := range files {
+		rel := genericFilePath(file)
+		if pattern != "" && !matchGenericPath(pattern, rel) {
+			continue
+		}
+		target, err := safeJoin(destination, rel)
+		if err != nil {
+			return nil, err
+		}
+		downloads = append(downloads, genericDownload{
+			file:   file,
+			rel:    rel,
+			url:    genericFileURL(registryName, packageName, version, rel),
+			target: target,
+		})
+	}
+	if len(downloads) == 0 {
+		progress.Error("No files found")
+		if pattern != "" {
+			return nil, fmt.Errorf("no file of %s/%s in registry '%s' matches %s",
+				packageName, version, registryName, pattern)
+		}
+		return nil, fmt.Errorf("%s/%s in registry '%s' has no files", packageName, version, registryName)
+	}
+
+	results := runGenericDownloads(ctx, downloads, workers, progress)
+	pulled, err := collectDownloadResults(results, progress)
+	if err != nil {
+		return pulled, err
+	}
+	progress.Success(fmt.Sprintf("Pulled %d file(s) of %s/%s into %s", len(pulled), packageName, version, destination))
+	return pulled, nil
+}
+
+// collectDownloadResults returns the files pulled, listing the failed
+// downloads and failing when there are any
+func collectDownloadResults(results []genericDownloadResult, progress p.Reporter) ([]pulledFile, error) {
+	pulled := make([]pulledFile, 0, len(results))
+	var failed []genericDownloadResult
+	skipped := 0
+	for _, r := range results {
+		if r.err != nil {
+			failed = append(failed, r)
+			continue
+		}
+		if r.skipped {
+			skipped++
+		}
+		pulled = append(pulled, r.pulledFile)
+	}
+	if len(failed) > 0 {
+		fmt.Println("\nFailed downloads:")
+		for _, r := range failed {
+			fmt.Printf("  - %s: %v\n", r.Name, r.err)
+		}
+		return pulled, fmt.Errorf("%d of %d file(s) failed to download", len(failed), len(results))
+	}
+	if skipped > 0 {
+		progress.Step(fmt.Sprintf("Skipped %d file(s) already present with a matching checksum", skipped))
+	}
+	return pulled, nil
+}
+
+// runGenericDownloads downloads the files concurrently, reporting completed
+// files on a progress bar, and returns one result per download
+func runGenericDownloads(
+	ctx context.Context,
+	downloads []genericDownload,
+	workers int,
+	progress p.Reporter,
+) []genericDownloadResult {
+	if workers <= 0 || workers > len(downloads) {
+		workers = len(downloads)
+	}
+	progress.Step(fmt.Sprintf("Starting download: %d files with %d workers. Please wait ....", len(downloads), workers))
+
+	progressBar, _ := pterm.DefaultProgressbar.WithTotal(len(downloads)).WithTitle("Downloading files").Start()
+	defer progressBar.Stop()
+
+	startTime := time.Now()
+	jobs := make(chan genericDownload, len(downloads))
+	for _, d := range downloads {
+		jobs <- d
+	}
+	close(jobs)
+
+	results := make([]genericDownloadResult, 0, len(downloads))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for d := range jobs {
+				r := genericDownloadResult{pulledFile: pulledFile{Name: d.rel, Path: d.target}}
+				if err := ctx.Err(); err != nil {
+					r.err = err
+				} else {
+					var size int64
+					size, r.Checksum, r.skipped, r.err = downloadGenericFile(ctx, d)
+					r.Size = common.GetSize(size)
+				}
+
+				mu.Lock()
+				results = append(results, r)
+				progressBar.UpdateTitle(fmt.Sprintf("Downloading files (%d/%d completed)", len(results), len(downloads)))
+				progressBar.Increment()
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	progress.Step(fmt.Sprintf("Processed %d files in %v", len(results), time.Since(startTime).Round(time.Millisecond)))
+	return results
+}
+
+// downloadGenericFile downloads one file to its target, verifying it against
+// the registry checksums. A file already present with a matching checksum is
+// skipped, and a partial download left by an earlier pull is resumed.
+func downloadGenericFile(ctx context.Context, d genericDownload) (int64, string, bool, error) {
+	expected := parseFileChecksums(d.file.Checksums)
+	if expected != (utils.FileChecksums{}) {
+		if info, err := os.Stat(d.target); err == nil && info.Mode().IsRegular() {
+			if checksum, err := verifyFileChecksums(d.target, expected); err == nil {
+				return info.Size(), checksum, true, nil
+			}
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(d.target), 0755); err != nil {
+		return 0, "", false, fmt.Errorf("failed to create destination directory: %w", err)
+	}
+	partial := d.target + partialSuffix
+	if err := resumeDownload(ctx, d.url, partial); err != nil {
+		return 0, "", false, err
+	}
+
+	checksum, err := verifyFileChecksums(partial, expected)
+	if err != nil {
+		os.Remove(partial)
+		return 0, "", false, err
+	}
+	info, err := os.Stat(partial)
+	if err != nil {
+		return 0, "", false, err
+	}
+	if err := os.Rename(partial, d.target); err != nil {
+		return 0, "", false, fmt.Errorf("failed to move %s into place: %w", d.target, err)
+	}
+	return info.Size(), checksum, false, nil
+}
+
+// genericFileURL returns the download URL of a generic file. The URL is built
+// directly, as the generated client would encode the slashes of a nested
+// file path as %2F; each segment is escaped on its own instead.
+func genericFileURL(registryName, packageName, version, rel string) string {
+	segments := strings.Split(rel, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return fmt.Sprintf("%s/pkg/%s/%s/files/%s/%s/%s",
+		strings.TrimRight(config.Global.Registry.PkgURL, "/"), config.Global.AccountID, registryName,
+		url.PathEscape(packageName), url.PathEscape(version), strings.Join(segments, "/"))
+}
+
+// resumeDownload downloads rawURL into partial, continuing from the bytes a
+// previous attempt already wrote when the server honours range requests
+func resumeDownload(ctx context.Context, rawURL, partial string) error {
+	var offset int64
+	if info, err := os.Stat(partial); err == nil {
+		offset = info.Size()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	setPkgAuthHeaders(req)
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := httpclient.NewRetryClientWithoutProgress().Do(req)
+	if err != nil {
+		return fmt.Errorf("download request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		flags |= os.O_APPEND
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		// the partial file already holds every byte; verification decides
+		// whether it is usable
+		return nil
+	case resp.StatusCode >= 400:
+		return fmt.Errorf("server returned %s", resp.Status)
+	default:
+		// the server sent the whole file
+		flags |= os.O_TRUNC
+	}
+
+	out, err := os.OpenFile(partial, flags, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create destination file: %w", err)
+	}
+	_, err = io.Copy(out, resp.Body)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", partial, err)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package command
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/harness/harness-cli/config"
+	"github.com/harness/harness-cli/internal/api/ar"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// genericFilesServer lists the given file -> content map as the files of
+// app/1.0 in registry "reg" and serves them from the generic file endpoint,
+// honouring range requests. It records the Range header of every download.
+func genericFilesServer(t *testing.T, files map[string]string) (*httptest.Server, map[string]string) {
+	t.Helper()
+	const prefix = "/pkg/acct/reg/files/app/1.0/"
+	var mu sync.Mutex
+	ranges := map[string]string{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if name, ok := strings.CutPrefix(r.URL.Path, prefix); ok {
+			assert.Equal(t, "token", r.Header.Get("x-api-key"))
+			content, found := files[name]
+			if !found {
+				http.NotFound(w, r)
+				return
+			}
+			mu.Lock()
+			ranges[name] = r.Header.Get("Range")
+			mu.Unlock()
+			http.ServeContent(w, r, name, time.Time{}, strings.NewReader(content))
+			return
+		}
+		require.True(t, strings.HasSuffix(r.URL.Path, "/artifact/app/+/version/1.0/files"), "unexpected path %s", r.URL.Path)
+		var details []ar.FileDetail
+		for name, content := range files {
+			sum := sha256.Sum256([]byte(content))
+			details = append(details, ar.FileDetail{
+				Name:      "/" + name,
+				Checksums: []string{"SHA-256: " + hex.EncodeToString(sum[:])},
+			})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ar.FileDetailResponse{Data: ar.ListFileDetail{Files: details}, Status: "SUCCESS"})
+	}))
+	t.Cleanup(ts.Close)
+
+	origPkgURL := config.Global.Registry.PkgURL
+	config.Global.Registry.PkgURL = ts.URL
+	t.Cleanup(func() { config.Global.Registry.PkgURL = origPkgURL })
+	return ts, ranges
+}
+
+func TestMatchGenericPath(t *testing.T) {
+	cases := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"build/**/*.so", "build/libz.so", true},
+		{"build/**/*.so", "build/linux/amd64/libz.so", true},
+		{"build/**/*.so", "build/linux/libz.a", false},
+		{"build/**/*.so", "src/libz.so", false},
+		{"*.txt", "notes.txt", true},
+		{"*.txt", "docs/notes.txt", false},
+		{"**/*.txt", "docs/notes.txt", true},
+		{"docs/**", "docs/a/b.md", true},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.want, matchGenericPath(c.pattern, c.name), "%s ~ %s", c.pattern, c.name)
+	}
+}
+
+func TestGenericFileURLEscapesSegments(t *testing.T) {
+	withGlobalConfig(t, "acct", "", "")
+	origPkgURL := config.Global.Registry.PkgURL
+	config.Global.Registry.PkgURL = "https://pkg.example.com/"
+	t.Cleanup(func() { config.Global.Registry.PkgURL = origPkgURL })
+
+	assert.Equal(t, "https://pkg.example.com/pkg/acct/bin/files/my%20app/1.0+build/docs/release%20notes%231.md",
+		genericFileURL("bin", "my app", "1.0+build", "docs/release notes#1.md"))
+}
+
+func TestPullGenericGlobKeepsLayout(t *testing.T) {
+	withPullConfig(t)
+	ts, _ := genericFilesServer(t, map[string]string{
+		"build/linux/libz.so": "linux",
+		"build/mac/libz.so":   "mac",
+		"build/libz.a":        "static",
+		"README.md":           "readme",
+	})
+	dest := t.TempDir()
+
+	cmd := NewPullGenericCmd(newPullTestFactory(t, ts))
+	cmd.SetArgs([]string{"reg", "app/1.0/build/**/*.so", dest})
+	cmd.SetOut(io.Discard)
+	require.NoError(t, cmd.Execute())
+
+	data, err := os.ReadFile(filepath.Join(dest, "build", "linux", "libz.so"))
+	require.NoError(t, err)
+	assert.Equal(t, "linux", string(data))
+	assert.FileExists(t, filepath.Join(dest, "build", "mac", "libz.so"))
+	assert.NoFileExists(t, filepath.Join(dest, "build", "libz.a"))
+	assert.NoFileExists(t, filepath.Join(dest, "README.md"))
+}
+
+func TestPullGenericVersionResumesPartialFile(t *testing.T) {
+	withPullConfig(t)
+	big := strings.Repeat("0123456789", 1000)
+	ts, ranges := genericFilesServer(t, map[string]string{
+		"data/big.bin": big,
+		"README.md":    "readme",
+	})
+	dest := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dest, "data"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dest, "data", "big.bin"+partialSuffix), []byte(big[:4000]), 0644))
+
+	cmd := NewPullGenericCmd(newPullTestFactory(t, ts))
+	cmd.SetArgs([]string{"reg", "app/1.0", dest})
+	cmd.SetOut(io.Discard)
+	require.NoError(t, cmd.Execute())
+
+	assert.Equal(t, "bytes=4000-", ranges["data/big.bin"])
+	assert.Empty(t, ranges["README.md"])
+	data, err := os.ReadFile(filepath.Join(dest, "data", "big.bin"))
+	require.NoError(t, err)
+	assert.True(t, bytes.Equal([]byte(big), data), "resumed file differs from the original")
+	assert.NoFileExists(t, filepath.Join(dest, "data", "big.bin"+partialSuffix))
+}
+
+func TestPullGenericSkipsUnchangedFiles(t *testing.T) {
+	withPullConfig(t)
+	ts, ranges := genericFilesServer(t, map[string]string{"a.txt": "same", "b.txt": "new"})
+	dest := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dest, "a.txt"), []byte("same"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dest, "b.txt"), []byte("old"), 0644))
+
+	cmd := NewPullGenericCmd(newPullTestFactory(t, ts))
+	cmd.SetArgs([]string{"reg", "app/1.0/", dest})
+	cmd.SetOut(io.Discard)
+	require.NoError(t, cmd.Execute())
+
+	_, downloaded := ranges["a.txt"]
+	assert.False(t, downloaded, "a file with a matching checksum must not be downloaded again")
+	data, err := os.ReadFile(filepath.Join(dest, "b.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(data))
+}
@@ -0,0 +1,128 @@
+package command
+
+import (
+	"fmt"
+
+	"github.com/harness/harness-cli/cmd/cmdutils"
+	"github.com/harness/harness-cli/config"
+	"github.com/harness/harness-cli/internal/api/ar"
+	"github.com/harness/harness-cli/util"
+	"github.com/harness/harness-cli/util/common/printer"
+	p "github.com/harness/harness-cli/util/common/progress"
+	"github.com/harness/harness-cli/util/common/upload"
+
+	"github.com/spf13/cobra"
+)
+
+// pulledHFRepo describes the snapshot written by pull huggingface
+type pulledHFRepo struct {
+	Repository string       `json:"repository"`
+	Type       string       `json:"type"`
+	Revision   string       `json:"revision"`
+	Commit     string       `json:"commit,omitempty"`
+	Path       string       `json:"path"`
+	Files      []pulledFile `json:"files"`
+}
+
+// NewPullHuggingFaceCmd creates a new cobra.Command for pulling a snapshot of
+// a HuggingFace model or dataset repository.
+// command example: hc artifact pull huggingface <registry_name> <destination> --name <org>/<model>
+//
+// Every file of the revision is downloaded into the destination with the
+// repository's layout. LFS files are verified against their sha256, files
+// already present are skipped and interrupted downloads are resumed.
+func NewPullHuggingFaceCmd(c *cmdutils.Factory) *cobra.Command {
+	const expectedNumberOfArgument = 2
+	var name, revision, artifactType, pkgURL string
+	var maxConcurrentDownloads int
+	cmd := &cobra.Command{
+		Use:   "huggingface <registry_name> <destination>",
+		Short: "Pull HuggingFace Models and Datasets",
+		Long: `Pull a snapshot of a HuggingFace model or dataset repository at a revision from a
+Harness HuggingFace registry into a local directory, which can then be loaded
+with from_pretrained or load_dataset without the public hub. Files already
+downloaded are skipped and interrupted downloads resume.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != expectedNumberOfArgument {
+				return fmt.Errorf(
+					"Error: Invalid number of argument,  accepts %d arg(s), received %d  \nUsage :\n %s",
+					expectedNumberOfArgument, len(args), cmd.UseLine(),
+				)
+			}
+			return nil
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			if pkgURL != "" {
+				config.Global.Registry.PkgURL = util.GetPkgUrl(pkgURL)
+			}
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			registryName, destination := args[0], args[1]
+			ctx := cmd.Context()
+			progress := p.NewConsoleReporter()
+
+			repo, err := newHFRepo(newPkgUploader(progress), registryName, artifactType, name, revision)
+			if err != nil {
+				return err
+			}
+
+			progress.Start(fmt.Sprintf("Listing files of %s@%s in registry '%s'", name, revision, registryName))
+			commit, siblings, err := repo.revisionInfo(ctx)
+			if err != nil {
+				progress.Error("Failed to list files")
+				return err
+			}
+			if len(siblings) == 0 {
+				progress.Error("No files found")
+				return fmt.Errorf("%s@%s in registry '%s' has no files", name, revision, registryName)
+			}
+
+			downloads := make([]genericDownload, 0, len(siblings))
+			for _, s := range siblings {
+				target, err := safeJoin(destination, s.Name)
+				if err != nil {
+					return err
+				}
+				file := ar.FileDetail{Name: s.Name}
+				if s.LFS != nil && s.LFS.SHA256 != "" {
+					file.Checksums = []string{"SHA-256: " + s.LFS.SHA256}
+				}
+				downloads = append(downloads, genericDownload{
+					file:   file,
+					rel:    s.Name,
+					url:    repo.resolveURL(s.Name),
+					target: target,
+				})
+			}
+
+			results := runGenericDownloads(ctx, downloads, maxConcurrentDownloads, progress)
+			pulled, err := collectDownloadResults(results, progress)
+			if err != nil {
+				return err
+			}
+			progress.Success(fmt.Sprintf("Pulled %d file(s) of %s@%s into %s", len(pulled), name, revision,
+				destination))
+
+			options := printer.DefaultJsonOptions()
+			options.ShowPagination = false
+			return printer.PrintJsonWithOptions(pulledHFRepo{
+				Repository: name,
+				Type:       artifactType,
+				Revision:   revision,
+				Commit:     commit,
+				Path:       destination,
+				Files:      pulled,
+			}, options)
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "Repository name <org>/<name>")
+	cmd.Flags().StringVar(&revision, "revision", "main", "Revision (branch, tag or commit) to pull")
+	cmd.Flags().StringVar(&artifactType, "artifact-type", hfModel, "Repository type: model or dataset")
+	cmd.Flags().StringVar(&pkgURL, "pkg-url", "", "Base URL for the Packages")
+	cmd.Flags().IntVar(&maxConcurrentDownloads, "max-concurrent-downloads", upload.DefaultUploadWorker,
+		"Maximum number of files to download in parallel")
+	cmd.MarkFlagRequired("name")
+
+	return cmd
+}
@@ -0,0 +1,49 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullHuggingFaceSnapshot(t *testing.T) {
+	s := newHFServer(t)
+	weights := strings.Repeat("w", 64)
+	s.files["config.json"] = []byte("{}")
+	s.files["tokenizer/vocab.txt"] = []byte("a\nb\n")
+	s.files["model.safetensors"] = []byte(weights)
+	s.lfsPaths["model.safetensors"] = true
+	dest := t.TempDir()
+
+	require.NoError(t, runCobraCmd(t, NewPullHuggingFaceCmd(nil), "hf", dest, "--name", "org/model"))
+
+	for name, want := range map[string]string{
+		"config.json":         "{}",
+		"tokenizer/vocab.txt": "a\nb\n",
+		"model.safetensors":   weights,
+	} {
+		got, err := os.ReadFile(filepath.Join(dest, filepath.FromSlash(name)))
+		require.NoError(t, err, name)
+		assert.Equal(t, want, string(got), name)
+	}
+}
+
+func TestPullHuggingFaceResumesPartialDownload(t *testing.T) {
+	s := newHFServer(t)
+	weights := strings.Repeat("a", 32) + strings.Repeat("b", 32)
+	s.files["model.safetensors"] = []byte(weights)
+	s.lfsPaths["model.safetensors"] = true
+	dest := t.TempDir()
+	writeFile(t, dest, "model.safetensors"+partialSuffix, weights[:40])
+
+	require.NoError(t, runCobraCmd(t, NewPullHuggingFaceCmd(nil), "hf", dest, "--name", "org/model"))
+
+	got, err := os.ReadFile(filepath.Join(dest, "model.safetensors"))
+	require.NoError(t, err)
+	assert.Equal(t, weights, string(got))
+	assert.NoFileExists(t, filepath.Join(dest, "model.safetensors"+partialSuffix))
+}
@@ -0,0 +1,316 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/harness/harness-cli/cmd/artifact/command/utils"
+	"github.com/harness/harness-cli/cmd/cmdutils"
+	"github.com/harness/harness-cli/config"
+	ar "github.com/harness/harness-cli/internal/api/ar"
+	client2 "github.com/harness/harness-cli/util/client"
+	"github.com/harness/harness-cli/util/common"
+	"github.com/harness/harness-cli/util/common/auth"
+	"github.com/harness/harness-cli/util/common/httpclient"
+	"github.com/harness/harness-cli/util/common/pagination"
+	"github.com/harness/harness-cli/util/common/printer"
+	p "github.com/harness/harness-cli/util/common/progress"
+
+	"github.com/spf13/cobra"
+)
+
+// pullFormat describes how the files of one package type are laid out locally
+type pullFormat struct {
+	name string
+	// packageHelp describes the package_name argument
+	packageHelp string
+	// layout returns the slash-separated path, relative to the destination, a
+	// file of the package version is saved to
+	layout func(pkg, version, file string) string
+}
+
+// flatLayout saves every file directly in the destination directory
+func flatLayout(_, _, file string) string {
+	return file
+}
+
+// pullFormats lists the package types `hc artifact pull` supports besides
+// generic, which addresses files by path and has its own command
+var pullFormats = []pullFormat{
+	{
+		name:        "maven",
+		packageHelp: "<groupId>:<artifactId>",
+		// Maven repository layout: <group path>/<artifactId>/<version>/<file>
+		layout: func(pkg, version, file string) string {
+			groupID, artifactID, ok := strings.Cut(pkg, ":")
+			if !ok {
+				return file
+			}
+			return path.Join(strings.ReplaceAll(groupID, ".", "/"), artifactID, version, file)
+		},
+	},
+	{name: "npm", packageHelp: "npm package name, e.g. @scope/name", layout: flatLayout},
+	{name: "python", packageHelp: "Python project name", layout: flatLayout},
+	{name: "nuget", packageHelp: "NuGet package ID", layout: flatLayout},
+	{
+		name:        "go",
+		packageHelp: "Go module path",
+		// GOPROXY layout: <module>/@v/<version>.{info,mod,zip}
+		layout: func(pkg, _, file string) string {
+			return path.Join(pkg, "@v", path.Base(file))
+		},
+	},
+	{name: "cargo", packageHelp: "crate name", layout: flatLayout},
+	{name: "conda", packageHelp: "conda package name", layout: flatLayout},
+	{name: "rpm", packageHelp: "RPM package name", layout: flatLayout},
+	{name: "debian", packageHelp: "Debian package name", layout: flatLayout},
+	{name: "helm", packageHelp: "chart name", layout: flatLayout},
+	{name: "dart", packageHelp: "Dart package name", layout: flatLayout},
+	{name: "composer", packageHelp: "<vendor>/<package>", layout: flatLayout},
+	{name: "puppet", packageHelp: "<author>-<module>", layout: flatLayout},
+	{name: "swift", packageHelp: "<scope>.<name>", layout: flatLayout},
+	{name: "conan", packageHelp: "Conan recipe name", layout: flatLayout},
+}
+
+// pulledFile is one downloaded file of a package version
+type pulledFile struct {
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Size     string `json:"size"`
+	Checksum string `json:"checksum"`
+}
+
+// NewPullPackageCmd creates the pull command of a package type.
+// command example: hc artifact pull npm <registry_name> <package_name> <version> <destination_path>
+func NewPullPackageCmd(c *cmdutils.Factory, format pullFormat) *cobra.Command {
+	const expectedNumberOfArgument = 4
+	cmd := &cobra.Command{
+		Use:   format.name + " <registry_name> <package_name> <version> <destination_path>",
+		Short: fmt.Sprintf("Pull %s Artifacts", strings.ToUpper(format.name[:1])+format.name[1:]),
+		Long: fmt.Sprintf(`Pull every file of a %s package version from Harness Artifact Registry into
+<destination_path>, using the format's native layout. package_name is the %s.
+Each file is verified against the checksum published by the registry.`, format.name, format.packageHelp),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != expectedNumberOfArgument {
+				return fmt.Errorf(
+					"Error: Invalid number of argument,  accepts %d arg(s), received %d  \nUsage :\n %s",
+					expectedNumberOfArgument, len(args), cmd.UseLine(),
+				)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			registryName, packageName, version, destinationPath := args[0], args[1], args[2], args[3]
+			progress := p.NewConsoleReporter()
+
+			progress.Start(fmt.Sprintf("Listing files of %s %s in registry '%s'", packageName, version, registryName))
+			files, err := listVersionFiles(cmd.Context(), c.RegistryHttpClient(), registryName, packageName, version)
+			if err != nil {
+				progress.Error("Failed to list files")
+				return err
+			}
+			if len(files) == 0 {
+				progress.Error("No files found")
+				return fmt.Errorf("%s %s in registry '%s' has no files", packageName, version, registryName)
+			}
+
+			pulled := make([]pulledFile, 0, len(files))
+			for _, file := range files {
+				rel := format.layout(packageName, version, file.Name)
+				progress.Step(fmt.Sprintf("Downloading %s", rel))
+				target, size, checksum, err := downloadVersionFile(cmd.Context(), registryName, packageName, version,
+					file, destinationPath, rel)
+				if err != nil {
+					progress.Error(fmt.Sprintf("Failed to download %s", file.Name))
+					return err
+				}
+				if checksum == "" {
+					progress.Step(fmt.Sprintf("No checksum published for %s, download not verified", file.Name))
+				}
+				pulled = append(pulled, pulledFile{
+					Name:     file.Name,
+					Path:     target,
+					Size:     common.GetSize(size),
+					Checksum: checksum,
+				})
+			}
+			progress.Success(fmt.Sprintf("Pulled %d file(s) of %s %s into %s",
+				len(pulled), packageName, version, destinationPath))
+
+			return printer.Print(pulled, 0, 1, int64(len(pulled)), false, [][]string{
+				{"name", "Name"},
+				{"path", "Path"},
+				{"size", "Size"},
+				{"checksum", "Checksum"},
+			})
+		},
+	}
+	return cmd
+}
+
+// errVersionNotFound is returned by listVersionFiles when the registry has no
+// such package version
+var errVersionNotFound = errors.New("version not found")
+
+// listVersionFiles returns every file of a package version, following pagination
+func listVersionFiles(
+	ctx context.Context,
+	client *ar.ClientWithResponses,
+	registryName, packageName, version string,
+) ([]ar.FileDetail, error) {
+	registryRef := client2.GetRef(config.Global.AccountID, config.Global.OrgID, config.Global.ProjectID,
+		registryName)
+	size := int64(pagination.DefaultPageSize)
+	fetch := func(ctx context.Context, page int64) (pagination.Page[ar.FileDetail], error) {
+		resp, err := client.GetArtifactFilesWithResponse(ctx, registryRef, packageName, version,
+			&ar.GetArtifactFilesParams{Page: &page, Size: &size})
+		if err != nil {
+			return pagination.Page[ar.FileDetail]{}, fmt.Errorf("failed to list files: %w", err)
+		}
+		if resp.StatusCode() == http.StatusNotFound {
+			return pagination.Page[ar.FileDetail]{}, fmt.Errorf("%s %s: %w", packageName, version,
+				errVersionNotFound)
+		}
+		if resp.JSON200 == nil {
+			return pagination.Page[ar.FileDetail]{}, fmt.Errorf("failed to list files: %s %s", resp.Status(),
+				string(resp.Body))
+		}
+		data := resp.JSON200.Data
+		return pagination.Page[ar.FileDetail]{Items: data.Files, PageCount: derefInt64(data.PageCount)}, nil
+	}
+	return pagination.Collect(ctx, fetch, pagination.Options{Concurrency: pagination.DefaultConcurrency})
+}
+
+// downloadVersionFile downloads a listed file to <destination>/<rel>, verifies
+// it against the registry checksums and returns the saved path, its size and
+// the checksum it was verified with. A file that fails verification is removed.
+func downloadVersionFile(
+	ctx context.Context,
+	registryName, packageName, version string,
+	file ar.FileDetail,
+	destination, rel string,
+) (string, int64, string, error) {
+	// the files endpoint serves the files of every package type by package,
+	// version and file name
+	rawURL := genericFileURL(registryName, packageName, version, file.Name)
+	target, err := safeJoin(destination, rel)
+	if err != nil {
+		return "", 0, "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return "", 0, "", fmt.Errorf("failed to create destination directory: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return "", 0, "", fmt.Errorf("failed to create request: %w", err)
+	}
+	setPkgAuthHeaders(req)
+	resp, err := httpclient.NewRetryClientWithoutProgress().Do(req)
+	if err != nil {
+		return "", 0, "", fmt.Errorf("failed to download %s: %w", file.Name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return "", 0, "", fmt.Errorf("failed to download %s: server returned %s", file.Name, resp.Status)
+	}
+
+	out, err := os.Create(target)
+	if err != nil {
+		return "", 0, "", fmt.Errorf("failed to create destination file: %w", err)
+	}
+	written, err := io.Copy(out, resp.Body)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(target)
+		return "", 0, "", fmt.Errorf("failed to write %s: %w", target, err)
+	}
+
+	checksum, err := verifyFileChecksums(target, parseFileChecksums(file.Checksums))
+	if err != nil {
+		os.Remove(target)
+		return "", 0, "", fmt.Errorf("%s: %w", file.Name, err)
+	}
+	return target, written, checksum, nil
+}
+
+// setPkgAuthHeaders authenticates a package download the way the package
+// client does
+func setPkgAuthHeaders(req *http.Request) {
+	if strings.HasPrefix(config.Global.AuthToken, auth.JWTTokenPrefix) {
+		req.Header.Set("Authorization", config.Global.AuthToken)
+	} else {
+		req.Header.Set("x-api-key", config.Global.AuthToken)
+	}
+	req.Header.Set("User-Agent", config.UserAgent())
+}
+
+// safeJoin joins a registry-provided relative path onto the destination,
+// refusing paths that would escape it
+func safeJoin(destination, rel string) (string, error) {
+	cleaned := path.Clean("/" + rel)
+	target := filepath.Join(destination, filepath.FromSlash(cleaned))
+	if cleaned == "/" {
+		return "", fmt.Errorf("invalid file path %q", rel)
+	}
+	return target, nil
+}
+
+// parseFileChecksums reads the "<ALGORITHM>: <hex>" checksums the registry
+// lists for a file
+func parseFileChecksums(checksums []string) utils.FileChecksums {
+	var c utils.FileChecksums
+	for _, entry := range checksums {
+		algo, value, ok := strings.Cut(entry, ":")
+		if !ok {
+			continue
+		}
+		value = strings.ToLower(strings.TrimSpace(value))
+		switch strings.ReplaceAll(strings.ToUpper(strings.TrimSpace(algo)), "-", "") {
+		case "MD5":
+			c.MD5 = value
+		case "SHA1":
+			c.SHA1 = value
+		case "SHA256":
+			c.SHA256 = value
+		case "SHA512":
+			c.SHA512 = value
+		}
+	}
+	return c
+}
+
+// verifyFileChecksums compares a downloaded file against the strongest
+// published checksum and returns it as "<algorithm>:<hex>", or "" when the
+// registry lists no checksum for the file
+func verifyFileChecksums(file string, expected utils.FileChecksums) (string, error) {
+	actual, err := utils.ComputeFileChecksums(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to compute checksums: %w", err)
+	}
+	for _, c := range []struct{ algo, want, got string }{
+		{"sha512", expected.SHA512, actual.SHA512},
+		{"sha256", expected.SHA256, actual.SHA256},
+		{"sha1", expected.SHA1, actual.SHA1},
+		{"md5", expected.MD5, actual.MD5},
+	} {
+		if c.want == "" {
+			continue
+		}
+		if c.want != c.got {
+			return "", fmt.Errorf("%s checksum mismatch: registry lists %s, downloaded file has %s",
+				c.algo, c.want, c.got)
+		}
+		return c.algo + ":" + c.got, nil
+	}
+	return "", nil
+}
@@ -0,0 +1,123 @@
+package command
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/harness/harness-cli/config"
+	"github.com/harness/harness-cli/internal/api/ar"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// filesServer answers the version files API with the given file -> content
+// map and serves each file under the package files endpoint, recording the
+// escaped paths requested. corrupt names a file whose listed checksum does
+// not match its content.
+func filesServer(t *testing.T, files map[string]string, corrupt string) (*httptest.Server, *[]string) {
+	t.Helper()
+	var downloads []string
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := strings.CutPrefix(r.URL.Path, "/pkg/acct/"); ok {
+			assert.Equal(t, "token", r.Header.Get("x-api-key"))
+			downloads = append(downloads, r.URL.EscapedPath())
+			_, _ = io.WriteString(w, files[path.Base(r.URL.Path)])
+			return
+		}
+		require.True(t, strings.HasSuffix(r.URL.Path, "/files"), "unexpected path %s", r.URL.Path)
+		var details []ar.FileDetail
+		for name, content := range files {
+			sum := sha256.Sum256([]byte(content))
+			if name == corrupt {
+				sum = sha256.Sum256([]byte("something else"))
+			}
+			details = append(details, ar.FileDetail{
+				Name:            name,
+				Checksums:       []string{"SHA-256: " + hex.EncodeToString(sum[:]), "MD5: ignored-when-sha256-listed"},
+				DownloadCommand: fmt.Sprintf("curl --location '%s/elsewhere/%s' --header 'x-api-key: <API_KEY>' -O", ts.URL, name),
+			})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ar.FileDetailResponse{Data: ar.ListFileDetail{Files: details}, Status: "SUCCESS"})
+	}))
+	t.Cleanup(ts.Close)
+	origPkgURL := config.Global.Registry.PkgURL
+	config.Global.Registry.PkgURL = ts.URL
+	t.Cleanup(func() { config.Global.Registry.PkgURL = origPkgURL })
+	return ts, &downloads
+}
+
+func formatByName(t *testing.T, name string) pullFormat {
+	t.Helper()
+	for _, f := range pullFormats {
+		if f.name == name {
+			return f
+		}
+	}
+	t.Fatalf("no pull format %s", name)
+	return pullFormat{}
+}
+
+func TestPullPackageMavenLayout(t *testing.T) {
+	withPullConfig(t)
+	ts, _ := filesServer(t, map[string]string{"app-1.0.jar": "jar", "app-1.0.pom": "<project/>"}, "")
+	dest := t.TempDir()
+
+	cmd := NewPullPackageCmd(newPullTestFactory(t, ts), formatByName(t, "maven"))
+	cmd.SetArgs([]string{"maven-reg", "com.acme:app", "1.0", dest})
+	cmd.SetOut(io.Discard)
+	require.NoError(t, cmd.Execute())
+
+	data, err := os.ReadFile(filepath.Join(dest, "com", "acme", "app", "1.0", "app-1.0.jar"))
+	require.NoError(t, err)
+	assert.Equal(t, "jar", string(data))
+	assert.FileExists(t, filepath.Join(dest, "com", "acme", "app", "1.0", "app-1.0.pom"))
+}
+
+func TestPullPackageChecksumMismatch(t *testing.T) {
+	withPullConfig(t)
+	ts, downloads := filesServer(t, map[string]string{"ui-1.0.0.tgz": "tarball"}, "ui-1.0.0.tgz")
+	dest := t.TempDir()
+
+	cmd := NewPullPackageCmd(newPullTestFactory(t, ts), formatByName(t, "npm"))
+	cmd.SetArgs([]string{"npm-reg", "@acme/ui", "1.0.0", dest})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "sha256 checksum mismatch")
+	assert.NoFileExists(t, filepath.Join(dest, "ui-1.0.0.tgz"))
+	// the URL is built from the package, version and file name, each escaped
+	assert.Equal(t, []string{"/pkg/acct/npm-reg/files/@acme%2Fui/1.0.0/ui-1.0.0.tgz"}, *downloads)
+}
+
+func TestPullFormatLayouts(t *testing.T) {
+	assert.Equal(t, "example.com/mod/@v/v1.2.0.zip", formatByName(t, "go").layout("example.com/mod", "v1.2.0", "v1.2.0.zip"))
+	assert.Equal(t, "app-1.0.jar", formatByName(t, "maven").layout("app", "1.0", "app-1.0.jar"))
+	assert.Equal(t, "requests-2.31.0.tar.gz", formatByName(t, "python").layout("requests", "2.31.0", "requests-2.31.0.tar.gz"))
+
+	// a registry-provided path never escapes the destination
+	target, err := safeJoin("/tmp/dest", "../../etc/passwd")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("/tmp/dest", "etc", "passwd"), target)
+}
+
+func TestParseFileChecksums(t *testing.T) {
+	c := parseFileChecksums([]string{"SHA-512: AA", "SHA-256: bb", "SHA-1: cc", "MD5: dd", "garbage"})
+	assert.Equal(t, "aa", c.SHA512)
+	assert.Equal(t, "bb", c.SHA256)
+	assert.Equal(t, "cc", c.SHA1)
+	assert.Equal(t, "dd", c.MD5)
+}
@@ -44,129 +44,133 @@ func NewPushCargoCmd(f *cmdutils.Factory) *cobra.Command {
 
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			registryName := args[0]
-			filePath := args[1]
-
 			// Create progress reporter
 			progress := p.NewConsoleReporter()
+			return pushCargo(cmd.Context(), newPkgUploader(progress), args[0], args[1], progress)
+		},
+	}
 
-			// Validate Registry Name and file_path
-			progress.Start("Validating input parameters")
-			if registryName == "" {
-				progress.Error("Registry name is required")
-				return errors.NewValidationError("registry_name", "registry name is required")
-			}
-			if filePath == "" {
-				progress.Error("File path is required")
-				return errors.NewValidationError("file_path", "file path is required")
-			}
+	cmd.Flags().StringVar(&pkgURL, "pkg-url", "", "Base URL for the Packages")
+	return cmd
+}
 
-			// Resolve file path (supports glob patterns like *.crate)
-			files, err := utils.ResolveFilePath(filePath, CargoFileExtension)
-			if err != nil {
-				progress.Error("Failed to resolve file path")
-				return err
-			}
-			filePath = files[0]
-			progress.Step(fmt.Sprintf("Uploading file: %s", filePath))
+// pushCargo uploads a crate
+func pushCargo(ctx context.Context, u pkgUploader, registryName, filePath string, progress p.Reporter) error {
+	// Validate Registry Name and file_path
+	progress.Start("Validating input parameters")
+	if registryName == "" {
+		progress.Error("Registry name is required")
+		return errors.NewValidationError("registry_name", "registry name is required")
+	}
+	if filePath == "" {
+		progress.Error("File path is required")
+		return errors.NewValidationError("file_path", "file path is required")
+	}
 
-			fileName := filepath.Base(filePath)
+	// Resolve file path (supports glob patterns like *.crate)
+	files, err := utils.ResolveFilePath(filePath, CargoFileExtension)
+	if err != nil {
+		progress.Error("Failed to resolve file path")
+		return err
+	}
+	filePath = files[0]
+	progress.Step(fmt.Sprintf("Uploading file: %s", filePath))
 
-			// Validate file exists
-			fileInfo, err := os.Stat(filePath)
-			if err != nil {
-				return errors.NewValidationError("file_path", fmt.Sprintf("failed to access package file: %v", err))
-			}
-			if fileInfo.IsDir() {
-				return errors.NewValidationError("file_path", "package file path must be a file, not a directory")
-			}
+	fileName := filepath.Base(filePath)
 
-			// validate file name
-			valid, err := fileutil.IsFilenameAcceptable(fileName, CargoFileExtension)
-			if !valid {
-				progress.Error("Invalid file name")
-				return errors.NewValidationError("file_path",
-					fmt.Sprintf("failed to validate package file name: %v", err))
-			}
+	// Validate file exists
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		return errors.NewValidationError("file_path", fmt.Sprintf("failed to access package file: %v", err))
+	}
+	if fileInfo.IsDir() {
+		return errors.NewValidationError("file_path", "package file path must be a file, not a directory")
+	}
 
-			metadata, err := getMetadataFromCrateFile(filePath)
+	// validate file name
+	valid, err := fileutil.IsFilenameAcceptable(fileName, CargoFileExtension)
+	if !valid {
+		progress.Error("Invalid file name")
+		return errors.NewValidationError("file_path",
+			fmt.Sprintf("failed to validate package file name: %v", err))
+	}
 
-			if err != nil {
-				progress.Error("Failed to get metadata from payload")
-				return err
-			}
+	metadata, err := getMetadataFromCrateFile(filePath)
 
-			packageName := metadata.Package.Name
-			version := metadata.Package.Version
+	if err != nil {
+		progress.Error("Failed to get metadata from payload")
+		return err
+	}
 
-			if len(packageName) == 0 {
-				return errors.NewValidationError("package_name", "Package name is not present in metadata")
-			}
-			if len(version) == 0 {
-				return errors.NewValidationError("version", "Version is not present in metadata")
-			}
-			file, err := os.Open(filePath)
-			if err != nil {
-				progress.Error("Failed to open package file")
-				return err
-			}
-			defer file.Close()
-			fileData, err := os.ReadFile(filePath)
+	packageName := metadata.Package.Name
+	version := metadata.Package.Version
 
-			if err != nil {
-				return fmt.Errorf("failed to read file: %v", err)
-			}
+	if len(packageName) == 0 {
+		return errors.NewValidationError("package_name", "Package name is not present in metadata")
+	}
+	if len(version) == 0 {
+		return errors.NewValidationError("version", "Version is not present in metadata")
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		progress.Error("Failed to open package file")
+		return err
+	}
+	defer file.Close()
+	fileData, err := os.ReadFile(filePath)
 
-			payload, err := makeCargoPackagePayload(packageName, version, fileData)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %v", err)
+	}
 
-			if err != nil {
-				return fmt.Errorf("failed to create package payload: %v", err)
-			}
+	payload, err := makeCargoPackagePayload(packageName, version, fileData)
 
-			// Compute checksums of the file for X-Checksum-* headers
-			checksums, err := utils.ComputeFileChecksums(filePath)
-			if err != nil {
-				progress.Error("Failed to compute file checksums")
-				return fmt.Errorf("failed to compute checksums for %s: %w", filePath, err)
-			}
+	if err != nil {
+		return fmt.Errorf("failed to create package payload: %v", err)
+	}
 
-			progress.Success("Input parameters validated")
-
-			bufferSize := int64(len(payload))
-			pkgClient := f.PkgHttpClientWithProgress(progress, bufferSize, "cargo")
-
-			// Initialize progress reader
-			progress.Step("Uploading package to registry")
-
-			resp, err := pkgClient.UploadCargoPackageWithBodyWithResponse(
-				context.Background(),
-				config.Global.AccountID,
-				registryName,
-				"application/octet-stream",
-				bytes.NewReader(payload),
-				func(ctx context.Context, req *http.Request) error {
-					utils.SetChecksumHeaders(req.Header, checksums)
-					return nil
-				},
-			)
-
-			if err != nil {
-				progress.Error("Failed to upload package")
-				return err
-			}
-			// Check response
-			if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusCreated {
-				progress.Error("Upload failed")
-				return fmt.Errorf("failed to push package: %s \n response: %s", resp.Status(), resp.Body)
-			}
+	// Compute checksums of the file for X-Checksum-* headers
+	checksums, err := utils.ComputeFileChecksums(filePath)
+	if err != nil {
+		progress.Error("Failed to compute file checksums")
+		return fmt.Errorf("failed to compute checksums for %s: %w", filePath, err)
+	}
 
-			progress.Success(fmt.Sprintf("Successfully uploaded package %s", filePath))
+	progress.Success("Input parameters validated")
+
+	bufferSize := int64(len(payload))
+	pkgClient, err := u.client(bufferSize, "cargo")
+	if err != nil {
+		return err
+	}
+
+	// Initialize progress reader
+	progress.Step("Uploading package to registry")
+
+	resp, err := pkgClient.UploadCargoPackageWithBodyWithResponse(
+		ctx,
+		config.Global.AccountID,
+		registryName,
+		"application/octet-stream",
+		bytes.NewReader(payload),
+		func(ctx context.Context, req *http.Request) error {
+			utils.SetChecksumHeaders(req.Header, checksums)
 			return nil
 		},
+	)
+
+	if err != nil {
+		progress.Error("Failed to upload package")
+		return err
+	}
+	// Check response
+	if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusCreated {
+		progress.Error("Upload failed")
+		return fmt.Errorf("failed to push package: %s \n response: %s", resp.Status(), resp.Body)
 	}
 
-	cmd.Flags().StringVar(&pkgURL, "pkg-url", "", "Base URL for the Packages")
-	return cmd
+	progress.Success(fmt.Sprintf("Successfully uploaded package %s", filePath))
+	return nil
 }
 
 func getMetadataFromCrateFile(filePath string) (*CargoPackageMetadata, error) {
@@ -1,7 +1,15 @@
 package command
 
 import (
+	"fmt"
+	"net/http"
+
 	"github.com/harness/harness-cli/cmd/cmdutils"
+	"github.com/harness/harness-cli/config"
+	"github.com/harness/harness-cli/internal/api/ar_pkg"
+	"github.com/harness/harness-cli/util/common/auth"
+	"github.com/harness/harness-cli/util/common/httpclient"
+	p "github.com/harness/harness-cli/util/common/progress"
 
 	"github.com/spf13/cobra"
 )
@@ -31,6 +39,49 @@ func NewPushArtifactCmd(f *cmdutils.Factory) *cobra.Command {
 	cmd.AddCommand(NewPushPuppetCmd(f))
 	cmd.AddCommand(NewPushDebianCmd(f))
 	cmd.AddCommand(NewPushConanCmd(f))
+	cmd.AddCommand(NewPushDockerCmd(f))
+	cmd.AddCommand(NewPushHelmCmd(f))
+	cmd.AddCommand(NewPushHuggingFaceCmd(f))
 
 	return cmd
 }
+
+// pkgUploader creates the clients a push sends its files to the packages
+// API at pkgURL with. Retries and failed requests are reported to progress,
+// and each upload draws a progress bar unless quiet, as when publish runs
+// several pushes at once.
+type pkgUploader struct {
+	pkgURL   string
+	progress p.Reporter
+	quiet    bool
+}
+
+// newPkgUploader returns the uploader of a push command, drawing the
+// progress of each upload
+func newPkgUploader(progress p.Reporter) pkgUploader {
+	return pkgUploader{pkgURL: config.Global.Registry.PkgURL, progress: progress}
+}
+
+// quietPkgUploader returns an uploader reporting nothing
+func quietPkgUploader(pkgURL string) pkgUploader {
+	return pkgUploader{pkgURL: pkgURL, progress: p.NewNopReporter(), quiet: true}
+}
+
+// httpClient returns the client to send an upload of size bytes with
+func (u pkgUploader) httpClient(size int64, saveFilename string) *http.Client {
+	if u.quiet {
+		// the bar is only drawn for a known size
+		size = 0
+	}
+	return httpclient.NewRetryClientWithProgress(u.progress, size, saveFilename)
+}
+
+// client returns the package client to send an upload of size bytes with
+func (u pkgUploader) client(size int64, saveFilename string) (*ar_pkg.ClientWithResponses, error) {
+	client, err := ar_pkg.NewClientWithResponses(u.pkgURL,
+		ar_pkg.WithHTTPClient(u.httpClient(size, saveFilename)), auth.GetAuthOptionARPKG())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create package client: %w", err)
+	}
+	return client, nil
+}
@@ -35,90 +35,95 @@ func NewPushComposerCmd(c *cmdutils.Factory) *cobra.Command {
 			}
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			registryName := args[0]
-			filePath := args[1]
-			fileName := filepath.Base(filePath)
-
 			// Create progress reporter
 			progress := p.NewConsoleReporter()
+			return pushComposer(cmd.Context(), newPkgUploader(progress), args[0], args[1], progress)
+		},
+	}
 
-			// Validate Registry Name and file_path
-			progress.Start("Validating input parameters")
-			if registryName == "" {
-				progress.Error("Registry name is required")
-				return errors.NewValidationError("registry_name", "registry name is required")
-			}
-			if filePath == "" {
-				progress.Error("File path is required")
-				return errors.NewValidationError("file_path", "file path is required")
-			}
+	cmd.Flags().StringVar(&pkgURL, "pkg-url", "", "Base URL for the Packages")
+	return cmd
+}
 
-			// Validate file exists
-			fileInfo, err := os.Stat(filePath)
-			if err != nil {
-				return errors.NewValidationError("file_path", fmt.Sprintf("failed to access package file: %v", err))
-			}
-			if fileInfo.IsDir() {
-				return errors.NewValidationError("file_path", "package file path must be a file, not a directory")
-			}
+// pushComposer uploads a Composer package archive
+func pushComposer(ctx context.Context, u pkgUploader, registryName, filePath string, progress p.Reporter) error {
+	fileName := filepath.Base(filePath)
 
-			// validate file name
-			valid, err := validateComposerFileName(fileName)
-			if !valid {
-				progress.Error("Invalid file name")
-				return errors.NewValidationError("file_path", fmt.Sprintf("failed to validate package file name: %v", err))
-			}
+	// Validate Registry Name and file_path
+	progress.Start("Validating input parameters")
+	if registryName == "" {
+		progress.Error("Registry name is required")
+		return errors.NewValidationError("registry_name", "registry name is required")
+	}
+	if filePath == "" {
+		progress.Error("File path is required")
+		return errors.NewValidationError("file_path", "file path is required")
+	}
+
+	// Validate file exists
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		return errors.NewValidationError("file_path", fmt.Sprintf("failed to access package file: %v", err))
+	}
+	if fileInfo.IsDir() {
+		return errors.NewValidationError("file_path", "package file path must be a file, not a directory")
+	}
 
-			progress.Success("Input parameters validated")
+	// validate file name
+	valid, err := validateComposerFileName(fileName)
+	if !valid {
+		progress.Error("Invalid file name")
+		return errors.NewValidationError("file_path", fmt.Sprintf("failed to validate package file name: %v", err))
+	}
 
-			// Compute checksums of the file for X-Checksum-* headers
-			checksums, err := utils.ComputeFileChecksums(filePath)
-			if err != nil {
-				progress.Error("Failed to compute file checksums")
-				return fmt.Errorf("failed to compute checksums for %s: %w", filePath, err)
-			}
+	progress.Success("Input parameters validated")
 
-			// Upload package
-			progress.Step("Uploading package to registry")
+	// Compute checksums of the file for X-Checksum-* headers
+	checksums, err := utils.ComputeFileChecksums(filePath)
+	if err != nil {
+		progress.Error("Failed to compute file checksums")
+		return fmt.Errorf("failed to compute checksums for %s: %w", filePath, err)
+	}
 
-			file, err := os.Open(filePath)
-			if err != nil {
-				progress.Error("Failed to open package file")
-				return err
-			}
-			defer file.Close()
-
-			pkgClient := c.PkgHttpClientWithProgress(progress, fileInfo.Size(), "composer")
-
-			resp, err := pkgClient.UploadComposerPackageWithBodyWithResponse(
-				context.Background(),
-				config.Global.AccountID,
-				registryName,
-				"application/octet-stream",
-				file,
-				func(ctx context.Context, req *http.Request) error {
-					utils.SetChecksumHeaders(req.Header, checksums)
-					return nil
-				},
-			)
-
-			if err != nil {
-				progress.Error("Failed to upload package")
-				return err
-			}
-			// Check response
-			if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusCreated {
-				progress.Error("Upload failed")
-				return fmt.Errorf("failed to push package: %s \n response: %s", resp.Status(), resp.Body)
-			}
+	// Upload package
+	progress.Step("Uploading package to registry")
 
-			progress.Success(fmt.Sprintf("Successfully uploaded package %s", filePath))
+	file, err := os.Open(filePath)
+	if err != nil {
+		progress.Error("Failed to open package file")
+		return err
+	}
+	defer file.Close()
+
+	pkgClient, err := u.client(fileInfo.Size(), "composer")
+	if err != nil {
+		return err
+	}
+
+	resp, err := pkgClient.UploadComposerPackageWithBodyWithResponse(
+		ctx,
+		config.Global.AccountID,
+		registryName,
+		"application/octet-stream",
+		file,
+		func(ctx context.Context, req *http.Request) error {
+			utils.SetChecksumHeaders(req.Header, checksums)
 			return nil
 		},
+	)
+
+	if err != nil {
+		progress.Error("Failed to upload package")
+		return err
+	}
+	// Check response
+	if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusCreated {
+		progress.Error("Upload failed")
+		return fmt.Errorf("failed to push package: %s \n response: %s", resp.Status(), resp.Body)
 	}
 
-	cmd.Flags().StringVar(&pkgURL, "pkg-url", "", "Base URL for the Packages")
-	return cmd
+	progress.Success(fmt.Sprintf("Successfully uploaded package %s", filePath))
+	return nil
 }
 
 func validateComposerFileName(fileName string) (bool, error) {
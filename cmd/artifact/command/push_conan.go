@@ -33,13 +33,18 @@ var (
 	conanPackageIDPattern = regexp.MustCompile(`^[a-f0-9]{40}$`)                 // PKGID
 )
 
+// conanPushOptions are the revisions and package layer of a Conan push
+type conanPushOptions struct {
+	recipeRevision  string
+	packageDir      string
+	packageID       string
+	packageRevision string
+}
+
 // NewPushConanCmd pushes a Conan package (v2 protocol): each recipe- and package-layer
 // file is PUT to its revision path, with conanmanifest.txt last (finalization marker).
 func NewPushConanCmd(c *cmdutils.Factory) *cobra.Command {
-	var recipeRevision string
-	var packageDir string
-	var packageID string
-	var packageRevision string
+	var opts conanPushOptions
 
 	cmd := &cobra.Command{
 		Use:   "conan <registry_name> <reference> <recipe_dir>",
@@ -59,113 +64,115 @@ func NewPushConanCmd(c *cmdutils.Factory) *cobra.Command {
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			registryName := args[0]
-			reference := args[1]
-			recipeDir := args[2]
-
 			progress := p.NewConsoleReporter()
+			return pushConan(cmd.Context(), newPkgUploader(progress), args[0], args[1], args[2], opts, progress)
+		},
+	}
 
-			progress.Start("Validating input parameters")
+	cmd.Flags().StringVar(&opts.recipeRevision, "recipe-revision", "", "Recipe revision (RREV). Defaults to the MD5 of conanmanifest.txt")
+	cmd.Flags().StringVar(&opts.packageDir, "package-dir", "", "Directory containing package-layer files (conaninfo.txt, conanmanifest.txt, conan_package.tgz)")
+	cmd.Flags().StringVar(&opts.packageID, "package-id", "", "Conan package id (PKGID); required with --package-dir")
+	cmd.Flags().StringVar(&opts.packageRevision, "package-revision", "", "Package revision (PREV). Defaults to the MD5 of the package's conanmanifest.txt")
 
-			ref, err := parseConanReference(reference)
-			if err != nil {
-				progress.Error("Invalid Conan reference")
-				return errors.NewValidationError("reference", err.Error())
-			}
+	return cmd
+}
 
-			// Unrecognised files (e.g. .DS_Store) are skipped to avoid a server 400.
-			recipeFiles, recipeSkipped, err := collectConanLayerFiles(recipeDir, conanutil.IsConanRecipeFile)
-			if err != nil {
-				progress.Error("Failed to read recipe directory")
-				return errors.NewValidationError("recipe_dir", err.Error())
-			}
-			if len(recipeSkipped) > 0 {
-				progress.Step(fmt.Sprintf("Skipping %d non-Conan file(s) in recipe dir: %s",
-					len(recipeSkipped), strings.Join(recipeSkipped, ", ")))
-			}
+// pushConan uploads the recipe layer of a Conan reference from recipeDir, and
+// its package layer when opts name a package directory
+func pushConan(ctx context.Context, u pkgUploader, registryName, reference, recipeDir string,
+	opts conanPushOptions, progress p.Reporter) error {
+	progress.Start("Validating input parameters")
 
-			// Default RREV to the MD5 of conanmanifest.txt.
-			if recipeRevision == "" {
-				recipeRevision, err = conanRevisionFromManifest(recipeDir)
-				if err != nil {
-					progress.Error("Failed to derive recipe revision")
-					return errors.NewValidationError("recipe-revision", err.Error())
-				}
-			}
-			if !conanRevisionPattern.MatchString(recipeRevision) {
-				progress.Error("Invalid recipe revision")
-				return errors.NewValidationError("recipe-revision",
-					fmt.Sprintf("recipe revision must be a 32-char MD5 or 40-char SHA, got: %s", recipeRevision))
-			}
+	ref, err := parseConanReference(reference)
+	if err != nil {
+		progress.Error("Invalid Conan reference")
+		return errors.NewValidationError("reference", err.Error())
+	}
 
-			// Validate package-layer inputs before uploading anything.
-			var packageFiles []string
-			if packageDir != "" {
-				if packageID == "" {
-					progress.Error("Missing package id")
-					return errors.NewValidationError("package-id", "--package-id is required when --package-dir is set")
-				}
-				if !conanPackageIDPattern.MatchString(packageID) {
-					progress.Error("Invalid package id")
-					return errors.NewValidationError("package-id",
-						fmt.Sprintf("package id must be a 40-char SHA-1, got: %s", packageID))
-				}
-				var packageSkipped []string
-				packageFiles, packageSkipped, err = collectConanLayerFiles(packageDir, conanutil.IsConanPackageFile)
-				if err != nil {
-					progress.Error("Failed to read package directory")
-					return errors.NewValidationError("package-dir", err.Error())
-				}
-				if len(packageSkipped) > 0 {
-					progress.Step(fmt.Sprintf("Skipping %d non-Conan file(s) in package dir: %s",
-						len(packageSkipped), strings.Join(packageSkipped, ", ")))
-				}
-				if packageRevision == "" {
-					packageRevision, err = conanRevisionFromManifest(packageDir)
-					if err != nil {
-						progress.Error("Failed to derive package revision")
-						return errors.NewValidationError("package-revision", err.Error())
-					}
-				}
-				if !conanRevisionPattern.MatchString(packageRevision) {
-					progress.Error("Invalid package revision")
-					return errors.NewValidationError("package-revision",
-						fmt.Sprintf("package revision must be a 32-char MD5 or 40-char SHA, got: %s", packageRevision))
-				}
-			}
+	// Unrecognised files (e.g. .DS_Store) are skipped to avoid a server 400.
+	recipeFiles, recipeSkipped, err := collectConanLayerFiles(recipeDir, conanutil.IsConanRecipeFile)
+	if err != nil {
+		progress.Error("Failed to read recipe directory")
+		return errors.NewValidationError("recipe_dir", err.Error())
+	}
+	if len(recipeSkipped) > 0 {
+		progress.Step(fmt.Sprintf("Skipping %d non-Conan file(s) in recipe dir: %s",
+			len(recipeSkipped), strings.Join(recipeSkipped, ", ")))
+	}
 
-			progress.Success(fmt.Sprintf("Validated Conan reference %s", ref.Display()))
+	// Default RREV to the MD5 of conanmanifest.txt.
+	if opts.recipeRevision == "" {
+		opts.recipeRevision, err = conanRevisionFromManifest(recipeDir)
+		if err != nil {
+			progress.Error("Failed to derive recipe revision")
+			return errors.NewValidationError("recipe-revision", err.Error())
+		}
+	}
+	if !conanRevisionPattern.MatchString(opts.recipeRevision) {
+		progress.Error("Invalid recipe revision")
+		return errors.NewValidationError("recipe-revision",
+			fmt.Sprintf("recipe revision must be a 32-char MD5 or 40-char SHA, got: %s", opts.recipeRevision))
+	}
 
-			// Recipe layer (manifest last).
-			progress.Step(fmt.Sprintf("Uploading recipe files (rrev %s)", recipeRevision))
-			for _, filePath := range orderConanFiles(recipeFiles) {
-				if err := uploadConanRecipeFile(c, registryName, ref, recipeRevision, filePath, progress); err != nil {
-					return err
-				}
+	// Validate package-layer inputs before uploading anything.
+	var packageFiles []string
+	if opts.packageDir != "" {
+		if opts.packageID == "" {
+			progress.Error("Missing package id")
+			return errors.NewValidationError("package-id", "--package-id is required when --package-dir is set")
+		}
+		if !conanPackageIDPattern.MatchString(opts.packageID) {
+			progress.Error("Invalid package id")
+			return errors.NewValidationError("package-id",
+				fmt.Sprintf("package id must be a 40-char SHA-1, got: %s", opts.packageID))
+		}
+		var packageSkipped []string
+		packageFiles, packageSkipped, err = collectConanLayerFiles(opts.packageDir, conanutil.IsConanPackageFile)
+		if err != nil {
+			progress.Error("Failed to read package directory")
+			return errors.NewValidationError("package-dir", err.Error())
+		}
+		if len(packageSkipped) > 0 {
+			progress.Step(fmt.Sprintf("Skipping %d non-Conan file(s) in package dir: %s",
+				len(packageSkipped), strings.Join(packageSkipped, ", ")))
+		}
+		if opts.packageRevision == "" {
+			opts.packageRevision, err = conanRevisionFromManifest(opts.packageDir)
+			if err != nil {
+				progress.Error("Failed to derive package revision")
+				return errors.NewValidationError("package-revision", err.Error())
 			}
+		}
+		if !conanRevisionPattern.MatchString(opts.packageRevision) {
+			progress.Error("Invalid package revision")
+			return errors.NewValidationError("package-revision",
+				fmt.Sprintf("package revision must be a 32-char MD5 or 40-char SHA, got: %s", opts.packageRevision))
+		}
+	}
 
-			// Package layer (manifest last), if requested.
-			if packageDir != "" {
-				progress.Step(fmt.Sprintf("Uploading package files (pkgid %s, prev %s)", packageID, packageRevision))
-				for _, filePath := range orderConanFiles(packageFiles) {
-					if err := uploadConanPackageFile(c, registryName, ref, recipeRevision, packageID,
-						packageRevision, filePath, progress); err != nil {
-						return err
-					}
-				}
-			}
+	progress.Success(fmt.Sprintf("Validated Conan reference %s", ref.Display()))
 
-			progress.Success(fmt.Sprintf("Successfully uploaded Conan package %s to registry '%s'", ref.Display(), registryName))
-			return nil
-		},
+	// Recipe layer (manifest last).
+	progress.Step(fmt.Sprintf("Uploading recipe files (rrev %s)", opts.recipeRevision))
+	for _, filePath := range orderConanFiles(recipeFiles) {
+		if err := uploadConanRecipeFile(ctx, u, registryName, ref, opts.recipeRevision, filePath, progress); err != nil {
+			return err
+		}
 	}
 
-	cmd.Flags().StringVar(&recipeRevision, "recipe-revision", "", "Recipe revision (RREV). Defaults to the MD5 of conanmanifest.txt")
-	cmd.Flags().StringVar(&packageDir, "package-dir", "", "Directory containing package-layer files (conaninfo.txt, conanmanifest.txt, conan_package.tgz)")
-	cmd.Flags().StringVar(&packageID, "package-id", "", "Conan package id (PKGID); required with --package-dir")
-	cmd.Flags().StringVar(&packageRevision, "package-revision", "", "Package revision (PREV). Defaults to the MD5 of the package's conanmanifest.txt")
+	// Package layer (manifest last), if requested.
+	if opts.packageDir != "" {
+		progress.Step(fmt.Sprintf("Uploading package files (pkgid %s, prev %s)", opts.packageID, opts.packageRevision))
+		for _, filePath := range orderConanFiles(packageFiles) {
+			if err := uploadConanPackageFile(ctx, u, registryName, ref, opts.recipeRevision, opts.packageID,
+				opts.packageRevision, filePath, progress); err != nil {
+				return err
+			}
+		}
+	}
 
-	return cmd
+	progress.Success(fmt.Sprintf("Successfully uploaded Conan package %s to registry '%s'", ref.Display(), registryName))
+	return nil
 }
 
 // parseConanReference parses name/version[@user/channel], defaulting absent
@@ -272,12 +279,13 @@ func conanRevisionFromManifest(dir string) (string, error) {
 
 // uploadConanRecipeFile PUTs a single recipe-layer file to its RREV path.
 func uploadConanRecipeFile(
-	c *cmdutils.Factory,
+	ctx context.Context,
+	u pkgUploader,
 	registryName string,
 	ref conanutil.ConanRef,
 	rrev string,
 	filePath string,
-	progress *p.ConsoleReporter,
+	progress p.Reporter,
 ) error {
 	fileName := filepath.Base(filePath)
 	file, checksums, size, err := openConanFile(filePath, progress)
@@ -287,9 +295,12 @@ func uploadConanRecipeFile(
 	defer file.Close()
 
 	progress.Step(fmt.Sprintf("Uploading %s", fileName))
-	client := c.PkgHttpClientWithProgress(progress, size, "conan")
+	client, err := u.client(size, "conan")
+	if err != nil {
+		return err
+	}
 	resp, err := client.UploadConanRecipeFileWithBodyWithResponse(
-		context.Background(),
+		ctx,
 		config.Global.AccountID,
 		registryName,
 		ref.Name,
@@ -318,14 +329,15 @@ func uploadConanRecipeFile(
 
 // uploadConanPackageFile PUTs a single package-layer file to its PKGID/PREV path.
 func uploadConanPackageFile(
-	c *cmdutils.Factory,
+	ctx context.Context,
+	u pkgUploader,
 	registryName string,
 	ref conanutil.ConanRef,
 	rrev string,
 	pkgID string,
 	prev string,
 	filePath string,
-	progress *p.ConsoleReporter,
+	progress p.Reporter,
 ) error {
 	fileName := filepath.Base(filePath)
 	file, checksums, size, err := openConanFile(filePath, progress)
@@ -335,9 +347,12 @@ func uploadConanPackageFile(
 	defer file.Close()
 
 	progress.Step(fmt.Sprintf("Uploading %s", fileName))
-	client := c.PkgHttpClientWithProgress(progress, size, "conan")
+	client, err := u.client(size, "conan")
+	if err != nil {
+		return err
+	}
 	resp, err := client.UploadConanPackageFileWithBodyWithResponse(
-		context.Background(),
+		ctx,
 		config.Global.AccountID,
 		registryName,
 		ref.Name,
@@ -367,7 +382,7 @@ func uploadConanPackageFile(
 }
 
 // openConanFile validates the file, computes its checksums, and returns an open handle and its size.
-func openConanFile(filePath string, progress *p.ConsoleReporter) (*os.File, utils.FileChecksums, int64, error) {
+func openConanFile(filePath string, progress p.Reporter) (*os.File, utils.FileChecksums, int64, error) {
 	info, err := os.Stat(filePath)
 	if err != nil {
 		return nil, utils.FileChecksums{}, 0, errors.NewValidationError("file_path", fmt.Sprintf("failed to access file: %v", err))
@@ -30,7 +30,6 @@ const (
 
 func NewPushCondaCmd(c *cmdutils.Factory) *cobra.Command {
 	var pkgURL string
-	var customHeaders map[string]string
 	cmd := &cobra.Command{
 		Use:   "conda <registry_name> <file_path>",
 		Short: "Push Conda Artifacts",
@@ -42,108 +41,110 @@ func NewPushCondaCmd(c *cmdutils.Factory) *cobra.Command {
 			}
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			registryName := args[0]
-			filePath := args[1]
-			fileName := filepath.Base(filePath)
-
 			// Create progress reporter
 			progress := p.NewConsoleReporter()
+			return pushConda(cmd.Context(), newPkgUploader(progress), args[0], args[1], progress)
+		},
+	}
 
-			// Validate Registry Name and file_path
-			progress.Start("Validating input parameters")
-			if registryName == "" {
-				progress.Error("Registry name is required")
-				return errors.NewValidationError("registry_name", "registry name is required")
-			}
-			if filePath == "" {
-				progress.Error("File path is required")
-				return errors.NewValidationError("file_path", "file path is required")
-			}
+	cmd.Flags().StringVar(&pkgURL, "pkg-url", "", "Base URL for the Packages")
+	return cmd
+}
 
-			// Validate file exists
-			fileInfo, err := os.Stat(filePath)
-			if err != nil {
-				return errors.NewValidationError("file_path", fmt.Sprintf("failed to access package file: %v", err))
-			}
-			if fileInfo.IsDir() {
-				return errors.NewValidationError("file_path", "package file path must be a file, not a directory")
-			}
+// pushConda uploads a .conda or .tar.bz2 package
+func pushConda(ctx context.Context, u pkgUploader, registryName, filePath string, progress p.Reporter) error {
+	fileName := filepath.Base(filePath)
 
-			// validate file name
-			valid, err := validateFileName(fileName)
-			if !valid {
-				progress.Error("Invalid file name")
-				return errors.NewValidationError("file_path", fmt.Sprintf("failed to validate package file name: %v", err))
-			}
+	// Validate Registry Name and file_path
+	progress.Start("Validating input parameters")
+	if registryName == "" {
+		progress.Error("Registry name is required")
+		return errors.NewValidationError("registry_name", "registry name is required")
+	}
+	if filePath == "" {
+		progress.Error("File path is required")
+		return errors.NewValidationError("file_path", "file path is required")
+	}
 
-			// get metadata from file
-			metadata, err := GetMetadataFromPayload(filePath, fileName)
-			if err != nil {
-				progress.Error("Failed to get metadata from payload")
-				return err
-			}
+	// Validate file exists
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		return errors.NewValidationError("file_path", fmt.Sprintf("failed to access package file: %v", err))
+	}
+	if fileInfo.IsDir() {
+		return errors.NewValidationError("file_path", "package file path must be a file, not a directory")
+	}
 
-			progress.Success("Input parameters validated")
+	// validate file name
+	valid, err := validateFileName(fileName)
+	if !valid {
+		progress.Error("Invalid file name")
+		return errors.NewValidationError("file_path", fmt.Sprintf("failed to validate package file name: %v", err))
+	}
 
-			// Upload package
-			progress.Step("Uploading package to registry")
+	// get metadata from file
+	metadata, err := GetMetadataFromPayload(filePath, fileName)
+	if err != nil {
+		progress.Error("Failed to get metadata from payload")
+		return err
+	}
 
-			file, err := os.Open(filePath)
-			if err != nil {
-				progress.Error("Failed to open package file")
-				return err
-			}
-			defer file.Close()
+	progress.Success("Input parameters validated")
 
-			// Initialize customHeaders if nil
-			if customHeaders == nil {
-				customHeaders = make(map[string]string)
-			}
+	// Upload package
+	progress.Step("Uploading package to registry")
 
-			// add X-File-Name in header
-			customHeaders["X-File-Name"] = filepath.Base(filePath)
+	file, err := os.Open(filePath)
+	if err != nil {
+		progress.Error("Failed to open package file")
+		return err
+	}
+	defer file.Close()
 
-			// add X-Subdir in header
-			customHeaders["X-Subdir"] = metadata.Subdir
+	customHeaders := make(map[string]string)
 
-			// Create custom header editor function
-			customHeaderEditor := func(ctx context.Context, req *http.Request) error {
-				// Add custom headers from the map
-				for key, value := range customHeaders {
-					req.Header.Set(key, value)
-				}
-				return nil
-			}
+	// add X-File-Name in header
+	customHeaders["X-File-Name"] = filepath.Base(filePath)
 
-			// Initialize the package client with retry and progress support
-			pkgClient := c.PkgHttpClientWithProgress(progress, fileInfo.Size(), "conda")
+	// add X-Subdir in header
+	customHeaders["X-Subdir"] = metadata.Subdir
 
-			resp, err := pkgClient.UploadCondaPackageWithBodyWithResponse(
-				context.Background(),
-				config.Global.AccountID,
-				registryName,
-				"application/octet-stream",
-				file,
-				customHeaderEditor,
-			)
+	// Create custom header editor function
+	customHeaderEditor := func(ctx context.Context, req *http.Request) error {
+		// Add custom headers from the map
+		for key, value := range customHeaders {
+			req.Header.Set(key, value)
+		}
+		return nil
+	}
 
-			if err != nil {
-				progress.Error("Failed to upload package")
-				return err
-			}
-			// Check response
-			if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusCreated {
-				progress.Error("Upload failed")
-				return fmt.Errorf("failed to push package: %s \n response: %s", resp.Status(), resp.Body)
-			}
+	// Initialize the package client with retry and progress support
+	pkgClient, err := u.client(fileInfo.Size(), "conda")
+	if err != nil {
+		return err
+	}
 
-			progress.Success(fmt.Sprintf("Successfully uploaded package %s", filePath))
-			return nil
-		},
+	resp, err := pkgClient.UploadCondaPackageWithBodyWithResponse(
+		ctx,
+		config.Global.AccountID,
+		registryName,
+		"application/octet-stream",
+		file,
+		customHeaderEditor,
+	)
+
+	if err != nil {
+		progress.Error("Failed to upload package")
+		return err
+	}
+	// Check response
+	if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusCreated {
+		progress.Error("Upload failed")
+		return fmt.Errorf("failed to push package: %s \n response: %s", resp.Status(), resp.Body)
 	}
 
-	cmd.Flags().StringVar(&pkgURL, "pkg-url", "", "Base URL for the Packages")
-	return cmd
+	progress.Success(fmt.Sprintf("Successfully uploaded package %s", filePath))
+	return nil
 }
 
 func validateFileName(fileName string) (bool, error) {
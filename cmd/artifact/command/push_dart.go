@@ -40,159 +40,164 @@ func NewPushDartCmd(f *cmdutils.Factory) *cobra.Command {
 			}
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			registryName := args[0]
-			packageFilePath := args[1]
-
 			// Create progress reporter
 			progress := p.NewConsoleReporter()
+			return pushDart(cmd.Context(), newPkgUploader(progress), args[0], args[1], progress)
+		},
+	}
 
-			// Validate input parameters
-			progress.Start("Validating input parameters")
-			if registryName == "" {
-				progress.Error("Registry name is required")
-				return fmt.Errorf("registry name is required")
-			}
-			if packageFilePath == "" {
-				progress.Error("Package file path is required")
-				return fmt.Errorf("package file path is required")
-			}
+	cmd.Flags().StringVar(&pkgURL, "pkg-url", "", "Base URL for the Packages service")
 
-			// Resolve file path (supports glob patterns like *.tar.gz)
-			files, err := utils.ResolveFilePath(packageFilePath, ".gz", ".tgz")
-			if err != nil {
-				progress.Error("Failed to resolve file path")
-				return err
-			}
-			packageFilePath = files[0]
-			progress.Step(fmt.Sprintf("Uploading file: %s", packageFilePath))
+	return cmd
+}
 
-			fileInfo, err := os.Stat(packageFilePath)
-			if err != nil {
-				progress.Error("Failed to access package file")
-				return fmt.Errorf("failed to access package file: %w", err)
-			}
-			if fileInfo.IsDir() {
-				progress.Error("Package file path must be a file, not a directory")
-				return errors.New("package file path must be a file, not a directory")
-			}
+// pushDart uploads a Dart package archive
+func pushDart(ctx context.Context, u pkgUploader, registryName, packageFilePath string, progress p.Reporter) error {
+	// Validate input parameters
+	progress.Start("Validating input parameters")
+	if registryName == "" {
+		progress.Error("Registry name is required")
+		return fmt.Errorf("registry name is required")
+	}
+	if packageFilePath == "" {
+		progress.Error("Package file path is required")
+		return fmt.Errorf("package file path is required")
+	}
 
-			ext := filepath.Ext(packageFilePath)
-			if ext != ".gz" && ext != ".tgz" {
-				progress.Error(fmt.Sprintf("Package file must be a .tar.gz or .tgz file, got: %s", ext))
-				return fmt.Errorf("package file must be a .tar.gz or .tgz file, got: %s", ext)
-			}
-			progress.Success("Input parameters validated")
+	// Resolve file path (supports glob patterns like *.tar.gz)
+	files, err := utils.ResolveFilePath(packageFilePath, ".gz", ".tgz")
+	if err != nil {
+		progress.Error("Failed to resolve file path")
+		return err
+	}
+	packageFilePath = files[0]
+	progress.Step(fmt.Sprintf("Uploading file: %s", packageFilePath))
 
-			// Compute checksums of the file for X-Checksum-* headers
-			checksums, err := utils.ComputeFileChecksums(packageFilePath)
-			if err != nil {
-				progress.Error("Failed to compute file checksums")
-				return fmt.Errorf("failed to compute checksums for %s: %w", packageFilePath, err)
-			}
+	fileInfo, err := os.Stat(packageFilePath)
+	if err != nil {
+		progress.Error("Failed to access package file")
+		return fmt.Errorf("failed to access package file: %w", err)
+	}
+	if fileInfo.IsDir() {
+		progress.Error("Package file path must be a file, not a directory")
+		return errors.New("package file path must be a file, not a directory")
+	}
 
-			// Extract pubspec.yaml from tarball
-			progress.Step("Extracting pubspec.yaml from tarball")
-			pubspecBytes, err := extractPubspecFromTarball(packageFilePath)
-			if err != nil {
-				progress.Error("Failed to extract pubspec.yaml from tarball")
-				return fmt.Errorf("failed to extract pubspec.yaml from tarball: %w", err)
-			}
+	ext := filepath.Ext(packageFilePath)
+	if ext != ".gz" && ext != ".tgz" {
+		progress.Error(fmt.Sprintf("Package file must be a .tar.gz or .tgz file, got: %s", ext))
+		return fmt.Errorf("package file must be a .tar.gz or .tgz file, got: %s", ext)
+	}
+	progress.Success("Input parameters validated")
 
-			// Parse pubspec.yaml
-			progress.Step("Parsing pubspec.yaml")
-			pubspec, err := parsePubspec(pubspecBytes)
-			if err != nil {
-				progress.Error("Failed to parse pubspec.yaml")
-				return fmt.Errorf("failed to parse pubspec.yaml: %w", err)
-			}
+	// Compute checksums of the file for X-Checksum-* headers
+	checksums, err := utils.ComputeFileChecksums(packageFilePath)
+	if err != nil {
+		progress.Error("Failed to compute file checksums")
+		return fmt.Errorf("failed to compute checksums for %s: %w", packageFilePath, err)
+	}
 
-			if pubspec.Name == "" || pubspec.Version == "" {
-				progress.Error("Pubspec.yaml must contain non-empty 'name' and 'version'")
-				return fmt.Errorf("pubspec.yaml must contain non-empty 'name' and 'version'")
-			}
+	// Extract pubspec.yaml from tarball
+	progress.Step("Extracting pubspec.yaml from tarball")
+	pubspecBytes, err := extractPubspecFromTarball(packageFilePath)
+	if err != nil {
+		progress.Error("Failed to extract pubspec.yaml from tarball")
+		return fmt.Errorf("failed to extract pubspec.yaml from tarball: %w", err)
+	}
 
-			if config.Global.Registry.PkgURL == "" {
-				progress.Error("pkg-url must be set")
-				return fmt.Errorf("pkg-url must be set")
-			}
-			progress.Success(fmt.Sprintf("Package metadata extracted: %s@%s", pubspec.Name, pubspec.Version))
+	// Parse pubspec.yaml
+	progress.Step("Parsing pubspec.yaml")
+	pubspec, err := parsePubspec(pubspecBytes)
+	if err != nil {
+		progress.Error("Failed to parse pubspec.yaml")
+		return fmt.Errorf("failed to parse pubspec.yaml: %w", err)
+	}
 
-			// Open the tar.gz file for upload
-			progress.Step("Preparing package file for upload")
-			file, err := os.Open(packageFilePath)
-			if err != nil {
-				progress.Error("Failed to open package file")
-				return fmt.Errorf("failed to open package file: %w", err)
-			}
-			defer file.Close()
-
-			uploadID := uuid.New().String()
-
-			// Create a pipe for streaming multipart form data
-			progress.Step("Preparing multipart upload")
-			pr, pw := io.Pipe()
-			writer := multipart.NewWriter(pw)
-
-			// Write multipart form in a goroutine
-			go func() {
-				defer pw.Close()
-				defer writer.Close()
-
-				// Create form file field "file"
-				part, err := writer.CreateFormFile("file", filepath.Base(packageFilePath))
-				if err != nil {
-					pw.CloseWithError(fmt.Errorf("failed to create form file: %w", err))
-					return
-				}
-
-				// Copy file content to the form field
-				if _, err := io.Copy(part, file); err != nil {
-					pw.CloseWithError(fmt.Errorf("failed to write file to form: %w", err))
-					return
-				}
-			}()
-
-			// Upload package using generated client with progress tracking
-			progress.Step("Uploading package to registry")
-
-			// Initialize progress reader for upload tracking
-			bufferSize := fileInfo.Size()
-
-			// Initialize the package client
-			progress.Step("Initializing package client")
-			pkgClient := f.PkgHttpClientWithProgress(progress, bufferSize, fileInfo.Name())
-
-			resp, err := pkgClient.UploadDartPackageWithBodyWithResponse(
-				context.Background(),
-				config.Global.AccountID,
-				registryName,
-				uploadID,
-				writer.FormDataContentType(),
-				pr,
-				func(ctx context.Context, req *http.Request) error {
-					utils.SetChecksumHeaders(req.Header, checksums)
-					return nil
-				},
-			)
-			if err != nil {
-				progress.Error("Failed to upload Dart package")
-				return fmt.Errorf("failed to upload Dart package: %w", err)
-			}
+	if pubspec.Name == "" || pubspec.Version == "" {
+		progress.Error("Pubspec.yaml must contain non-empty 'name' and 'version'")
+		return fmt.Errorf("pubspec.yaml must contain non-empty 'name' and 'version'")
+	}
 
-			// Check response
-			if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusCreated && resp.StatusCode() != http.StatusNoContent {
-				progress.Error("Upload failed")
-				return fmt.Errorf("failed to upload Dart package: %s \n response: %s", resp.Status(), resp.Body)
-			}
+	if u.pkgURL == "" {
+		progress.Error("pkg-url must be set")
+		return fmt.Errorf("pkg-url must be set")
+	}
+	progress.Success(fmt.Sprintf("Package metadata extracted: %s@%s", pubspec.Name, pubspec.Version))
+
+	// Open the tar.gz file for upload
+	progress.Step("Preparing package file for upload")
+	file, err := os.Open(packageFilePath)
+	if err != nil {
+		progress.Error("Failed to open package file")
+		return fmt.Errorf("failed to open package file: %w", err)
+	}
+	defer file.Close()
+
+	uploadID := uuid.New().String()
 
-			progress.Success(fmt.Sprintf("Successfully uploaded Dart package '%s@%s' to registry '%s'", pubspec.Name, pubspec.Version, registryName))
+	// Create a pipe for streaming multipart form data
+	progress.Step("Preparing multipart upload")
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+
+	// Write multipart form in a goroutine
+	go func() {
+		defer pw.Close()
+		defer writer.Close()
+
+		// Create form file field "file"
+		part, err := writer.CreateFormFile("file", filepath.Base(packageFilePath))
+		if err != nil {
+			pw.CloseWithError(fmt.Errorf("failed to create form file: %w", err))
+			return
+		}
+
+		// Copy file content to the form field
+		if _, err := io.Copy(part, file); err != nil {
+			pw.CloseWithError(fmt.Errorf("failed to write file to form: %w", err))
+			return
+		}
+	}()
+
+	// Upload package using generated client with progress tracking
+	progress.Step("Uploading package to registry")
+
+	// Initialize progress reader for upload tracking
+	bufferSize := fileInfo.Size()
+
+	// Initialize the package client
+	progress.Step("Initializing package client")
+	pkgClient, err := u.client(bufferSize, fileInfo.Name())
+	if err != nil {
+		pr.CloseWithError(err)
+		return err
+	}
+
+	resp, err := pkgClient.UploadDartPackageWithBodyWithResponse(
+		ctx,
+		config.Global.AccountID,
+		registryName,
+		uploadID,
+		writer.FormDataContentType(),
+		pr,
+		func(ctx context.Context, req *http.Request) error {
+			utils.SetChecksumHeaders(req.Header, checksums)
 			return nil
 		},
+	)
+	if err != nil {
+		progress.Error("Failed to upload Dart package")
+		return fmt.Errorf("failed to upload Dart package: %w", err)
 	}
 
-	cmd.Flags().StringVar(&pkgURL, "pkg-url", "", "Base URL for the Packages service")
+	// Check response
+	if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusCreated && resp.StatusCode() != http.StatusNoContent {
+		progress.Error("Upload failed")
+		return fmt.Errorf("failed to upload Dart package: %s \n response: %s", resp.Status(), resp.Body)
+	}
 
-	return cmd
+	progress.Success(fmt.Sprintf("Successfully uploaded Dart package '%s@%s' to registry '%s'", pubspec.Name, pubspec.Version, registryName))
+	return nil
 }
 
 // extractPubspecFromTarball extracts pubspec.yaml from a Dart .tar.gz package
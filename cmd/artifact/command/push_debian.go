@@ -17,7 +17,6 @@ import (
 	"github.com/harness/harness-cli/config"
 	pkgclient "github.com/harness/harness-cli/internal/api/ar_pkg"
 	"github.com/harness/harness-cli/util/artifact"
-	"github.com/harness/harness-cli/util/common/auth"
 	"github.com/harness/harness-cli/util/common/errors"
 	"github.com/harness/harness-cli/util/common/fileutil"
 	p "github.com/harness/harness-cli/util/common/progress"
@@ -38,10 +37,7 @@ type DscMetadata struct {
 }
 
 func NewPushDebianCmd(c *cmdutils.Factory) *cobra.Command {
-	var distribution string
-	var component string
-	var sourceFile string
-	var originSourceFile string
+	var opts debianPushOptions
 
 	cmd := &cobra.Command{
 		Use:   "debian <registry_name> <file_path>",
@@ -57,41 +53,55 @@ func NewPushDebianCmd(c *cmdutils.Factory) *cobra.Command {
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			registryName := args[0]
-			filePath := args[1]
-
 			// Create progress reporter
 			progress := p.NewConsoleReporter()
-
-			// Determine file type based on extension
-			fileExt := filepath.Ext(filePath)
-
-			switch fileExt {
-			case DebFileExtension:
-				// Handle .deb package
-				return handleDebPackage(registryName, filePath, distribution, component, progress)
-			case DscFileExtension:
-				// Handle .dsc source package
-				return handleDebSourcePackage(registryName, filePath, distribution, component, sourceFile, originSourceFile, progress)
-			default:
-				progress.Error("Unsupported file type")
-				return errors.NewValidationError("file_path", fmt.Sprintf("file must be either .deb or .dsc, got: %s", fileExt))
-			}
+			return pushDebian(cmd.Context(), newPkgUploader(progress), args[0], args[1], opts, progress)
 		},
 	}
 
-	cmd.Flags().StringVar(&distribution, "distribution", "", "Debian distribution name (e.g., focal, bullseye)")
-	cmd.Flags().StringVar(&component, "component", "", "Debian component name (e.g., main, contrib, non-free)")
-	cmd.Flags().StringVar(&sourceFile, "source-file", "", "Path to source file (only for .dsc files)")
-	cmd.Flags().StringVar(&originSourceFile, "origin-source-file", "", "Path to origin source file (only for .dsc files)")
+	cmd.Flags().StringVar(&opts.distribution, "distribution", "", "Debian distribution name (e.g., focal, bullseye)")
+	cmd.Flags().StringVar(&opts.component, "component", "", "Debian component name (e.g., main, contrib, non-free)")
+	cmd.Flags().StringVar(&opts.sourceFile, "source-file", "", "Path to source file (only for .dsc files)")
+	cmd.Flags().StringVar(&opts.originSourceFile, "origin-source-file", "", "Path to origin source file (only for .dsc files)")
 	cmd.MarkFlagRequired("distribution")
 	cmd.MarkFlagRequired("component")
 
 	return cmd
 }
 
+// debianPushOptions are the distribution and component a Debian package is
+// pushed to, and the source files of a .dsc source package
+type debianPushOptions struct {
+	distribution     string
+	component        string
+	sourceFile       string
+	originSourceFile string
+}
+
+// pushDebian uploads a .deb package, or a .dsc source package with its
+// source files
+func pushDebian(ctx context.Context, u pkgUploader, registryName, filePath string, opts debianPushOptions,
+	progress p.Reporter) error {
+	// Determine file type based on extension
+	fileExt := filepath.Ext(filePath)
+
+	switch fileExt {
+	case DebFileExtension:
+		// Handle .deb package
+		return handleDebPackage(ctx, u, registryName, filePath, opts.distribution, opts.component, progress)
+	case DscFileExtension:
+		// Handle .dsc source package
+		return handleDebSourcePackage(ctx, u, registryName, filePath, opts.distribution, opts.component,
+			opts.sourceFile, opts.originSourceFile, progress)
+	default:
+		progress.Error("Unsupported file type")
+		return errors.NewValidationError("file_path", fmt.Sprintf("file must be either .deb or .dsc, got: %s", fileExt))
+	}
+}
+
 // handleDebPackage handles uploading .deb packages
-func handleDebPackage(registryName, filePath, distribution, component string, progress *p.ConsoleReporter) error {
+func handleDebPackage(ctx context.Context, u pkgUploader, registryName, filePath, distribution, component string,
+	progress p.Reporter) error {
 	// Resolve file path (supports glob patterns like *.deb)
 	files, err := utils.ResolveFilePath(filePath, DebFileExtension)
 	if err != nil {
@@ -128,13 +138,6 @@ func handleDebPackage(registryName, filePath, distribution, component string, pr
 		return fmt.Errorf("failed to compute checksums for %s: %w", filePath, err)
 	}
 
-	// Initialize the package client
-	pkgClient, err := pkgclient.NewClientWithResponses(config.Global.Registry.PkgURL,
-		auth.GetAuthOptionARPKG())
-	if err != nil {
-		return fmt.Errorf("failed to create package client: %w", err)
-	}
-
 	file, err := os.Open(filePath)
 	if err != nil {
 		progress.Error("Failed to open package file")
@@ -159,11 +162,12 @@ func handleDebPackage(registryName, filePath, distribution, component string, pr
 
 	fileWriter.Close()
 
-	// Initialize progress reader
+	// Initialize the package client
 	progress.Step("Uploading package to registry")
-	bufferSize := int64(formData.Len())
-	reader, closer := p.Reader(bufferSize, &formData, "debian")
-	defer closer()
+	pkgClient, err := u.client(int64(formData.Len()), "debian")
+	if err != nil {
+		return err
+	}
 
 	// Build query parameters
 	params := &pkgclient.UploadDebianDebFileParams{
@@ -172,12 +176,12 @@ func handleDebPackage(registryName, filePath, distribution, component string, pr
 	}
 
 	resp, err := pkgClient.UploadDebianDebFileWithBodyWithResponse(
-		context.Background(),
+		ctx,
 		config.Global.AccountID,
 		registryName,
 		params,
 		fileWriter.FormDataContentType(),
-		reader,
+		&formData,
 		func(ctx context.Context, req *http.Request) error {
 			utils.SetChecksumHeaders(req.Header, checksums)
 			return nil
@@ -199,7 +203,7 @@ func handleDebPackage(registryName, filePath, distribution, component string, pr
 }
 
 // handleDebSourcePackage handles uploading .dsc source packages
-func handleDebSourcePackage(registryName, dscFilePath, distribution, component, sourceFile, originSourceFile string, progress *p.ConsoleReporter) error {
+func handleDebSourcePackage(ctx context.Context, u pkgUploader, registryName, dscFilePath, distribution, component, sourceFile, originSourceFile string, progress p.Reporter) error {
 	// Validate at least one source file is provided
 	if sourceFile == "" && originSourceFile == "" {
 		progress.Error("At least one source file is required")
@@ -234,23 +238,16 @@ func handleDebSourcePackage(registryName, dscFilePath, distribution, component,
 	}
 	progress.Success(fmt.Sprintf("Extracted package: %s, version: %s", dscMetadata.Source, dscMetadata.Version))
 
-	// Initialize the package client
-	pkgClient, err := pkgclient.NewClientWithResponses(config.Global.Registry.PkgURL,
-		auth.GetAuthOptionARPKG())
-	if err != nil {
-		return fmt.Errorf("failed to create package client: %w", err)
-	}
-
 	// Upload DSC file first
 	progress.Step(fmt.Sprintf("Uploading: %s", dscFilePath))
-	if err := uploadDscFile(pkgClient, registryName, dscFilePath, distribution, component, progress); err != nil {
+	if err := uploadDscFile(ctx, u, registryName, dscFilePath, distribution, component, progress); err != nil {
 		return err
 	}
 
 	// Upload tar.xz file if provided
 	if sourceFile != "" {
 		progress.Step(fmt.Sprintf("Uploading: %s", sourceFile))
-		if err := uploadSourceFile(pkgClient, registryName, sourceFile, dscMetadata.Source, dscMetadata.Version, distribution, component, progress, false); err != nil {
+		if err := uploadSourceFile(ctx, u, registryName, sourceFile, dscMetadata.Source, dscMetadata.Version, distribution, component, progress, false); err != nil {
 			return err
 		}
 	}
@@ -259,7 +256,7 @@ func handleDebSourcePackage(registryName, dscFilePath, distribution, component,
 	if originSourceFile != "" {
 		progress.Step(fmt.Sprintf("Uploading: %s", originSourceFile))
 		upstreamVersion := artifact.ExtractUpstreamVersion(dscMetadata.Version)
-		if err := uploadSourceFile(pkgClient, registryName, originSourceFile, dscMetadata.Source, upstreamVersion, distribution, component, progress, true); err != nil {
+		if err := uploadSourceFile(ctx, u, registryName, originSourceFile, dscMetadata.Source, upstreamVersion, distribution, component, progress, true); err != nil {
 			return err
 		}
 	}
@@ -308,7 +305,7 @@ func parseDscFile(filePath string) (*DscMetadata, error) {
 }
 
 // uploadDscFile uploads a .dsc file
-func uploadDscFile(client *pkgclient.ClientWithResponses, registryName, filePath, distribution, component string, progress *p.ConsoleReporter) error {
+func uploadDscFile(ctx context.Context, u pkgUploader, registryName, filePath, distribution, component string, progress p.Reporter) error {
 	// Compute checksums
 	checksums, err := utils.ComputeFileChecksums(filePath)
 	if err != nil {
@@ -341,10 +338,11 @@ func uploadDscFile(client *pkgclient.ClientWithResponses, registryName, filePath
 
 	fileWriter.Close()
 
-	// Initialize progress reader
-	bufferSize := int64(formData.Len())
-	reader, closer := p.Reader(bufferSize, &formData, "dsc")
-	defer closer()
+	// Initialize the package client
+	client, err := u.client(int64(formData.Len()), "dsc")
+	if err != nil {
+		return err
+	}
 
 	// Build query parameters
 	params := &pkgclient.UploadDebianDscFileParams{
@@ -353,12 +351,12 @@ func uploadDscFile(client *pkgclient.ClientWithResponses, registryName, filePath
 	}
 
 	resp, err := client.UploadDebianDscFileWithBodyWithResponse(
-		context.Background(),
+		ctx,
 		config.Global.AccountID,
 		registryName,
 		params,
 		fileWriter.FormDataContentType(),
-		reader,
+		&formData,
 		func(ctx context.Context, req *http.Request) error {
 			utils.SetChecksumHeaders(req.Header, checksums)
 			return nil
@@ -380,7 +378,7 @@ func uploadDscFile(client *pkgclient.ClientWithResponses, registryName, filePath
 }
 
 // uploadSourceFile uploads a source file
-func uploadSourceFile(client *pkgclient.ClientWithResponses, registryName, filePath, packageName, version, distribution, component string, progress *p.ConsoleReporter, isOrig bool) error {
+func uploadSourceFile(ctx context.Context, u pkgUploader, registryName, filePath, packageName, version, distribution, component string, progress p.Reporter, isOrig bool) error {
 	// Validate file exists
 	fileInfo, err := os.Stat(filePath)
 	if err != nil {
@@ -422,10 +420,11 @@ func uploadSourceFile(client *pkgclient.ClientWithResponses, registryName, fileP
 
 	fileWriter.Close()
 
-	// Initialize progress reader
-	bufferSize := int64(formData.Len())
-	reader, closer := p.Reader(bufferSize, &formData, "source file")
-	defer closer()
+	// Initialize the package client
+	client, err := u.client(int64(formData.Len()), "source file")
+	if err != nil {
+		return err
+	}
 
 	// Build query parameters
 	params := &pkgclient.UploadDebianSrcFileParams{
@@ -436,12 +435,12 @@ func uploadSourceFile(client *pkgclient.ClientWithResponses, registryName, fileP
 	}
 
 	resp, err := client.UploadDebianSrcFileWithBodyWithResponse(
-		context.Background(),
+		ctx,
 		config.Global.AccountID,
 		registryName,
 		params,
 		fileWriter.FormDataContentType(),
-		reader,
+		&formData,
 		func(ctx context.Context, req *http.Request) error {
 			utils.SetChecksumHeaders(req.Header, checksums)
 			return nil
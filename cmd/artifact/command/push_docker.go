@@ -0,0 +1,235 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/harness/harness-cli/cmd/cmdutils"
+	"github.com/harness/harness-cli/util/common/printer"
+	p "github.com/harness/harness-cli/util/common/progress"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/spf13/cobra"
+)
+
+// pushedImage is one reference written by push docker
+type pushedImage struct {
+	Reference string `json:"reference"`
+	Digest    string `json:"digest"`
+	MediaType string `json:"mediaType"`
+}
+
+// NewPushDockerCmd creates a new cobra.Command for pushing container images
+// without a Docker daemon.
+// command example: hc artifact push docker <registry_name> <image.tar|oci-dir> --tag <repository>:<tag>
+//
+// The source is a `docker save` tarball or an OCI image layout directory. A
+// layout holding a multi-arch index is pushed as the index with every
+// platform image, unless --platform selects one image.
+func NewPushDockerCmd(c *cmdutils.Factory) *cobra.Command {
+	const expectedNumberOfArgument = 2
+	var tags []string
+	var platform string
+	cmd := &cobra.Command{
+		Use:   "docker <registry_name> <image.tar|oci-dir>",
+		Short: "Push Docker Images",
+		Long: `Push a container image from a 'docker save' tarball or an OCI image layout
+directory to a Harness Docker registry, without a Docker daemon. Multi-arch
+indexes are pushed with all their platform images unless --platform selects one.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != expectedNumberOfArgument {
+				return fmt.Errorf(
+					"Error: Invalid number of argument,  accepts %d arg(s), received %d  \nUsage :\n %s",
+					expectedNumberOfArgument, len(args), cmd.UseLine(),
+				)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pushed, err := pushDockerImage(cmd.Context(), c, args[0], args[1], tags, platform, p.NewConsoleReporter())
+			if err != nil {
+				return err
+			}
+			options := printer.DefaultJsonOptions()
+			options.ShowPagination = false
+			return printer.PrintJsonWithOptions(pushed, options)
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&tags, "tag", "t", nil,
+		"Image reference <repository>:<tag> within the registry; repeat to push several tags")
+	cmd.Flags().StringVar(&platform, "platform", "",
+		"Push only the image for this platform (os/arch[/variant]) from a multi-arch source")
+	cmd.MarkFlagRequired("tag")
+
+	return cmd
+}
+
+// pushDockerImage pushes the image or index of source under every tag
+func pushDockerImage(ctx context.Context, c *cmdutils.Factory, registryName, source string, tags []string,
+	platform string, progress p.Reporter) ([]pushedImage, error) {
+	var want *v1.Platform
+	if platform != "" {
+		parsed, err := v1.ParsePlatform(platform)
+		if err != nil {
+			return nil, fmt.Errorf("invalid platform %q: %w", platform, err)
+		}
+		want = parsed
+	}
+
+	progress.Start(fmt.Sprintf("Loading image from %s", source))
+	img, index, err := loadImageSource(source, tags[0], want)
+	if err != nil {
+		progress.Error("Failed to load image")
+		return nil, err
+	}
+
+	reg, err := resolveDockerRegistry(ctx, c, registryName)
+	if err != nil {
+		progress.Error("Failed to resolve registry")
+		return nil, err
+	}
+
+	pushed := make([]pushedImage, 0, len(tags))
+	for _, tag := range tags {
+		ref, err := reg.reference(tag)
+		if err != nil {
+			return nil, err
+		}
+		progress.Step(fmt.Sprintf("Pushing %s", ref))
+		var result pushedImage
+		if index != nil {
+			err = remote.WriteIndex(ref, index, reg.options...)
+		} else {
+			err = remote.Write(ref, img, reg.options...)
+		}
+		if err != nil {
+			progress.Error(fmt.Sprintf("Failed to push %s", ref))
+			return nil, fmt.Errorf("failed to push %s: %w", ref, err)
+		}
+		if index != nil {
+			result, err = describePushed(ref, index)
+		} else {
+			result, err = describePushed(ref, img)
+		}
+		if err != nil {
+			progress.Error(fmt.Sprintf("Failed to read the digest of %s", ref))
+			return nil, fmt.Errorf("pushed %s but failed to read its digest: %w", ref, err)
+		}
+		pushed = append(pushed, result)
+	}
+	progress.Success(fmt.Sprintf("Pushed %d reference(s) to registry '%s'", len(pushed), registryName))
+	return pushed, nil
+}
+
+// describePushed returns the digest and media type of a pushed manifest
+func describePushed(ref name.Reference, m interface {
+	Digest() (v1.Hash, error)
+	MediaType() (types.MediaType, error)
+}) (pushedImage, error) {
+	digest, err := m.Digest()
+	if err != nil {
+		return pushedImage{Reference: ref.String()}, err
+	}
+	mediaType, err := m.MediaType()
+	if err != nil {
+		return pushedImage{Reference: ref.String()}, err
+	}
+	return pushedImage{Reference: ref.String(), Digest: digest.String(), MediaType: string(mediaType)}, nil
+}
+
+// loadImageSource reads an image or index from a docker save tarball or an
+// OCI layout. Exactly one of the returned image and index is set.
+func loadImageSource(source, tag string, platform *v1.Platform) (v1.Image, v1.ImageIndex, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot access %q: %w", source, err)
+	}
+	if info.IsDir() {
+		return loadOCILayout(source, platform)
+	}
+	img, err := loadDockerTarball(source, tag)
+	if err != nil {
+		return nil, nil, err
+	}
+	if platform != nil {
+		ok, err := imageMatchesPlatform(img, *platform)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !ok {
+			return nil, nil, fmt.Errorf("%s holds no image for platform %s", source, platform)
+		}
+	}
+	return img, nil, nil
+}
+
+// loadOCILayout reads an OCI layout. A layout with one manifest yields that
+// image or index; one with several is pushed as its own index.
+func loadOCILayout(dir string, platform *v1.Platform) (v1.Image, v1.ImageIndex, error) {
+	index, err := layout.ImageIndexFromPath(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s is not an OCI image layout: %w", dir, err)
+	}
+	if platform != nil {
+		img, err := selectPlatformImage(index, *platform)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", dir, err)
+		}
+		return img, nil, nil
+	}
+
+	manifest, err := index.IndexManifest()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s/index.json: %w", dir, err)
+	}
+	if len(manifest.Manifests) != 1 {
+		return nil, index, nil
+	}
+	desc := manifest.Manifests[0]
+	switch {
+	case desc.MediaType.IsImage():
+		img, err := index.Image(desc.Digest)
+		return img, nil, err
+	case desc.MediaType.IsIndex():
+		child, err := index.ImageIndex(desc.Digest)
+		return nil, child, err
+	default:
+		return nil, nil, fmt.Errorf("%s holds unsupported manifest type %s", dir, desc.MediaType)
+	}
+}
+
+// loadDockerTarball reads a docker save tarball. A tarball of several images
+// yields the one saved under the tag being pushed.
+func loadDockerTarball(path, tag string) (v1.Image, error) {
+	opener := func() (io.ReadCloser, error) { return os.Open(path) }
+	manifest, err := tarball.LoadManifest(opener)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a docker image tarball: %w", path, err)
+	}
+	if len(manifest) == 1 {
+		return tarball.Image(opener, nil)
+	}
+	for _, entry := range manifest {
+		for _, repoTag := range entry.RepoTags {
+			saved, err := name.NewTag(repoTag)
+			if err != nil {
+				continue
+			}
+			// compare without the registry, so a tarball saved from another
+			// registry matches the tag being pushed
+			if wanted, err := name.NewTag(tag); err == nil && saved.RepositoryStr() == wanted.RepositoryStr() &&
+				saved.TagStr() == wanted.TagStr() {
+				return tarball.Image(opener, &saved)
+			}
+		}
+	}
+	return nil, fmt.Errorf("%s holds %d images and none is tagged %s", path, len(manifest), tag)
+}
@@ -0,0 +1,144 @@
+package command
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/harness/harness-cli/cmd/cmdutils"
+	"github.com/harness/harness-cli/internal/api/ar"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dockerRegistryServer serves an in-memory OCI registry under /v2/ and
+// answers the registry API with a Docker registry "docker-reg" located at
+// <server>/acct/docker-reg. It returns the factory and the image prefix.
+func dockerRegistryServer(t *testing.T) (*cmdutils.Factory, string) {
+	t.Helper()
+	withPullConfig(t)
+	reg := registry.New(registry.Logger(log.New(io.Discard, "", 0)))
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/v2/") {
+			reg.ServeHTTP(w, r)
+			return
+		}
+		require.True(t, strings.HasSuffix(r.URL.Path, "/registry/acct/docker-reg/+"), "unexpected path %s", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ar.RegistryResponse{
+			Data:   ar.Registry{Identifier: "docker-reg", PackageType: ar.DOCKER, Url: ts.URL + "/acct/docker-reg"},
+			Status: "SUCCESS",
+		})
+	}))
+	t.Cleanup(ts.Close)
+	return newPullTestFactory(t, ts), strings.TrimPrefix(ts.URL, "http://") + "/acct/docker-reg"
+}
+
+func mustRef(t *testing.T, ref string) name.Reference {
+	t.Helper()
+	r, err := name.ParseReference(ref, name.Insecure)
+	require.NoError(t, err)
+	return r
+}
+
+func platformImage(t *testing.T, arch string) v1.Image {
+	t.Helper()
+	img, err := random.Image(256, 1)
+	require.NoError(t, err)
+	cfg, err := img.ConfigFile()
+	require.NoError(t, err)
+	cfg = cfg.DeepCopy()
+	cfg.OS, cfg.Architecture = "linux", arch
+	img, err = mutate.ConfigFile(img, cfg)
+	require.NoError(t, err)
+	return img
+}
+
+// multiArchLayout writes an OCI layout holding one index of an amd64 and an
+// arm64 image
+func multiArchLayout(t *testing.T) (string, v1.ImageIndex, v1.Image) {
+	t.Helper()
+	arm := platformImage(t, "arm64")
+	idx := mutate.AppendManifests(empty.Index,
+		mutate.IndexAddendum{Add: platformImage(t, "amd64"), Descriptor: v1.Descriptor{Platform: &v1.Platform{OS: "linux", Architecture: "amd64"}}},
+		mutate.IndexAddendum{Add: arm, Descriptor: v1.Descriptor{Platform: &v1.Platform{OS: "linux", Architecture: "arm64"}}},
+	)
+	dir := t.TempDir()
+	l, err := layout.Write(dir, empty.Index)
+	require.NoError(t, err)
+	require.NoError(t, l.AppendIndex(idx))
+	return dir, idx, arm
+}
+
+func runPushDocker(t *testing.T, f *cmdutils.Factory, args ...string) error {
+	t.Helper()
+	cmd := NewPushDockerCmd(f)
+	cmd.SetArgs(args)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	return cmd.Execute()
+}
+
+func TestPushDockerTarball(t *testing.T) {
+	f, prefix := dockerRegistryServer(t)
+	img := platformImage(t, "amd64")
+	tag, err := name.NewTag("example.com/team/app:1.0")
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "image.tar")
+	require.NoError(t, tarball.WriteToFile(path, tag, img))
+
+	require.NoError(t, runPushDocker(t, f, "docker-reg", path, "--tag", "team/app:1.0", "--tag", "team/app:latest"))
+
+	want, err := img.Digest()
+	require.NoError(t, err)
+	for _, ref := range []string{"team/app:1.0", "team/app:latest"} {
+		desc, err := remote.Head(mustRef(t, prefix+"/"+ref))
+		require.NoError(t, err)
+		assert.Equal(t, want, desc.Digest, ref)
+	}
+}
+
+func TestPushDockerOCILayoutIndex(t *testing.T) {
+	f, prefix := dockerRegistryServer(t)
+	dir, idx, _ := multiArchLayout(t)
+
+	require.NoError(t, runPushDocker(t, f, "docker-reg", dir, "--tag", "app:multi"))
+
+	pushed, err := remote.Index(mustRef(t, prefix+"/app:multi"))
+	require.NoError(t, err)
+	want, _ := idx.Digest()
+	got, _ := pushed.Digest()
+	assert.Equal(t, want, got)
+}
+
+func TestPushDockerPlatformSelection(t *testing.T) {
+	f, prefix := dockerRegistryServer(t)
+	dir, _, arm := multiArchLayout(t)
+
+	require.NoError(t, runPushDocker(t, f, "docker-reg", dir, "--tag", "app:arm", "--platform", "linux/arm64"))
+
+	desc, err := remote.Head(mustRef(t, prefix+"/app:arm"))
+	require.NoError(t, err)
+	want, _ := arm.Digest()
+	assert.Equal(t, want, desc.Digest)
+
+	err = runPushDocker(t, f, "docker-reg", dir, "--tag", "app:s390x", "--platform", "linux/s390x")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no image for platform linux/s390x")
+}
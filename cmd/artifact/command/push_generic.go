@@ -77,16 +77,20 @@ func NewPushGenericCmd(c *cmdutils.Factory) *cobra.Command {
 				stats.fileCount, bytesize.New(float64(stats.totalBytes)),
 				packageName, version, registryName)
 
-			// TODO(bulk-checksum): Once the bulk-upload-by-checksum API ships, insert a
-			// pre-flight pass here that:
-			//   1. Computes a checksum (SHA-256) for each candidate file in `jobs`.
-			//   2. Sends the {DestPath -> checksum} map to the bulk-check endpoint.
-			//   3. Receives back the subset of files the server does NOT already have
-			//      (or whose stored checksum differs).
-			//   4. Filters `jobs` down to only that subset before calling engine.Execute,
-			//      and prints a "skipping N unchanged files" summary.
-			// This avoids re-uploading unchanged files and matches the semantics of
-			// the future server-side dedup API.
+			// Skip files the registry already holds with the same size and
+			// checksums, so re-pushing a build only uploads what changed
+			detector := newHeadChangeDetector(c.PkgHttpClient(), registryName, upload.DefaultUploadWorker)
+			jobs, skipped, err := filterUnchangedGenericJobs(ctx, detector, jobs)
+			if err != nil {
+				return err
+			}
+			if skipped > 0 {
+				fmt.Printf("skipping %d unchanged files\n", skipped)
+			}
+			if len(jobs) == 0 {
+				fmt.Printf("All %d file(s) are already up to date in %s/%s\n", skipped, packageName, version)
+				return nil
+			}
 
 			engine := upload.NewFileUploadEngine(upload.DefaultUploadWorker, progress.NewConsoleReporter())
 			results := engine.Execute(ctx, jobs)
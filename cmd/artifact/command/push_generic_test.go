@@ -415,10 +415,17 @@ func equal(a, b []string) bool {
 }
 
 // withGenericServer stands up a stub registry server, points config.Global at
-// it, and restores all globals on cleanup. The handler runs for every request.
+// it, and restores all globals on cleanup. The pre-flight HEAD checks find no
+// file on the registry; the handler runs for every other request.
 func withGenericServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
 	t.Helper()
-	srv := httptest.NewServer(handler)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		handler(w, r)
+	}))
 	t.Cleanup(srv.Close)
 
 	orig := config.Global
@@ -450,8 +457,7 @@ func runGenericCmd(t *testing.T, args ...string) (string, error) {
 		doneCh <- buf.String()
 	}()
 
-	factory := &cmdutils.Factory{}
-	cmd := NewPushGenericCmd(factory)
+	cmd := NewPushGenericCmd(cmdutils.NewFactory())
 	cmd.SetArgs(args)
 	cmd.SetOut(new(bytes.Buffer))
 	cmd.SetErr(new(bytes.Buffer))
@@ -35,115 +35,121 @@ func NewPushGoCmd(c *cmdutils.Factory) *cobra.Command {
 			}
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			registryName := args[0]
-			dir := args[1]
-
 			// Create progress reporter
 			progress := p.NewConsoleReporter()
+			return pushGo(cmd.Context(), newPkgUploader(progress), args[0], args[1], version, output, progress)
+		},
+	}
 
-			// Validate Registry Name and Version
-			progress.Start("Validating input parameters")
-			if registryName == "" {
-				progress.Error("Registry name is required")
-				return errors.NewValidationError("registry_name", "registry name is required")
-			}
-			if version == "" {
-				progress.Error("Version is required")
-				return errors.NewValidationError("version", "version is required")
-			}
+	cmd.Flags().StringVar(&pkgURL, "pkg-url", "", "Base URL for the Packages")
+	cmd.Flags().StringVar(&version, "version", "", "Version for the package")
+	return cmd
+}
 
-			// Validate directory exists
-			dirInfo, err := os.Stat(dir)
-			if err != nil {
-				return errors.NewValidationError("folder_path", fmt.Sprintf("failed to access folder: %v", err))
-			}
-			if !dirInfo.IsDir() {
-				return errors.NewValidationError("folder_path", "path must be a directory")
-			}
+// pushGo generates the module files of dir at version into output and
+// uploads them
+func pushGo(ctx context.Context, u pkgUploader, registryName, dir, version, output string,
+	progress p.Reporter) error {
+	// Validate Registry Name and Version
+	progress.Start("Validating input parameters")
+	if registryName == "" {
+		progress.Error("Registry name is required")
+		return errors.NewValidationError("registry_name", "registry name is required")
+	}
+	if version == "" {
+		progress.Error("Version is required")
+		return errors.NewValidationError("version", "version is required")
+	}
 
-			progress.Success("Input parameters validated")
+	// Validate directory exists
+	dirInfo, err := os.Stat(dir)
+	if err != nil {
+		return errors.NewValidationError("folder_path", fmt.Sprintf("failed to access folder: %v", err))
+	}
+	if !dirInfo.IsDir() {
+		return errors.NewValidationError("folder_path", "path must be a directory")
+	}
 
-			// Generate package files
-			generator := gopkg.NewGenerator(dir, output, version)
-			packageName, err := generator.Generate(progress)
-			if err != nil {
-				return err
-			}
+	progress.Success("Input parameters validated")
 
-			// Create form data
-			progress.Step("Preparing package upload")
-			var formData bytes.Buffer
-			var formWriter = multipart.NewWriter(&formData)
-
-			// Add files to form
-			var files = []struct {
-				name     string
-				filename string
-			}{
-				{"mod", packageName + ".mod"},
-				{"info", packageName + ".info"},
-				{"zip", packageName + ".zip"},
-			}
+	// Generate package files
+	generator := gopkg.NewGenerator(dir, output, version)
+	packageName, err := generator.Generate(progress)
+	if err != nil {
+		return err
+	}
 
-			// Upload files
-			progress.Step("Preparing package files")
-			for _, file := range files {
-				progress.Step(fmt.Sprintf("Adding %s to upload", file.filename))
-				f, openErr := os.Open(filepath.Join(output, file.filename))
-				if openErr != nil {
-					progress.Error(fmt.Sprintf("Failed to open %s", file.filename))
-					return openErr
-				}
-				defer f.Close()
-
-				part, formErr := formWriter.CreateFormFile(file.name, file.filename)
-				if formErr != nil {
-					progress.Error(fmt.Sprintf("Failed to create form field for %s", file.filename))
-					return formErr
-				}
-
-				if _, copyErr := io.Copy(part, f); copyErr != nil {
-					progress.Error(fmt.Sprintf("Failed to copy %s content", file.filename))
-					return copyErr
-				}
-			}
+	// Create form data
+	progress.Step("Preparing package upload")
+	var formData bytes.Buffer
+	var formWriter = multipart.NewWriter(&formData)
+
+	// Add files to form
+	var files = []struct {
+		name     string
+		filename string
+	}{
+		{"mod", packageName + ".mod"},
+		{"info", packageName + ".info"},
+		{"zip", packageName + ".zip"},
+	}
 
-			// Close multipart writer
-			if closeErr := formWriter.Close(); closeErr != nil {
-				progress.Error("Failed to finalize form data")
-				return closeErr
-			}
-			// Upload package
-			progress.Step("Uploading package to registry")
-			bufferSize := int64(formData.Len())
-
-			// Initialize the package client
-			pkgClient := c.PkgHttpClientWithProgress(progress, bufferSize, "go")
-
-			resp, err := pkgClient.UploadGoPackageWithBodyWithResponse(
-				context.Background(),
-				config.Global.AccountID,
-				registryName,
-				formWriter.FormDataContentType(),
-				&formData,
-			)
-			if err != nil {
-				progress.Error("Failed to upload package")
-				return err
-			}
+	// Upload files
+	progress.Step("Preparing package files")
+	for _, file := range files {
+		progress.Step(fmt.Sprintf("Adding %s to upload", file.filename))
+		f, openErr := os.Open(filepath.Join(output, file.filename))
+		if openErr != nil {
+			progress.Error(fmt.Sprintf("Failed to open %s", file.filename))
+			return openErr
+		}
+		defer f.Close()
+
+		part, formErr := formWriter.CreateFormFile(file.name, file.filename)
+		if formErr != nil {
+			progress.Error(fmt.Sprintf("Failed to create form field for %s", file.filename))
+			return formErr
+		}
+
+		if _, copyErr := io.Copy(part, f); copyErr != nil {
+			progress.Error(fmt.Sprintf("Failed to copy %s content", file.filename))
+			return copyErr
+		}
+	}
 
-			// Check response
-			if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusCreated {
-				progress.Error("Upload failed")
-				return fmt.Errorf("failed to push package: %s \n response: %s", resp.Status(), resp.Body)
-			}
+	// Close multipart writer
+	if closeErr := formWriter.Close(); closeErr != nil {
+		progress.Error("Failed to finalize form data")
+		return closeErr
+	}
+	// Upload package
+	progress.Step("Uploading package to registry")
+	bufferSize := int64(formData.Len())
+
+	// Initialize the package client
+	pkgClient, err := u.client(bufferSize, "go")
+	if err != nil {
+		return err
+	}
 
-			progress.Success(fmt.Sprintf("Successfully uploaded package %s", packageName))
-			return nil
-		},
+	resp, err := pkgClient.UploadGoPackageWithBodyWithResponse(
+		ctx,
+		config.Global.AccountID,
+		registryName,
+		formWriter.FormDataContentType(),
+		&formData,
+	)
+	if err != nil {
+		progress.Error("Failed to upload package")
+		return err
 	}
 
-	cmd.Flags().StringVar(&pkgURL, "pkg-url", "", "Base URL for the Packages")
-	cmd.Flags().StringVar(&version, "version", "", "Version for the package")
-	return cmd
+	// Check response
+	if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusCreated {
+		progress.Error("Upload failed")
+		return fmt.Errorf("failed to push package: %s \n response: %s", resp.Status(), resp.Body)
+	}
+
+	progress.Success(fmt.Sprintf("Successfully uploaded package %s", packageName))
+	return nil
 }
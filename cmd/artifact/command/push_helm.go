@@ -0,0 +1,284 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/harness/harness-cli/cmd/cmdutils"
+	"github.com/harness/harness-cli/module/ar/migrate/util"
+	"github.com/harness/harness-cli/util/common/printer"
+	p "github.com/harness/harness-cli/util/common/progress"
+
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/spf13/cobra"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/downloader"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/provenance"
+	"helm.sh/helm/v3/pkg/registry"
+)
+
+// pushedChart describes the chart written by push helm
+type pushedChart struct {
+	Reference  string `json:"reference"`
+	Digest     string `json:"digest"`
+	Chart      string `json:"chart"`
+	Version    string `json:"version"`
+	Package    string `json:"package"`
+	Provenance string `json:"provenance,omitempty"`
+}
+
+// helmPackageOptions controls how a chart directory is packaged
+type helmPackageOptions struct {
+	destination          string
+	skipDependencyUpdate bool
+	sign                 bool
+	key                  string
+	keyring              string
+	passphraseFile       string
+}
+
+// NewPushHelmCmd creates a new cobra.Command for pushing Helm charts to a
+// Helm OCI registry.
+// command example: hc artifact push helm <registry_name> <chart-dir|chart.tgz>
+//
+// A chart directory is packaged first, after its dependencies are updated,
+// and optionally signed into a provenance file that is pushed with the chart.
+func NewPushHelmCmd(c *cmdutils.Factory) *cobra.Command {
+	const expectedNumberOfArgument = 2
+	var opts helmPackageOptions
+	cmd := &cobra.Command{
+		Use:   "helm <registry_name> <chart-dir|chart.tgz>",
+		Short: "Push Helm Charts",
+		Long: `Push a Helm chart to a Harness Helm registry as an OCI artifact. A chart
+directory is packaged first, updating its dependencies unless
+--skip-dependency-update is set; --sign writes a provenance file with a GPG key
+and pushes it along with the chart. A packaged chart is pushed as is, together
+with its <chart>.tgz.prov file when one exists.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != expectedNumberOfArgument {
+				return fmt.Errorf(
+					"Error: Invalid number of argument,  accepts %d arg(s), received %d  \nUsage :\n %s",
+					expectedNumberOfArgument, len(args), cmd.UseLine(),
+				)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := pushHelmChart(cmd.Context(), c, args[0], args[1], opts, p.NewConsoleReporter())
+			if err != nil {
+				return err
+			}
+			options := printer.DefaultJsonOptions()
+			options.ShowPagination = false
+			return printer.PrintJsonWithOptions(result, options)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.destination, "destination", "d", "",
+		"Keep the packaged chart (and provenance file) in this directory")
+	cmd.Flags().BoolVar(&opts.skipDependencyUpdate, "skip-dependency-update", false,
+		"Package the chart with the dependencies already in its charts/ directory")
+	cmd.Flags().BoolVar(&opts.sign, "sign", false, "Sign the chart with a GPG key into a provenance file")
+	cmd.Flags().StringVar(&opts.key, "key", "", "Name of the key to sign with")
+	cmd.Flags().StringVar(&opts.keyring, "keyring", os.ExpandEnv("$HOME/.gnupg/pubring.gpg"),
+		"Keyring holding the signing key")
+	cmd.Flags().StringVar(&opts.passphraseFile, "passphrase-file", "",
+		`File holding the passphrase of the signing key, or "-" to read it from stdin`)
+
+	return cmd
+}
+
+// pushHelmChart packages the chart directory source, or loads the packaged
+// chart source, and pushes it with its provenance file
+func pushHelmChart(ctx context.Context, c *cmdutils.Factory, registryName, source string, opts helmPackageOptions,
+	progress p.Reporter) (pushedChart, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return pushedChart{}, fmt.Errorf("cannot access %q: %w", source, err)
+	}
+	if opts.sign && opts.key == "" {
+		return pushedChart{}, fmt.Errorf("--sign needs --key to name the signing key")
+	}
+
+	// a chart packaged into a temporary directory is not reported
+	keepPackage := opts.destination != ""
+	archive := source
+	if info.IsDir() {
+		if opts.destination == "" {
+			tmpDir, err := os.MkdirTemp("", "hc-helm-*")
+			if err != nil {
+				return pushedChart{}, err
+			}
+			defer os.RemoveAll(tmpDir)
+			opts.destination = tmpDir
+		}
+		progress.Start(fmt.Sprintf("Packaging chart %s", source))
+		if archive, err = packageChart(source, opts); err != nil {
+			progress.Error("Failed to package chart")
+			return pushedChart{}, err
+		}
+	} else if opts.sign {
+		progress.Start(fmt.Sprintf("Signing chart %s", source))
+		if err := signChart(archive, opts); err != nil {
+			progress.Error("Failed to sign chart")
+			return pushedChart{}, fmt.Errorf("failed to sign %s: %w", archive, err)
+		}
+	} else {
+		progress.Start(fmt.Sprintf("Loading chart %s", source))
+	}
+
+	meta, err := loadChartArchive(archive)
+	if err != nil {
+		progress.Error("Invalid chart")
+		return pushedChart{}, err
+	}
+	chartData, err := os.ReadFile(archive)
+	if err != nil {
+		return pushedChart{}, err
+	}
+	result := pushedChart{Chart: meta.Name, Version: meta.Version, Package: archive}
+	var provData []byte
+	if data, err := os.ReadFile(archive + ".prov"); err == nil {
+		provData = data
+		result.Provenance = archive + ".prov"
+	} else if !os.IsNotExist(err) {
+		return pushedChart{}, err
+	}
+	img, err := util.ChartImage(meta, chartData, provData)
+	if err != nil {
+		progress.Error("Invalid chart")
+		return pushedChart{}, fmt.Errorf("chart %s: %w", meta.Name, err)
+	}
+
+	reg, err := resolveDockerRegistry(ctx, c, registryName)
+	if err != nil {
+		progress.Error("Failed to resolve registry")
+		return pushedChart{}, err
+	}
+	ref, err := reg.reference(meta.Name + ":" + meta.Version)
+	if err != nil {
+		return pushedChart{}, err
+	}
+	progress.Step(fmt.Sprintf("Pushing %s", ref))
+	if err := remote.Write(ref, img, reg.options...); err != nil {
+		progress.Error(fmt.Sprintf("Failed to push %s", ref))
+		return pushedChart{}, fmt.Errorf("failed to push %s: %w", ref, err)
+	}
+	digest, err := img.Digest()
+	if err != nil {
+		return pushedChart{}, err
+	}
+	result.Reference, result.Digest = ref.String(), digest.String()
+	if info.IsDir() && !keepPackage {
+		result.Package, result.Provenance = "", ""
+	}
+	progress.Success(fmt.Sprintf("Pushed chart %s:%s to registry '%s'", meta.Name, meta.Version,
+		registryName))
+
+	return result, nil
+}
+
+// signChart writes the provenance file <archive>.prov, signed with the
+// configured key
+func signChart(archive string, opts helmPackageOptions) error {
+	signer, err := provenance.NewFromKeyring(opts.keyring, opts.key)
+	if err != nil {
+		return err
+	}
+	if err := signer.DecryptKey(func(string) ([]byte, error) {
+		return readPassphrase(opts.passphraseFile)
+	}); err != nil {
+		return err
+	}
+	sig, err := signer.ClearSign(archive)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(archive+".prov", []byte(sig), 0o644)
+}
+
+// readPassphrase reads the first line of the passphrase file, or of stdin
+// for "-"
+func readPassphrase(file string) ([]byte, error) {
+	if file == "" {
+		return nil, fmt.Errorf("--passphrase-file is needed to unlock the signing key")
+	}
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return nil, err
+	}
+	line, _, _ := strings.Cut(string(data), "\n")
+	return []byte(strings.TrimSuffix(line, "\r")), nil
+}
+
+// packageChart packages a chart directory the way `helm package` does and
+// returns the path of the archive
+func packageChart(dir string, opts helmPackageOptions) (string, error) {
+	// loading validates Chart.yaml
+	ch, err := loader.LoadDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("invalid chart %s: %w", dir, err)
+	}
+
+	if len(ch.Metadata.Dependencies) > 0 && !opts.skipDependencyUpdate {
+		settings := cli.New()
+		registryClient, err := registry.NewClient(registry.ClientOptCredentialsFile(settings.RegistryConfig))
+		if err != nil {
+			return "", fmt.Errorf("failed to create helm registry client: %w", err)
+		}
+		manager := &downloader.Manager{
+			Out:              io.Discard,
+			ChartPath:        dir,
+			Keyring:          opts.keyring,
+			Getters:          getter.All(settings),
+			RegistryClient:   registryClient,
+			RepositoryConfig: settings.RepositoryConfig,
+			RepositoryCache:  settings.RepositoryCache,
+		}
+		if err := manager.Update(); err != nil {
+			return "", fmt.Errorf("failed to update dependencies of %s: %w", dir, err)
+		}
+	}
+
+	if err := os.MkdirAll(opts.destination, 0o755); err != nil {
+		return "", err
+	}
+	// reload so the packaged chart carries the updated dependencies
+	if ch, err = loader.LoadDir(dir); err != nil {
+		return "", fmt.Errorf("invalid chart %s: %w", dir, err)
+	}
+	archive, err := chartutil.Save(ch, opts.destination)
+	if err != nil {
+		return "", fmt.Errorf("failed to package %s: %w", dir, err)
+	}
+	if opts.sign {
+		if err := signChart(archive, opts); err != nil {
+			return "", fmt.Errorf("failed to sign %s: %w", archive, err)
+		}
+	}
+	return filepath.Clean(archive), nil
+}
+
+// loadChartArchive loads a packaged chart
+func loadChartArchive(path string) (*chart.Metadata, error) {
+	// loading validates Chart.yaml
+	ch, err := loader.LoadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chart %s: %w", path, err)
+	}
+	return ch.Metadata, nil
+}
@@ -0,0 +1,95 @@
+package command
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/harness/harness-cli/cmd/cmdutils"
+	"github.com/harness/harness-cli/module/ar/migrate/util"
+
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+const demoChartYAML = `apiVersion: v2
+name: demo
+description: A demo chart
+version: 0.1.0
+appVersion: "1.2.3"
+`
+
+func runPushHelm(t *testing.T, f *cmdutils.Factory, args ...string) error {
+	t.Helper()
+	cmd := NewPushHelmCmd(f)
+	cmd.SetArgs(args)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	return cmd.Execute()
+}
+
+func TestPushHelmPackagesChartDirectory(t *testing.T) {
+	f, prefix := dockerRegistryServer(t)
+	dir := makeTree(t, map[string]string{
+		"demo/Chart.yaml":                demoChartYAML,
+		"demo/values.yaml":               "replicas: 1\n",
+		"demo/templates/deployment.yaml": "kind: Deployment\n",
+	})
+	dest := t.TempDir()
+
+	require.NoError(t, runPushHelm(t, f, "docker-reg", filepath.Join(dir, "demo"), "--destination", dest))
+	assert.FileExists(t, filepath.Join(dest, "demo-0.1.0.tgz"))
+
+	img, err := remote.Image(mustRef(t, prefix+"/demo:0.1.0"))
+	require.NoError(t, err)
+	manifest, err := img.Manifest()
+	require.NoError(t, err)
+	assert.Equal(t, util.HelmChartConfigMediaType, string(manifest.Config.MediaType))
+	require.Len(t, manifest.Layers, 1)
+	assert.Equal(t, util.HelmChartContentMediaType, string(manifest.Layers[0].MediaType))
+	assert.Equal(t, "demo", manifest.Annotations["org.opencontainers.image.title"])
+	// the chart labels HAR indexes the chart by
+	assert.Equal(t, "demo-0.1.0", manifest.Annotations["helm.sh/chart"])
+	assert.Equal(t, "1.2.3", manifest.Annotations["chart.appVersion"])
+	assert.Equal(t, "A demo chart", manifest.Annotations["chart.description"])
+
+	raw, err := img.RawConfigFile()
+	require.NoError(t, err)
+	var meta chart.Metadata
+	require.NoError(t, json.Unmarshal(raw, &meta))
+	assert.Equal(t, "demo", meta.Name)
+	assert.Equal(t, "1.2.3", meta.AppVersion)
+}
+
+func TestPushHelmArchiveWithProvenance(t *testing.T) {
+	f, prefix := dockerRegistryServer(t)
+	dir := makeTree(t, map[string]string{"demo/Chart.yaml": demoChartYAML})
+	ch, err := loader.LoadDir(filepath.Join(dir, "demo"))
+	require.NoError(t, err)
+	archive, err := chartutil.Save(ch, t.TempDir())
+	require.NoError(t, err)
+	writeFile(t, filepath.Dir(archive), filepath.Base(archive)+".prov", "-----BEGIN PGP SIGNED MESSAGE-----\n")
+
+	require.NoError(t, runPushHelm(t, f, "docker-reg", archive))
+
+	img, err := remote.Image(mustRef(t, prefix+"/demo:0.1.0"))
+	require.NoError(t, err)
+	manifest, err := img.Manifest()
+	require.NoError(t, err)
+	require.Len(t, manifest.Layers, 2)
+	assert.Equal(t, util.HelmChartProvenanceMediaType, string(manifest.Layers[1].MediaType))
+}
+
+func TestPushHelmRejectsInvalidChart(t *testing.T) {
+	f, _ := dockerRegistryServer(t)
+	dir := makeTree(t, map[string]string{"demo/Chart.yaml": "apiVersion: v2\nname: demo\n"})
+
+	err := runPushHelm(t, f, "docker-reg", filepath.Join(dir, "demo"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "chart.metadata.version is required")
+}
@@ -0,0 +1,172 @@
+package command
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/harness/harness-cli/cmd/cmdutils"
+	"github.com/harness/harness-cli/config"
+	"github.com/harness/harness-cli/util"
+	"github.com/harness/harness-cli/util/common/printer"
+	p "github.com/harness/harness-cli/util/common/progress"
+	"github.com/harness/harness-cli/util/common/upload"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+)
+
+// pushedHFRepo describes the revision written by push huggingface
+type pushedHFRepo struct {
+	Repository  string `json:"repository"`
+	Type        string `json:"type"`
+	Revision    string `json:"revision"`
+	Commit      string `json:"commit,omitempty"`
+	Files       int    `json:"files"`
+	LFSUploaded int    `json:"lfsUploaded"`
+	LFSSkipped  int    `json:"lfsSkipped"`
+}
+
+// hfPushOptions are the repository, revision and commit a push of a
+// HuggingFace directory writes
+type hfPushOptions struct {
+	name, revision, artifactType, message string
+	maxConcurrentUploads                  int
+}
+
+// NewPushHuggingFaceCmd creates a new cobra.Command for pushing a local
+// HuggingFace model or dataset repository.
+// command example: hc artifact push huggingface <registry_name> <dir> --name <org>/<model>
+//
+// Files the registry wants stored through LFS, such as safetensors weights,
+// are uploaded first, in chunks when the registry asks for a multipart
+// transfer; the revision is then committed with every file of the directory.
+func NewPushHuggingFaceCmd(c *cmdutils.Factory) *cobra.Command {
+	const expectedNumberOfArgument = 2
+	var opts hfPushOptions
+	var pkgURL string
+	cmd := &cobra.Command{
+		Use:   "huggingface <registry_name> <dir>",
+		Short: "Push HuggingFace Models and Datasets",
+		Long: `Push a local HuggingFace model or dataset directory (weights, config and
+tokenizer files) to a Harness HuggingFace registry as one commit on a revision.
+Large files go through LFS, chunked when the registry asks for it; LFS files the
+registry already holds are not uploaded again, nor are the parts of a chunked
+upload already sent, so an interrupted push can simply be run again.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != expectedNumberOfArgument {
+				return fmt.Errorf(
+					"Error: Invalid number of argument,  accepts %d arg(s), received %d  \nUsage :\n %s",
+					expectedNumberOfArgument, len(args), cmd.UseLine(),
+				)
+			}
+			return nil
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			if pkgURL != "" {
+				config.Global.Registry.PkgURL = util.GetPkgUrl(pkgURL)
+			}
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			progress := p.NewConsoleReporter()
+			result, err := pushHuggingFace(cmd.Context(), newPkgUploader(progress), args[0], args[1], opts, progress)
+			if err != nil {
+				return err
+			}
+			options := printer.DefaultJsonOptions()
+			options.ShowPagination = false
+			return printer.PrintJsonWithOptions(result, options)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.name, "name", "", "Repository name <org>/<name>")
+	cmd.Flags().StringVar(&opts.revision, "revision", "main", "Revision (branch) to commit to")
+	cmd.Flags().StringVar(&opts.artifactType, "artifact-type", hfModel, "Repository type: model or dataset")
+	cmd.Flags().StringVarP(&opts.message, "message", "m", "", "Commit message")
+	cmd.Flags().StringVar(&pkgURL, "pkg-url", "", "Base URL for the Packages")
+	cmd.Flags().IntVar(&opts.maxConcurrentUploads, "max-concurrent-uploads", upload.DefaultUploadWorker,
+		"Maximum number of LFS files to upload in parallel")
+	cmd.MarkFlagRequired("name")
+
+	return cmd
+}
+
+// pushHuggingFace uploads the LFS files of dir, then commits every file of
+// it to the revision
+func pushHuggingFace(ctx context.Context, u pkgUploader, registryName, dir string, opts hfPushOptions,
+	progress p.Reporter) (pushedHFRepo, error) {
+	name, revision := opts.name, opts.revision
+	repo, err := newHFRepo(u, registryName, opts.artifactType, name, revision)
+	if err != nil {
+		return pushedHFRepo{}, err
+	}
+
+	progress.Start(fmt.Sprintf("Reading %s", dir))
+	files, err := collectHFFiles(dir, repo.uploadStateDir())
+	if err != nil {
+		progress.Error("Failed to read directory")
+		return pushedHFRepo{}, err
+	}
+	if len(files) == 0 {
+		progress.Error("Nothing to push")
+		return pushedHFRepo{}, fmt.Errorf("no files found in %s", dir)
+	}
+
+	progress.Step(fmt.Sprintf("Checking upload mode of %d file(s)", len(files)))
+	if err := repo.preUpload(ctx, files); err != nil {
+		progress.Error("Pre-upload check failed")
+		return pushedHFRepo{}, err
+	}
+	var lfsFiles []*hfFile
+	for _, f := range files {
+		if f.lfs {
+			lfsFiles = append(lfsFiles, f)
+		}
+	}
+
+	result := pushedHFRepo{Repository: name, Type: opts.artifactType, Revision: revision, Files: len(files)}
+	if len(lfsFiles) > 0 {
+		transfer, objects, err := repo.lfsBatch(ctx, lfsFiles)
+		if err != nil {
+			progress.Error("LFS batch request failed")
+			return pushedHFRepo{}, err
+		}
+		g, gctx := errgroup.WithContext(ctx)
+		g.SetLimit(max(opts.maxConcurrentUploads, 1))
+		for _, f := range lfsFiles {
+			obj := objects[f.sha256]
+			if _, ok := obj.Actions["upload"]; !ok {
+				result.LFSSkipped++
+				continue
+			}
+			result.LFSUploaded++
+			g.Go(func() error {
+				progress.Step(fmt.Sprintf("Uploading %s (%d bytes)", f.path, f.size))
+				if err := repo.uploadLFS(gctx, transfer, f, obj); err != nil {
+					return fmt.Errorf("failed to upload %s: %w", f.path, err)
+				}
+				return nil
+			})
+		}
+		if err := g.Wait(); err != nil {
+			progress.Error("LFS upload failed")
+			return pushedHFRepo{}, err
+		}
+		if result.LFSSkipped > 0 {
+			progress.Step(fmt.Sprintf("skipping %d LFS file(s) already in the registry", result.LFSSkipped))
+		}
+	}
+
+	progress.Step(fmt.Sprintf("Committing %d file(s) to %s@%s", len(files), name, revision))
+	message := opts.message
+	if message == "" {
+		message = fmt.Sprintf("Upload %s with hc", name)
+	}
+	if result.Commit, err = repo.commit(ctx, files, message); err != nil {
+		progress.Error("Commit failed")
+		return pushedHFRepo{}, err
+	}
+	progress.Success(fmt.Sprintf("Pushed %d file(s) to %s@%s in registry '%s'", len(files), name, revision,
+		registryName))
+
+	return result, nil
+}
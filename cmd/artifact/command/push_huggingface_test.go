@@ -0,0 +1,281 @@
+package command
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/harness/harness-cli/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hfServer is a HuggingFace Hub API for model org/model of registry "hf".
+// Files larger than lfsThreshold go through LFS, uploaded in chunkSize parts
+// when chunkSize is set.
+type hfServer struct {
+	mu           sync.Mutex
+	lfsThreshold int
+	chunkSize    int
+	lfs          map[string][]byte         // stored LFS objects by oid
+	parts        map[string]map[int][]byte // multipart uploads in progress
+	files        map[string][]byte         // committed files at main
+	lfsPaths     map[string]bool
+	uploads      []string // oids uploaded
+	partPuts     map[int]int
+	failPart     int // the next upload of this part number is refused
+}
+
+func newHFServer(t *testing.T) *hfServer {
+	t.Helper()
+	// upload state is recorded under the user cache directory
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	s := &hfServer{
+		lfsThreshold: 16,
+		lfs:          map[string][]byte{},
+		parts:        map[string]map[int][]byte{},
+		files:        map[string][]byte{},
+		lfsPaths:     map[string]bool{},
+		partPuts:     map[int]int{},
+	}
+	const base = "/pkg/acct/hf/huggingface"
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		p := strings.TrimPrefix(r.URL.Path, base)
+		switch {
+		case p == "/api/models/org/model/preupload/main":
+			var req struct {
+				Files []struct {
+					Path string `json:"path"`
+					Size int    `json:"size"`
+				} `json:"files"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			var resp struct {
+				Files []map[string]string `json:"files"`
+			}
+			for _, f := range req.Files {
+				mode := "regular"
+				if f.Size > s.lfsThreshold {
+					mode = "lfs"
+				}
+				resp.Files = append(resp.Files, map[string]string{"path": f.Path, "uploadMode": mode})
+			}
+			_ = json.NewEncoder(w).Encode(resp)
+		case p == "/org/model.git/info/lfs/objects/batch":
+			var req struct {
+				Objects []lfsObject `json:"objects"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			transfer := "basic"
+			if s.chunkSize > 0 {
+				transfer = "multipart"
+			}
+			var objects []lfsObject
+			for _, o := range req.Objects {
+				if _, ok := s.lfs[o.Oid]; !ok {
+					o.Actions = map[string]lfsAction{"verify": {Href: ts.URL + base + "/verify"}}
+					if transfer == "multipart" {
+						header := map[string]string{"chunk_size": strconv.Itoa(s.chunkSize)}
+						for n := 1; int64(n-1)*int64(s.chunkSize) < o.Size; n++ {
+							header[fmt.Sprintf("%05d", n)] = fmt.Sprintf("%s/parts/%s/%d", ts.URL, o.Oid, n)
+						}
+						o.Actions["upload"] = lfsAction{Href: ts.URL + base + "/complete/" + o.Oid, Header: header}
+					} else {
+						o.Actions["upload"] = lfsAction{Href: ts.URL + base + "/lfs/" + o.Oid}
+					}
+				}
+				objects = append(objects, o)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"transfer": transfer, "objects": objects})
+		case strings.HasPrefix(p, "/lfs/"):
+			body, _ := io.ReadAll(r.Body)
+			s.lfs[strings.TrimPrefix(p, "/lfs/")] = body
+			s.uploads = append(s.uploads, strings.TrimPrefix(p, "/lfs/"))
+		case strings.HasPrefix(r.URL.Path, "/parts/"):
+			oid, num, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/parts/"), "/")
+			n, _ := strconv.Atoi(num)
+			if n == s.failPart {
+				s.failPart = 0
+				http.Error(w, "connection reset", http.StatusForbidden)
+				return
+			}
+			s.partPuts[n]++
+			body, _ := io.ReadAll(r.Body)
+			if s.parts[oid] == nil {
+				s.parts[oid] = map[int][]byte{}
+			}
+			s.parts[oid][n] = body
+			w.Header().Set("ETag", fmt.Sprintf(`"etag-%d"`, n))
+		case strings.HasPrefix(p, "/complete/"):
+			oid := strings.TrimPrefix(p, "/complete/")
+			var req struct {
+				Parts []struct {
+					PartNumber int    `json:"partNumber"`
+					ETag       string `json:"etag"`
+				} `json:"parts"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			var whole []byte
+			for _, part := range req.Parts {
+				assert.Equal(t, fmt.Sprintf(`"etag-%d"`, part.PartNumber), part.ETag)
+				whole = append(whole, s.parts[oid][part.PartNumber]...)
+			}
+			s.lfs[oid] = whole
+			s.uploads = append(s.uploads, oid)
+		case p == "/verify":
+		case p == "/api/models/org/model/commit/main":
+			assert.Equal(t, "application/x-ndjson", r.Header.Get("Content-Type"))
+			scanner := bufio.NewScanner(r.Body)
+			for scanner.Scan() {
+				var op struct {
+					Key   string         `json:"key"`
+					Value map[string]any `json:"value"`
+				}
+				require.NoError(t, json.Unmarshal(scanner.Bytes(), &op))
+				path, _ := op.Value["path"].(string)
+				switch op.Key {
+				case "file":
+					content, err := base64.StdEncoding.DecodeString(op.Value["content"].(string))
+					require.NoError(t, err)
+					s.files[path] = content
+				case "lfsFile":
+					content, ok := s.lfs[op.Value["oid"].(string)]
+					if !ok {
+						http.Error(w, "unknown LFS object", http.StatusBadRequest)
+						return
+					}
+					s.files[path], s.lfsPaths[path] = content, true
+				}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]string{"commitOid": "c0ffee"})
+		case p == "/api/models/org/model/revision/main":
+			var siblings []hfSibling
+			for path, content := range s.files {
+				sib := hfSibling{Name: path}
+				if s.lfsPaths[path] {
+					sum := sha256.Sum256(content)
+					sib.LFS = &struct {
+						SHA256 string `json:"sha256"`
+						Size   int64  `json:"size"`
+					}{SHA256: hex.EncodeToString(sum[:]), Size: int64(len(content))}
+				}
+				siblings = append(siblings, sib)
+			}
+			sort.Slice(siblings, func(i, j int) bool { return siblings[i].Name < siblings[j].Name })
+			_ = json.NewEncoder(w).Encode(map[string]any{"sha": "c0ffee", "siblings": siblings})
+		case strings.HasPrefix(p, "/org/model/resolve/main/"):
+			content, ok := s.files[strings.TrimPrefix(p, "/org/model/resolve/main/")]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(content))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	withPullConfig(t)
+	origPkgURL := config.Global.Registry.PkgURL
+	config.Global.Registry.PkgURL = ts.URL
+	t.Cleanup(func() { config.Global.Registry.PkgURL = origPkgURL })
+	return s
+}
+
+func sha256Hex(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestPushHuggingFaceModel(t *testing.T) {
+	s := newHFServer(t)
+	s.chunkSize = 8
+	weights := strings.Repeat("w", 20)
+	dir := makeTree(t, map[string]string{
+		"config.json":                 `{"model_type":"bert"}`,
+		"tokenizer/vocab.txt":         "a\nb\n",
+		"model.safetensors":           weights,
+		".git/HEAD":                   "ref: refs/heads/main",
+		".cache/huggingface/.gitkeep": "",
+	})
+
+	require.NoError(t, runCobraCmd(t, NewPushHuggingFaceCmd(nil), "hf", dir, "--name", "org/model"))
+
+	assert.Equal(t, map[string][]byte{
+		"config.json":         []byte(`{"model_type":"bert"}`),
+		"tokenizer/vocab.txt": []byte("a\nb\n"),
+		"model.safetensors":   []byte(weights),
+	}, s.files)
+	assert.True(t, s.lfsPaths["model.safetensors"])
+	assert.Len(t, s.parts[sha256Hex(weights)], 3)
+}
+
+func TestPushHuggingFaceResumesChunkedUpload(t *testing.T) {
+	s := newHFServer(t)
+	s.chunkSize = 8
+	s.failPart = 2
+	weights := strings.Repeat("w", 10) + strings.Repeat("x", 10)
+	dir := makeTree(t, map[string]string{"model.safetensors": weights})
+
+	err := runCobraCmd(t, NewPushHuggingFaceCmd(nil), "hf", dir, "--name", "org/model")
+	require.ErrorContains(t, err, "failed to upload part 2 of model.safetensors")
+	// the state is kept outside the pushed directory, keyed by repository
+	// and revision
+	state, err := filepath.Glob(filepath.Join(os.Getenv("XDG_CACHE_HOME"),
+		"hc/huggingface/upload/*/org/model/main/model.safetensors.json"))
+	require.NoError(t, err)
+	require.Len(t, state, 1)
+	assert.NoDirExists(t, filepath.Join(dir, ".cache"))
+
+	require.NoError(t, runCobraCmd(t, NewPushHuggingFaceCmd(nil), "hf", dir, "--name", "org/model"))
+	// the part sent before the interruption is not sent again
+	assert.Equal(t, map[int]int{1: 1, 2: 1, 3: 1}, s.partPuts)
+	assert.Equal(t, []byte(weights), s.files["model.safetensors"])
+	assert.NoFileExists(t, state[0])
+}
+
+func TestPushHuggingFaceSkipsStoredLFSObjects(t *testing.T) {
+	s := newHFServer(t)
+	weights := strings.Repeat("w", 32)
+	s.lfs[sha256Hex(weights)] = []byte(weights)
+	dir := makeTree(t, map[string]string{"model.safetensors": weights, "config.json": "{}"})
+
+	require.NoError(t, runCobraCmd(t, NewPushHuggingFaceCmd(nil), "hf", dir, "--name", "org/model"))
+
+	assert.Empty(t, s.uploads)
+	assert.Equal(t, []byte(weights), s.files["model.safetensors"])
+}
+
+func TestPushHuggingFaceValidatesRepository(t *testing.T) {
+	newHFServer(t)
+	dir := makeTree(t, map[string]string{"config.json": "{}"})
+
+	err := runCobraCmd(t, NewPushHuggingFaceCmd(nil), "hf", dir, "--name", "model")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected format: <org>/<name>")
+
+	err = runCobraCmd(t, NewPushHuggingFaceCmd(nil), "hf", dir, "--name", "org/model", "--artifact-type", "space")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid artifact type "space"`)
+}
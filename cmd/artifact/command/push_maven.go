@@ -6,9 +6,12 @@ import (
 	"context"
 	"crypto/md5"
 	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
 	"encoding/hex"
 	"encoding/xml"
 	"fmt"
+	"hash"
 	"io"
 	"net/http"
 	"os"
@@ -32,17 +35,31 @@ const (
 	jarFileExtension = ".jar"
 	xmlFileExtension = ".xml"
 	pomFileExtension = ".pom"
+	ascFileExtension = ".asc"
 )
 
+// NewPushMavenCmd creates a new cobra.Command for pushing a Maven artifact.
+// command example: hc artifact push maven <registry_name> <file_path> --pom-file pom.xml
+//
+// Without a POM file the coordinates come from --group-id, --artifact-id and
+// --version, and a minimal POM is generated for them. md5, sha1, sha256 and
+// sha512 checksums are uploaded for every file, and maven-metadata.xml is
+// updated with the version.
 func NewPushMavenCmd(c *cmdutils.Factory) *cobra.Command {
 	var pkgURL string
-	var pomPath string
+	var opts mavenPushOptions
 	var maxConcurrentUploads int = upload.DefaultUploadWorker
 	const expectedNumberOfArgument = 2
 	cmd := &cobra.Command{
 		Use:   "maven <registry_name> <file_path>",
 		Short: "Push Maven Artifacts",
-		Long:  "Push Maven Artifacts to Harness Artifact Registry",
+		Long: `Push Maven Artifacts to Harness Artifact Registry.
+
+The coordinates are read from --pom-file, or given with --group-id, --artifact-id
+and --version for files without a POM (vendored jars, native binaries), in which
+case a minimal POM is generated. --sources and --javadoc attach the matching
+jars, and a detached signature <file>.asc next to any of the files is uploaded
+with it.`,
 		Args: func(cmd *cobra.Command, args []string) error {
 			if len(args) != expectedNumberOfArgument {
 				return fmt.Errorf(
@@ -63,179 +80,282 @@ func NewPushMavenCmd(c *cmdutils.Factory) *cobra.Command {
 
 			// Create progress reporter
 			progress := p.NewConsoleReporter()
-			var mavenFilesToUpload []string
-
-			packageFileName := filepath.Base(pkgFilePath)
 
-			// Validate file exists
-			fileInfo, err := os.Stat(pkgFilePath)
+			coords, jobs, err := planMavenPush(c, registryName, pkgFilePath, opts, progress)
 			if err != nil {
-				return errors.NewValidationError("file_path", fmt.Sprintf("failed to access package file: %v", err))
-			}
-			if fileInfo.IsDir() {
-				return errors.NewValidationError("file_path", "package file path must be a file, not a directory")
+				return err
 			}
 
-			// validate file name
-			valid, err := isValidMavenPackageFile(packageFileName)
-			if !valid {
-				progress.Error("Invalid file name")
-				return errors.NewValidationError("file_path", fmt.Sprintf("failed to validate package file name: %v", err))
-			}
+			progress.Success(fmt.Sprintf("Prepared %d upload jobs", len(jobs)))
 
-			pomFileName := filepath.Base(pomPath)
+			// uploads using the engine
+			engine := upload.NewFileUploadEngine(maxConcurrentUploads, progress)
+			results := engine.Execute(context.Background(), jobs)
 
-			fileInfo, err = os.Stat(pomPath)
-			if err != nil {
-				return errors.NewValidationError("file_path", fmt.Sprintf("failed to access POM file: %v", err))
-			}
-			if fileInfo.IsDir() {
-				return errors.NewValidationError("file_path", "POM file path must be a file, not a directory")
+			if upload.HasUploadErrors(results) {
+				errors := upload.GetUploadErrors(results)
+				for jobID, err := range errors {
+					progress.Error(fmt.Sprintf("Failed to upload %s: %v", jobID, err))
+				}
+				return fmt.Errorf("failed to upload %d files", len(errors))
 			}
 
-			// validate file name
-			valid, err = isValidPomFile(pomFileName)
-			if !valid {
-				progress.Error("Invalid file name")
-				return errors.NewValidationError("file_path", fmt.Sprintf("failed to validate POM file name: %v", err))
+			if err := updateMavenMetadata(c.PkgHttpClient(), registryName, coords, progress); err != nil {
+				return err
 			}
+			progress.Success("maven-metadata.xml uploaded successfully")
+			progress.Success("Successfully uploaded package")
 
-			//reading  project details from pom file
-			coordsFromPom, err := parseMavenProjectLevelPom(pomPath)
+			return nil
 
-			if err != nil {
-				return errors.NewValidationError("XML_ERROR", fmt.Sprintf("failed to parse POM file: %v", err))
-			}
+		},
+	}
 
-			//reading  project detail from package war/jar file
-			coordsFromPackage, err := parseMavenArtifact(pkgFilePath)
+	cmd.Flags().StringVar(&opts.pomPath, "pom-file", "", "pom file path")
+	cmd.Flags().StringVar(&opts.groupID, "group-id", "", "Group ID, to push without a POM file")
+	cmd.Flags().StringVar(&opts.artifactID, "artifact-id", "", "Artifact ID, to push without a POM file")
+	cmd.Flags().StringVar(&opts.version, "version", "", "Version, to push without a POM file")
+	cmd.Flags().StringVar(&opts.packaging, "packaging", "", "Packaging of the generated POM (default: the file extension)")
+	cmd.Flags().StringVar(&opts.classifier, "classifier", "", "Classifier of the file, e.g. linux-x86_64")
+	cmd.Flags().StringVar(&opts.sourcesPath, "sources", "", "Sources jar to attach")
+	cmd.Flags().StringVar(&opts.javadocPath, "javadoc", "", "Javadoc jar to attach")
+	cmd.Flags().StringVar(&pkgURL, "pkg-url", "", "Base URL for the Packages")
+	cmd.Flags().IntVar(&maxConcurrentUploads, "max-concurrent-uploads", upload.DefaultUploadWorker, "Maximum number of concurrent file uploads, 1 for sequential")
+	cmd.MarkFlagsOneRequired("pom-file", "group-id")
+	cmd.MarkFlagsMutuallyExclusive("pom-file", "group-id")
+	cmd.MarkFlagsMutuallyExclusive("pom-file", "packaging")
+	cmd.MarkFlagsRequiredTogether("group-id", "artifact-id", "version")
 
-			if err != nil {
-				return errors.NewValidationError("PACKAGE_ERROR", fmt.Sprintf("failed to parse provided package file: %v", err))
-			}
+	return cmd
+}
 
-			//verify that package and pom is of same project and versionß
-			if err := compareMavenCoordinates(coordsFromPom, coordsFromPackage); err != nil {
-				return errors.NewValidationError("ERROR", fmt.Sprintf("failed to match package and POM parameters: %v", err))
+// mavenPushOptions are the flags of push maven
+type mavenPushOptions struct {
+	pomPath                      string
+	groupID, artifactID, version string
+	packaging, classifier        string
+	sourcesPath, javadocPath     string
+}
 
-			}
-			progress.Success(fmt.Sprintf("Maven coordinates validated successfully:"))
+// planMavenPush validates a push of pkgFilePath and returns the coordinates
+// of the version with the upload jobs of its files and their checksums
+func planMavenPush(c *cmdutils.Factory, registryName, pkgFilePath string, opts mavenPushOptions,
+	progress p.Reporter) (*mavenPackageMetadata, []upload.FileUploadJob, error) {
+	packageFileName := filepath.Base(pkgFilePath)
 
-			// Check for SNAPSHOT version , currently not supported
-			if err := validateSnapshotVersion(coordsFromPom.Version); err != nil {
-				return errors.NewValidationError("SNAPSHOT_ERROR", fmt.Sprintf("Failed in validating version : %v", err))
-			}
-			progress.Success("Input parameters validated")
+	// Validate file exists
+	if err := validateMavenInputFile("file_path", "package", pkgFilePath); err != nil {
+		return nil, nil, err
+	}
 
-			//Adding pom and package file
-			mavenFilesToUpload = append(mavenFilesToUpload, pkgFilePath)
-			mavenFilesToUpload = append(mavenFilesToUpload, pomPath)
+	var coords *mavenPackageMetadata
+	var mavenFilesToUpload []mavenUpload
+	mainFileName := packageFileName
+	if opts.pomPath != "" {
+		// validate file name
+		valid, err := isValidMavenPackageFile(packageFileName)
+		if !valid {
+			progress.Error("Invalid file name")
+			return nil, nil, errors.NewValidationError("file_path", fmt.Sprintf("failed to validate package file name: %v", err))
+		}
 
-			if len(mavenFilesToUpload) == 0 {
-				return errors.NewValidationError("Empty Folder", fmt.Sprintf("No Maven files found to process :"))
-			}
+		pomFileName := filepath.Base(opts.pomPath)
 
-			//Generating all  checksum artifacts in memory
-			checksumFiles, err := getAllChecksumFileToUpload(mavenFilesToUpload, coordsFromPom)
-			if err != nil {
-				return errors.NewValidationError("CHECKSUM_ERROR", fmt.Sprintf("Failed to generate CHECKSUM files: %v", err))
-			}
+		if err := validateMavenInputFile("file_path", "POM", opts.pomPath); err != nil {
+			return nil, nil, err
+		}
 
-			progress.Success("Checksum files generated")
+		// validate file name
+		valid, err = isValidPomFile(pomFileName)
+		if !valid {
+			progress.Error("Invalid file name")
+			return nil, nil, errors.NewValidationError("file_path", fmt.Sprintf("failed to validate POM file name: %v", err))
+		}
 
-			pkgClient := c.PkgHttpClient()
+		//reading  project details from pom file
+		coordsFromPom, err := parseMavenProjectLevelPom(opts.pomPath)
 
-			progress.Step("Preparing upload jobs")
-			jobs := make([]upload.FileUploadJob, 0, len(mavenFilesToUpload)+len(checksumFiles))
+		if err != nil {
+			return nil, nil, errors.NewValidationError("XML_ERROR", fmt.Sprintf("failed to parse POM file: %v", err))
+		}
 
-			// Adding files to upload in jobs
-			for _, fileNameWithPath := range mavenFilesToUpload {
-				progress.Step(fmt.Sprintf("Processing job for %s ", filepath.Base(fileNameWithPath)))
-				fileInfo, err := os.Stat(fileNameWithPath)
-				if err != nil {
-					return errors.NewValidationError("FILE_ERROR", fmt.Sprintf("Failed to stat file: %v", err))
-				}
+		//reading  project detail from package war/jar file
+		coordsFromPackage, err := parseMavenArtifact(pkgFilePath)
 
-				fileName := upload.NormalizeFileName(fileNameWithPath, coordsFromPom.ArtifactID, coordsFromPom.Version)
-				job := upload.NewMavenUploadJobFromDisk(
-					fileNameWithPath,
-					fileName,
-					registryName,
-					coordsFromPom.GroupID,
-					coordsFromPom.ArtifactID,
-					coordsFromPom.Version,
-					fileInfo.Size(),
-					pkgClient,
-				)
-				jobs = append(jobs, job)
-			}
+		if err != nil {
+			return nil, nil, errors.NewValidationError("PACKAGE_ERROR", fmt.Sprintf("failed to parse provided package file: %v", err))
+		}
 
-			// Add checksum upload jobs
-			progress.Step("preparing job for checksum files ")
-			for _, checksumFile := range checksumFiles {
-				job := upload.NewMavenUploadJobFromMemory(
-					checksumFile.FileName,
-					registryName,
-					coordsFromPom.GroupID,
-					coordsFromPom.ArtifactID,
-					coordsFromPom.Version,
-					checksumFile.Content,
-					pkgClient,
-				)
-				jobs = append(jobs, job)
-			}
+		//verify that package and pom is of same project and versionß
+		if err := compareMavenCoordinates(coordsFromPom, coordsFromPackage); err != nil {
+			return nil, nil, errors.NewValidationError("ERROR", fmt.Sprintf("failed to match package and POM parameters: %v", err))
 
-			progress.Success(fmt.Sprintf("Prepared %d upload jobs", len(jobs)))
+		}
+		progress.Success(fmt.Sprintf("Maven coordinates validated successfully:"))
+		coords = coordsFromPom
+
+		mavenFilesToUpload = append(mavenFilesToUpload, mavenUpload{
+			name: upload.NormalizeFileName(opts.pomPath, coords.ArtifactID, coords.Version),
+			path: opts.pomPath,
+		})
+		if opts.classifier != "" {
+			mainFileName = mavenFileName(coords, opts.classifier, mavenExtension(packageFileName))
+		}
+	} else {
+		// no POM to read the coordinates from: take them from the flags and
+		// generate a minimal POM so the version resolves as a dependency
+		coords = &mavenPackageMetadata{GroupID: opts.groupID, ArtifactID: opts.artifactID, Version: opts.version}
+		ext := mavenExtension(packageFileName)
+		if ext == "" {
+			ext = opts.packaging
+		}
+		if opts.packaging == "" {
+			opts.packaging = ext
+		}
+		if ext == "" {
+			return nil, nil, errors.NewValidationError("packaging",
+				fmt.Sprintf("cannot derive the packaging of %s, set --packaging", packageFileName))
+		}
+		pom, err := generateMavenPom(coords, opts.packaging)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to generate POM: %w", err)
+		}
+		mainFileName = mavenFileName(coords, opts.classifier, ext)
+		mavenFilesToUpload = append(mavenFilesToUpload, mavenUpload{
+			name:    upload.NormalizePomFilename(coords.ArtifactID, coords.Version),
+			content: pom,
+		})
+		progress.Success(fmt.Sprintf("Generated POM for %s:%s:%s", coords.GroupID, coords.ArtifactID,
+			coords.Version))
+	}
 
-			// uploads using the engine
-			engine := upload.NewFileUploadEngine(maxConcurrentUploads, progress)
-			results := engine.Execute(context.Background(), jobs)
+	// Check for SNAPSHOT version , currently not supported
+	if err := validateSnapshotVersion(coords.Version); err != nil {
+		return nil, nil, errors.NewValidationError("SNAPSHOT_ERROR", fmt.Sprintf("Failed in validating version : %v", err))
+	}
 
-			if upload.HasUploadErrors(results) {
-				errors := upload.GetUploadErrors(results)
-				for jobID, err := range errors {
-					progress.Error(fmt.Sprintf("Failed to upload %s: %v", jobID, err))
-				}
-				return fmt.Errorf("failed to upload %d files", len(errors))
-			}
+	//Adding package and the optional sources and javadoc jars
+	mavenFilesToUpload = append(mavenFilesToUpload, mavenUpload{name: mainFileName, path: pkgFilePath})
+	for _, attached := range []struct{ flag, path, classifier string }{
+		{"sources", opts.sourcesPath, "sources"},
+		{"javadoc", opts.javadocPath, "javadoc"},
+	} {
+		if attached.path == "" {
+			continue
+		}
+		if err := validateMavenInputFile(attached.flag, attached.flag, attached.path); err != nil {
+			return nil, nil, err
+		}
+		mavenFilesToUpload = append(mavenFilesToUpload, mavenUpload{
+			name: mavenFileName(coords, attached.classifier, jarFileExtension[1:]),
+			path: attached.path,
+		})
+	}
 
-			progress.Step("Downloading maven-metadata.xml")
-			//download maven-metadata.xml
-			mavenMetadataXML, isCreated, err := getMavenMetadataXml(pkgClient, registryName, coordsFromPom)
+	// detached signatures next to the files are attached as <file>.asc
+	for _, file := range mavenFilesToUpload {
+		if file.path == "" {
+			continue
+		}
+		if info, err := os.Stat(file.path + ascFileExtension); err == nil && !info.IsDir() {
+			mavenFilesToUpload = append(mavenFilesToUpload, mavenUpload{
+				name: file.name + ascFileExtension,
+				path: file.path + ascFileExtension,
+			})
+		}
+	}
+	progress.Success("Input parameters validated")
 
-			if err != nil {
-				return errors.NewValidationError("DOWNLOAD_ERROR", fmt.Sprintf("Failed in downlaoding  : %v", err))
-			}
+	//Generating all  checksum artifacts in memory
+	checksumFiles, err := getAllChecksumFileToUpload(mavenFilesToUpload)
+	if err != nil {
+		return nil, nil, errors.NewValidationError("CHECKSUM_ERROR", fmt.Sprintf("Failed to generate CHECKSUM files: %v", err))
+	}
 
-			if isCreated {
-				progress.Success(fmt.Sprintf("maven-metadata.xml not found, created new metadata"))
-			} else {
-				progress.Success(fmt.Sprintf("maven-metadata.xml fetched successfully"))
-			}
+	progress.Success("Checksum files generated")
+
+	pkgClient := c.PkgHttpClient()
+
+	progress.Step("Preparing upload jobs")
+	jobs := make([]upload.FileUploadJob, 0, len(mavenFilesToUpload)+len(checksumFiles))
+
+	// Adding files to upload in jobs
+	for _, file := range mavenFilesToUpload {
+		progress.Step(fmt.Sprintf("Processing job for %s ", file.name))
+		if file.path == "" {
+			jobs = append(jobs, upload.NewMavenUploadJobFromMemory(
+				file.name,
+				registryName,
+				coords.GroupID,
+				coords.ArtifactID,
+				coords.Version,
+				file.content,
+				pkgClient,
+			))
+			continue
+		}
+		fileInfo, err := os.Stat(file.path)
+		if err != nil {
+			return nil, nil, errors.NewValidationError("FILE_ERROR", fmt.Sprintf("Failed to stat file: %v", err))
+		}
 
-			progress.Step("Updating maven-metadata.xml")
-			mavenMetadataXML.addNewVersion(coordsFromPom.Version)
-			//uploading maven-metadata.xml
+		job := upload.NewMavenUploadJobFromDisk(
+			file.path,
+			file.name,
+			registryName,
+			coords.GroupID,
+			coords.ArtifactID,
+			coords.Version,
+			fileInfo.Size(),
+			pkgClient,
+		)
+		jobs = append(jobs, job)
+	}
 
-			err = uploadMavenMetadataXML(pkgClient, registryName, progress, coordsFromPom, mavenMetadataXML)
-			if err != nil {
-				return errors.NewValidationError("UPLOAD_ERROR", fmt.Sprintf("Failed in uploading : %v", err))
-			}
+	// Add checksum upload jobs
+	progress.Step("preparing job for checksum files ")
+	for _, checksumFile := range checksumFiles {
+		job := upload.NewMavenUploadJobFromMemory(
+			checksumFile.FileName,
+			registryName,
+			coords.GroupID,
+			coords.ArtifactID,
+			coords.Version,
+			checksumFile.Content,
+			pkgClient,
+		)
+		jobs = append(jobs, job)
+	}
+	return coords, jobs, nil
+}
 
-			progress.Success("maven-metadata.xml uploaded successfully")
-			progress.Success("Successfully uploaded package")
+// updateMavenMetadata adds the version to maven-metadata.xml, once its files
+// are uploaded
+func updateMavenMetadata(pkgClient *pkgclient.ClientWithResponses, registryName string,
+	coords *mavenPackageMetadata, progress p.Reporter) error {
+	progress.Step("Downloading maven-metadata.xml")
+	//download maven-metadata.xml
+	mavenMetadataXML, isCreated, err := getMavenMetadataXml(pkgClient, registryName, coords)
 
-			return nil
+	if err != nil {
+		return errors.NewValidationError("DOWNLOAD_ERROR", fmt.Sprintf("Failed in downlaoding  : %v", err))
+	}
 
-		},
+	if isCreated {
+		progress.Success(fmt.Sprintf("maven-metadata.xml not found, created new metadata"))
+	} else {
+		progress.Success(fmt.Sprintf("maven-metadata.xml fetched successfully"))
 	}
 
-	cmd.Flags().StringVar(&pomPath, "pom-file", "", "pom file path")
-	cmd.Flags().StringVar(&pkgURL, "pkg-url", "", "Base URL for the Packages")
-	cmd.Flags().IntVar(&maxConcurrentUploads, "max-concurrent-uploads", upload.DefaultUploadWorker, "Maximum number of concurrent file uploads, 1 for sequential")
-	cmd.MarkFlagRequired("pom-file")
+	progress.Step("Updating maven-metadata.xml")
+	mavenMetadataXML.addNewVersion(coords.Version)
+	//uploading maven-metadata.xml
 
-	return cmd
+	err = uploadMavenMetadataXML(pkgClient, registryName, progress, coords, mavenMetadataXML)
+	if err != nil {
+		return errors.NewValidationError("UPLOAD_ERROR", fmt.Sprintf("Failed in uploading : %v", err))
+	}
+	return nil
 }
 
 func uploadSingleMavenPackageFile(pkgClient *pkgclient.ClientWithResponses, fileNameWithPath string, registryName string, progress *p.ConsoleReporter, coords *mavenPackageMetadata) error {
@@ -579,16 +699,11 @@ func createChecksumFiles(
 
 	return nil
 }
-func getAllChecksumFileToUpload(mavenFiles []string, coordsFromPom *mavenPackageMetadata) ([]InMemoryUploadFile, error) {
+func getAllChecksumFileToUpload(mavenFiles []mavenUpload) ([]InMemoryUploadFile, error) {
 	var checksumFiles []InMemoryUploadFile
 
-	for _, filePath := range mavenFiles {
-		ext := strings.ToLower(filepath.Ext(filePath))
-		if ext == ".md5" || ext == ".sha1" {
-			continue
-		}
-
-		artifacts, err := generateChecksumArtifacts(filePath, coordsFromPom)
+	for _, file := range mavenFiles {
+		artifacts, err := generateChecksumArtifacts(file)
 		if err != nil {
 			return nil, err
 		}
@@ -598,46 +713,106 @@ func getAllChecksumFileToUpload(mavenFiles []string, coordsFromPom *mavenPackage
 
 	return checksumFiles, nil
 }
-func generateChecksumArtifacts(filePath string, coordsFromPom *mavenPackageMetadata) ([]InMemoryUploadFile, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open %s: %w", filePath, err)
+
+// generateChecksumArtifacts computes the .md5, .sha1, .sha256 and .sha512
+// sidecars of a file
+func generateChecksumArtifacts(file mavenUpload) ([]InMemoryUploadFile, error) {
+	var r io.Reader = bytes.NewReader(file.content)
+	if file.path != "" {
+		f, err := os.Open(file.path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", file.path, err)
+		}
+		defer f.Close()
+		r = f
 	}
-	defer file.Close()
 
-	md5Hash := md5.New()
-	sha1Hash := sha1.New()
+	hashes := []struct {
+		ext  string
+		hash hash.Hash
+	}{
+		{".md5", md5.New()},
+		{".sha1", sha1.New()},
+		{".sha256", sha256.New()},
+		{".sha512", sha512.New()},
+	}
+	writers := make([]io.Writer, len(hashes))
+	for i, h := range hashes {
+		writers[i] = h.hash
+	}
+	if _, err := io.Copy(io.MultiWriter(writers...), r); err != nil {
+		return nil, fmt.Errorf("failed to hash %s: %w", file.name, err)
+	}
 
-	if _, err := io.Copy(io.MultiWriter(md5Hash, sha1Hash), file); err != nil {
-		return nil, fmt.Errorf("failed to hash %s: %w", filePath, err)
+	artifacts := make([]InMemoryUploadFile, 0, len(hashes))
+	for _, h := range hashes {
+		artifacts = append(artifacts, InMemoryUploadFile{
+			FileName: file.name + h.ext,
+			Content:  []byte(hex.EncodeToString(h.hash.Sum(nil))),
+		})
 	}
+	return artifacts, nil
+}
 
-	md5Sum := hex.EncodeToString(md5Hash.Sum(nil))
-	sha1Sum := hex.EncodeToString(sha1Hash.Sum(nil))
+// validateMavenInputFile checks that path is a regular file, what naming the
+// file in errors
+func validateMavenInputFile(field, what, path string) error {
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return errors.NewValidationError(field, fmt.Sprintf("failed to access %s file: %v", what, err))
+	}
+	if fileInfo.IsDir() {
+		return errors.NewValidationError(field, fmt.Sprintf("%s file path must be a file, not a directory", what))
+	}
+	return nil
+}
 
-	baseName := filepath.Base(filePath)
+// mavenExtension returns the extension of a file name without the dot,
+// keeping compressed tarballs such as tar.gz whole
+func mavenExtension(fileName string) string {
+	lower := strings.ToLower(fileName)
+	for _, ext := range []string{".tar.gz", ".tar.bz2", ".tar.xz"} {
+		if strings.HasSuffix(lower, ext) {
+			return fileName[len(fileName)-len(ext)+1:]
+		}
+	}
+	return strings.TrimPrefix(filepath.Ext(fileName), ".")
+}
 
-	if baseName == "pom.xml" {
-		baseName = normalizePomFilename(coordsFromPom) + ".pom"
+// mavenFileName is the repository file name of an artifact file:
+// <artifactId>-<version>[-<classifier>].<ext>
+func mavenFileName(coords *mavenPackageMetadata, classifier, ext string) string {
+	name := normalizePomFilename(coords)
+	if classifier != "" {
+		name += "-" + classifier
 	}
+	return name + "." + ext
+}
 
-	return []InMemoryUploadFile{
-		{
-			FileName: baseName + ".md5",
-			Content:  []byte(md5Sum),
-		},
-		{
-			FileName: baseName + ".sha1",
-			Content:  []byte(sha1Sum),
-		},
-	}, nil
+// generateMavenPom renders a minimal POM declaring the coordinates and packaging
+func generateMavenPom(coords *mavenPackageMetadata, packaging string) ([]byte, error) {
+	body, err := xml.MarshalIndent(generatedPom{
+		Xmlns:             "http://maven.apache.org/POM/4.0.0",
+		XmlnsXsi:          "http://www.w3.org/2001/XMLSchema-instance",
+		XsiSchemaLocation: "http://maven.apache.org/POM/4.0.0 http://maven.apache.org/xsd/maven-4.0.0.xsd",
+		ModelVersion:      "4.0.0",
+		GroupID:           coords.GroupID,
+		ArtifactID:        coords.ArtifactID,
+		Version:           coords.Version,
+		Packaging:         packaging,
+		Description:       "POM was generated by hc artifact push maven",
+	}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte(xml.Header), body...), '\n'), nil
 }
 
 func uploadInMemoryMavenFile(
 	pkgClient *pkgclient.ClientWithResponses,
 	checkSumfile InMemoryUploadFile,
 	registryName string,
-	progress *p.ConsoleReporter,
+	progress p.Reporter,
 	coords *mavenPackageMetadata,
 ) error {
 
@@ -777,7 +952,7 @@ func newMavenMetadataXml(groupID string, artifactID string, version string) *Mav
 func uploadMavenMetadataXML(
 	pkgClient *pkgclient.ClientWithResponses,
 	registryName string,
-	progress *p.ConsoleReporter,
+	progress p.Reporter,
 	coords *mavenPackageMetadata,
 	metadata *MavenMetadataXMLStruct,
 ) error {
@@ -844,6 +1019,28 @@ type pomXML struct {
 	} `xml:"parent"`
 }
 
+// generatedPom is the POM generated for files pushed without one
+type generatedPom struct {
+	XMLName           xml.Name `xml:"project"`
+	Xmlns             string   `xml:"xmlns,attr"`
+	XmlnsXsi          string   `xml:"xmlns:xsi,attr"`
+	XsiSchemaLocation string   `xml:"xsi:schemaLocation,attr"`
+	ModelVersion      string   `xml:"modelVersion"`
+	GroupID           string   `xml:"groupId"`
+	ArtifactID        string   `xml:"artifactId"`
+	Version           string   `xml:"version"`
+	Packaging         string   `xml:"packaging"`
+	Description       string   `xml:"description"`
+}
+
+// mavenUpload is a file of the version being pushed with its name in the
+// repository, read from path or, when path is empty, held in content
+type mavenUpload struct {
+	name    string
+	path    string
+	content []byte
+}
+
 type mavenPackageMetadata struct {
 	GroupID    string
 	ArtifactID string
@@ -3,10 +3,14 @@ package command
 import (
 	"archive/zip"
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"testing"
 
@@ -554,3 +558,112 @@ func TestCompareMavenCoordinates(t *testing.T) {
 		t.Error("compareMavenCoordinates() with nil coords should return error")
 	}
 }
+
+func TestNewPushMavenCmd_GeneratedPom(t *testing.T) {
+	uploads := map[string]string{}
+	srv := withMavenServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "maven-metadata.xml") && r.Method == http.MethodGet {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		uploads[r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]] = string(body)
+		w.WriteHeader(http.StatusCreated)
+	})
+	defer srv.Close()
+
+	dir := t.TempDir()
+	native := filepath.Join(dir, "libzstd.so")
+	sources := filepath.Join(dir, "sources.jar")
+	for path, content := range map[string]string{native: "ELF", native + ".asc": "signature", sources: "src"} {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := runMavenCmd(t, "test-registry", native, "--group-id", "com.github.luben", "--artifact-id", "zstd-jni",
+		"--version", "1.5.6", "--classifier", "linux_amd64", "--sources", sources)
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+
+	var names []string
+	for name := range uploads {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var want []string
+	for _, name := range []string{
+		"zstd-jni-1.5.6.pom",
+		"zstd-jni-1.5.6-linux_amd64.so",
+		"zstd-jni-1.5.6-linux_amd64.so.asc",
+		"zstd-jni-1.5.6-sources.jar",
+	} {
+		want = append(want, name)
+		for _, ext := range []string{".md5", ".sha1", ".sha256", ".sha512"} {
+			want = append(want, name+ext)
+		}
+	}
+	want = append(want, "maven-metadata.xml")
+	sort.Strings(want)
+	if strings.Join(names, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("uploaded files:\n%s\nwant:\n%s", strings.Join(names, "\n"), strings.Join(want, "\n"))
+	}
+
+	coords, err := parsePomXMLData([]byte(uploads["zstd-jni-1.5.6.pom"]))
+	if err != nil {
+		t.Fatalf("generated POM does not parse: %v", err)
+	}
+	if coords.GroupID != "com.github.luben" || coords.ArtifactID != "zstd-jni" || coords.Version != "1.5.6" {
+		t.Errorf("unexpected coordinates in generated POM: %+v", coords)
+	}
+	if !strings.Contains(uploads["zstd-jni-1.5.6.pom"], "<packaging>so</packaging>") {
+		t.Errorf("generated POM should use the file extension as packaging:\n%s", uploads["zstd-jni-1.5.6.pom"])
+	}
+	sum := sha256.Sum256([]byte("ELF"))
+	if got := uploads["zstd-jni-1.5.6-linux_amd64.so.sha256"]; got != hex.EncodeToString(sum[:]) {
+		t.Errorf("sha256 sidecar = %q", got)
+	}
+}
+
+func TestNewPushMavenCmd_CoordinateFlags(t *testing.T) {
+	srv := withMavenServer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	})
+	defer srv.Close()
+
+	jarFile := createTestJarFile(t, "com.example", "test-app", "1.0.0")
+	pomFile := createTestPomFile(t, "com.example", "test-app", "1.0.0", "Test App")
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"pom and coordinates", []string{"--pom-file", pomFile, "--group-id", "com.example", "--artifact-id", "test-app",
+			"--version", "1.0.0"}},
+		{"partial coordinates", []string{"--group-id", "com.example", "--artifact-id", "test-app"}},
+		{"snapshot", []string{"--group-id", "com.example", "--artifact-id", "test-app", "--version", "1.0.0-SNAPSHOT"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := runMavenCmd(t, append([]string{"test-registry", jarFile}, tt.args...)...); err == nil {
+				t.Fatal("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestMavenExtension(t *testing.T) {
+	for name, want := range map[string]string{
+		"app.jar":           "jar",
+		"dist.tar.gz":       "tar.gz",
+		"natives.Tar.Bz2":   "Tar.Bz2",
+		"libfoo.so":         "so",
+		"tool":              "",
+		"bundle-1.0.0.json": "json",
+	} {
+		if got := mavenExtension(name); got != want {
+			t.Errorf("mavenExtension(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
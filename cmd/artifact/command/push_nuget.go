@@ -16,7 +16,6 @@ import (
 	"github.com/harness/harness-cli/util"
 	"github.com/harness/harness-cli/util/common/errors"
 	"github.com/harness/harness-cli/util/common/fileutil"
-	"github.com/harness/harness-cli/util/common/httpclient"
 	p "github.com/harness/harness-cli/util/common/progress"
 
 	"github.com/spf13/cobra"
@@ -49,133 +48,137 @@ func NewPushNugetCmd(c *cmdutils.Factory) *cobra.Command {
 			}
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			registryName := args[0]
-			filePath := args[1]
-
 			// Create progress reporter
 			progress := p.NewConsoleReporter()
+			return pushNuget(cmd.Context(), newPkgUploader(progress), args[0], args[1], path, progress)
+		},
+	}
 
-			// Validate Registry Name and file_path
-			progress.Start("Validating input parameters")
-
-			// Resolve file path (supports glob patterns like *.nupkg)
-			files, err := utils.ResolveFilePath(filePath, NugetFileExtension)
-			if err != nil {
-				progress.Error("Failed to resolve file path")
-				return err
-			}
-			filePath = files[0]
-			progress.Step(fmt.Sprintf("Uploading file: %s", filePath))
+	cmd.Flags().StringVar(&path, "path", "", "Nested directory")
+	cmd.Flags().StringVar(&pkgURL, "pkg-url", "", "Base URL for the Packages")
 
-			fileName := filepath.Base(filePath)
+	return cmd
+}
 
-			// Validate file exists
-			fileInfo, err := os.Stat(filePath)
-			if err != nil {
-				return errors.NewValidationError("file_path", fmt.Sprintf("failed to access package file: %v", err))
-			}
-			if fileInfo.IsDir() {
-				return errors.NewValidationError("file_path", "package file path must be a file, not a directory")
-			}
+// pushNuget uploads a .nupkg package, into the nested directory path when
+// it is set
+func pushNuget(ctx context.Context, u pkgUploader, registryName, filePath, path string, progress p.Reporter) error {
+	// Validate Registry Name and file_path
+	progress.Start("Validating input parameters")
 
-			// validate file name
-			valid, err := fileutil.IsFilenameAcceptable(fileName, NugetFileExtension)
-			if !valid {
-				progress.Error("Invalid file name")
-				return errors.NewValidationError("file_path", fmt.Sprintf("failed to validate package file name: %v", err))
-			}
+	// Resolve file path (supports glob patterns like *.nupkg)
+	files, err := utils.ResolveFilePath(filePath, NugetFileExtension)
+	if err != nil {
+		progress.Error("Failed to resolve file path")
+		return err
+	}
+	filePath = files[0]
+	progress.Step(fmt.Sprintf("Uploading file: %s", filePath))
 
-			progress.Success("Input parameters validated")
+	fileName := filepath.Base(filePath)
 
-			// Compute checksums of the file for X-Checksum-* headers
-			checksums, err := utils.ComputeFileChecksums(filePath)
-			if err != nil {
-				progress.Error("Failed to compute file checksums")
-				return fmt.Errorf("failed to compute checksums for %s: %w", filePath, err)
-			}
+	// Validate file exists
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		return errors.NewValidationError("file_path", fmt.Sprintf("failed to access package file: %v", err))
+	}
+	if fileInfo.IsDir() {
+		return errors.NewValidationError("file_path", "package file path must be a file, not a directory")
+	}
 
-			file, err := os.Open(filePath)
-			if err != nil {
-				progress.Error("Failed to open package file")
-				return err
-			}
-			defer file.Close()
+	// validate file name
+	valid, err := fileutil.IsFilenameAcceptable(fileName, NugetFileExtension)
+	if !valid {
+		progress.Error("Invalid file name")
+		return errors.NewValidationError("file_path", fmt.Sprintf("failed to validate package file name: %v", err))
+	}
 
-			var formData bytes.Buffer
-			fileWriter := multipart.NewWriter(&formData)
+	progress.Success("Input parameters validated")
 
-			// Create the form field "package" to match curl
-			part, err := fileWriter.CreateFormFile("package", filepath.Base(filePath))
-			if err != nil {
-				return err
-			}
+	// Compute checksums of the file for X-Checksum-* headers
+	checksums, err := utils.ComputeFileChecksums(filePath)
+	if err != nil {
+		progress.Error("Failed to compute file checksums")
+		return fmt.Errorf("failed to compute checksums for %s: %w", filePath, err)
+	}
 
-			// Copy the file into the multipart field
-			_, err = io.Copy(part, file)
-			if err != nil {
-				return err
-			}
+	file, err := os.Open(filePath)
+	if err != nil {
+		progress.Error("Failed to open package file")
+		return err
+	}
+	defer file.Close()
 
-			fileWriter.Close()
-
-			// Initialize progress reader
-			progress.Step("Uploading package to registry")
-			bufferSize := int64(formData.Len())
-
-			if len(path) > 0 {
-				//This section will get executed only when a nested path is provided via flag
-				apiUrlForNestedDirectory := fmt.Sprintf("%s/pkg/%s/%s/nuget/%s", config.Global.Registry.PkgURL, config.Global.AccountID, registryName, path)
-				err := uploadNugetPackageDirect(
-					context.Background(),
-					apiUrlForNestedDirectory,
-					fileWriter.FormDataContentType(),
-					&formData,
-					config.Global.AuthToken,
-					progress,
-					bufferSize,
-					checksums,
-				)
-				if err != nil {
-					return err
-				}
-
-			} else {
-				pkgClient := c.PkgHttpClientWithProgress(progress, bufferSize, "nupkg")
-				resp, err := pkgClient.UploadNugetPackageWithBodyWithResponse(
-					context.Background(),
-					config.Global.AccountID,
-					registryName,
-					fileWriter.FormDataContentType(),
-					&formData,
-					func(ctx context.Context, req *http.Request) error {
-						utils.SetChecksumHeaders(req.Header, checksums)
-						return nil
-					},
-				)
+	var formData bytes.Buffer
+	fileWriter := multipart.NewWriter(&formData)
 
-				if err != nil {
-					progress.Error("Failed to upload package")
-					return err
-				}
-				// Check response
-				if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusCreated {
-					progress.Error("Upload failed")
-					return fmt.Errorf("failed to push package: %s \n response: %s", resp.Status(), resp.Body)
-				}
-			}
+	// Create the form field "package" to match curl
+	part, err := fileWriter.CreateFormFile("package", filepath.Base(filePath))
+	if err != nil {
+		return err
+	}
 
-			progress.Success(fmt.Sprintf("Successfully uploaded package %s", filePath))
-			return nil
-		},
+	// Copy the file into the multipart field
+	_, err = io.Copy(part, file)
+	if err != nil {
+		return err
 	}
 
-	cmd.Flags().StringVar(&path, "path", "", "Nested directory")
-	cmd.Flags().StringVar(&pkgURL, "pkg-url", "", "Base URL for the Packages")
+	fileWriter.Close()
+
+	// Initialize progress reader
+	progress.Step("Uploading package to registry")
+	bufferSize := int64(formData.Len())
+
+	if len(path) > 0 {
+		//This section will get executed only when a nested path is provided via flag
+		apiUrlForNestedDirectory := fmt.Sprintf("%s/pkg/%s/%s/nuget/%s", u.pkgURL, config.Global.AccountID, registryName, path)
+		err := uploadNugetPackageDirect(
+			ctx,
+			apiUrlForNestedDirectory,
+			fileWriter.FormDataContentType(),
+			&formData,
+			config.Global.AuthToken,
+			u.httpClient(bufferSize, "nupkg"),
+			checksums,
+		)
+		if err != nil {
+			return err
+		}
+
+	} else {
+		pkgClient, err := u.client(bufferSize, "nupkg")
+		if err != nil {
+			return err
+		}
+		resp, err := pkgClient.UploadNugetPackageWithBodyWithResponse(
+			ctx,
+			config.Global.AccountID,
+			registryName,
+			fileWriter.FormDataContentType(),
+			&formData,
+			func(ctx context.Context, req *http.Request) error {
+				utils.SetChecksumHeaders(req.Header, checksums)
+				return nil
+			},
+		)
 
-	return cmd
+		if err != nil {
+			progress.Error("Failed to upload package")
+			return err
+		}
+		// Check response
+		if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusCreated {
+			progress.Error("Upload failed")
+			return fmt.Errorf("failed to push package: %s \n response: %s", resp.Status(), resp.Body)
+		}
+	}
+
+	progress.Success(fmt.Sprintf("Successfully uploaded package %s", filePath))
+	return nil
 }
 
-func uploadNugetPackageDirect(ctx context.Context, url string, contentType string, body io.Reader, apiKey string, progress p.Reporter, bufferSize int64, checksums utils.FileChecksums) error {
+func uploadNugetPackageDirect(ctx context.Context, url string, contentType string, body io.Reader, apiKey string, client *http.Client, checksums utils.FileChecksums) error {
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, body)
 	if err != nil {
@@ -188,9 +191,6 @@ func uploadNugetPackageDirect(ctx context.Context, url string, contentType strin
 
 	utils.SetChecksumHeaders(req.Header, checksums)
 
-	//creating retry client
-	client := httpclient.NewRetryClientWithProgress(progress, bufferSize, "nupkg")
-
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("request failed: %w", err)
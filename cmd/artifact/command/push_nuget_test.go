@@ -15,6 +15,7 @@ import (
 	"github.com/harness/harness-cli/config"
 	pkgclient "github.com/harness/harness-cli/internal/api/ar_pkg"
 	"github.com/harness/harness-cli/util/common/auth"
+	"github.com/harness/harness-cli/util/common/httpclient"
 )
 
 // withNugetServer spins up a stub server and points the global config at it
@@ -353,8 +354,7 @@ func TestUploadNugetPackageDirect(t *testing.T) {
 		"multipart/form-data",
 		body,
 		config.Global.AuthToken,
-		progress,
-		int64(body.Len()),
+		httpclient.NewRetryClientWithProgress(progress, int64(body.Len()), "nupkg"),
 		checksums,
 	)
 
@@ -56,113 +56,118 @@ func NewPushPuppetCmd(c *cmdutils.Factory) *cobra.Command {
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			registryName := args[0]
-			packageFilePath := args[1]
-
 			progress := p.NewConsoleReporter()
+			return pushPuppet(cmd.Context(), newPkgUploader(progress), args[0], args[1], progress)
+		},
+	}
 
-			progress.Start("Validating input parameters")
+	return cmd
+}
 
-			files, err := utils.ResolveFilePath(packageFilePath, puppetTarGzExt, puppetTgzExt, ".gz")
-			if err != nil {
-				progress.Error("Failed to resolve file path")
-				return err
-			}
-			packageFilePath = files[0]
-			progress.Step(fmt.Sprintf("Uploading file: %s", packageFilePath))
+// pushPuppet uploads a Puppet module tarball
+func pushPuppet(ctx context.Context, u pkgUploader, registryName, packageFilePath string, progress p.Reporter) error {
+	progress.Start("Validating input parameters")
 
-			fileInfo, err := os.Stat(packageFilePath)
-			if err != nil {
-				progress.Error("Failed to access package file")
-				return fmt.Errorf("failed to access package file: %w", err)
-			}
-			if fileInfo.IsDir() {
-				progress.Error("Package file path must be a file, not a directory")
-				return errors.New("package file path must be a file, not a directory")
-			}
-			if !isPuppetTarball(packageFilePath) {
-				progress.Error(fmt.Sprintf("Package file must be a %s or %s tarball", puppetTarGzExt, puppetTgzExt))
-				return fmt.Errorf("package file must be a %s or %s tarball, got: %s", puppetTarGzExt, puppetTgzExt, filepath.Ext(packageFilePath))
-			}
-			progress.Success("Input parameters validated")
+	files, err := utils.ResolveFilePath(packageFilePath, puppetTarGzExt, puppetTgzExt, ".gz")
+	if err != nil {
+		progress.Error("Failed to resolve file path")
+		return err
+	}
+	packageFilePath = files[0]
+	progress.Step(fmt.Sprintf("Uploading file: %s", packageFilePath))
 
-			// Compute checksums of the file for X-Checksum-* headers
-			checksums, err := utils.ComputeFileChecksums(packageFilePath)
-			if err != nil {
-				progress.Error("Failed to compute file checksums")
-				return fmt.Errorf("failed to compute checksums for %s: %w", packageFilePath, err)
-			}
+	fileInfo, err := os.Stat(packageFilePath)
+	if err != nil {
+		progress.Error("Failed to access package file")
+		return fmt.Errorf("failed to access package file: %w", err)
+	}
+	if fileInfo.IsDir() {
+		progress.Error("Package file path must be a file, not a directory")
+		return errors.New("package file path must be a file, not a directory")
+	}
+	if !isPuppetTarball(packageFilePath) {
+		progress.Error(fmt.Sprintf("Package file must be a %s or %s tarball", puppetTarGzExt, puppetTgzExt))
+		return fmt.Errorf("package file must be a %s or %s tarball, got: %s", puppetTarGzExt, puppetTgzExt, filepath.Ext(packageFilePath))
+	}
+	progress.Success("Input parameters validated")
 
-			progress.Step(fmt.Sprintf("Extracting %s from tarball", puppetMetadataKey))
-			metadata, err := extractPuppetMetadata(packageFilePath)
-			if err != nil {
-				progress.Error(fmt.Sprintf("Failed to extract %s from tarball", puppetMetadataKey))
-				return fmt.Errorf("failed to extract %s from tarball: %w", puppetMetadataKey, err)
-			}
-			if metadata.Name == "" || metadata.Version == "" {
-				progress.Error(fmt.Sprintf("%s must contain non-empty 'name' and 'version'", puppetMetadataKey))
-				return fmt.Errorf("%s must contain non-empty 'name' and 'version'", puppetMetadataKey)
-			}
-			progress.Success(fmt.Sprintf("Module metadata extracted: %s@%s", metadata.Name, metadata.Version))
+	// Compute checksums of the file for X-Checksum-* headers
+	checksums, err := utils.ComputeFileChecksums(packageFilePath)
+	if err != nil {
+		progress.Error("Failed to compute file checksums")
+		return fmt.Errorf("failed to compute checksums for %s: %w", packageFilePath, err)
+	}
 
-			file, err := os.Open(packageFilePath)
-			if err != nil {
-				progress.Error("Failed to open package file")
-				return fmt.Errorf("failed to open package file: %w", err)
-			}
-			defer file.Close()
-
-			pr, pw := io.Pipe()
-			writer := multipart.NewWriter(pw)
-			go func() {
-				defer pw.Close()
-				defer writer.Close()
-				part, err := writer.CreateFormFile("file", filepath.Base(packageFilePath))
-				if err != nil {
-					pw.CloseWithError(fmt.Errorf("failed to create form file: %w", err))
-					return
-				}
-				if _, err := io.Copy(part, file); err != nil {
-					pw.CloseWithError(fmt.Errorf("failed to copy file to form: %w", err))
-					return
-				}
-			}()
-
-			progress.Step("Uploading module to registry")
-
-			pkgClient := c.PkgHttpClientWithProgress(progress, fileInfo.Size(), fileInfo.Name())
-			resp, err := pkgClient.UploadPuppetPackageWithBodyWithResponse(
-				context.Background(),
-				config.Global.AccountID,
-				registryName,
-				writer.FormDataContentType(),
-				pr,
-				func(ctx context.Context, req *http.Request) error {
-					utils.SetChecksumHeaders(req.Header, checksums)
-					return nil
-				},
-			)
-			if err != nil {
-				progress.Error("Failed to upload Puppet module")
-				return fmt.Errorf("failed to upload Puppet module: %w", err)
-			}
+	progress.Step(fmt.Sprintf("Extracting %s from tarball", puppetMetadataKey))
+	metadata, err := extractPuppetMetadata(packageFilePath)
+	if err != nil {
+		progress.Error(fmt.Sprintf("Failed to extract %s from tarball", puppetMetadataKey))
+		return fmt.Errorf("failed to extract %s from tarball: %w", puppetMetadataKey, err)
+	}
+	if metadata.Name == "" || metadata.Version == "" {
+		progress.Error(fmt.Sprintf("%s must contain non-empty 'name' and 'version'", puppetMetadataKey))
+		return fmt.Errorf("%s must contain non-empty 'name' and 'version'", puppetMetadataKey)
+	}
+	progress.Success(fmt.Sprintf("Module metadata extracted: %s@%s", metadata.Name, metadata.Version))
 
-			if resp.StatusCode() != http.StatusOK &&
-				resp.StatusCode() != http.StatusCreated &&
-				resp.StatusCode() != http.StatusNoContent {
-				progress.Error("Upload failed")
-				return fmt.Errorf("failed to upload Puppet module: %s\nresponse: %s", resp.Status(), resp.Body)
-			}
+	file, err := os.Open(packageFilePath)
+	if err != nil {
+		progress.Error("Failed to open package file")
+		return fmt.Errorf("failed to open package file: %w", err)
+	}
+	defer file.Close()
+
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+	go func() {
+		defer pw.Close()
+		defer writer.Close()
+		part, err := writer.CreateFormFile("file", filepath.Base(packageFilePath))
+		if err != nil {
+			pw.CloseWithError(fmt.Errorf("failed to create form file: %w", err))
+			return
+		}
+		if _, err := io.Copy(part, file); err != nil {
+			pw.CloseWithError(fmt.Errorf("failed to copy file to form: %w", err))
+			return
+		}
+	}()
 
-			progress.Success(fmt.Sprintf(
-				"Successfully uploaded Puppet module '%s@%s' to registry '%s'",
-				metadata.Name, metadata.Version, registryName,
-			))
+	progress.Step("Uploading module to registry")
+
+	pkgClient, err := u.client(fileInfo.Size(), fileInfo.Name())
+	if err != nil {
+		pr.CloseWithError(err)
+		return err
+	}
+	resp, err := pkgClient.UploadPuppetPackageWithBodyWithResponse(
+		ctx,
+		config.Global.AccountID,
+		registryName,
+		writer.FormDataContentType(),
+		pr,
+		func(ctx context.Context, req *http.Request) error {
+			utils.SetChecksumHeaders(req.Header, checksums)
 			return nil
 		},
+	)
+	if err != nil {
+		progress.Error("Failed to upload Puppet module")
+		return fmt.Errorf("failed to upload Puppet module: %w", err)
 	}
 
-	return cmd
+	if resp.StatusCode() != http.StatusOK &&
+		resp.StatusCode() != http.StatusCreated &&
+		resp.StatusCode() != http.StatusNoContent {
+		progress.Error("Upload failed")
+		return fmt.Errorf("failed to upload Puppet module: %s\nresponse: %s", resp.Status(), resp.Body)
+	}
+
+	progress.Success(fmt.Sprintf(
+		"Successfully uploaded Puppet module '%s@%s' to registry '%s'",
+		metadata.Name, metadata.Version, registryName,
+	))
+	return nil
 }
 
 // isPuppetTarball returns true if path ends with .tar.gz or .tgz.
@@ -62,64 +62,9 @@ func NewPushPythonCmd(c *cmdutils.Factory) *cobra.Command {
 			// Create progress reporter
 			progress := p.NewConsoleReporter()
 
-			// Validate file exists
-			fileInfo, err := os.Stat(filePath)
+			jobs, err := pythonUploadJobs(c, registryName, filePath, progress)
 			if err != nil {
-				return errors.NewValidationError("file_path", fmt.Sprintf("failed to access package file: %v", err))
-			}
-			var pythonPkgFiles []string
-			if fileInfo.IsDir() {
-				//scan whole directory and return all .whl and .tar.gz files
-				progress.Step(fmt.Sprintf("Scanning folder %s for python packages ", filePath))
-				pythonPkgFiles, err = scanFolderForPackages(filePath, progress)
-				if err != nil {
-					return err
-				}
-
-				if len(pythonPkgFiles) == 0 {
-					return errors.NewValidationError("Empty Folder", fmt.Sprintf("No python packages found at : %s", filePath))
-				}
-
-			} else {
-				//handle  single package file scenario
-				pythonPkgFiles = append(pythonPkgFiles, filePath)
-
-			}
-
-			progress.Success("Input parameters validated")
-
-			pkgClient := c.PkgHttpClient()
-
-			progress.Step("Preparing python upload jobs")
-			jobs := make([]upload.FileUploadJob, 0, len(pythonPkgFiles))
-			for _, fileNameWithPath := range pythonPkgFiles {
-				progress.Step(fmt.Sprintf("Processing job for %s ", filepath.Base(fileNameWithPath)))
-				metadata, err := extractPythonPackageMetadata(fileNameWithPath)
-				if err != nil {
-					return fmt.Errorf("failed to extract metadata from %s: %w", filepath.Base(fileNameWithPath), err)
-				}
-
-				fileInfo, err := os.Stat(fileNameWithPath)
-				if err != nil {
-					return fmt.Errorf("failed to stat file %s: %w", fileNameWithPath, err)
-				}
-
-				// Compute checksums of the file for X-Checksum-* headers
-				checksums, err := utils.ComputeFileChecksums(fileNameWithPath)
-				if err != nil {
-					return fmt.Errorf("failed to compute checksums for %s: %w", fileNameWithPath, err)
-				}
-
-				job := upload.NewPythonUploadJob(
-					fileNameWithPath,
-					registryName,
-					metadata.Name,
-					metadata.Version,
-					fileInfo.Size(),
-					checksums,
-					pkgClient,
-				)
-				jobs = append(jobs, job)
+				return err
 			}
 
 			progress.Success(fmt.Sprintf("Prepared %d upload jobs", len(jobs)))
@@ -149,6 +94,72 @@ func NewPushPythonCmd(c *cmdutils.Factory) *cobra.Command {
 	return cmd
 }
 
+// pythonUploadJobs returns the upload jobs of a python package file, or of
+// every package in a folder
+func pythonUploadJobs(c *cmdutils.Factory, registryName, filePath string,
+	progress p.Reporter) ([]upload.FileUploadJob, error) {
+	// Validate file exists
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		return nil, errors.NewValidationError("file_path", fmt.Sprintf("failed to access package file: %v", err))
+	}
+	var pythonPkgFiles []string
+	if fileInfo.IsDir() {
+		//scan whole directory and return all .whl and .tar.gz files
+		progress.Step(fmt.Sprintf("Scanning folder %s for python packages ", filePath))
+		pythonPkgFiles, err = scanFolderForPackages(filePath, progress)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(pythonPkgFiles) == 0 {
+			return nil, errors.NewValidationError("Empty Folder", fmt.Sprintf("No python packages found at : %s", filePath))
+		}
+
+	} else {
+		//handle  single package file scenario
+		pythonPkgFiles = append(pythonPkgFiles, filePath)
+
+	}
+
+	progress.Success("Input parameters validated")
+
+	pkgClient := c.PkgHttpClient()
+
+	progress.Step("Preparing python upload jobs")
+	jobs := make([]upload.FileUploadJob, 0, len(pythonPkgFiles))
+	for _, fileNameWithPath := range pythonPkgFiles {
+		progress.Step(fmt.Sprintf("Processing job for %s ", filepath.Base(fileNameWithPath)))
+		metadata, err := extractPythonPackageMetadata(fileNameWithPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract metadata from %s: %w", filepath.Base(fileNameWithPath), err)
+		}
+
+		fileInfo, err := os.Stat(fileNameWithPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat file %s: %w", fileNameWithPath, err)
+		}
+
+		// Compute checksums of the file for X-Checksum-* headers
+		checksums, err := utils.ComputeFileChecksums(fileNameWithPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute checksums for %s: %w", fileNameWithPath, err)
+		}
+
+		job := upload.NewPythonUploadJob(
+			fileNameWithPath,
+			registryName,
+			metadata.Name,
+			metadata.Version,
+			fileInfo.Size(),
+			checksums,
+			pkgClient,
+		)
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
 func uploadSinglePythonPackageFile(fileNameWithPath string, registryName string, progress *p.ConsoleReporter) error {
 	// Initialize the package client
 	pkgClient, err := pkgclient.NewClientWithResponses(config.Global.Registry.PkgURL,
@@ -348,7 +359,7 @@ func isValidPythonPackageFile(fileName string) (bool, error) {
 }
 
 // scanFolderForPackages scans a folder and returns all .tar.gz and .whl files
-func scanFolderForPackages(folderPath string, progress *p.ConsoleReporter) ([]string, error) {
+func scanFolderForPackages(folderPath string, progress p.Reporter) ([]string, error) {
 	entries, err := os.ReadDir(folderPath)
 	if err != nil {
 		return nil, err
@@ -38,117 +38,121 @@ func NewPushRpmCmd(c *cmdutils.Factory) *cobra.Command {
 			}
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			registryName := args[0]
-			filePath := args[1]
-
 			// Create progress reporter
 			progress := p.NewConsoleReporter()
+			return pushRpm(cmd.Context(), newPkgUploader(progress), args[0], args[1], progress)
+		},
+	}
 
-			// Validate Registry Name and file_path
-			progress.Start("Validating input parameters")
-			if registryName == "" {
-				progress.Error("Registry name is required")
-				return errors.NewValidationError("registry_name", "registry name is required")
-			}
-			if filePath == "" {
-				progress.Error("File path is required")
-				return errors.NewValidationError("file_path", "file path is required")
-			}
+	cmd.Flags().StringVar(&pkgURL, "pkg-url", "", "Base URL for the Packages")
+	return cmd
+}
 
-			// Resolve file path (supports glob patterns like *.rpm)
-			files, err := utils.ResolveFilePath(filePath, RpmFileExtension)
-			if err != nil {
-				progress.Error("Failed to resolve file path")
-				return err
-			}
-			filePath = files[0]
-			progress.Step(fmt.Sprintf("Uploading file: %s", filePath))
+// pushRpm uploads an RPM package
+func pushRpm(ctx context.Context, u pkgUploader, registryName, filePath string, progress p.Reporter) error {
+	// Validate Registry Name and file_path
+	progress.Start("Validating input parameters")
+	if registryName == "" {
+		progress.Error("Registry name is required")
+		return errors.NewValidationError("registry_name", "registry name is required")
+	}
+	if filePath == "" {
+		progress.Error("File path is required")
+		return errors.NewValidationError("file_path", "file path is required")
+	}
 
-			fileName := filepath.Base(filePath)
+	// Resolve file path (supports glob patterns like *.rpm)
+	files, err := utils.ResolveFilePath(filePath, RpmFileExtension)
+	if err != nil {
+		progress.Error("Failed to resolve file path")
+		return err
+	}
+	filePath = files[0]
+	progress.Step(fmt.Sprintf("Uploading file: %s", filePath))
 
-			// Validate file exists
-			fileInfo, err := os.Stat(filePath)
-			if err != nil {
-				return errors.NewValidationError("file_path", fmt.Sprintf("failed to access package file: %v", err))
-			}
-			if fileInfo.IsDir() {
-				return errors.NewValidationError("file_path", "package file path must be a file, not a directory")
-			}
+	fileName := filepath.Base(filePath)
 
-			// validate file name
-			valid, err := fileutil.IsFilenameAcceptable(fileName, RpmFileExtension)
-			if !valid {
-				progress.Error("Invalid file name")
-				return errors.NewValidationError("file_path", fmt.Sprintf("failed to validate package file name: %v", err))
-			}
+	// Validate file exists
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		return errors.NewValidationError("file_path", fmt.Sprintf("failed to access package file: %v", err))
+	}
+	if fileInfo.IsDir() {
+		return errors.NewValidationError("file_path", "package file path must be a file, not a directory")
+	}
 
-			progress.Success("Input parameters validated")
+	// validate file name
+	valid, err := fileutil.IsFilenameAcceptable(fileName, RpmFileExtension)
+	if !valid {
+		progress.Error("Invalid file name")
+		return errors.NewValidationError("file_path", fmt.Sprintf("failed to validate package file name: %v", err))
+	}
 
-			// Compute checksums of the file for X-Checksum-* headers
-			checksums, err := utils.ComputeFileChecksums(filePath)
-			if err != nil {
-				progress.Error("Failed to compute file checksums")
-				return fmt.Errorf("failed to compute checksums for %s: %w", filePath, err)
-			}
+	progress.Success("Input parameters validated")
 
-			// Initialize the package client with retry support
+	// Compute checksums of the file for X-Checksum-* headers
+	checksums, err := utils.ComputeFileChecksums(filePath)
+	if err != nil {
+		progress.Error("Failed to compute file checksums")
+		return fmt.Errorf("failed to compute checksums for %s: %w", filePath, err)
+	}
 
-			file, err := os.Open(filePath)
-			if err != nil {
-				progress.Error("Failed to open package file")
-				return err
-			}
-			defer file.Close()
+	// Initialize the package client with retry support
 
-			var formData bytes.Buffer
-			fileWriter := multipart.NewWriter(&formData)
+	file, err := os.Open(filePath)
+	if err != nil {
+		progress.Error("Failed to open package file")
+		return err
+	}
+	defer file.Close()
 
-			// Create the form field "file" to match curl
-			part, err := fileWriter.CreateFormFile("file", filepath.Base(filePath))
-			if err != nil {
-				return err
-			}
+	var formData bytes.Buffer
+	fileWriter := multipart.NewWriter(&formData)
 
-			// Copy the file into the multipart field
-			_, err = io.Copy(part, file)
-			if err != nil {
-				return err
-			}
+	// Create the form field "file" to match curl
+	part, err := fileWriter.CreateFormFile("file", filepath.Base(filePath))
+	if err != nil {
+		return err
+	}
 
-			fileWriter.Close()
-			bufferSize := int64(formData.Len())
-			pkgClient := c.PkgHttpClientWithProgress(progress, bufferSize, "rpm")
-
-			// Upload package
-			progress.Step("Uploading package to registry")
-
-			resp, err := pkgClient.UploadRpmPackageWithBodyWithResponse(
-				context.Background(),
-				config.Global.AccountID,
-				registryName,
-				fileWriter.FormDataContentType(),
-				&formData,
-				func(ctx context.Context, req *http.Request) error {
-					utils.SetChecksumHeaders(req.Header, checksums)
-					return nil
-				},
-			)
-
-			if err != nil {
-				progress.Error("Failed to upload package")
-				return err
-			}
-			// Check response
-			if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusCreated {
-				progress.Error("Upload failed")
-				return fmt.Errorf("failed to push package: %s \n response: %s", resp.Status(), resp.Body)
-			}
+	// Copy the file into the multipart field
+	_, err = io.Copy(part, file)
+	if err != nil {
+		return err
+	}
+
+	fileWriter.Close()
+	bufferSize := int64(formData.Len())
+	pkgClient, err := u.client(bufferSize, "rpm")
+	if err != nil {
+		return err
+	}
 
-			progress.Success(fmt.Sprintf("Successfully uploaded package %s", filePath))
+	// Upload package
+	progress.Step("Uploading package to registry")
+
+	resp, err := pkgClient.UploadRpmPackageWithBodyWithResponse(
+		ctx,
+		config.Global.AccountID,
+		registryName,
+		fileWriter.FormDataContentType(),
+		&formData,
+		func(ctx context.Context, req *http.Request) error {
+			utils.SetChecksumHeaders(req.Header, checksums)
 			return nil
 		},
+	)
+
+	if err != nil {
+		progress.Error("Failed to upload package")
+		return err
+	}
+	// Check response
+	if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusCreated {
+		progress.Error("Upload failed")
+		return fmt.Errorf("failed to push package: %s \n response: %s", resp.Status(), resp.Body)
 	}
 
-	cmd.Flags().StringVar(&pkgURL, "pkg-url", "", "Base URL for the Packages")
-	return cmd
+	progress.Success(fmt.Sprintf("Successfully uploaded package %s", filePath))
+	return nil
 }
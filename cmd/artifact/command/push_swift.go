@@ -27,7 +27,6 @@ const (
 func NewPushSwiftCmd(c *cmdutils.Factory) *cobra.Command {
 
 	var metadataPath string
-	var customHeaders map[string]string
 	const expectedNumberOfArgument = 3
 	cmd := &cobra.Command{
 		Use:   "swift  <registry_name> <file_path> <SCOPE>/<NAME>/<VERSION>",
@@ -44,127 +43,132 @@ func NewPushSwiftCmd(c *cmdutils.Factory) *cobra.Command {
 		},
 		//PreRun: func(cmd *cobra.Command, args []string) {}, --not in use
 		RunE: func(cmd *cobra.Command, args []string) error {
-			registryName := args[0]
-			filePath := args[1]
-			targetPackagePath := args[2]
-
 			// Create progress reporter
 			progress := p.NewConsoleReporter()
+			return pushSwift(cmd.Context(), newPkgUploader(progress), args[0], args[1], args[2], metadataPath, progress)
+		},
+	}
 
-			// Validate Registry Name and file_path
-			progress.Start("Validating input parameters")
+	cmd.Flags().StringVar(&metadataPath, "metadata-path", "", "Path to metadata file")
+	return cmd
+}
 
-			fileName := filepath.Base(filePath)
+// pushSwift uploads the source archive of a Swift package release, with its
+// metadata file when metadataPath is set
+func pushSwift(ctx context.Context, u pkgUploader, registryName, filePath, targetPackagePath, metadataPath string,
+	progress p.Reporter) error {
+	// Validate Registry Name and file_path
+	progress.Start("Validating input parameters")
 
-			// validate file name
-			valid, err := fileutil.IsFilenameAcceptable(fileName, SwiftSupportedFileExtension)
-			if !valid {
-				progress.Error("Invalid file name")
-				return errors.NewValidationError("file_path", fmt.Sprintf("failed to validate package file name: %v", err))
-			}
+	fileName := filepath.Base(filePath)
 
-			// Validate file exists
-			fileInfo, err := os.Stat(filePath)
-			if err != nil {
-				return errors.NewValidationError("file_path", fmt.Sprintf("failed to access package file: %v", err))
-			}
-			if fileInfo.IsDir() {
-				return errors.NewValidationError("file_path", "package file path must be a file, not a directory")
-			}
+	// validate file name
+	valid, err := fileutil.IsFilenameAcceptable(fileName, SwiftSupportedFileExtension)
+	if !valid {
+		progress.Error("Invalid file name")
+		return errors.NewValidationError("file_path", fmt.Sprintf("failed to validate package file name: %v", err))
+	}
 
-			targetDetail, err := parseTargetPath(targetPackagePath)
-			if err != nil {
-				progress.Error("Failed to validate input parameter")
-				return err
-			}
-			targetScope := targetDetail[0]
-			packageName := targetDetail[1]
-			version := targetDetail[2]
+	// Validate file exists
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		return errors.NewValidationError("file_path", fmt.Sprintf("failed to access package file: %v", err))
+	}
+	if fileInfo.IsDir() {
+		return errors.NewValidationError("file_path", "package file path must be a file, not a directory")
+	}
 
-			progress.Success("Input parameters validated")
+	targetDetail, err := parseTargetPath(targetPackagePath)
+	if err != nil {
+		progress.Error("Failed to validate input parameter")
+		return err
+	}
+	targetScope := targetDetail[0]
+	packageName := targetDetail[1]
+	version := targetDetail[2]
 
-			file, err := os.Open(filePath)
-			if err != nil {
-				progress.Error("Failed to open package file")
-				return err
-			}
-			defer file.Close()
+	progress.Success("Input parameters validated")
 
-			var formData bytes.Buffer
-			fileWriter := multipart.NewWriter(&formData)
+	file, err := os.Open(filePath)
+	if err != nil {
+		progress.Error("Failed to open package file")
+		return err
+	}
+	defer file.Close()
 
-			// Create the form field "file" to match curl
-			part, err := fileWriter.CreateFormFile("source-archive", filepath.Base(filePath))
-			if err != nil {
-				return err
-			}
+	var formData bytes.Buffer
+	fileWriter := multipart.NewWriter(&formData)
 
-			// Copy the file into the multipart field
-			_, err = io.Copy(part, file)
-			if err != nil {
-				return err
-			}
+	// Create the form field "file" to match curl
+	part, err := fileWriter.CreateFormFile("source-archive", filepath.Base(filePath))
+	if err != nil {
+		return err
+	}
 
-			// Add metadata file if provided from flag
-			if metadataPath != "" {
-				progress.Step("adding metadata file to upload")
-				metadataFile, err := os.Open(metadataPath)
-				if err != nil {
-					progress.Error("Failed to open metadata file")
-					return fmt.Errorf("failed to open metadata file: %w", err)
-				}
-				defer metadataFile.Close()
-
-				metadataPart, err := fileWriter.CreateFormFile("metadata", filepath.Base(metadataPath))
-				if err != nil {
-					return fmt.Errorf("failed to create metadata form field: %w", err)
-				}
-
-				_, err = io.Copy(metadataPart, metadataFile)
-				if err != nil {
-					return fmt.Errorf("failed to copy metadata file: %w", err)
-				}
-			}
+	// Copy the file into the multipart field
+	_, err = io.Copy(part, file)
+	if err != nil {
+		return err
+	}
 
-			fileWriter.Close()
+	// Add metadata file if provided from flag
+	if metadataPath != "" {
+		progress.Step("adding metadata file to upload")
+		metadataFile, err := os.Open(metadataPath)
+		if err != nil {
+			progress.Error("Failed to open metadata file")
+			return fmt.Errorf("failed to open metadata file: %w", err)
+		}
+		defer metadataFile.Close()
 
-			additionalHeader := getAdditionalHeader(customHeaders)
+		metadataPart, err := fileWriter.CreateFormFile("metadata", filepath.Base(metadataPath))
+		if err != nil {
+			return fmt.Errorf("failed to create metadata form field: %w", err)
+		}
 
-			// Initialize progress reader
-			progress.Step("Uploading package to registry")
-			bufferSize := int64(formData.Len())
+		_, err = io.Copy(metadataPart, metadataFile)
+		if err != nil {
+			return fmt.Errorf("failed to copy metadata file: %w", err)
+		}
+	}
 
-			pkgClient := c.PkgHttpClientWithProgress(progress, bufferSize, "swift")
+	fileWriter.Close()
 
-			resp, err := pkgClient.UploadSwiftPackageWithBodyWithResponse(
-				context.Background(),
-				config.Global.AccountID,
-				registryName,
-				targetScope,
-				packageName,
-				version,
-				fileWriter.FormDataContentType(),
-				&formData,
-				additionalHeader,
-			)
+	additionalHeader := getAdditionalHeader(nil)
 
-			if err != nil {
-				progress.Error("Failed to upload package")
-				return err
-			}
-			// Check response
-			if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusCreated {
-				progress.Error("Upload failed")
-				return fmt.Errorf("failed to push package: %s \n response: %s", resp.Status(), resp.Body)
-			}
+	// Initialize progress reader
+	progress.Step("Uploading package to registry")
+	bufferSize := int64(formData.Len())
 
-			progress.Success(fmt.Sprintf("Successfully uploaded package %s", filePath))
-			return nil
-		},
+	pkgClient, err := u.client(bufferSize, "swift")
+	if err != nil {
+		return err
 	}
 
-	cmd.Flags().StringVar(&metadataPath, "metadata-path", "", "Path to metadata file")
-	return cmd
+	resp, err := pkgClient.UploadSwiftPackageWithBodyWithResponse(
+		ctx,
+		config.Global.AccountID,
+		registryName,
+		targetScope,
+		packageName,
+		version,
+		fileWriter.FormDataContentType(),
+		&formData,
+		additionalHeader,
+	)
+
+	if err != nil {
+		progress.Error("Failed to upload package")
+		return err
+	}
+	// Check response
+	if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusCreated {
+		progress.Error("Upload failed")
+		return fmt.Errorf("failed to push package: %s \n response: %s", resp.Status(), resp.Body)
+	}
+
+	progress.Success(fmt.Sprintf("Successfully uploaded package %s", filePath))
+	return nil
 }
 
 // parsing input of the form ,based on first and last slash
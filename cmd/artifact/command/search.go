@@ -0,0 +1,320 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/harness/harness-cli/cmd/cmdutils"
+	"github.com/harness/harness-cli/internal/api/ar"
+	"github.com/harness/harness-cli/module/ar/migrate/util"
+	client2 "github.com/harness/harness-cli/util/client"
+	"github.com/harness/harness-cli/util/common/pagination"
+	"github.com/harness/harness-cli/util/common/printer"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/gobwas/glob"
+	"github.com/spf13/cobra"
+)
+
+// searchFields lists the fields of the search query language
+var searchFields = []string{"type", "registry", "name", "version", "label", "downloads", "latest", "deployed"}
+
+// searchQuery is a parsed search query. Type, registry, latest, deployed
+// and a search term are sent to GetAllHarnessArtifacts; the remaining terms
+// are filtered client-side. Every term must match, while the comma separated
+// values of type and registry are alternatives.
+type searchQuery struct {
+	types      []string
+	registries []string
+	latest     *bool
+	deployed   *bool
+	names      []glob.Glob
+	words      []string
+	versions   []func(string) bool
+	labels     []string
+	downloads  []func(int64) bool
+	// searchTerm narrows the server-side listing; matches are still checked
+	// client-side since the server matches substrings
+	searchTerm string
+}
+
+// NewSearchArtifactCmd creates a new cobra.Command for searching artifacts
+// across registries.
+// command example: hc artifact search 'type:npm name:@acme/* version:>=2.0 label:team=payments downloads:<5'
+func NewSearchArtifactCmd(c *cmdutils.Factory) *cobra.Command {
+	var limit int
+	cmd := &cobra.Command{
+		Use:   "search <query>",
+		Short: "Search artifacts across registries",
+		Long: `Search the artifacts of every registry in scope with a query made of
+space separated terms, all of which must match:
+
+  type:npm,maven        package type, comma separated alternatives
+  registry:npm-local    registry, comma separated alternatives
+  name:@acme/*          artifact name, * and ? wildcards
+  version:>=2.0         version: a semver constraint (>=2.0, ~1.4, <3.0.0),
+                        a pattern (1.4.*) or an exact version
+  label:team=payments   label; label:team matches any value of the key
+  downloads:<5          download count, with <, <=, >, >= or =
+  latest:true           only the latest version of each artifact
+  deployed:true         only deployed versions
+  payments              a word the artifact name contains
+
+Type, registry, latest, deployed and part of the name are sent to the server;
+the other terms are filtered client-side while every page is fetched.`,
+		Example: `  hc artifact search 'type:npm name:@acme/* version:>=2.0 label:team=payments downloads:<5'
+  hc artifact search registry:docker-prod,docker-stage latest:true downloads:0
+  hc artifact search payments --limit 20 --format json`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query, err := parseSearchQuery(strings.Join(args, " "))
+			if err != nil {
+				return err
+			}
+			artifacts, err := searchArtifacts(cmd.Context(), c.RegistryHttpClient(), query, limit)
+			if err != nil {
+				return err
+			}
+			return printer.Print(artifacts, 0, 1, int64(len(artifacts)), false, [][]string{
+				{"name", "Artifact"},
+				{"version", "Version"},
+				{"packageType", "Package Type"},
+				{"registryIdentifier", "Registry"},
+				{"labels", "Labels"},
+				{"downloadsCount", "Download Count"},
+			})
+		},
+	}
+
+	cmd.Flags().IntVar(&limit, "limit", 0, "maximum number of artifacts to return (0 for all)")
+
+	return cmd
+}
+
+// parseSearchQuery parses the terms of a search query
+func parseSearchQuery(q string) (*searchQuery, error) {
+	query := &searchQuery{}
+	terms := strings.Fields(q)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("empty search query")
+	}
+	for _, term := range terms {
+		field, value, ok := strings.Cut(term, ":")
+		if !ok {
+			query.words = append(query.words, strings.ToLower(term))
+			if query.searchTerm == "" {
+				query.searchTerm = term
+			}
+			continue
+		}
+		if value == "" {
+			return nil, fmt.Errorf("missing value in %q", term)
+		}
+		var err error
+		switch field {
+		case "type":
+			for _, t := range strings.Split(value, ",") {
+				query.types = append(query.types, strings.ToUpper(t))
+			}
+		case "registry":
+			query.registries = append(query.registries, strings.Split(value, ",")...)
+		case "latest":
+			query.latest, err = parseSearchBool(value)
+		case "deployed":
+			query.deployed, err = parseSearchBool(value)
+		case "name":
+			err = query.addName(value)
+		case "version":
+			err = query.addVersion(value)
+		case "label":
+			query.labels = append(query.labels, value)
+		case "downloads":
+			err = query.addDownloads(value)
+		default:
+			return nil, fmt.Errorf("unknown search field %q (supported: %s)", field,
+				strings.Join(searchFields, ", "))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %q: %w", term, err)
+		}
+	}
+	return query, nil
+}
+
+func parseSearchBool(value string) (*bool, error) {
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("expected true or false")
+	}
+	return &b, nil
+}
+
+// addName adds a name pattern; its leading literal part becomes the search
+// term when none is set yet
+func (q *searchQuery) addName(value string) error {
+	if _, err := util.IsWildCardExpression(value); err != nil {
+		return err
+	}
+	g, err := glob.Compile(value)
+	if err != nil {
+		return err
+	}
+	q.names = append(q.names, g)
+	if literal, _, _ := strings.Cut(strings.ReplaceAll(value, "?", "*"), "*"); q.searchTerm == "" {
+		q.searchTerm = literal
+	}
+	return nil
+}
+
+// addVersion adds a version filter: a semver constraint when the value
+// starts with an operator, a pattern when it has wildcards, else an exact
+// version
+func (q *searchQuery) addVersion(value string) error {
+	if strings.ContainsAny(value[:1], "<>=!~^") {
+		constraint, err := semver.NewConstraint(value)
+		if err != nil {
+			return err
+		}
+		q.versions = append(q.versions, func(version string) bool {
+			v, err := semver.NewVersion(version)
+			return err == nil && constraint.Check(v)
+		})
+		return nil
+	}
+	wildcard, err := util.IsWildCardExpression(value)
+	if err != nil {
+		return err
+	}
+	if !wildcard {
+		q.versions = append(q.versions, func(version string) bool { return version == value })
+		return nil
+	}
+	g, err := glob.Compile(value)
+	if err != nil {
+		return err
+	}
+	q.versions = append(q.versions, g.Match)
+	return nil
+}
+
+// addDownloads adds a download count comparison such as <5 or >=100
+func (q *searchQuery) addDownloads(value string) error {
+	op := strings.TrimRight(value, "0123456789")
+	n, err := strconv.ParseInt(value[len(op):], 10, 64)
+	if err != nil {
+		return fmt.Errorf("expected a number, optionally prefixed with <, <=, >, >= or =")
+	}
+	var match func(int64) bool
+	switch op {
+	case "<":
+		match = func(d int64) bool { return d < n }
+	case "<=":
+		match = func(d int64) bool { return d <= n }
+	case ">":
+		match = func(d int64) bool { return d > n }
+	case ">=":
+		match = func(d int64) bool { return d >= n }
+	case "", "=":
+		match = func(d int64) bool { return d == n }
+	default:
+		return fmt.Errorf("unknown comparison %q", op)
+	}
+	q.downloads = append(q.downloads, match)
+	return nil
+}
+
+// params translates the server-side terms to GetAllHarnessArtifacts
+// parameters
+func (q *searchQuery) params() *ar.GetAllHarnessArtifactsParams {
+	params := &ar.GetAllHarnessArtifactsParams{LatestVersion: q.latest, DeployedArtifact: q.deployed}
+	if len(q.types) > 0 {
+		params.PackageType = &q.types
+	}
+	if len(q.registries) > 0 {
+		params.RegIdentifier = &q.registries
+	}
+	if q.searchTerm != "" {
+		params.SearchTerm = &q.searchTerm
+	}
+	return params
+}
+
+// matches applies the client-side terms to an artifact
+func (q *searchQuery) matches(a ar.ArtifactMetadata) bool {
+	// the server filters these, but a server ignoring a filter must not
+	// widen the result
+	if len(q.types) > 0 && (a.PackageType == nil || !slices.Contains(q.types, string(*a.PackageType))) {
+		return false
+	}
+	if len(q.registries) > 0 && !slices.Contains(q.registries, a.RegistryIdentifier) {
+		return false
+	}
+	for _, name := range q.names {
+		if !name.Match(a.Name) {
+			return false
+		}
+	}
+	for _, word := range q.words {
+		if !strings.Contains(strings.ToLower(a.Name), word) {
+			return false
+		}
+	}
+	for _, version := range q.versions {
+		if !version(a.Version) {
+			return false
+		}
+	}
+	var labels []string
+	if a.Labels != nil {
+		labels = *a.Labels
+	}
+	for _, label := range q.labels {
+		if matchingLabel(labels, []string{label}) == "" {
+			return false
+		}
+	}
+	var downloads int64
+	if a.DownloadsCount != nil {
+		downloads = *a.DownloadsCount
+	}
+	for _, match := range q.downloads {
+		if !match(downloads) {
+			return false
+		}
+	}
+	return true
+}
+
+// searchArtifacts pages through GetAllHarnessArtifacts and returns the
+// artifacts matching the query, stopping after limit matches when limit is
+// positive
+func searchArtifacts(ctx context.Context, client *ar.ClientWithResponses, query *searchQuery,
+	limit int) ([]ar.ArtifactMetadata, error) {
+	size := int64(pagination.DefaultPageSize)
+	// each page keeps its matches only, so the limit counts matches
+	fetch := func(ctx context.Context, page int64) (pagination.Page[ar.ArtifactMetadata], error) {
+		params := query.params()
+		params.Page, params.Size = &page, &size
+		resp, err := client.GetAllHarnessArtifactsWithResponse(ctx, client2.GetScopeRef(), params)
+		if err != nil {
+			return pagination.Page[ar.ArtifactMetadata]{}, fmt.Errorf("failed to list artifacts: %w", err)
+		}
+		if resp.JSON200 == nil {
+			return pagination.Page[ar.ArtifactMetadata]{}, fmt.Errorf("failed to list artifacts: %s %s",
+				resp.Status(), string(resp.Body))
+		}
+		data := resp.JSON200.Data
+		var matches []ar.ArtifactMetadata
+		for _, a := range data.Artifacts {
+			if query.matches(a) {
+				matches = append(matches, a)
+			}
+		}
+		return pagination.Page[ar.ArtifactMetadata]{Items: matches, PageCount: derefInt64(data.PageCount)}, nil
+	}
+	return pagination.Collect(ctx, fetch, pagination.Options{Concurrency: pagination.DefaultConcurrency,
+		Limit: limit})
+}
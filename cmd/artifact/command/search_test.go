@@ -0,0 +1,155 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/harness/harness-cli/internal/api/ar"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func searchArtifact(name, version string, packageType ar.PackageType, registry string, downloads int64,
+	labels ...string) ar.ArtifactMetadata {
+	return ar.ArtifactMetadata{Name: name, Version: version, PackageType: &packageType,
+		RegistryIdentifier: registry, DownloadsCount: &downloads, Labels: &labels}
+}
+
+// searchServer serves the artifacts in pages of the requested size and
+// records the query of every request
+func searchServer(t *testing.T, artifacts []ar.ArtifactMetadata, queries *[]url.Values) *ar.ClientWithResponses {
+	t.Helper()
+	withPullConfig(t)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/spaces/acct/+/artifacts" {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		q := r.URL.Query()
+		*queries = append(*queries, q)
+		page, _ := strconv.Atoi(q.Get("page"))
+		size, _ := strconv.Atoi(q.Get("size"))
+		start, end := min(page*size, len(artifacts)), min((page+1)*size, len(artifacts))
+		pageIndex, pageCount := int64(page), int64((len(artifacts)+size-1)/size)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ar.ListArtifactResponse{Data: ar.ListArtifact{
+			Artifacts: artifacts[start:end], PageIndex: &pageIndex, PageCount: &pageCount}})
+	}))
+	t.Cleanup(ts.Close)
+	client, err := ar.NewClientWithResponses(ts.URL)
+	require.NoError(t, err)
+	return client
+}
+
+func TestSearchFiltersAcrossPages(t *testing.T) {
+	var artifacts []ar.ArtifactMetadata
+	for i := 0; i < 150; i++ {
+		artifacts = append(artifacts, searchArtifact(fmt.Sprintf("@acme/lib-%d", i), "1.0.0", "NPM", "npm-local", 100))
+	}
+	artifacts = append(artifacts,
+		searchArtifact("@acme/pay", "2.1.0", "NPM", "npm-local", 3, "team=payments"),
+		searchArtifact("@acme/pay-ui", "2.0.0-beta.1", "NPM", "npm-local", 0, "team=payments"),
+		searchArtifact("@acme/ledger", "3.0.0", "NPM", "npm-remote", 4, "team=payments", "tier=1"),
+		searchArtifact("@acme/billing", "2.4.0", "NPM", "npm-local", 12, "team=payments"),
+		searchArtifact("@other/pay", "2.1.0", "NPM", "npm-local", 0, "team=payments"),
+	)
+	var queries []url.Values
+	client := searchServer(t, artifacts, &queries)
+
+	query, err := parseSearchQuery("type:npm name:@acme/* version:>=2.0 label:team=payments downloads:<5")
+	require.NoError(t, err)
+	result, err := searchArtifacts(t.Context(), client, query, 0)
+	require.NoError(t, err)
+
+	var names []string
+	for _, a := range result {
+		names = append(names, a.Name+"@"+a.Version)
+	}
+	assert.Equal(t, []string{"@acme/pay@2.1.0", "@acme/ledger@3.0.0"}, names)
+	require.Len(t, queries, 2)
+	assert.Equal(t, "NPM", queries[0].Get("package_type"))
+	assert.Equal(t, "@acme/", queries[0].Get("search_term"))
+	assert.Equal(t, "1", queries[1].Get("page"))
+
+	// the limit stops the pagination
+	queries = nil
+	query, err = parseSearchQuery("lib")
+	require.NoError(t, err)
+	result, err = searchArtifacts(t.Context(), client, query, 10)
+	require.NoError(t, err)
+	assert.Len(t, result, 10)
+	assert.Len(t, queries, 1)
+	assert.Equal(t, "lib", queries[0].Get("search_term"))
+}
+
+func TestSearchQueryMatches(t *testing.T) {
+	a := searchArtifact("payments-api", "1.4.2", "DOCKER", "docker-prod", 7, "team=payments", "critical")
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"payments", true},
+		{"PAYMENTS api", true},
+		{"billing", false},
+		{"type:docker,helm registry:docker-prod,docker-stage", true},
+		{"type:helm", false},
+		{"registry:docker-stage", false},
+		{"name:payments-*", true},
+		{"name:payments", false},
+		{"name:payments-ap?", true},
+		{"version:1.4.2", true},
+		{"version:1.4.*", true},
+		{"version:~1.4", true},
+		{"version:<1.4.0", false},
+		{"version:>=1.0,<2.0", true},
+		{"label:team", true},
+		{"label:team=payments label:critical", true},
+		{"label:team=billing", false},
+		{"downloads:7", true},
+		{"downloads:>=7", true},
+		{"downloads:>7", false},
+		{"downloads:<=10", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			query, err := parseSearchQuery(tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, query.matches(a))
+		})
+	}
+}
+
+func TestParseSearchQueryTranslatesParams(t *testing.T) {
+	query, err := parseSearchQuery("type:npm,maven registry:npm-local latest:true deployed:false payments")
+	require.NoError(t, err)
+	params := query.params()
+	assert.Equal(t, []string{"NPM", "MAVEN"}, *params.PackageType)
+	assert.Equal(t, []string{"npm-local"}, *params.RegIdentifier)
+	assert.True(t, *params.LatestVersion)
+	assert.False(t, *params.DeployedArtifact)
+	assert.Equal(t, "payments", *params.SearchTerm)
+}
+
+func TestParseSearchQueryRejectsInvalidTerms(t *testing.T) {
+	for query, msg := range map[string]string{
+		"":                "empty search query",
+		"owner:payments":  `unknown search field "owner"`,
+		"type:":           `missing value in "type:"`,
+		"downloads:lots":  "expected a number",
+		"downloads:=>5":   `unknown comparison "=>"`,
+		"version:>=two":   `invalid "version:>=two"`,
+		"latest:maybe":    "expected true or false",
+		"name:pay-[0-9]":  "unsupported wildcard character",
+		"version:1.{2,3}": "unsupported wildcard character",
+	} {
+		_, err := parseSearchQuery(query)
+		assert.ErrorContains(t, err, msg, query)
+	}
+}
@@ -0,0 +1,20 @@
+package command
+
+import (
+	"github.com/harness/harness-cli/cmd/cmdutils"
+
+	"github.com/spf13/cobra"
+)
+
+// NewSyncArtifactCmd creates a new cobra.Command for syncing local content to artifacts
+func NewSyncArtifactCmd(f *cmdutils.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Sync local content to Harness Artifact Registry",
+		Long:  `Sync local content to Harness Artifact Registry, uploading only what changed`,
+	}
+
+	cmd.AddCommand(NewSyncGenericCmd(f))
+
+	return cmd
+}
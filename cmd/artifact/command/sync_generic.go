@@ -0,0 +1,287 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/harness/harness-cli/cmd/artifact/command/utils"
+	"github.com/harness/harness-cli/cmd/cmdutils"
+	"github.com/harness/harness-cli/config"
+	ar "github.com/harness/harness-cli/internal/api/ar"
+	"github.com/harness/harness-cli/internal/api/ar_pkg"
+	"github.com/harness/harness-cli/util"
+	"github.com/harness/harness-cli/util/common/httpclient"
+	"github.com/harness/harness-cli/util/common/progress"
+	"github.com/harness/harness-cli/util/common/upload"
+
+	"github.com/inhies/go-bytesize"
+	"github.com/spf13/cobra"
+)
+
+// genericSyncPlan is what a sync changes on the registry, by file path
+// relative to the version
+type genericSyncPlan struct {
+	added     []string
+	changed   []string
+	deleted   []string
+	unchanged int
+	uploads   []upload.FileUploadJob
+}
+
+// NewSyncGenericCmd creates a new cobra.Command that makes a generic version
+// mirror a local directory.
+//
+// Usage:
+//
+//	hc artifact sync generic <registry> <dir> --name <pkg> [--version v] [--delete] [--dry-run]
+//
+// Every file under <dir> lands at <pkg>/<version>/<relative-path>. New files
+// and files whose checksum differs from the registry copy are uploaded; with
+// --delete, files of the version that no longer exist under <dir> are deleted.
+func NewSyncGenericCmd(c *cmdutils.Factory) *cobra.Command {
+	var packageName, packageVersion, pkgURL string
+	var includeHidden, deleteExtraneous, dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "generic <registry> <dir>",
+		Short: "Sync a directory to a Generic Artifact version",
+		Long: "Make a generic artifact version mirror a local directory: upload new and changed files " +
+			"(compared by checksum) and, with --delete, delete the files of the version that are absent " +
+			"locally. Use --dry-run to preview the changes.",
+		Args: cobra.ExactArgs(2),
+		PreRun: func(cmd *cobra.Command, args []string) {
+			if pkgURL != "" {
+				config.Global.Registry.PkgURL = util.GetPkgUrl(pkgURL)
+			}
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			registryName, dir := args[0], args[1]
+			ctx := cmd.Context()
+
+			version := packageVersion
+			if version == "" {
+				version = "1.0.0"
+			}
+			info, err := os.Stat(dir)
+			if err != nil {
+				return fmt.Errorf("cannot access %q: %w", dir, err)
+			}
+			if !info.IsDir() {
+				return fmt.Errorf("%s is not a directory", dir)
+			}
+
+			fmt.Printf("Scanning %s ...\n", dir)
+			jobs, stats, err := collectGenericUploadJobs([]string{dir}, registryName, packageName, version,
+				includeHidden, config.Global.Registry.PkgURL, httpclient.NewRetryClientWithoutProgress())
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Found %d local file(s) (%s)\n", stats.fileCount, bytesize.New(float64(stats.totalBytes)))
+			rebaseGenericJobs(jobs, dir, packageName, version)
+
+			remote, err := listVersionFiles(ctx, c.RegistryHttpClient(), registryName, packageName, version)
+			if err != nil && !errors.Is(err, errVersionNotFound) {
+				return err
+			}
+			plan, err := planGenericSync(ctx, jobs, remote, packageName, version)
+			if err != nil {
+				return err
+			}
+			if !deleteExtraneous {
+				plan.deleted = nil
+			}
+
+			printGenericSyncPlan(plan, dryRun)
+			if dryRun {
+				fmt.Println("Dry run: no changes made")
+				return nil
+			}
+
+			if len(plan.uploads) > 0 {
+				engine := upload.NewFileUploadEngine(upload.DefaultUploadWorker, progress.NewConsoleReporter())
+				results := engine.Execute(ctx, plan.uploads)
+				if upload.HasUploadErrors(results) {
+					printGenericUploadFailures(results)
+					failed := len(results) - upload.GetSuccessfulUploads(results)
+					return fmt.Errorf("%d of %d file(s) failed to upload, no files deleted", failed, len(results))
+				}
+			}
+			if err := deleteGenericFiles(ctx, c.PkgHttpClient(), registryName, packageName, version,
+				plan.deleted); err != nil {
+				return err
+			}
+
+			fmt.Printf("Synced %s to %s/%s in registry '%s': %d added, %d changed, %d deleted, %d unchanged\n",
+				dir, packageName, version, registryName,
+				len(plan.added), len(plan.changed), len(plan.deleted), plan.unchanged)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&packageName, "name", "n", "", "name for the artifact")
+	cmd.Flags().StringVar(&packageVersion, "version", "", "version for the artifact (defaults to '1.0.0')")
+	cmd.Flags().StringVar(&pkgURL, "pkg-url", "", "Base URL for the Packages")
+	cmd.Flags().BoolVar(&includeHidden, "include-hidden", false,
+		"Include hidden files and directories (names starting with '.')")
+	cmd.Flags().BoolVar(&deleteExtraneous, "delete", false,
+		"Delete files of the version that do not exist in the local directory")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the changes without uploading or deleting anything")
+
+	cmd.MarkFlagRequired("name")
+
+	return cmd
+}
+
+// rebaseGenericJobs moves jobs collected from dir to the root of the version:
+// collectGenericUploadJobs keeps the directory's basename as a prefix, while a
+// sync mirrors the directory's contents
+func rebaseGenericJobs(jobs []upload.FileUploadJob, dir, packageName, version string) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return
+	}
+	prefix := filepath.ToSlash(filepath.Base(abs))
+	if prefix == "." || prefix == "/" {
+		return
+	}
+	for _, job := range jobs {
+		gj, ok := job.(*upload.GenericUploadJob)
+		if !ok {
+			continue
+		}
+		gj.ID = strings.TrimPrefix(gj.ID, prefix+"/")
+		gj.DestPath = fmt.Sprintf("%s/%s/%s", packageName, version, gj.ID)
+	}
+}
+
+// listedChangeDetector compares jobs against the checksums of a version's
+// file listing, which a sync fetches anyway to find deleted files
+type listedChangeDetector map[string]utils.FileChecksums
+
+// Unchanged reports the jobs whose strongest checksum listed by the registry
+// matches the local file
+func (l listedChangeDetector) Unchanged(_ context.Context, jobs []*upload.GenericUploadJob) (map[string]bool, error) {
+	unchanged := make(map[string]bool)
+	for _, job := range jobs {
+		listed, ok := l[job.DestPath]
+		if !ok {
+			continue
+		}
+		for _, c := range []struct{ want, got string }{
+			{listed.SHA512, job.Checksums.SHA512},
+			{listed.SHA256, job.Checksums.SHA256},
+			{listed.SHA1, job.Checksums.SHA1},
+			{listed.MD5, job.Checksums.MD5},
+		} {
+			if c.want != "" {
+				unchanged[job.DestPath] = c.want == c.got
+				break
+			}
+		}
+	}
+	return unchanged, nil
+}
+
+// planGenericSync splits the local jobs into added, changed and unchanged
+// files and lists the remote files absent locally
+func planGenericSync(
+	ctx context.Context,
+	jobs []upload.FileUploadJob,
+	remote []ar.FileDetail,
+	packageName, version string,
+) (genericSyncPlan, error) {
+	listed := make(listedChangeDetector, len(remote))
+	for _, file := range remote {
+		listed[fmt.Sprintf("%s/%s/%s", packageName, version, genericFilePath(file))] = parseFileChecksums(file.Checksums)
+	}
+
+	var plan genericSyncPlan
+	uploads, skipped, err := filterUnchangedGenericJobs(ctx, listed, jobs)
+	if err != nil {
+		return plan, err
+	}
+	plan.uploads = uploads
+	plan.unchanged = skipped
+
+	local := make(map[string]bool, len(jobs))
+	for _, job := range jobs {
+		if gj, ok := job.(*upload.GenericUploadJob); ok {
+			local[gj.DestPath] = true
+		}
+	}
+	for _, job := range uploads {
+		gj, ok := job.(*upload.GenericUploadJob)
+		if !ok {
+			continue
+		}
+		if _, exists := listed[gj.DestPath]; exists {
+			plan.changed = append(plan.changed, gj.ID)
+		} else {
+			plan.added = append(plan.added, gj.ID)
+		}
+	}
+	for _, file := range remote {
+		rel := genericFilePath(file)
+		if !local[fmt.Sprintf("%s/%s/%s", packageName, version, rel)] {
+			plan.deleted = append(plan.deleted, rel)
+		}
+	}
+	sort.Strings(plan.added)
+	sort.Strings(plan.changed)
+	sort.Strings(plan.deleted)
+	return plan, nil
+}
+
+// printGenericSyncPlan lists every file a sync adds, changes or deletes
+func printGenericSyncPlan(plan genericSyncPlan, dryRun bool) {
+	verb := ""
+	if dryRun {
+		verb = "would be "
+	}
+	for _, group := range []struct {
+		mark, label string
+		files       []string
+	}{
+		{"+", "added", plan.added},
+		{"~", "changed", plan.changed},
+		{"-", "deleted", plan.deleted},
+	} {
+		if len(group.files) == 0 {
+			continue
+		}
+		fmt.Printf("%d file(s) %s%s:\n", len(group.files), verb, group.label)
+		for _, f := range group.files {
+			fmt.Printf("  %s %s\n", group.mark, f)
+		}
+	}
+	fmt.Printf("%d file(s) unchanged\n", plan.unchanged)
+}
+
+// deleteGenericFiles deletes files of a generic version, stopping at the
+// first failure
+func deleteGenericFiles(
+	ctx context.Context,
+	client *ar_pkg.ClientWithResponses,
+	registryName, packageName, version string,
+	files []string,
+) error {
+	for _, rel := range files {
+		resp, err := client.DeleteGenericFileFromPath(ctx, config.Global.AccountID, registryName,
+			fmt.Sprintf("%s/%s/%s", packageName, version, rel), keepPathSlashes)
+		if err != nil {
+			return fmt.Errorf("failed to delete %s: %w", rel, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+			return fmt.Errorf("failed to delete %s: server returned %s", rel, resp.Status)
+		}
+		fmt.Printf("Deleted %s\n", rel)
+	}
+	return nil
+}
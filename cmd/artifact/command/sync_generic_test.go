@@ -0,0 +1,177 @@
+package command
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/harness/harness-cli/cmd/cmdutils"
+	"github.com/harness/harness-cli/config"
+	"github.com/harness/harness-cli/internal/api/ar"
+	"github.com/harness/harness-cli/internal/api/ar_pkg"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncServer holds the files of docs/1.0 in registry "reg" and records the
+// files uploaded and deleted
+type syncServer struct {
+	mu      sync.Mutex
+	files   map[string]string
+	put     []string
+	deleted []string
+}
+
+func newSyncServer(t *testing.T, files map[string]string) (*syncServer, *cmdutils.Factory) {
+	t.Helper()
+	s := &syncServer{files: files}
+	const prefix = "/pkg/acct/reg/files/docs/1.0/"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if rel, ok := strings.CutPrefix(r.URL.EscapedPath(), prefix); ok {
+			switch r.Method {
+			case http.MethodPut:
+				body, _ := io.ReadAll(r.Body)
+				if s.files == nil {
+					s.files = map[string]string{}
+				}
+				s.files[rel] = string(body)
+				s.put = append(s.put, rel)
+				w.WriteHeader(http.StatusCreated)
+			case http.MethodDelete:
+				delete(s.files, rel)
+				s.deleted = append(s.deleted, rel)
+				w.WriteHeader(http.StatusOK)
+			default:
+				t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			}
+			return
+		}
+		require.True(t, strings.HasSuffix(r.URL.Path, "/artifact/docs/+/version/1.0/files"), "unexpected path %s", r.URL.Path)
+		if s.files == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var details []ar.FileDetail
+		for name, content := range s.files {
+			sum := sha256.Sum256([]byte(content))
+			details = append(details, ar.FileDetail{
+				Name:      "/" + name,
+				Checksums: []string{"SHA-256: " + hex.EncodeToString(sum[:])},
+			})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ar.FileDetailResponse{Data: ar.ListFileDetail{Files: details}, Status: "SUCCESS"})
+	}))
+	t.Cleanup(ts.Close)
+
+	withPullConfig(t)
+	origPkgURL := config.Global.Registry.PkgURL
+	config.Global.Registry.PkgURL = ts.URL
+	t.Cleanup(func() { config.Global.Registry.PkgURL = origPkgURL })
+
+	client, err := ar.NewClientWithResponses(ts.URL)
+	require.NoError(t, err)
+	pkgClient, err := ar_pkg.NewClientWithResponses(ts.URL)
+	require.NoError(t, err)
+	return s, &cmdutils.Factory{
+		RegistryHttpClient: func() *ar.ClientWithResponses { return client },
+		PkgHttpClient:      func() *ar_pkg.ClientWithResponses { return pkgClient },
+	}
+}
+
+// runSyncCmd executes the generic sync command and returns what it printed
+func runSyncCmd(t *testing.T, f *cmdutils.Factory, args ...string) (string, error) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	origStdout := os.Stdout
+	os.Stdout = w
+	doneCh := make(chan string, 1)
+	go func() {
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, r)
+		doneCh <- buf.String()
+	}()
+
+	cmd := NewSyncGenericCmd(f)
+	cmd.SetArgs(args)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	runErr := cmd.Execute()
+
+	_ = w.Close()
+	os.Stdout = origStdout
+	return <-doneCh, runErr
+}
+
+func TestSyncGenericMirrorsDirectory(t *testing.T) {
+	s, f := newSyncServer(t, map[string]string{
+		"index.html":        "old home",
+		"css/site.css":      "body {}",
+		"api/removed.html":  "gone",
+		"api/unchanged.txt": "same",
+	})
+	dir := makeTree(t, map[string]string{
+		"index.html":        "new home",
+		"css/site.css":      "body {}",
+		"api/unchanged.txt": "same",
+		"api/v2/new.html":   "fresh",
+	})
+
+	stdout, err := runSyncCmd(t, f, "reg", dir, "--name", "docs", "--version", "1.0", "--delete")
+	require.NoError(t, err)
+
+	sort.Strings(s.put)
+	assert.Equal(t, []string{"api/v2/new.html", "index.html"}, s.put)
+	assert.Equal(t, []string{"api/removed.html"}, s.deleted)
+	assert.Equal(t, "new home", s.files["index.html"])
+	assert.Contains(t, stdout, "  + api/v2/new.html")
+	assert.Contains(t, stdout, "  ~ index.html")
+	assert.Contains(t, stdout, "  - api/removed.html")
+	assert.Contains(t, stdout, "1 added, 1 changed, 1 deleted, 2 unchanged")
+}
+
+func TestSyncGenericKeepsRemoteFilesWithoutDelete(t *testing.T) {
+	s, f := newSyncServer(t, map[string]string{"old.txt": "old"})
+	dir := makeTree(t, map[string]string{"new.txt": "new"})
+
+	_, err := runSyncCmd(t, f, "reg", dir, "--name", "docs", "--version", "1.0")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"new.txt"}, s.put)
+	assert.Empty(t, s.deleted)
+}
+
+func TestSyncGenericDryRun(t *testing.T) {
+	s, f := newSyncServer(t, map[string]string{"old.txt": "old", "keep.txt": "v1"})
+	dir := makeTree(t, map[string]string{"keep.txt": "v2", "new.txt": "new"})
+
+	stdout, err := runSyncCmd(t, f, "reg", dir, "--name", "docs", "--version", "1.0", "--delete", "--dry-run")
+	require.NoError(t, err)
+	assert.Empty(t, s.put)
+	assert.Empty(t, s.deleted)
+	assert.Contains(t, stdout, "1 file(s) would be added:")
+	assert.Contains(t, stdout, "1 file(s) would be changed:")
+	assert.Contains(t, stdout, "1 file(s) would be deleted:")
+	assert.Contains(t, stdout, "Dry run: no changes made")
+}
+
+func TestSyncGenericNewVersion(t *testing.T) {
+	s, f := newSyncServer(t, nil)
+	dir := makeTree(t, map[string]string{"a.txt": "a"})
+
+	_, err := runSyncCmd(t, f, "reg", dir, "--name", "docs", "--version", "1.0", "--delete")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a.txt"}, s.put)
+}
@@ -0,0 +1,409 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/harness/harness-cli/cmd/cmdutils"
+	"github.com/harness/harness-cli/config"
+	"github.com/harness/harness-cli/internal/api/ar"
+	"github.com/harness/harness-cli/util/common/pagination"
+	"github.com/harness/harness-cli/util/common/printer"
+
+	"github.com/spf13/cobra"
+)
+
+// statsDateFormat is the date format of the artifact stats endpoint
+const statsDateFormat = "01/02/2006"
+
+// artifactUsage describes how often an artifact is downloaded and, for a
+// single version, where that version is deployed
+type artifactUsage struct {
+	Artifact    string            `json:"artifact"`
+	Version     string            `json:"version,omitempty"`
+	PackageType string            `json:"packageType,omitempty"`
+	Downloads   []downloadPeriod  `json:"downloads,omitempty"`
+	Versions    []versionUsage    `json:"versions"`
+	Deployments []deploymentUsage `json:"deployments,omitempty"`
+	// ProdEnvs and PreProdEnvs count the environments the version is
+	// deployed to
+	ProdEnvs    *int `json:"prodEnvs,omitempty"`
+	PreProdEnvs *int `json:"preProdEnvs,omitempty"`
+}
+
+// downloadPeriod holds the downloads of an artifact over the last days, or
+// over all time when Days is zero
+type downloadPeriod struct {
+	Period    string `json:"period"`
+	Days      int    `json:"days,omitempty"`
+	Downloads int64  `json:"downloads"`
+	Size      int64  `json:"size"`
+}
+
+type versionUsage struct {
+	Version      string `json:"version"`
+	Downloads    int64  `json:"downloads"`
+	ProdEnvs     int    `json:"prodEnvs"`
+	NonProdEnvs  int    `json:"nonProdEnvs"`
+	Size         string `json:"size,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+type deploymentUsage struct {
+	Environment    string `json:"environment"`
+	EnvType        string `json:"envType,omitempty"`
+	Infrastructure string `json:"infrastructure,omitempty"`
+	Service        string `json:"service,omitempty"`
+	Pipeline       string `json:"pipeline,omitempty"`
+	ExecutionID    string `json:"executionId,omitempty"`
+	DeployedBy     string `json:"deployedBy,omitempty"`
+	LastDeployedAt string `json:"lastDeployedAt,omitempty"`
+	Count          int    `json:"count"`
+}
+
+// NewUsageArtifactCmd creates a new cobra.Command for showing the usage of an
+// artifact.
+// command example: hc artifact usage <registry_name>/<artifact> --version <version>
+//
+// Without --version the downloads of every version are listed, which helps
+// finding unused versions before deleting them; with --version the
+// deployments of that version are listed as well.
+func NewUsageArtifactCmd(c *cmdutils.Factory) *cobra.Command {
+	const expectedNumberOfArgument = 1
+	var version, envType string
+	var days []int
+	cmd := &cobra.Command{
+		Use:   "usage <registry_name>/<artifact>",
+		Short: "Show deployments and downloads of an artifact",
+		Long: `Show how often an artifact was downloaded over the last days and in total, the
+downloads of each of its versions and the environments they are deployed to.
+With --version, the downloads and deployments of that version (environment,
+service, pipeline and when it was last deployed) are listed; the download
+periods count every version of the artifact, so they are left out.`,
+		Example: `  hc artifact usage docker-prod/payments-api
+  hc artifact usage docker-prod/payments-api --version 1.4.0 --env-type production
+  hc artifact usage docker-prod/payments-api --days 1,7,30,365`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) != expectedNumberOfArgument {
+				return fmt.Errorf(
+					"Error: Invalid number of argument,  accepts %d arg(s), received %d  \nUsage :\n %s",
+					expectedNumberOfArgument, len(args), cmd.UseLine(),
+				)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			registryName, artifact, err := parseArtifactRef(args[0])
+			if err != nil {
+				return err
+			}
+			var env *ar.GetArtifactDeploymentsParamsEnvType
+			switch strings.ToLower(envType) {
+			case "":
+			case "production", "prod":
+				e := ar.Production
+				env = &e
+			case "preproduction", "pre-production", "preprod":
+				e := ar.PreProduction
+				env = &e
+			default:
+				return fmt.Errorf("invalid env type %q (expected production or preproduction)", envType)
+			}
+			if env != nil && version == "" {
+				return fmt.Errorf("--env-type requires --version")
+			}
+			if cmd.Flags().Changed("days") && version != "" {
+				return fmt.Errorf("--days counts the downloads of every version and cannot be used with --version")
+			}
+			for _, d := range days {
+				if d <= 0 {
+					return fmt.Errorf("invalid period %d, days must be positive", d)
+				}
+			}
+
+			result, err := artifactUsageOf(cmd.Context(), c.RegistryHttpClient(), registryName, artifact, version,
+				env, days, time.Now())
+			if err != nil {
+				return err
+			}
+
+			if config.Global.Format == "json" {
+				options := printer.DefaultJsonOptions()
+				options.ShowPagination = false
+				return printer.PrintJsonWithOptions(result, options)
+			}
+			return printArtifactUsage(result)
+		},
+	}
+
+	cmd.Flags().StringVar(&version, "version", "", "Version to list the deployments of")
+	cmd.Flags().StringVar(&envType, "env-type", "",
+		"Only list deployments to environments of this type (production or preproduction)")
+	cmd.Flags().IntSliceVar(&days, "days", []int{7, 30, 90}, "Periods in days to count the artifact downloads over, without --version")
+
+	return cmd
+}
+
+func artifactUsageOf(ctx context.Context, client *ar.ClientWithResponses, registryName, artifact, version string,
+	env *ar.GetArtifactDeploymentsParamsEnvType, days []int, now time.Time) (*artifactUsage, error) {
+	registryRef := artifactRegistryRef(registryName)
+	result := &artifactUsage{Artifact: registryName + "/" + artifact, Version: version}
+
+	if version != "" {
+		summary, err := getVersionSummary(ctx, client,
+			versionRef{Registry: registryName, Artifact: artifact, Version: version})
+		if err != nil {
+			return nil, err
+		}
+		result.PackageType = string(summary.PackageType)
+	}
+
+	// the stats cover every version of the artifact, a version only has its
+	// total downloads
+	if version == "" {
+		for _, d := range append(slices.Clone(days), 0) {
+			period, err := artifactDownloads(ctx, client, registryRef, artifact, d, now)
+			if err != nil {
+				return nil, err
+			}
+			result.Downloads = append(result.Downloads, period)
+		}
+	}
+
+	versions, err := listVersionUsage(ctx, client, registryRef, artifact, version)
+	if err != nil {
+		return nil, err
+	}
+	result.Versions = versions
+	if version == "" {
+		return result, nil
+	}
+
+	size := int64(pagination.DefaultPageSize)
+	fetch := func(ctx context.Context, page int64) (pagination.Page[deploymentUsage], error) {
+		resp, err := client.GetArtifactDeploymentsWithResponse(ctx, registryRef, artifact, version,
+			&ar.GetArtifactDeploymentsParams{EnvType: env, Page: &page, Size: &size})
+		if err != nil {
+			return pagination.Page[deploymentUsage]{}, fmt.Errorf("failed to list deployments: %w", err)
+		}
+		if resp.JSON200 == nil {
+			return pagination.Page[deploymentUsage]{}, fmt.Errorf("failed to list deployments of %s:%s: %s %s",
+				result.Artifact, version, resp.Status(), string(resp.Body))
+		}
+		data := resp.JSON200.Data
+		// the first page is fetched alone, before the others
+		if stats := data.DeploymentsStats; stats != nil && page == 0 {
+			result.ProdEnvs, result.PreProdEnvs = &stats.Production, &stats.PreProduction
+		}
+		var details []ar.ArtifactDeploymentsDetail
+		if data.Deployments.Deployments != nil {
+			details = *data.Deployments.Deployments
+		}
+		var deployments []deploymentUsage
+		for _, d := range details {
+			deployments = append(deployments, deploymentUsage{
+				Environment:    firstNonEmpty(d.EnvName, d.EnvIdentifier),
+				EnvType:        derefEnvType(d.EnvType),
+				Infrastructure: firstNonEmpty(d.InfraName, d.InfraIdentifier),
+				Service:        firstNonEmpty(d.ServiceName, d.ServiceIdentifier),
+				Pipeline:       firstNonEmpty(d.LastPipelineExecutionName, d.PipelineId),
+				ExecutionID:    derefString(d.LastPipelineExecutionId),
+				DeployedBy:     firstNonEmpty(d.LastDeployedByName, d.LastDeployedById),
+				LastDeployedAt: formatMillis(derefString(d.LastDeployedAt)),
+				Count:          derefInt(d.Count),
+			})
+		}
+		return pagination.Page[deploymentUsage]{Items: deployments,
+			PageCount: derefInt64(data.Deployments.PageCount)}, nil
+	}
+	if result.Deployments, err = pagination.Collect(ctx, fetch,
+		pagination.Options{Concurrency: pagination.DefaultConcurrency}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// artifactDownloads returns the downloads of an artifact over the last days
+// before now, or over all time when days is zero
+func artifactDownloads(ctx context.Context, client *ar.ClientWithResponses, registryRef, artifact string,
+	days int, now time.Time) (downloadPeriod, error) {
+	period := downloadPeriod{Period: "all time", Days: days}
+	params := &ar.GetArtifactStatsParams{}
+	if days > 0 {
+		period.Period = fmt.Sprintf("last %d days", days)
+		if days == 1 {
+			period.Period = "last day"
+		}
+		from, to := now.AddDate(0, 0, -days).Format(statsDateFormat), now.Format(statsDateFormat)
+		params.From, params.To = &from, &to
+	}
+
+	resp, err := client.GetArtifactStatsWithResponse(ctx, registryRef, artifact, params)
+	if err != nil {
+		return period, fmt.Errorf("failed to fetch download stats: %w", err)
+	}
+	if resp.JSON200 == nil {
+		return period, fmt.Errorf("failed to fetch download stats of %s: %s %s", artifact, resp.Status(),
+			string(resp.Body))
+	}
+	stats := resp.JSON200.Data
+	if stats.DownloadCount != nil {
+		period.Downloads = *stats.DownloadCount
+	}
+	if stats.DownloadSize != nil {
+		period.Size = *stats.DownloadSize
+	}
+	return period, nil
+}
+
+// listVersionUsage lists the downloads and deployment counts of the versions
+// of an artifact, following pagination; with a version only that version is
+// returned
+func listVersionUsage(ctx context.Context, client *ar.ClientWithResponses, registryRef, artifact,
+	version string) ([]versionUsage, error) {
+	size := int64(pagination.DefaultPageSize)
+	fetch := func(ctx context.Context, page int64) (pagination.Page[versionUsage], error) {
+		params := &ar.GetAllArtifactVersionsParams{Page: &page, Size: &size}
+		if version != "" {
+			params.SearchTerm = &version
+		}
+		resp, err := client.GetAllArtifactVersionsWithResponse(ctx, registryRef, artifact, params)
+		if err != nil {
+			return pagination.Page[versionUsage]{}, fmt.Errorf("failed to list versions: %w", err)
+		}
+		if resp.JSON200 == nil {
+			return pagination.Page[versionUsage]{}, fmt.Errorf("failed to list versions of %s: %s %s", artifact,
+				resp.Status(), string(resp.Body))
+		}
+		data := resp.JSON200.Data
+		var items []ar.ArtifactVersionMetadata
+		if data.ArtifactVersions != nil {
+			items = *data.ArtifactVersions
+		}
+		var versions []versionUsage
+		for _, v := range items {
+			// the search term matches versions containing it
+			if version != "" && v.Name != version {
+				continue
+			}
+			u := versionUsage{
+				Version:      v.Name,
+				Size:         derefString(v.Size),
+				LastModified: formatMillis(derefString(v.LastModified)),
+			}
+			if v.DownloadsCount != nil {
+				u.Downloads = *v.DownloadsCount
+			}
+			if v.DeploymentMetadata != nil {
+				u.ProdEnvs, u.NonProdEnvs = v.DeploymentMetadata.ProdEnvCount, v.DeploymentMetadata.NonProdEnvCount
+			}
+			versions = append(versions, u)
+		}
+		return pagination.Page[versionUsage]{Items: versions, PageCount: derefInt64(data.PageCount)}, nil
+	}
+	return pagination.Collect(ctx, fetch, pagination.Options{Concurrency: pagination.DefaultConcurrency})
+}
+
+func printArtifactUsage(result *artifactUsage) error {
+	fields := [][2]string{
+		{"Artifact", result.Artifact},
+		{"Version", result.Version},
+		{"Package Type", result.PackageType},
+	}
+	if result.ProdEnvs != nil {
+		fields = append(fields, [2]string{"Production Environments", strconv.Itoa(*result.ProdEnvs)})
+	}
+	if result.PreProdEnvs != nil {
+		fields = append(fields, [2]string{"Pre-Production Environments", strconv.Itoa(*result.PreProdEnvs)})
+	}
+	if err := printInspectSummary(fields); err != nil {
+		return err
+	}
+
+	// counts are formatted here, the table would print large numbers in
+	// e-notation
+	downloads := make([]map[string]string, 0, len(result.Downloads))
+	for _, d := range result.Downloads {
+		downloads = append(downloads, map[string]string{
+			"period":    d.Period,
+			"downloads": strconv.FormatInt(d.Downloads, 10),
+			"size":      formatBytes(d.Size),
+		})
+	}
+	if err := printInspectSection("Downloads", downloads, len(downloads), [][]string{
+		{"period", "Period"},
+		{"downloads", "Downloads"},
+		{"size", "Downloaded"},
+	}); err != nil {
+		return err
+	}
+
+	versions := make([]map[string]string, 0, len(result.Versions))
+	for _, v := range result.Versions {
+		versions = append(versions, map[string]string{
+			"version":      v.Version,
+			"downloads":    strconv.FormatInt(v.Downloads, 10),
+			"prodEnvs":     strconv.Itoa(v.ProdEnvs),
+			"nonProdEnvs":  strconv.Itoa(v.NonProdEnvs),
+			"size":         v.Size,
+			"lastModified": v.LastModified,
+		})
+	}
+	if err := printInspectSection("Versions", versions, len(versions), [][]string{
+		{"version", "Version"},
+		{"downloads", "Downloads"},
+		{"prodEnvs", "Prod Envs"},
+		{"nonProdEnvs", "Non-Prod Envs"},
+		{"size", "Size"},
+		{"lastModified", "Last Modified"},
+	}); err != nil {
+		return err
+	}
+
+	if result.Version == "" {
+		return nil
+	}
+	if len(result.Deployments) == 0 {
+		fmt.Println("\nNo deployments")
+		return nil
+	}
+	return printInspectSection("Deployments", result.Deployments, len(result.Deployments), [][]string{
+		{"environment", "Environment"},
+		{"envType", "Type"},
+		{"service", "Service"},
+		{"pipeline", "Pipeline"},
+		{"deployedBy", "Deployed By"},
+		{"lastDeployedAt", "Last Deployed"},
+		{"count", "Count"},
+	})
+}
+
+// formatMillis renders a timestamp in milliseconds as a date; other values
+// are returned as they are
+func formatMillis(s string) string {
+	ms, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return s
+	}
+	return time.UnixMilli(ms).UTC().Format(time.RFC3339)
+}
+
+func firstNonEmpty(values ...*string) string {
+	for _, v := range values {
+		if v != nil && *v != "" {
+			return *v
+		}
+	}
+	return ""
+}
+
+func derefEnvType(t *ar.EnvironmentType) string {
+	if t == nil {
+		return ""
+	}
+	return string(*t)
+}
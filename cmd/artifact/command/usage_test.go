@@ -0,0 +1,135 @@
+package command
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/harness/harness-cli/internal/api/ar"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// usageServer serves the usage endpoints of reg/api, which has versions 1.0
+// and 1.0.1; the from-to dates of the stats requests are
+// recorded in stats
+func usageServer(t *testing.T, stats *[]string) *httptest.Server {
+	t.Helper()
+	withPullConfig(t)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const prefix = "/registry/acct/reg/+/artifact/api/+"
+		q := r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		var body any
+		switch r.URL.Path {
+		case prefix + "/stats":
+			*stats = append(*stats, q.Get("from")+"-"+q.Get("to"))
+			count, size := int64(12), int64(2048)
+			if q.Get("from") == "" {
+				count = 3400000
+			}
+			body = ar.ArtifactStatsResponse{Data: ar.ArtifactStats{DownloadCount: &count, DownloadSize: &size}}
+		case prefix + "/versions":
+			downloads := int64(40)
+			versions := []ar.ArtifactVersionMetadata{{Name: "1.0", DownloadsCount: &downloads}, {Name: "1.0.1"}}
+			for i := range versions {
+				versions[i].DeploymentMetadata = &ar.DeploymentMetadata{ProdEnvCount: 1, NonProdEnvCount: 2}
+			}
+			if search := q.Get("search_term"); search != "" {
+				// the search matches every version containing the term
+				assert.Equal(t, "1.0", search)
+			}
+			body = ar.ListArtifactVersionResponse{Data: ar.ListArtifactVersion{ArtifactVersions: &versions}}
+		case prefix + "/version/1.0/summary":
+			body = ar.ArtifactVersionSummaryResponse{Data: ar.ArtifactVersionSummary{ImageName: "api",
+				Version: "1.0", PackageType: ar.DOCKER}}
+		case prefix + "/version/1.0/deploymentdetails":
+			assert.Equal(t, "Production", q.Get("env_type"))
+			env, envType, service, pipeline, at := "prod-us", ar.EnvironmentTypeProduction, "payments",
+				"deploy-payments", "1767225600000"
+			count := 3
+			body = ar.ArtifactDeploymentsResponse{Data: ar.ArtifactDeploymentsDetails{
+				Deployments: ar.ArtifactDeploymentsList{Deployments: &[]ar.ArtifactDeploymentsDetail{{
+					EnvName: &env, EnvType: &envType, ServiceName: &service, LastPipelineExecutionName: &pipeline,
+					LastDeployedAt: &at, Count: &count,
+				}}},
+				DeploymentsStats: &ar.DeploymentStats{Production: 1},
+			}}
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestUsageListsVersionDownloads(t *testing.T) {
+	var stats []string
+	ts := usageServer(t, &stats)
+	client, err := ar.NewClientWithResponses(ts.URL)
+	require.NoError(t, err)
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	result, err := artifactUsageOf(t.Context(), client, "reg", "api", "", nil, []int{7, 30}, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"03/03/2026-03/10/2026", "02/08/2026-03/10/2026", "-"}, stats)
+	require.Len(t, result.Downloads, 3)
+	assert.Equal(t, downloadPeriod{Period: "last 7 days", Days: 7, Downloads: 12, Size: 2048}, result.Downloads[0])
+	assert.Equal(t, "all time", result.Downloads[2].Period)
+	assert.Equal(t, int64(3400000), result.Downloads[2].Downloads)
+	assert.Equal(t, []versionUsage{
+		{Version: "1.0", Downloads: 40, ProdEnvs: 1, NonProdEnvs: 2},
+		{Version: "1.0.1", ProdEnvs: 1, NonProdEnvs: 2},
+	}, result.Versions)
+	assert.Empty(t, result.Deployments)
+}
+
+func TestUsageListsVersionDeployments(t *testing.T) {
+	var stats []string
+	ts := usageServer(t, &stats)
+	client, err := ar.NewClientWithResponses(ts.URL)
+	require.NoError(t, err)
+	env := ar.Production
+
+	result, err := artifactUsageOf(t.Context(), client, "reg", "api", "1.0", &env, nil, time.Now())
+	require.NoError(t, err)
+
+	assert.Equal(t, "DOCKER", result.PackageType)
+	// the artifact-wide download periods are not reported for a version
+	assert.Empty(t, stats)
+	assert.Empty(t, result.Downloads)
+	assert.Equal(t, []versionUsage{{Version: "1.0", Downloads: 40, ProdEnvs: 1, NonProdEnvs: 2}}, result.Versions)
+	require.NotNil(t, result.ProdEnvs)
+	assert.Equal(t, 1, *result.ProdEnvs)
+	assert.Equal(t, []deploymentUsage{{
+		Environment:    "prod-us",
+		EnvType:        "Production",
+		Service:        "payments",
+		Pipeline:       "deploy-payments",
+		LastDeployedAt: "2026-01-01T00:00:00Z",
+		Count:          3,
+	}}, result.Deployments)
+}
+
+func TestUsageValidatesFlags(t *testing.T) {
+	withPullConfig(t)
+	ts := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(ts.Close)
+	f := newPullTestFactory(t, ts)
+
+	err := runCobraCmd(t, NewUsageArtifactCmd(f), "reg/api", "--env-type", "production")
+	require.ErrorContains(t, err, "--env-type requires --version")
+	err = runCobraCmd(t, NewUsageArtifactCmd(f), "reg/api", "--version", "1.0", "--env-type", "qa")
+	require.ErrorContains(t, err, `invalid env type "qa"`)
+	err = runCobraCmd(t, NewUsageArtifactCmd(f), "reg/api", "--version", "1.0", "--days", "7")
+	require.ErrorContains(t, err, "cannot be used with --version")
+	err = runCobraCmd(t, NewUsageArtifactCmd(f), "reg", "--days", "7")
+	require.ErrorContains(t, err, "expected <registry>/<artifact>")
+}
@@ -20,13 +20,22 @@ func GetRootCmd(f *cmdutils.Factory) *cobra.Command {
 	}
 	// Add subcommands
 	rootCmd.AddCommand(command.NewListArtifactCmd(f))
+	rootCmd.AddCommand(command.NewSearchArtifactCmd(f))
 	rootCmd.AddCommand(command.NewGetArtifactCmd(f))
 	rootCmd.AddCommand(command.NewCreateArtifactCmd(f))
 	rootCmd.AddCommand(command.NewDeleteArtifactCmd(f))
 	rootCmd.AddCommand(command.NewPullArtifactCmd(f))
 	rootCmd.AddCommand(command.NewPushArtifactCmd(f))
+	rootCmd.AddCommand(command.NewSyncArtifactCmd(f))
+	rootCmd.AddCommand(command.NewPublishArtifactCmd(f))
 	rootCmd.AddCommand(command.NewMetadataCmd(f))
+	rootCmd.AddCommand(command.NewLabelCmd(f))
+	rootCmd.AddCommand(command.NewInspectArtifactCmd(f))
+	rootCmd.AddCommand(command.NewUsageArtifactCmd(f))
+	rootCmd.AddCommand(command.NewDiffArtifactCmd(f))
 	rootCmd.AddCommand(command.NewCopyArtifactCmd(f))
+	rootCmd.AddCommand(command.NewPromoteArtifactCmd(f))
+	rootCmd.AddCommand(command.NewCleanupArtifactCmd(f))
 	rootCmd.AddCommand(npm.GetRootCmd(f))
 	rootCmd.AddCommand(mvn.GetRootCmd(f))
 	rootCmd.AddCommand(pip.GetRootCmd(f))
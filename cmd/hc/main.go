@@ -109,7 +109,7 @@ func main() {
 	rootCmd.PersistentFlags().StringVar(&config.Global.AccountID, "account", "", "Account (overrides saved config)")
 	rootCmd.PersistentFlags().StringVar(&config.Global.OrgID, "org", "", "Org (overrides saved config)")
 	rootCmd.PersistentFlags().StringVar(&config.Global.ProjectID, "project", "", "Project (overrides saved config)")
-	rootCmd.PersistentFlags().StringVar(&config.Global.Format, "format", "table", "Format of the result")
+	rootCmd.PersistentFlags().StringVar(&config.Global.Format, "format", "table", "Format of the result: table or json (list --all also accepts ndjson)")
 	rootCmd.PersistentFlags().IntVar(&config.Global.TimeoutSeconds, "timeout", config.DefaultTimeoutSeconds,
 		"Request timeout in seconds (default: no timeout)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose logging to console")
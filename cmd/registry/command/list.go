@@ -2,16 +2,27 @@ package command
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/harness/harness-cli/cmd/cmdutils"
 	"github.com/harness/harness-cli/config"
 	ar "github.com/harness/harness-cli/internal/api/ar"
 	client2 "github.com/harness/harness-cli/util/client"
+	"github.com/harness/harness-cli/util/common/pagination"
 	"github.com/harness/harness-cli/util/common/printer"
 
 	"github.com/spf13/cobra"
 )
 
+var registryListColumns = [][]string{
+	{"identifier", "Registry"},
+	{"packageType", "Package Type"},
+	{"registrySize", "Size"},
+	{"type", "Registry Type"},
+	{"description", "Description"},
+	{"url", "Link"},
+}
+
 // NewListRegistryCmd wires up:
 //
 //	hc registry list
@@ -19,11 +30,35 @@ func NewListRegistryCmd(f *cmdutils.Factory) *cobra.Command {
 	var packageType string
 	var pageSize int32
 	var pageIndex int32
+	var all bool
+	var limit, concurrency int
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all registries",
-		Long:  "Lists all Harness Artifact Registries",
+		Long: `Lists all Harness Artifact Registries
+
+With --all every page is fetched, a few pages at a time, and printed as a
+single table or JSON document; with --format ndjson each registry is printed
+as a JSON line as soon as its page is fetched. --limit caps the number of
+registries and implies --all.`,
+		Example: `  hc registry list --package-type DOCKER
+  hc registry list --all --format ndjson | jq -r .identifier`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if limit > 0 {
+				all = true
+			}
+			if all {
+				if cmd.Flags().Changed("page") {
+					return fmt.Errorf("--page cannot be used with --all or --limit")
+				}
+				size := int64(pagination.DefaultPageSize)
+				if cmd.Flags().Changed("page-size") {
+					size = int64(pageSize)
+				}
+				return pagination.Print(cmd.Context(), registryPages(f, packageType, size),
+					pagination.Options{Concurrency: concurrency, Limit: limit}, registryListColumns)
+			}
+
 			// Create params for pagination if needed
 			params := &ar.GetAllRegistriesParams{}
 			if pageSize > 0 {
@@ -46,14 +81,7 @@ func NewListRegistryCmd(f *cmdutils.Factory) *cobra.Command {
 			}
 
 			err = printer.Print(response.JSON200.Data.Registries, *response.JSON200.Data.PageIndex,
-				*response.JSON200.Data.PageCount, *response.JSON200.Data.ItemCount, true, [][]string{
-					{"identifier", "Registry"},
-					{"packageType", "Package Type"},
-					{"registrySize", "Size"},
-					{"type", "Registry Type"},
-					{"description", "Description"},
-					{"url", "Link"},
-				})
+				*response.JSON200.Data.PageCount, *response.JSON200.Data.ItemCount, true, registryListColumns)
 
 			return err
 		},
@@ -62,6 +90,36 @@ func NewListRegistryCmd(f *cmdutils.Factory) *cobra.Command {
 	cmd.Flags().Int32Var(&pageSize, "page-size", 10, "number of items per page")
 	cmd.Flags().Int32Var(&pageIndex, "page", 0, "page number (zero-indexed)")
 	cmd.Flags().StringVar(&packageType, "package-type", "", "package type")
+	cmd.Flags().BoolVar(&all, "all", false, "fetch every page")
+	cmd.Flags().IntVar(&limit, "limit", 0, "maximum number of registries to list (implies --all)")
+	cmd.Flags().IntVar(&concurrency, "concurrency", pagination.DefaultConcurrency,
+		"maximum number of pages fetched at once with --all")
 
 	return cmd
 }
+
+// registryPages fetches the pages of the registries, optionally of one
+// package type
+func registryPages(f *cmdutils.Factory, packageType string, size int64) pagination.Fetcher[ar.RegistryMetadata] {
+	return func(ctx context.Context, page int64) (pagination.Page[ar.RegistryMetadata], error) {
+		params := &ar.GetAllRegistriesParams{Page: &page, Size: &size}
+		if len(packageType) > 0 {
+			params.PackageType = &[]string{packageType}
+		}
+		response, err := f.RegistryHttpClient().GetAllRegistriesWithResponse(ctx,
+			client2.GetRef(config.Global.AccountID, config.Global.OrgID, config.Global.ProjectID), params)
+		if err != nil {
+			return pagination.Page[ar.RegistryMetadata]{}, err
+		}
+		if response.JSON200 == nil {
+			return pagination.Page[ar.RegistryMetadata]{}, fmt.Errorf("unable to list registries: %s %s",
+				response.Status(), string(response.Body))
+		}
+		data := response.JSON200.Data
+		var pageCount int64
+		if data.PageCount != nil {
+			pageCount = *data.PageCount
+		}
+		return pagination.Page[ar.RegistryMetadata]{Items: data.Registries, PageCount: pageCount}, nil
+	}
+}
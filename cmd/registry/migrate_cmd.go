@@ -43,7 +43,7 @@ Example configuration file (config.yaml):
 
   source:
     endpoint: https://source-registry.example.com
-    type: JFROG                    # Supported: JFROG, NEXUS, HARBOR
+    type: JFROG                    # Supported: JFROG, NEXUS, HARBOR, GITLAB
     credentials:
       username: source_user
       password: source_password
@@ -76,7 +76,21 @@ Example configuration file (config.yaml):
 Supported artifact types:
   DOCKER, HELM, HELM_LEGACY, HELM_HTTP, MAVEN, NPM, NUGET, PYTHON, GO, GENERIC, CONDA, COMPOSER, SWIFT, DEBIAN, PUPPET, DART, RPM, RAW, CONAN
 
-Note: HARBOR source supports OCI artifact types only (DOCKER, HELM).
+Note: HARBOR source supports DOCKER and HELM from the OCI registry, and HELM_HTTP
+and HELM_LEGACY from the project's ChartMuseum repository. Harbor labels and the
+vulnerability scan summary are copied to HAR as version metadata.
+Note: a NEXUS sourceRegistry may be a group repository; it is expanded into its
+hosted members (nested groups included, proxies skipped) and content present in
+several members is migrated once. Docker repositories may use a connector port,
+a subdomain connector, or path-based routing.
+Note: GITLAB source supports MAVEN, NPM, PYTHON, NUGET, CONAN and GENERIC. The
+sourceRegistry is a GitLab project or group (full path or numeric ID), and the
+password is a personal/group access token with read_api scope.
+Note: MOCK_JFROG, MOCK_NEXUS and MOCK_HARBOR source types answer from embedded
+fixtures instead of the endpoint, to rehearse a config offline. MOCK_NEXUS
+repositories: maven-releases, maven-public (group), npm-hosted, pypi-hosted,
+nuget-hosted, helm-hosted, raw-hosted, docker-hosted. MOCK_HARBOR projects:
+library (DOCKER) and charts (HELM, HELM_HTTP, HELM_LEGACY).
 
 Environment variables can be used in the config file using ${VAR_NAME} syntax.
 
@@ -8,6 +8,7 @@ import (
 	"net/http"
 
 	"github.com/harness/harness-cli/module/ar/migrate/types"
+	"github.com/harness/harness-cli/module/ar/migrate/types/npm"
 
 	"github.com/google/go-containerregistry/pkg/authn"
 )
@@ -73,6 +74,27 @@ type Adapter interface {
 		files []*types.PackageFiles,
 		metadata map[string]interface{},
 	) error
+	// GetVersionMetadata returns source-side metadata (labels, scan summaries,
+	// ...) to carry over for a package, keyed by version (OCI tag or chart
+	// version). Sources with nothing to surface return (nil, nil).
+	GetVersionMetadata(
+		registry string,
+		p types.Package,
+		artifactType types.ArtifactType,
+	) (map[string]map[string]string, error)
+	// SetVersionMetadata attaches key/value metadata to a migrated version at
+	// the destination.
+	SetVersionMetadata(
+		ctx context.Context,
+		registry, pkg, version string,
+		metadata map[string]string,
+	) error
+	// GetNPMPackument returns the packument (registry document listing every
+	// version, dist-tag and deprecation) of an npm package.
+	GetNPMPackument(registry string, name string) (*npm.PackageMetadata, error)
+	// UpdateNPMPackument writes a modified packument back to the registry, the
+	// way `npm deprecate` does. Versions are never added or removed this way.
+	UpdateNPMPackument(registry string, name string, packument *npm.PackageMetadata) error
 }
 
 var registry = map[types.RegistryType]Factory{}
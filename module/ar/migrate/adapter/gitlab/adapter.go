@@ -0,0 +1,537 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+
+	adp "github.com/harness/harness-cli/module/ar/migrate/adapter"
+	"github.com/harness/harness-cli/module/ar/migrate/tree"
+	"github.com/harness/harness-cli/module/ar/migrate/types"
+	"github.com/harness/harness-cli/module/ar/migrate/types/npm"
+	"github.com/harness/harness-cli/module/ar/migrate/util"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// conanRevision is the fixed recipe/package revision GitLab serves over its
+	// Conan v1 API (it does not track revisions)
+	conanRevision = "0"
+	// packageStatusDefault is the status of a package that is fully processed
+	// and downloadable
+	packageStatusDefault = "default"
+)
+
+// gitlabPackageTypes maps GitLab package_type values to HAR artifact types.
+// Only these types are enumerated; everything else in the project is ignored.
+var gitlabPackageTypes = map[string]types.ArtifactType{
+	"maven":   types.MAVEN,
+	"npm":     types.NPM,
+	"pypi":    types.PYTHON,
+	"nuget":   types.NUGET,
+	"conan":   types.CONAN,
+	"generic": types.GENERIC,
+}
+
+func init() {
+	adapterType := types.GITLAB
+	if err := adp.RegisterFactory(adapterType, new(factory)); err != nil {
+		return
+	}
+}
+
+type factory struct{}
+
+func (f factory) Create(_ context.Context, config types.RegistryConfig) (adp.Adapter, error) {
+	return newAdapter(config)
+}
+
+type adapter struct {
+	client *client
+	reg    types.RegistryConfig
+
+	// listingsMu guards listings, the per-sourceRegistry snapshot built by
+	// GetFiles and reused by GetPackages, SearchFiles and DownloadFile so the
+	// Packages API is only walked once per registry.
+	listingsMu sync.Mutex
+	listings   map[string]*listing
+}
+
+// listing is everything enumerated from one project or group
+type listing struct {
+	packages []listedPackage
+	files    []types.File
+	created  map[string]string // file Uri -> created_at
+	download map[string]string // file Uri -> download URL
+}
+
+// listedPackage is a GitLab package together with the subtree its files were
+// placed under in the synthesized file tree
+type listedPackage struct {
+	pkg          GitlabPackage
+	artifactType types.ArtifactType
+	path         string
+}
+
+func newAdapter(config types.RegistryConfig) (adp.Adapter, error) {
+	return &adapter{
+		client:   newClient(&config),
+		reg:      config,
+		listings: make(map[string]*listing),
+	}, nil
+}
+
+// assertSupported returns a descriptive error for artifact types GitLab packages cannot map to
+func assertSupported(artifactType types.ArtifactType) error {
+	for _, t := range gitlabPackageTypes {
+		if t == artifactType {
+			return nil
+		}
+	}
+	return fmt.Errorf("GITLAB source supports only MAVEN, NPM, PYTHON, NUGET, CONAN and GENERIC; got %s",
+		artifactType)
+}
+
+func (a *adapter) GetKeyChain(sourcePackageHostname string) (authn.Keychain, error) {
+	var host string
+	if sourcePackageHostname != "" {
+		host = sourcePackageHostname
+	} else {
+		parsed, err := url.Parse(a.reg.Endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse [%s], err: %w", a.reg.Endpoint, err)
+		}
+		host = parsed.Host
+	}
+	return NewGitlabKeychain(a.reg.Credentials.Username, a.reg.Credentials.Password, host), nil
+}
+
+func (a *adapter) GetConfig() types.RegistryConfig {
+	return a.reg
+}
+
+func (a *adapter) ValidateCredentials() (bool, error) {
+	if err := a.client.currentUser(); err != nil {
+		return false, fmt.Errorf("failed to validate credentials: %w", err)
+	}
+	return true, nil
+}
+
+func (a *adapter) GetRegistry(_ context.Context, registry string) (types.RegistryInfo, error) {
+	scope, err := a.client.resolveScope(registry)
+	if err != nil {
+		return types.RegistryInfo{}, fmt.Errorf("failed to resolve %s: %w", registry, err)
+	}
+	return types.RegistryInfo{
+		Type: "gitlab",
+		URL:  scope.WebURL,
+		Path: scope.FullPath,
+	}, nil
+}
+
+// CreateRegistryIfDoesntExist is a no-op for GitLab source adapter
+func (a *adapter) CreateRegistryIfDoesntExist(_ string) (bool, error) {
+	return false, nil
+}
+
+// GetFiles enumerates every supported package file in the project or group.
+// Files are laid out as /{name}/{version}/{file} (Conan as
+// /{name}/{version}/{user}/{channel}/{rrev}/export|package/...), which is the
+// layout the HAR upload paths expect for each artifact type.
+func (a *adapter) GetFiles(registry string) ([]types.File, error) {
+	l, err := a.getListing(registry)
+	if err != nil {
+		return nil, err
+	}
+	return l.files, nil
+}
+
+// SearchFiles returns the created date of every file so date filters can be applied.
+// GitLab does not expose download timestamps, so Stats is always empty.
+func (a *adapter) SearchFiles(registry string) ([]types.SearchedFile, error) {
+	l, err := a.getListing(registry)
+	if err != nil {
+		return nil, err
+	}
+	searched := make([]types.SearchedFile, 0, len(l.files))
+	for _, f := range l.files {
+		searched = append(searched, types.SearchedFile{
+			Repo:    registry,
+			Path:    path.Dir(f.Uri),
+			Name:    f.Name,
+			Created: l.created[f.Uri],
+		})
+	}
+	return searched, nil
+}
+
+func (a *adapter) GetPackages(registry string, artifactType types.ArtifactType, root *types.TreeNode) (
+	[]types.Package,
+	error,
+) {
+	if err := assertSupported(artifactType); err != nil {
+		return nil, err
+	}
+	l, err := a.getListing(registry)
+	if err != nil {
+		return nil, err
+	}
+
+	var packages []types.Package
+	var conanFiles []*types.File
+	seen := make(map[string]bool)
+	for _, lp := range l.packages {
+		if lp.artifactType != artifactType || seen[lp.path] {
+			continue
+		}
+		seen[lp.path] = true
+		// Packages whose files were all dropped by date/pattern filters are
+		// absent from the tree and must not be handed to the package job.
+		node, err := tree.GetNodeForPath(root, lp.path)
+		if err != nil {
+			continue
+		}
+		if artifactType == types.CONAN {
+			files, err := tree.GetAllFiles(node)
+			if err != nil {
+				return nil, fmt.Errorf("get all files: %w", err)
+			}
+			conanFiles = append(conanFiles, files...)
+			continue
+		}
+
+		name := lp.pkg.Name
+		switch artifactType {
+		case types.GENERIC:
+			// Generic files keep their source path under the single
+			// default/default artifact, as for other sources.
+			name = "default"
+		case types.NUGET:
+			name = strings.ToLower(name)
+		}
+		packages = append(packages, types.Package{
+			Registry: registry,
+			Path:     lp.path,
+			Name:     name,
+			Size:     -1,
+		})
+	}
+	if artifactType == types.CONAN {
+		packages = util.GetConanPackages(conanFiles, registry)
+	}
+	log.Info().Msgf("Found %d %s packages in %s", len(packages), artifactType, registry)
+	return packages, nil
+}
+
+func (a *adapter) GetVersions(
+	p types.Package,
+	node *types.TreeNode,
+	registry, pkg string,
+	artifactType types.ArtifactType,
+) ([]types.Version, error) {
+	if err := assertSupported(artifactType); err != nil {
+		return nil, err
+	}
+	if artifactType == types.GENERIC {
+		return []types.Version{
+			{
+				Registry: registry,
+				Pkg:      pkg,
+				Path:     "/",
+				Name:     "default",
+				Size:     -1,
+			},
+		}, nil
+	}
+	if node == nil {
+		return nil, fmt.Errorf("node is nil")
+	}
+
+	// Every version directory left in the (filtered) tree is a version; leaf
+	// files directly under the package (e.g. maven-metadata.xml) are skipped.
+	var versions []types.Version
+	for _, child := range node.Children {
+		if child.IsLeaf {
+			continue
+		}
+		versions = append(versions, types.Version{
+			Registry: registry,
+			Pkg:      pkg,
+			Path:     child.Name,
+			Name:     child.Name,
+			Size:     -1,
+		})
+	}
+	return versions, nil
+}
+
+func (a *adapter) DownloadFile(registry string, uri string) (io.ReadCloser, http.Header, error) {
+	l, err := a.getListing(registry)
+	if err != nil {
+		return nil, nil, err
+	}
+	downloadURL, ok := l.download[uri]
+	if !ok {
+		return nil, nil, fmt.Errorf("file %s not found in %s", uri, registry)
+	}
+	return a.client.getFile(downloadURL)
+}
+
+func (a *adapter) UploadFile(
+	_ string,
+	_ io.ReadCloser,
+	_ *types.File,
+	_ http.Header,
+	_, _ string,
+	_ types.ArtifactType,
+	_ map[string]interface{},
+) error {
+	return fmt.Errorf("UploadFile not implemented for GITLAB")
+}
+
+func (a *adapter) GetOCIImagePath(_ string, _ string, _ string) (string, error) {
+	return "", fmt.Errorf("GetOCIImagePath not implemented for GITLAB")
+}
+
+func (a *adapter) AddNPMTag(_ string, _ string, _ string, _ string) error {
+	return nil
+}
+
+func (a *adapter) VersionExists(
+	_ context.Context,
+	_ types.Package,
+	_, _, _ string,
+	_ types.ArtifactType,
+) (bool, error) {
+	return false, fmt.Errorf("VersionExists not implemented for GITLAB")
+}
+
+func (a *adapter) FileExists(
+	_ context.Context,
+	_, _, _ string,
+	_ *types.File,
+	_ types.ArtifactType,
+) (bool, error) {
+	return false, fmt.Errorf("FileExists not implemented for GITLAB")
+}
+
+func (a *adapter) CreateVersion(
+	_ string,
+	_ string,
+	_ string,
+	_ types.ArtifactType,
+	_ []*types.PackageFiles,
+	_ map[string]interface{},
+) error {
+	return fmt.Errorf("CreateVersion not implemented for GITLAB")
+}
+
+func (a *adapter) BuildExistingIndex(
+	_ context.Context,
+	_ string,
+	_ int,
+) (*types.ExistingIndex, error) {
+	return nil, nil
+}
+
+func (a *adapter) GetNPMPackument(_ string, _ string) (*npm.PackageMetadata, error) {
+	return nil, fmt.Errorf("GetNPMPackument not implemented for GITLAB")
+}
+
+func (a *adapter) UpdateNPMPackument(_ string, _ string, _ *npm.PackageMetadata) error {
+	return fmt.Errorf("UpdateNPMPackument not implemented for GITLAB")
+}
+
+func (a *adapter) GetVersionMetadata(
+	_ string,
+	_ types.Package,
+	_ types.ArtifactType,
+) (map[string]map[string]string, error) {
+	return nil, nil
+}
+
+func (a *adapter) SetVersionMetadata(_ context.Context, _, _, _ string, _ map[string]string) error {
+	return fmt.Errorf("SetVersionMetadata not implemented for GITLAB")
+}
+
+// getListing returns the cached listing for registry, walking the Packages API on first use
+func (a *adapter) getListing(registry string) (*listing, error) {
+	a.listingsMu.Lock()
+	defer a.listingsMu.Unlock()
+	if l, ok := a.listings[registry]; ok {
+		return l, nil
+	}
+
+	scope, err := a.client.resolveScope(registry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", registry, err)
+	}
+	pkgs, err := a.client.listPackages(scope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list packages in %s: %w", registry, err)
+	}
+
+	l := &listing{
+		created:  make(map[string]string),
+		download: make(map[string]string),
+	}
+	index := make(map[string]int) // file Uri -> position in l.files
+	add := func(f types.File, created, downloadURL string) {
+		// A GitLab package may hold several uploads of the same file name;
+		// the one listed last is the most recent and wins.
+		if i, ok := index[f.Uri]; ok {
+			l.files[i] = f
+		} else {
+			index[f.Uri] = len(l.files)
+			l.files = append(l.files, f)
+		}
+		l.created[f.Uri] = created
+		l.download[f.Uri] = downloadURL
+	}
+
+	for _, pkg := range pkgs {
+		artifactType, ok := gitlabPackageTypes[pkg.PackageType]
+		if !ok {
+			log.Debug().Msgf("Skipping GitLab package %s (%s): unsupported package type", pkg.Name, pkg.PackageType)
+			continue
+		}
+		if pkg.Status != "" && pkg.Status != packageStatusDefault {
+			log.Warn().Msgf("Skipping GitLab package %s@%s with status %s", pkg.Name, pkg.Version, pkg.Status)
+			continue
+		}
+
+		if artifactType == types.CONAN {
+			lp, err := a.listConanFiles(registry, pkg, add)
+			if err != nil {
+				return nil, err
+			}
+			l.packages = append(l.packages, lp)
+			continue
+		}
+
+		files, err := a.client.listPackageFiles(pkg.ProjectID, pkg.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list files of package %s@%s: %w", pkg.Name, pkg.Version, err)
+		}
+		for _, file := range files {
+			downloadURL, err := a.client.downloadURL(pkg, file)
+			if err != nil {
+				log.Warn().Err(err).Msgf("Skipping file %s of package %s@%s", file.FileName, pkg.Name, pkg.Version)
+				continue
+			}
+			add(types.File{
+				Name:         file.FileName,
+				Registry:     registry,
+				Uri:          packageFileURI(pkg, file.FileName),
+				Size:         file.Size,
+				LastModified: file.CreatedAt,
+				SHA1:         file.FileSHA1,
+				SHA2:         file.FileSHA256,
+			}, file.CreatedAt, downloadURL)
+		}
+		l.packages = append(l.packages, listedPackage{
+			pkg:          pkg,
+			artifactType: artifactType,
+			path:         "/" + pkg.Name,
+		})
+	}
+
+	a.listings[registry] = l
+	return l, nil
+}
+
+// listConanFiles enumerates the recipe and binary package files of a Conan
+// package through the Conan v1 API, which (unlike the package files API)
+// tells the recipe and package layers apart.
+func (a *adapter) listConanFiles(
+	registry string,
+	pkg GitlabPackage,
+	add func(f types.File, created, downloadURL string),
+) (listedPackage, error) {
+	ref := parseConanRecipe(pkg)
+	lp := listedPackage{pkg: pkg, artifactType: types.CONAN, path: ref.basePath()}
+
+	recipeURLs, err := a.client.conanDownloadURLs(pkg.ProjectID, ref, "")
+	if err != nil {
+		return lp, fmt.Errorf("failed to list recipe files of %s: %w", ref.path(), err)
+	}
+	for name, downloadURL := range recipeURLs {
+		uri := fmt.Sprintf("%s/%s/export/%s", ref.basePath(), conanRevision, name)
+		add(types.File{Name: name, Registry: registry, Uri: uri, Size: -1}, pkg.CreatedAt, downloadURL)
+	}
+
+	pkgRefs, err := a.client.conanPackageReferences(pkg.ProjectID, ref)
+	if err != nil {
+		return lp, fmt.Errorf("failed to list package references of %s: %w", ref.path(), err)
+	}
+	for _, pkgRef := range pkgRefs {
+		urls, err := a.client.conanDownloadURLs(pkg.ProjectID, ref, pkgRef)
+		if err != nil {
+			return lp, fmt.Errorf("failed to list files of %s:%s: %w", ref.path(), pkgRef, err)
+		}
+		for name, downloadURL := range urls {
+			uri := fmt.Sprintf("%s/%s/package/%s/%s/%s", ref.basePath(), conanRevision, pkgRef, conanRevision, name)
+			add(types.File{Name: name, Registry: registry, Uri: uri, Size: -1}, pkg.CreatedAt, downloadURL)
+		}
+	}
+	return lp, nil
+}
+
+// packageFileURI is the synthesized tree path of a (non-Conan) package file
+func packageFileURI(pkg GitlabPackage, fileName string) string {
+	if pkg.Version == "" {
+		// Version-less entries, e.g. Maven's artifact-level maven-metadata.xml
+		return fmt.Sprintf("/%s/%s", pkg.Name, fileName)
+	}
+	return fmt.Sprintf("/%s/%s/%s", pkg.Name, pkg.Version, fileName)
+}
+
+// conanRecipe holds the coordinates of a Conan recipe (name/version@user/channel)
+type conanRecipe struct {
+	name    string
+	version string
+	user    string
+	channel string
+}
+
+// parseConanRecipe derives the recipe reference of a GitLab Conan package. The
+// API reports it either as the full "name/version@user/channel" recipe or as a
+// bare name, in which case user and channel are absent ("_").
+func parseConanRecipe(pkg GitlabPackage) conanRecipe {
+	ref := conanRecipe{
+		name:    pkg.Name,
+		version: pkg.Version,
+		user:    util.ConanPlaceholder,
+		channel: util.ConanPlaceholder,
+	}
+	nameVersion, userChannel, found := strings.Cut(pkg.Name, "@")
+	if !found {
+		return ref
+	}
+	if name, version, ok := strings.Cut(nameVersion, "/"); ok {
+		ref.name, ref.version = name, version
+	} else {
+		ref.name = nameVersion
+	}
+	if user, channel, ok := strings.Cut(userChannel, "/"); ok {
+		ref.user, ref.channel = user, channel
+	}
+	return ref
+}
+
+// path is the reference as used in Conan API routes: name/version/user/channel
+func (r conanRecipe) path() string {
+	return r.name + "/" + r.version + "/" + r.user + "/" + r.channel
+}
+
+// basePath is the reference subtree in the synthesized file tree
+func (r conanRecipe) basePath() string {
+	return "/" + r.path()
+}
@@ -0,0 +1,232 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/harness/harness-cli/module/ar/migrate/tree"
+	"github.com/harness/harness-cli/module/ar/migrate/types"
+)
+
+// gitlabServer spins up an httptest server that answers the subset of the
+// GitLab API the adapter uses for a single group ("acme") that owns project 7.
+// The project itself is not resolvable by path, forcing the group fallback.
+func gitlabServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	writeJSON := func(w http.ResponseWriter, v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer token" {
+			t.Errorf("Authorization = %q, want bearer token", got)
+		}
+		switch p := r.URL.EscapedPath(); p {
+		case "/api/v4/projects/acme":
+			w.WriteHeader(http.StatusNotFound)
+		case "/api/v4/groups/acme":
+			writeJSON(w, map[string]interface{}{"id": 3, "full_path": "acme", "web_url": "https://gl/acme"})
+		case "/api/v4/groups/3/packages":
+			if r.URL.Query().Get("page") == "1" {
+				w.Header().Set("X-Next-Page", "2")
+				writeJSON(w, []GitlabPackage{
+					{ID: 1, Name: "com/acme/app", Version: "1.0", PackageType: "maven", Status: "default", ProjectID: 7},
+					{ID: 2, Name: "@acme/ui", Version: "2.1.0", PackageType: "npm", Status: "default", ProjectID: 7},
+					{ID: 3, Name: "Acme.Core", Version: "1.2.3", PackageType: "nuget", Status: "default", ProjectID: 7},
+				})
+				return
+			}
+			writeJSON(w, []GitlabPackage{
+				{ID: 4, Name: "tools", Version: "0.1", PackageType: "generic", Status: "default", ProjectID: 7},
+				{ID: 5, Name: "Hello/0.1@acme+app/stable", Version: "0.1", PackageType: "conan", Status: "default",
+					ProjectID: 7},
+				{ID: 6, Name: "broken", Version: "9", PackageType: "pypi", Status: "error", ProjectID: 7},
+				{ID: 7, Name: "img", Version: "1", PackageType: "helm", Status: "default", ProjectID: 7},
+			})
+		case "/api/v4/projects/7/packages/1/package_files":
+			writeJSON(w, []GitlabPackageFile{
+				{ID: 10, FileName: "app-1.0.jar", Size: 5, CreatedAt: "2024-01-01T00:00:00Z"},
+				{ID: 11, FileName: "app-1.0.pom", Size: 3, CreatedAt: "2024-01-01T00:00:00Z"},
+			})
+		case "/api/v4/projects/7/packages/2/package_files":
+			writeJSON(w, []GitlabPackageFile{{ID: 20, FileName: "ui-2.1.0.tgz", Size: 7}})
+		case "/api/v4/projects/7/packages/3/package_files":
+			writeJSON(w, []GitlabPackageFile{{ID: 30, FileName: "acme.core.1.2.3.nupkg", Size: 9}})
+		case "/api/v4/projects/7/packages/4/package_files":
+			writeJSON(w, []GitlabPackageFile{
+				{ID: 40, FileName: "cli.bin", Size: 1},
+				{ID: 41, FileName: "cli.bin", Size: 2},
+			})
+		case "/api/v4/projects/7/packages/conan/v1/conans/Hello/0.1/acme+app/stable/download_urls":
+			writeJSON(w, map[string]string{"conanfile.py": "http://" + r.Host + "/conan/recipe/conanfile.py"})
+		case "/api/v4/projects/7/packages/conan/v1/conans/Hello/0.1/acme+app/stable/search":
+			writeJSON(w, map[string]interface{}{"abc123": map[string]interface{}{}})
+		case "/api/v4/projects/7/packages/conan/v1/conans/Hello/0.1/acme+app/stable/packages/abc123/download_urls":
+			writeJSON(w, map[string]string{"conan_package.tgz": "http://" + r.Host + "/conan/pkg/conan_package.tgz"})
+		case "/api/v4/projects/7/packages/maven/com/acme/app/1.0/app-1.0.jar":
+			_, _ = io.WriteString(w, "jar!!")
+		default:
+			t.Errorf("unexpected request path: %s", p)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestAdapter(t *testing.T, endpoint string) *adapter {
+	t.Helper()
+	a, err := newAdapter(types.RegistryConfig{
+		Type:        types.GITLAB,
+		Endpoint:    endpoint,
+		Credentials: types.CredentialsConfig{Password: "token"},
+	})
+	if err != nil {
+		t.Fatalf("newAdapter: %v", err)
+	}
+	return a.(*adapter)
+}
+
+// TestGetFilesLayout asserts the synthesized tree layout per package type, the
+// group fallback, pagination, and that unsupported or unfinished packages are skipped.
+func TestGetFilesLayout(t *testing.T) {
+	a := newTestAdapter(t, gitlabServer(t).URL)
+
+	files, err := a.GetFiles("acme")
+	if err != nil {
+		t.Fatalf("GetFiles: %v", err)
+	}
+	var uris []string
+	sizes := map[string]int{}
+	for _, f := range files {
+		uris = append(uris, f.Uri)
+		sizes[f.Uri] = f.Size
+	}
+	sort.Strings(uris)
+	want := []string{
+		"/@acme/ui/2.1.0/ui-2.1.0.tgz",
+		"/Acme.Core/1.2.3/acme.core.1.2.3.nupkg",
+		"/Hello/0.1/acme+app/stable/0/export/conanfile.py",
+		"/Hello/0.1/acme+app/stable/0/package/abc123/0/conan_package.tgz",
+		"/com/acme/app/1.0/app-1.0.jar",
+		"/com/acme/app/1.0/app-1.0.pom",
+		"/tools/0.1/cli.bin",
+	}
+	if strings.Join(uris, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("uris =\n%s\nwant\n%s", strings.Join(uris, "\n"), strings.Join(want, "\n"))
+	}
+	if sizes["/tools/0.1/cli.bin"] != 2 {
+		t.Errorf("duplicate generic upload: size = %d, want latest (2)", sizes["/tools/0.1/cli.bin"])
+	}
+}
+
+func TestGetPackagesAndVersions(t *testing.T) {
+	a := newTestAdapter(t, gitlabServer(t).URL)
+	files, err := a.GetFiles("acme")
+	if err != nil {
+		t.Fatalf("GetFiles: %v", err)
+	}
+	root := tree.TransformToTree(files)
+
+	cases := []struct {
+		artifactType types.ArtifactType
+		name, path   string
+		versions     []string
+	}{
+		{types.MAVEN, "com/acme/app", "/com/acme/app", []string{"1.0"}},
+		{types.NPM, "@acme/ui", "/@acme/ui", []string{"2.1.0"}},
+		{types.NUGET, "acme.core", "/Acme.Core", []string{"1.2.3"}},
+		{types.GENERIC, "default", "/tools", []string{"default"}},
+	}
+	for _, tc := range cases {
+		pkgs, err := a.GetPackages("acme", tc.artifactType, root)
+		if err != nil {
+			t.Fatalf("%s GetPackages: %v", tc.artifactType, err)
+		}
+		if len(pkgs) != 1 || pkgs[0].Name != tc.name || pkgs[0].Path != tc.path {
+			t.Fatalf("%s packages = %+v, want %s at %s", tc.artifactType, pkgs, tc.name, tc.path)
+		}
+		node, err := tree.GetNodeForPath(root, pkgs[0].Path)
+		if err != nil {
+			t.Fatalf("%s node: %v", tc.artifactType, err)
+		}
+		versions, err := a.GetVersions(pkgs[0], node, "acme", pkgs[0].Name, tc.artifactType)
+		if err != nil {
+			t.Fatalf("%s GetVersions: %v", tc.artifactType, err)
+		}
+		var got []string
+		for _, v := range versions {
+			got = append(got, v.Name)
+		}
+		if strings.Join(got, ",") != strings.Join(tc.versions, ",") {
+			t.Errorf("%s versions = %v, want %v", tc.artifactType, got, tc.versions)
+		}
+	}
+
+	conan, err := a.GetPackages("acme", types.CONAN, root)
+	if err != nil {
+		t.Fatalf("CONAN GetPackages: %v", err)
+	}
+	if len(conan) != 1 || conan[0].Name != "Hello/0.1@acme+app/stable" || conan[0].Path != "/Hello/0.1/acme+app/stable" {
+		t.Errorf("conan packages = %+v", conan)
+	}
+
+	if _, err := a.GetPackages("acme", types.DOCKER, root); err == nil {
+		t.Errorf("expected DOCKER to be rejected")
+	}
+}
+
+// TestGetPackagesSkipsFilteredOut asserts that packages whose files were all
+// removed from the tree (date/pattern filters) are not enumerated.
+func TestGetPackagesSkipsFilteredOut(t *testing.T) {
+	a := newTestAdapter(t, gitlabServer(t).URL)
+	if _, err := a.GetFiles("acme"); err != nil {
+		t.Fatalf("GetFiles: %v", err)
+	}
+	root := tree.TransformToTree([]types.File{{Name: "ui-2.1.0.tgz", Uri: "/@acme/ui/2.1.0/ui-2.1.0.tgz"}})
+
+	pkgs, err := a.GetPackages("acme", types.MAVEN, root)
+	if err != nil {
+		t.Fatalf("GetPackages: %v", err)
+	}
+	if len(pkgs) != 0 {
+		t.Errorf("expected no maven packages, got %+v", pkgs)
+	}
+}
+
+func TestDownloadFile(t *testing.T) {
+	a := newTestAdapter(t, gitlabServer(t).URL)
+	if _, err := a.GetFiles("acme"); err != nil {
+		t.Fatalf("GetFiles: %v", err)
+	}
+
+	body, _, err := a.DownloadFile("acme", "/com/acme/app/1.0/app-1.0.jar")
+	if err != nil {
+		t.Fatalf("DownloadFile: %v", err)
+	}
+	defer body.Close()
+	data, _ := io.ReadAll(body)
+	if string(data) != "jar!!" {
+		t.Errorf("body = %q", data)
+	}
+
+	if _, _, err := a.DownloadFile("acme", "/missing"); err == nil {
+		t.Errorf("expected error for unknown uri")
+	}
+}
+
+func TestParseConanRecipe(t *testing.T) {
+	ref := parseConanRecipe(GitlabPackage{Name: "Hello/0.1@user/stable", Version: "0.1"})
+	if ref.path() != "Hello/0.1/user/stable" {
+		t.Errorf("path = %q", ref.path())
+	}
+	bare := parseConanRecipe(GitlabPackage{Name: "Hello", Version: "0.2"})
+	if bare.path() != "Hello/0.2/_/_" {
+		t.Errorf("bare path = %q", bare.path())
+	}
+}
@@ -0,0 +1,274 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	httputil "github.com/harness/harness-cli/module/ar/migrate/http"
+	"github.com/harness/harness-cli/module/ar/migrate/http/auth/bearer"
+	"github.com/harness/harness-cli/module/ar/migrate/http/modifier/useragent"
+	"github.com/harness/harness-cli/module/ar/migrate/types"
+)
+
+const (
+	gitlabAPIVersion = "v4"
+	pageSize         = 100
+
+	scopeProjects = "projects"
+	scopeGroups   = "groups"
+)
+
+// GitlabScope is the project or group a sourceRegistry resolves to
+type GitlabScope struct {
+	// Kind is either "projects" or "groups", matching the API path segment
+	Kind     string
+	ID       int    `json:"id"`
+	FullPath string `json:"full_path"`
+	WebURL   string `json:"web_url"`
+}
+
+// GitlabPackage represents a package returned by the GitLab Packages API
+type GitlabPackage struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	PackageType string `json:"package_type"`
+	Status      string `json:"status"`
+	CreatedAt   string `json:"created_at"`
+	// ProjectID is only populated by the group-level listing
+	ProjectID   int    `json:"project_id"`
+	ProjectPath string `json:"project_path"`
+}
+
+// GitlabPackageFile represents a single file belonging to a GitLab package
+type GitlabPackageFile struct {
+	ID         int    `json:"id"`
+	PackageID  int    `json:"package_id"`
+	FileName   string `json:"file_name"`
+	Size       int    `json:"size"`
+	FileSHA1   string `json:"file_sha1"`
+	FileSHA256 string `json:"file_sha256"`
+	CreatedAt  string `json:"created_at"`
+}
+
+type client struct {
+	client *httputil.Client
+	url    string
+}
+
+func newClient(reg *types.RegistryConfig) *client {
+	url := strings.TrimSuffix(reg.Endpoint, "/")
+	return &client{
+		client: httputil.NewClient(
+			&http.Client{
+				Transport: httputil.GetHTTPTransport(httputil.WithInsecure(reg.Insecure)),
+			},
+			bearer.NewAuthorizer(reg.Credentials.Password),
+			useragent.NewModifier(),
+		),
+		url: url,
+	}
+}
+
+// apiURL joins the API base with the given path
+func (c *client) apiURL(path string) string {
+	return fmt.Sprintf("%s/api/%s/%s", c.url, gitlabAPIVersion, strings.TrimPrefix(path, "/"))
+}
+
+// get executes a GET request and returns the body and headers on a 200 response
+func (c *client) get(rawURL string) ([]byte, http.Header, error) {
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create request: %w", err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, resp.Header, types.ErrRegistryNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.Header, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(body))
+	}
+	return body, resp.Header, nil
+}
+
+// currentUser checks connectivity and that the token is accepted
+func (c *client) currentUser() error {
+	_, _, err := c.get(c.apiURL("user"))
+	return err
+}
+
+// resolveScope resolves a sourceRegistry (numeric ID or full path) to a project,
+// falling back to a group when no project with that path exists
+func (c *client) resolveScope(registry string) (GitlabScope, error) {
+	id := url.PathEscape(strings.Trim(registry, "/"))
+	for _, kind := range []string{scopeProjects, scopeGroups} {
+		body, _, err := c.get(c.apiURL(fmt.Sprintf("%s/%s", kind, id)))
+		if errors.Is(err, types.ErrRegistryNotFound) {
+			continue
+		}
+		if err != nil {
+			return GitlabScope{}, fmt.Errorf("get %s %q: %w", kind, registry, err)
+		}
+		var raw struct {
+			ID                int    `json:"id"`
+			FullPath          string `json:"full_path"`
+			PathWithNamespace string `json:"path_with_namespace"`
+			WebURL            string `json:"web_url"`
+		}
+		if err := json.Unmarshal(body, &raw); err != nil {
+			return GitlabScope{}, fmt.Errorf("decode response: %w", err)
+		}
+		scope := GitlabScope{Kind: kind, ID: raw.ID, FullPath: raw.FullPath, WebURL: raw.WebURL}
+		if scope.FullPath == "" {
+			scope.FullPath = raw.PathWithNamespace
+		}
+		return scope, nil
+	}
+	return GitlabScope{}, fmt.Errorf("no project or group %q found", registry)
+}
+
+// listPackages returns every package in the project or group, handling pagination
+func (c *client) listPackages(scope GitlabScope) ([]GitlabPackage, error) {
+	var all []GitlabPackage
+	next := "1"
+	for next != "" {
+		rawURL := c.apiURL(fmt.Sprintf("%s/%d/packages?per_page=%d&page=%s", scope.Kind, scope.ID, pageSize, next))
+		body, header, err := c.get(rawURL)
+		if err != nil {
+			return nil, err
+		}
+		var pkgs []GitlabPackage
+		if err := json.Unmarshal(body, &pkgs); err != nil {
+			return nil, fmt.Errorf("decode response: %w", err)
+		}
+		for i := range pkgs {
+			if pkgs[i].ProjectID == 0 && scope.Kind == scopeProjects {
+				pkgs[i].ProjectID = scope.ID
+			}
+		}
+		all = append(all, pkgs...)
+		next = header.Get("X-Next-Page")
+	}
+	return all, nil
+}
+
+// listPackageFiles returns every file of a package, handling pagination
+func (c *client) listPackageFiles(projectID, packageID int) ([]GitlabPackageFile, error) {
+	var all []GitlabPackageFile
+	next := "1"
+	for next != "" {
+		rawURL := c.apiURL(fmt.Sprintf("projects/%d/packages/%d/package_files?per_page=%d&page=%s",
+			projectID, packageID, pageSize, next))
+		body, header, err := c.get(rawURL)
+		if err != nil {
+			return nil, err
+		}
+		var files []GitlabPackageFile
+		if err := json.Unmarshal(body, &files); err != nil {
+			return nil, fmt.Errorf("decode response: %w", err)
+		}
+		all = append(all, files...)
+		next = header.Get("X-Next-Page")
+	}
+	return all, nil
+}
+
+// conanPackageReferences lists the binary package references (package IDs) of a
+// Conan recipe through the project-level Conan v1 API
+func (c *client) conanPackageReferences(projectID int, ref conanRecipe) ([]string, error) {
+	body, _, err := c.get(c.apiURL(fmt.Sprintf("projects/%d/packages/conan/v1/conans/%s/search",
+		projectID, ref.path())))
+	if err != nil {
+		return nil, err
+	}
+	var refs map[string]json.RawMessage
+	if err := json.Unmarshal(body, &refs); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	ids := make([]string, 0, len(refs))
+	for id := range refs {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// conanDownloadURLs returns the file name -> download URL map for the recipe
+// layer, or for the given package reference when pkgRef is non-empty
+func (c *client) conanDownloadURLs(projectID int, ref conanRecipe, pkgRef string) (map[string]string, error) {
+	path := fmt.Sprintf("projects/%d/packages/conan/v1/conans/%s", projectID, ref.path())
+	if pkgRef != "" {
+		path += "/packages/" + pkgRef
+	}
+	body, _, err := c.get(c.apiURL(path + "/download_urls"))
+	if err != nil {
+		return nil, err
+	}
+	urls := map[string]string{}
+	if err := json.Unmarshal(body, &urls); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return urls, nil
+}
+
+// downloadURL builds the package-manager download URL for a package file.
+// GitLab has no format-agnostic download endpoint, so each package type is
+// served from its own API.
+func (c *client) downloadURL(pkg GitlabPackage, file GitlabPackageFile) (string, error) {
+	project := strconv.Itoa(pkg.ProjectID)
+	switch pkg.PackageType {
+	case "generic":
+		return c.apiURL(fmt.Sprintf("projects/%s/packages/generic/%s/%s/%s", project,
+			url.PathEscape(pkg.Name), url.PathEscape(pkg.Version), url.PathEscape(file.FileName))), nil
+	case "maven":
+		return c.apiURL(fmt.Sprintf("projects/%s/packages/maven/%s/%s/%s", project,
+			pkg.Name, url.PathEscape(pkg.Version), url.PathEscape(file.FileName))), nil
+	case "npm":
+		return c.apiURL(fmt.Sprintf("projects/%s/packages/npm/%s/-/%s", project,
+			pkg.Name, url.PathEscape(file.FileName))), nil
+	case "pypi":
+		if file.FileSHA256 == "" {
+			return "", fmt.Errorf("pypi file %s has no sha256", file.FileName)
+		}
+		return c.apiURL(fmt.Sprintf("projects/%s/packages/pypi/files/%s/%s", project,
+			file.FileSHA256, url.PathEscape(file.FileName))), nil
+	case "nuget":
+		return c.apiURL(fmt.Sprintf("projects/%s/packages/nuget/download/%s/%s/%s", project,
+			url.PathEscape(strings.ToLower(pkg.Name)), url.PathEscape(pkg.Version),
+			url.PathEscape(file.FileName))), nil
+	default:
+		return "", fmt.Errorf("unsupported package type %q", pkg.PackageType)
+	}
+}
+
+// getFile downloads a file by its absolute URL
+func (c *client) getFile(rawURL string) (io.ReadCloser, http.Header, error) {
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create request: %w", err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("execute request: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(body))
+	}
+	return resp.Body, resp.Header, nil
+}
@@ -0,0 +1,47 @@
+package gitlab
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+)
+
+type gitlabKeychain struct {
+	username string
+	password string
+	hostname string
+}
+
+func NewGitlabKeychain(username, password, hostname string) authn.Keychain {
+	return gitlabKeychain{
+		username: username,
+		password: password,
+		hostname: hostname,
+	}
+}
+
+func (h gitlabKeychain) Resolve(r authn.Resource) (authn.Authenticator, error) {
+	serverURL, err := url.Parse("https://" + r.String())
+	if err != nil {
+		return authn.Anonymous, nil
+	}
+
+	if h.username == "" || h.password == "" {
+		return authn.Anonymous, nil
+	}
+
+	if strings.EqualFold(serverURL.Hostname(), h.hostname) {
+		return gitlabAuthenticator{h.username, h.password}, nil
+	}
+	return authn.Anonymous, nil
+}
+
+type gitlabAuthenticator struct{ username, password string }
+
+func (h gitlabAuthenticator) Authorization() (*authn.AuthConfig, error) {
+	return &authn.AuthConfig{
+		Username: h.username,
+		Password: h.password,
+	}, nil
+}
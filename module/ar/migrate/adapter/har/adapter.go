@@ -17,6 +17,7 @@ import (
 	pkgclient "github.com/harness/harness-cli/internal/api/ar_pkg"
 	adp "github.com/harness/harness-cli/module/ar/migrate/adapter"
 	"github.com/harness/harness-cli/module/ar/migrate/types"
+	"github.com/harness/harness-cli/module/ar/migrate/types/npm"
 	"github.com/harness/harness-cli/module/ar/migrate/util"
 	"github.com/harness/harness-cli/util/common/auth"
 
@@ -228,6 +229,34 @@ func (a *adapter) ociPrefixFromCache(registry string) string {
 	return strings.ToLower(config.Global.AccountID)
 }
 
+// GetVersionMetadata is a no-op for HAR, which is only used as a destination
+func (a *adapter) GetVersionMetadata(
+	_ string,
+	_ types.Package,
+	_ types.ArtifactType,
+) (map[string]map[string]string, error) {
+	return nil, nil
+}
+
+func (a *adapter) SetVersionMetadata(
+	ctx context.Context,
+	registry, pkg, version string,
+	metadata map[string]string,
+) error {
+	if len(metadata) == 0 {
+		return nil
+	}
+	return a.client.updateVersionMetadata(ctx, registry, pkg, version, metadata)
+}
+
+func (a *adapter) GetNPMPackument(registry string, name string) (*npm.PackageMetadata, error) {
+	return a.client.getNPMPackument(registry, name)
+}
+
+func (a *adapter) UpdateNPMPackument(registry string, name string, packument *npm.PackageMetadata) error {
+	return a.client.updateNPMPackument(registry, name, packument)
+}
+
 func (a *adapter) AddNPMTag(registry string, name string, version string, uri string) error {
 	return a.client.AddNPMTag(registry, name, version, uri)
 }
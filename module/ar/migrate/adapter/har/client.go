@@ -9,17 +9,21 @@ import (
 	"mime/multipart"
 	http2 "net/http"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/harness/harness-cli/config"
 	"github.com/harness/harness-cli/internal/api/ar"
 	pkgclient "github.com/harness/harness-cli/internal/api/ar_pkg"
+	"github.com/harness/harness-cli/internal/api/ar_v2"
 	"github.com/harness/harness-cli/internal/api/ar_v3"
 	"github.com/harness/harness-cli/module/ar/migrate/http"
 	"github.com/harness/harness-cli/module/ar/migrate/http/auth/xApiKey"
 	"github.com/harness/harness-cli/module/ar/migrate/http/modifier/useragent"
 	"github.com/harness/harness-cli/module/ar/migrate/types"
+	"github.com/harness/harness-cli/module/ar/migrate/types/npm"
+	"github.com/harness/harness-cli/module/ar/migrate/util"
 	"github.com/harness/harness-cli/util/common/auth"
 
 	"github.com/google/uuid"
@@ -90,6 +94,10 @@ func newClient(reg *types.RegistryConfig) *client {
 		ar.WithHTTPClient(retryingArHTTPClient()),
 		auth.GetXApiKeyOptionAR())
 
+	arV2Client, _ := ar_v2.NewClientWithResponses(config.Global.APIBaseURL+"/gateway/har/api/v2",
+		ar_v2.WithHTTPClient(retryingArHTTPClient()),
+		auth.GetXApiKeyOptionARV2())
+
 	arV3Client, _ := ar_v3.NewClientWithResponses(config.Global.APIBaseURL+"/gateway/har/api/v3",
 		ar_v3.WithHTTPClient(retryingArHTTPClient()),
 		auth.GetXApiKeyOptionARV3())
@@ -113,12 +121,14 @@ func newClient(reg *types.RegistryConfig) *client {
 		username:         username,
 		password:         token,
 		apiClient:        arClient,
+		arV2Client:       arV2Client,
 		arV3Client:       arV3Client,
 	}
 }
 
 type client struct {
 	apiClient        *ar.ClientWithResponses
+	arV2Client       *ar_v2.ClientWithResponses
 	arV3Client       *ar_v3.ClientWithResponses
 	client           *http.Client
 	rawPkgHTTPClient *http2.Client
@@ -787,6 +797,135 @@ func (c *client) AddNPMTag(registry string, name string, version string, tagUri
 	return nil
 }
 
+// npmPackumentFields are the package-level packument fields the migration
+// copies from the source; everything else is left as the destination has it
+var npmPackumentFields = []string{
+	"readme", "readmeFilename", "description", "homepage", "repository", "author", "bugs", "license",
+	"contributors", "keywords", "maintainers",
+}
+
+// getNPMPackument fetches the packument of an npm package
+func (c *client) getNPMPackument(registry string, name string) (*npm.PackageMetadata, error) {
+	body, err := c.getNPMPackumentJSON(registry, name)
+	if err != nil {
+		return nil, err
+	}
+	var packument npm.PackageMetadata
+	if err := json.Unmarshal(body, &packument); err != nil {
+		return nil, fmt.Errorf("failed to decode packument of '%s': %w", name, err)
+	}
+	return &packument, nil
+}
+
+func (c *client) getNPMPackumentJSON(registry string, name string) ([]byte, error) {
+	url := fmt.Sprintf("%s/pkg/%s/%s/npm/%s", c.url, config.Global.AccountID, registry,
+		util.EscapeNPMPackageName(name))
+
+	req, err := http2.NewRequest(http2.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get packument '%s': %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read packument '%s': %w", url, err)
+	}
+	if resp.StatusCode != http2.StatusOK {
+		return nil, fmt.Errorf("failed to get packument '%s', status code: %d, response: %s",
+			url, resp.StatusCode, string(body))
+	}
+	return body, nil
+}
+
+// updateNPMPackument writes a packument back the way `npm deprecate` does: a
+// PUT of the full document to the package URL. The document is re-read and
+// only the package-level fields and version deprecations the migration
+// copies are replaced, so fields npm.PackageMetadata does not model survive.
+func (c *client) updateNPMPackument(registry string, name string, packument *npm.PackageMetadata) error {
+	current, err := c.getNPMPackumentJSON(registry, name)
+	if err != nil {
+		return err
+	}
+	packumentJSON, err := mergeNPMPackumentJSON(current, packument)
+	if err != nil {
+		return fmt.Errorf("failed to update packument of '%s': %w", name, err)
+	}
+
+	url := fmt.Sprintf("%s/pkg/%s/%s/npm/%s", c.url, config.Global.AccountID, registry,
+		util.EscapeNPMPackageName(name))
+	req, err := http2.NewRequest(http2.MethodPut, url, bytes.NewReader(packumentJSON))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to update packument '%s': %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to update packument '%s', status code: %d, response: %s",
+			url, resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// mergeNPMPackumentJSON sets the npmPackumentFields and the deprecations of
+// packument on the raw packument document
+func mergeNPMPackumentJSON(current []byte, packument *npm.PackageMetadata) ([]byte, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(current, &doc); err != nil {
+		return nil, fmt.Errorf("invalid packument: %w", err)
+	}
+	typed, err := json.Marshal(packument)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(typed, &fields); err != nil {
+		return nil, err
+	}
+	for _, field := range npmPackumentFields {
+		if v, ok := fields[field]; ok && string(v) != "null" {
+			doc[field] = v
+		}
+	}
+
+	var versions map[string]map[string]json.RawMessage
+	if raw, ok := doc["versions"]; ok {
+		if err := json.Unmarshal(raw, &versions); err != nil {
+			return nil, fmt.Errorf("invalid packument versions: %w", err)
+		}
+	}
+	for version, v := range packument.Versions {
+		raw, ok := versions[version]
+		if !ok || v == nil || raw == nil {
+			continue
+		}
+		if msg, _ := v.Deprecated.(string); msg != "" {
+			if raw["deprecated"], err = json.Marshal(msg); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if versions != nil {
+		if doc["versions"], err = json.Marshal(versions); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(doc)
+}
+
 func (c *client) uploadDartFile(
 	registry string,
 	name string,
@@ -1304,6 +1443,39 @@ func (c *client) artifactVersionExists(
 	return false, nil
 }
 
+// updateVersionMetadata sets key/value metadata on a package version
+func (c *client) updateVersionMetadata(
+	ctx context.Context,
+	registry, pkg, version string,
+	metadata map[string]string,
+) error {
+	keys := make([]string, 0, len(metadata))
+	for k := range metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	items := make([]ar_v2.MetadataItemInput, 0, len(keys))
+	for _, k := range keys {
+		items = append(items, ar_v2.MetadataItemInput{Key: k, Value: metadata[k]})
+	}
+
+	response, err := c.arV2Client.UpdateMetadataWithResponse(ctx,
+		&ar_v2.UpdateMetadataParams{AccountIdentifier: config.Global.AccountID},
+		ar_v2.UpdateMetadataJSONRequestBody{
+			RegistryIdentifier: registry,
+			Package:            &pkg,
+			Version:            &version,
+			Metadata:           items,
+		})
+	if err != nil {
+		return fmt.Errorf("failed to update metadata: %w", err)
+	}
+	if response.StatusCode() >= http2.StatusBadRequest {
+		return fmt.Errorf("failed to update metadata: %s: %s", response.Status(), string(response.Body))
+	}
+	return nil
+}
+
 func (c *client) createGoVersion(
 	registry string,
 	artifactName string,
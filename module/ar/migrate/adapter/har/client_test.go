@@ -1,6 +1,7 @@
 package har
 
 import (
+	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/harness/harness-cli/config"
 	pkgclient "github.com/harness/harness-cli/internal/api/ar_pkg"
+	migratehttp "github.com/harness/harness-cli/module/ar/migrate/http"
 	"github.com/harness/harness-cli/module/ar/migrate/types"
 )
 
@@ -93,3 +95,54 @@ func TestUploadRawFile(t *testing.T) {
 		})
 	}
 }
+
+// TestUpdateNPMPackumentKeepsUnmodelledFields checks that the PUT carries the
+// destination document with only the copied fields replaced
+func TestUpdateNPMPackumentKeepsUnmodelledFields(t *testing.T) {
+	config.Global.AccountID = "acct1"
+	current := `{"_id":"@acme/ui","name":"@acme/ui","custom":{"a":1},"dist-tags":{"latest":"1.0.0"},
+		"versions":{"1.0.0":{"name":"@acme/ui","version":"1.0.0","_npmUser":{"name":"bob"},"dist":{}}}}`
+
+	var gotPaths []string
+	var put map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPaths = append(gotPaths, r.Method+" "+r.URL.EscapedPath())
+		if r.Method == http.MethodPut {
+			if err := json.NewDecoder(r.Body).Decode(&put); err != nil {
+				t.Errorf("decode PUT body: %v", err)
+			}
+			return
+		}
+		_, _ = w.Write([]byte(current))
+	}))
+	defer srv.Close()
+	c := &client{client: migratehttp.NewClient(http.DefaultClient), url: srv.URL}
+
+	packument, err := c.getNPMPackument("npm-reg", "@acme/ui")
+	if err != nil {
+		t.Fatalf("getNPMPackument: %v", err)
+	}
+	packument.Readme = "# ui"
+	packument.Versions["1.0.0"].Deprecated = "use 2.x"
+	if err := c.updateNPMPackument("npm-reg", "@acme/ui", packument); err != nil {
+		t.Fatalf("updateNPMPackument: %v", err)
+	}
+
+	wantPath := "/pkg/acct1/npm-reg/npm/@acme%2Fui"
+	if len(gotPaths) != 3 || gotPaths[2] != "PUT "+wantPath {
+		t.Errorf("requests = %v, want the PUT to %s", gotPaths, wantPath)
+	}
+	if put["readme"] != "# ui" {
+		t.Errorf("readme = %v, want the copied readme", put["readme"])
+	}
+	if put["custom"] == nil {
+		t.Error("custom field was dropped")
+	}
+	version := put["versions"].(map[string]any)["1.0.0"].(map[string]any)
+	if version["deprecated"] != "use 2.x" {
+		t.Errorf("deprecated = %v, want the copied deprecation", version["deprecated"])
+	}
+	if version["_npmUser"] == nil {
+		t.Error("_npmUser was dropped")
+	}
+}
@@ -9,9 +9,11 @@ import (
 
 	adp "github.com/harness/harness-cli/module/ar/migrate/adapter"
 	"github.com/harness/harness-cli/module/ar/migrate/types"
+	"github.com/harness/harness-cli/module/ar/migrate/types/npm"
 	"github.com/harness/harness-cli/module/ar/migrate/util"
 
 	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/rs/zerolog/log"
 )
 
 func init() {
@@ -39,12 +41,18 @@ func newAdapter(config types.RegistryConfig) (adp.Adapter, error) {
 	}, nil
 }
 
-// assertOCISupported returns a descriptive error for non-OCI artifact types
-func assertOCISupported(artifactType types.ArtifactType) error {
-	if artifactType == types.DOCKER || artifactType == types.HELM {
+// assertSupported returns a descriptive error for artifact types Harbor cannot serve
+func assertSupported(artifactType types.ArtifactType) error {
+	if artifactType == types.DOCKER || artifactType == types.HELM || isChartMuseum(artifactType) {
 		return nil
 	}
-	return fmt.Errorf("HARBOR source supports only OCI artifact types (DOCKER, HELM); got %s", artifactType)
+	return fmt.Errorf("HARBOR source supports only DOCKER, HELM, HELM_HTTP and HELM_LEGACY; got %s", artifactType)
+}
+
+// isChartMuseum reports whether the artifact type is served from the project's
+// legacy ChartMuseum repository rather than the OCI registry
+func isChartMuseum(artifactType types.ArtifactType) bool {
+	return artifactType == types.HELM_HTTP || artifactType == types.HELM_LEGACY
 }
 
 func (a *adapter) GetKeyChain(sourcePackageHostname string) (authn.Keychain, error) {
@@ -102,9 +110,12 @@ func (a *adapter) GetPackages(registry string, artifactType types.ArtifactType,
 	[]types.Package,
 	error,
 ) {
-	if err := assertOCISupported(artifactType); err != nil {
+	if err := assertSupported(artifactType); err != nil {
 		return nil, err
 	}
+	if isChartMuseum(artifactType) {
+		return a.getChartPackages(registry)
+	}
 
 	repos, err := a.client.listRepositories(registry)
 	if err != nil {
@@ -126,6 +137,82 @@ func (a *adapter) GetPackages(registry string, artifactType types.ArtifactType,
 	return packages, nil
 }
 
+// getChartPackages returns one package per ChartMuseum chart version. URL is the
+// chart archive path relative to the project's chart repository, which is what
+// DownloadFile expects.
+func (a *adapter) getChartPackages(registry string) ([]types.Package, error) {
+	charts, err := a.client.listCharts(registry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list charts in project %s: %w", registry, err)
+	}
+
+	var packages []types.Package
+	for _, c := range charts {
+		versions, err := a.client.listChartVersions(registry, c.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list versions of chart %s: %w", c.Name, err)
+		}
+		for _, v := range versions {
+			if len(v.URLs) == 0 {
+				log.Warn().Msgf("Chart %s:%s has no download URL, skipping", c.Name, v.Version)
+				continue
+			}
+			packages = append(packages, types.Package{
+				Registry: registry,
+				Path:     "/",
+				Name:     c.Name,
+				Version:  v.Version,
+				URL:      v.URLs[0],
+				Metadata: labelMetadata(v.Labels),
+			})
+		}
+	}
+	return packages, nil
+}
+
+// GetVersionMetadata returns the Harbor labels and, for OCI artifacts, the
+// vulnerability summary of the latest scan, keyed by tag or chart version.
+// A package with a version only gets that tag; a repository package, which
+// migrates every tag, gets them all.
+func (a *adapter) GetVersionMetadata(
+	registry string,
+	p types.Package,
+	artifactType types.ArtifactType,
+) (map[string]map[string]string, error) {
+	if err := assertSupported(artifactType); err != nil {
+		return nil, err
+	}
+	if isChartMuseum(artifactType) {
+		if len(p.Metadata) == 0 {
+			return nil, nil
+		}
+		return map[string]map[string]string{p.Version: p.Metadata}, nil
+	}
+
+	artifacts, err := a.client.listArtifacts(registry, p.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list artifacts of %s/%s: %w", registry, p.Name, err)
+	}
+	result := map[string]map[string]string{}
+	for _, art := range artifacts {
+		md := artifactMetadata(art)
+		if len(md) == 0 {
+			continue
+		}
+		for _, tag := range art.Tags {
+			if p.Version == "" || tag.Name == p.Version {
+				result[tag.Name] = md
+			}
+		}
+	}
+	return result, nil
+}
+
+// SetVersionMetadata is a no-op for Harbor source adapter
+func (a *adapter) SetVersionMetadata(_ context.Context, _, _, _ string, _ map[string]string) error {
+	return fmt.Errorf("SetVersionMetadata not implemented for HARBOR")
+}
+
 // GetOCIImagePath builds the crane-compatible image reference for a Harbor repository.
 // Harbor image path: <host>/<project>/<repo>
 func (a *adapter) GetOCIImagePath(registry string, packageHostname string, image string) (string, error) {
@@ -145,7 +232,7 @@ func (a *adapter) GetOCIImagePath(registry string, packageHostname string, image
 	return util.GenOCIImagePath(host, registry, image), nil
 }
 
-// --- Stubs for non-OCI operations (Harbor is OCI-only) ---
+// --- Stubs for operations a Harbor source does not need ---
 
 func (a *adapter) GetVersions(
 	_ types.Package,
@@ -153,14 +240,16 @@ func (a *adapter) GetVersions(
 	_, _ string,
 	artifactType types.ArtifactType,
 ) ([]types.Version, error) {
-	if err := assertOCISupported(artifactType); err != nil {
+	if err := assertSupported(artifactType); err != nil {
 		return nil, err
 	}
-	return nil, fmt.Errorf("GetVersions not implemented for HARBOR (OCI uses crane)")
+	return nil, fmt.Errorf("GetVersions not implemented for HARBOR (OCI uses crane, charts are per-version packages)")
 }
 
-func (a *adapter) DownloadFile(_ string, _ string) (io.ReadCloser, http.Header, error) {
-	return nil, nil, fmt.Errorf("DownloadFile not implemented for HARBOR")
+// DownloadFile downloads a ChartMuseum chart archive (or its .prov sidecar) by
+// the URL recorded in the package
+func (a *adapter) DownloadFile(registry string, uri string) (io.ReadCloser, http.Header, error) {
+	return a.client.getFile(a.client.chartURL(registry, uri))
 }
 
 func (a *adapter) UploadFile(
@@ -222,3 +311,11 @@ func (a *adapter) BuildExistingIndex(
 ) (*types.ExistingIndex, error) {
 	return nil, nil
 }
+
+func (a *adapter) GetNPMPackument(_ string, _ string) (*npm.PackageMetadata, error) {
+	return nil, fmt.Errorf("GetNPMPackument not implemented for HARBOR")
+}
+
+func (a *adapter) UpdateNPMPackument(_ string, _ string, _ *npm.PackageMetadata) error {
+	return fmt.Errorf("UpdateNPMPackument not implemented for HARBOR")
+}
@@ -0,0 +1,165 @@
+package harbor
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/harness/harness-cli/module/ar/migrate/types"
+)
+
+// harborServer answers the subset of the Harbor API used for project "lib":
+// a ChartMuseum chart with two versions and an OCI repository "team/app"
+// holding a labelled, scanned artifact.
+func harborServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	writeJSON := func(w http.ResponseWriter, v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch p := r.URL.EscapedPath(); p {
+		case "/api/chartrepo/lib/charts":
+			writeJSON(w, []HarborChart{{Name: "nginx", TotalVersions: 2}})
+		case "/api/chartrepo/lib/charts/nginx":
+			writeJSON(w, []HarborChartVersion{
+				{Name: "nginx", Version: "1.1.0", URLs: []string{"charts/nginx-1.1.0.tgz"},
+					Labels: []HarborLabel{{Name: "stable"}, {Name: "approved"}}},
+				{Name: "nginx", Version: "1.0.0", URLs: []string{"charts/nginx-1.0.0.tgz"}},
+				{Name: "nginx", Version: "0.9.0"},
+			})
+		case "/chartrepo/lib/charts/nginx-1.1.0.tgz":
+			_, _ = io.WriteString(w, "chart")
+		case "/api/v2.0/projects/lib/repositories/team%252Fapp/artifacts":
+			if q := r.URL.Query(); q.Get("with_label") != "true" || q.Get("with_scan_overview") != "true" {
+				t.Errorf("artifacts listed without labels/scan overview: %s", r.URL.RawQuery)
+			}
+			writeJSON(w, []HarborArtifact{
+				{
+					Digest: "sha256:aaa",
+					Tags:   []HarborTag{{Name: "v1"}, {Name: "latest"}},
+					Labels: []HarborLabel{{Name: "prod"}},
+					ScanOverview: map[string]HarborScanOverview{
+						"application/vnd.security.vulnerability.report; version=1.1": {
+							ScanStatus: "Success",
+							Severity:   "High",
+							Summary: &HarborVulnerabilitySummary{
+								Total: 3, Fixable: 2, Summary: map[string]int{"High": 1, "Low": 2},
+							},
+						},
+					},
+				},
+				{Digest: "sha256:bbb", Tags: []HarborTag{{Name: "old"}}},
+			})
+		default:
+			t.Errorf("unexpected request path: %s", p)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestAdapter(t *testing.T, endpoint string) *adapter {
+	t.Helper()
+	a, err := newAdapter(types.RegistryConfig{Type: types.HARBOR, Endpoint: endpoint})
+	if err != nil {
+		t.Fatalf("newAdapter: %v", err)
+	}
+	return a.(*adapter)
+}
+
+func TestGetPackagesChartMuseum(t *testing.T) {
+	a := newTestAdapter(t, harborServer(t).URL)
+
+	for _, artifactType := range []types.ArtifactType{types.HELM_HTTP, types.HELM_LEGACY} {
+		pkgs, err := a.GetPackages("lib", artifactType, nil)
+		if err != nil {
+			t.Fatalf("%s GetPackages: %v", artifactType, err)
+		}
+		if len(pkgs) != 2 {
+			t.Fatalf("%s packages = %+v, want the 2 downloadable versions", artifactType, pkgs)
+		}
+		if pkgs[0].Name != "nginx" || pkgs[0].Version != "1.1.0" || pkgs[0].URL != "charts/nginx-1.1.0.tgz" {
+			t.Errorf("%s package = %+v", artifactType, pkgs[0])
+		}
+		if got := pkgs[0].Metadata[metadataLabels]; got != "approved,stable" {
+			t.Errorf("%s labels = %q", artifactType, got)
+		}
+		if pkgs[1].Metadata != nil {
+			t.Errorf("%s unlabelled version metadata = %v", artifactType, pkgs[1].Metadata)
+		}
+	}
+
+	if _, err := a.GetPackages("lib", types.MAVEN, nil); err == nil {
+		t.Errorf("expected MAVEN to be rejected")
+	}
+}
+
+func TestDownloadChart(t *testing.T) {
+	a := newTestAdapter(t, harborServer(t).URL)
+
+	body, _, err := a.DownloadFile("lib", "charts/nginx-1.1.0.tgz")
+	if err != nil {
+		t.Fatalf("DownloadFile: %v", err)
+	}
+	defer body.Close()
+	data, _ := io.ReadAll(body)
+	if string(data) != "chart" {
+		t.Errorf("body = %q", data)
+	}
+}
+
+func TestGetVersionMetadataOCI(t *testing.T) {
+	a := newTestAdapter(t, harborServer(t).URL)
+
+	md, err := a.GetVersionMetadata("lib", types.Package{Name: "team/app"}, types.DOCKER)
+	if err != nil {
+		t.Fatalf("GetVersionMetadata: %v", err)
+	}
+	if len(md) != 2 {
+		t.Fatalf("metadata = %v, want entries for v1 and latest only", md)
+	}
+	want := map[string]string{
+		metadataLabels:              "prod",
+		metadataVulnSeverity:        "High",
+		metadataVulnTotal:           "3",
+		metadataVulnFixable:         "2",
+		"harbor.vulnerability.high": "1",
+		"harbor.vulnerability.low":  "2",
+	}
+	for _, tag := range []string{"v1", "latest"} {
+		for k, v := range want {
+			if md[tag][k] != v {
+				t.Errorf("%s[%s] = %q, want %q", tag, k, md[tag][k], v)
+			}
+		}
+	}
+}
+
+func TestGetVersionMetadataOCIVersion(t *testing.T) {
+	a := newTestAdapter(t, harborServer(t).URL)
+
+	md, err := a.GetVersionMetadata("lib", types.Package{Name: "team/app", Version: "v1"}, types.DOCKER)
+	if err != nil {
+		t.Fatalf("GetVersionMetadata: %v", err)
+	}
+	if len(md) != 1 || md["v1"][metadataLabels] != "prod" {
+		t.Fatalf("metadata = %v, want the entry for v1 only", md)
+	}
+}
+
+func TestGetVersionMetadataChartMuseum(t *testing.T) {
+	a := newTestAdapter(t, harborServer(t).URL)
+
+	p := types.Package{Name: "nginx", Version: "1.1.0", Metadata: map[string]string{metadataLabels: "stable"}}
+	md, err := a.GetVersionMetadata("lib", p, types.HELM_HTTP)
+	if err != nil {
+		t.Fatalf("GetVersionMetadata: %v", err)
+	}
+	if md["1.1.0"][metadataLabels] != "stable" {
+		t.Errorf("metadata = %v", md)
+	}
+}
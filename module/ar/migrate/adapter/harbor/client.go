@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 
 	httputil "github.com/harness/harness-cli/module/ar/migrate/http"
@@ -151,3 +152,146 @@ func repoShortName(project, fullName string) string {
 	prefix := project + "/"
 	return strings.TrimPrefix(fullName, prefix)
 }
+
+// HarborLabel is a Harbor label attached to an artifact or chart version
+type HarborLabel struct {
+	Name string `json:"name"`
+}
+
+// HarborTag is a tag pointing at an artifact
+type HarborTag struct {
+	Name string `json:"name"`
+}
+
+// HarborVulnerabilitySummary is the per-severity vulnerability count of a scan report
+type HarborVulnerabilitySummary struct {
+	Total   int            `json:"total"`
+	Fixable int            `json:"fixable"`
+	Summary map[string]int `json:"summary"`
+}
+
+// HarborScanOverview is a scan report overview keyed by report mime type in Harbor responses
+type HarborScanOverview struct {
+	ScanStatus string                      `json:"scan_status"`
+	Severity   string                      `json:"severity"`
+	Summary    *HarborVulnerabilitySummary `json:"summary"`
+}
+
+// HarborArtifact represents an OCI artifact within a Harbor repository
+type HarborArtifact struct {
+	Digest       string                        `json:"digest"`
+	Tags         []HarborTag                   `json:"tags"`
+	Labels       []HarborLabel                 `json:"labels"`
+	ScanOverview map[string]HarborScanOverview `json:"scan_overview"`
+}
+
+// HarborChart represents a chart in a project's ChartMuseum repository
+type HarborChart struct {
+	Name          string `json:"name"`
+	TotalVersions int    `json:"total_versions"`
+}
+
+// HarborChartVersion represents a single version of a ChartMuseum chart
+type HarborChartVersion struct {
+	Name    string        `json:"name"`
+	Version string        `json:"version"`
+	Created string        `json:"created"`
+	Digest  string        `json:"digest"`
+	URLs    []string      `json:"urls"`
+	Labels  []HarborLabel `json:"labels"`
+}
+
+// getJSON executes a GET request against the given URL and decodes a 200 response into v
+func (c *client) getJSON(rawURL string, v interface{}) (http.Header, error) {
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(body))
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return resp.Header, nil
+}
+
+// listArtifacts returns all artifacts of a repository together with their tags,
+// labels and scan overview, handling pagination. repo is the short repository name.
+func (c *client) listArtifacts(project, repo string) ([]HarborArtifact, error) {
+	var all []HarborArtifact
+	// Harbor expects a "/" inside a repository name to be double-encoded
+	escapedRepo := url.PathEscape(url.PathEscape(repo))
+	for page := 1; ; page++ {
+		rawURL := fmt.Sprintf(
+			"%s/api/%s/projects/%s/repositories/%s/artifacts?page=%d&page_size=%d"+
+				"&with_tag=true&with_label=true&with_scan_overview=true",
+			c.url, harborAPIVersion, project, escapedRepo, page, pageSize)
+		var artifacts []HarborArtifact
+		header, err := c.getJSON(rawURL, &artifacts)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, artifacts...)
+		if nextPage(header.Get("Link")) == "" || len(artifacts) < pageSize {
+			break
+		}
+	}
+	return all, nil
+}
+
+// listCharts returns the charts in the project's ChartMuseum repository
+func (c *client) listCharts(project string) ([]HarborChart, error) {
+	var charts []HarborChart
+	if _, err := c.getJSON(fmt.Sprintf("%s/api/chartrepo/%s/charts", c.url, project), &charts); err != nil {
+		return nil, err
+	}
+	return charts, nil
+}
+
+// listChartVersions returns every version of a ChartMuseum chart
+func (c *client) listChartVersions(project, chart string) ([]HarborChartVersion, error) {
+	var versions []HarborChartVersion
+	rawURL := fmt.Sprintf("%s/api/chartrepo/%s/charts/%s", c.url, project, url.PathEscape(chart))
+	if _, err := c.getJSON(rawURL, &versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// chartURL resolves a chart version URL to an absolute download URL. ChartMuseum
+// returns URLs relative to the project's chart repository (e.g. "charts/x-1.0.0.tgz").
+func (c *client) chartURL(project, uri string) string {
+	if strings.HasPrefix(uri, "http://") || strings.HasPrefix(uri, "https://") {
+		return uri
+	}
+	return fmt.Sprintf("%s/chartrepo/%s/%s", c.url, project, strings.TrimPrefix(uri, "/"))
+}
+
+// getFile downloads a file by its absolute URL
+func (c *client) getFile(rawURL string) (io.ReadCloser, http.Header, error) {
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create request: %w", err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("execute request: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(body))
+	}
+	return resp.Body, resp.Header, nil
+}
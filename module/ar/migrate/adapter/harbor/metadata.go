@@ -0,0 +1,61 @@
+package harbor
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Metadata keys written to HAR for Harbor labels and scan results
+const (
+	metadataLabels          = "harbor.labels"
+	metadataVulnSeverity    = "harbor.vulnerability.severity"
+	metadataVulnTotal       = "harbor.vulnerability.total"
+	metadataVulnFixable     = "harbor.vulnerability.fixable"
+	metadataVulnCountPrefix = "harbor.vulnerability."
+)
+
+// labelMetadata flattens Harbor labels into a single comma separated value
+func labelMetadata(labels []HarborLabel) map[string]string {
+	if len(labels) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(labels))
+	for _, l := range labels {
+		names = append(names, l.Name)
+	}
+	sort.Strings(names)
+	return map[string]string{metadataLabels: strings.Join(names, ",")}
+}
+
+// artifactMetadata builds the metadata of an OCI artifact from its labels and
+// the vulnerability summary of its scan report. Artifacts that were never
+// scanned only carry their labels.
+func artifactMetadata(art HarborArtifact) map[string]string {
+	md := labelMetadata(art.Labels)
+
+	// scan_overview is keyed by report mime type; Harbor only produces one
+	// vulnerability report per artifact, so pick the first complete one
+	mimeTypes := make([]string, 0, len(art.ScanOverview))
+	for mt := range art.ScanOverview {
+		mimeTypes = append(mimeTypes, mt)
+	}
+	sort.Strings(mimeTypes)
+	for _, mt := range mimeTypes {
+		overview := art.ScanOverview[mt]
+		if overview.Summary == nil {
+			continue
+		}
+		if md == nil {
+			md = map[string]string{}
+		}
+		md[metadataVulnSeverity] = overview.Severity
+		md[metadataVulnTotal] = strconv.Itoa(overview.Summary.Total)
+		md[metadataVulnFixable] = strconv.Itoa(overview.Summary.Fixable)
+		for severity, count := range overview.Summary.Summary {
+			md[metadataVulnCountPrefix+strings.ToLower(severity)] = strconv.Itoa(count)
+		}
+		break
+	}
+	return md
+}
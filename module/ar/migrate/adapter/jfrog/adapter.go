@@ -3,6 +3,7 @@ package jfrog
 import (
 	"compress/gzip"
 	"context"
+	"encoding/json"
 	"encoding/xml"
 	"errors"
 	"fmt"
@@ -17,6 +18,7 @@ import (
 	adp "github.com/harness/harness-cli/module/ar/migrate/adapter"
 	"github.com/harness/harness-cli/module/ar/migrate/tree"
 	"github.com/harness/harness-cli/module/ar/migrate/types"
+	"github.com/harness/harness-cli/module/ar/migrate/types/npm"
 	"github.com/harness/harness-cli/module/ar/migrate/util"
 
 	"github.com/google/go-containerregistry/pkg/authn"
@@ -1390,6 +1392,39 @@ func (a *adapter) BuildExistingIndex(
 	return nil, nil
 }
 
+func (a *adapter) GetVersionMetadata(
+	_ string,
+	_ types.Package,
+	_ types.ArtifactType,
+) (map[string]map[string]string, error) {
+	return nil, nil
+}
+
+func (a *adapter) SetVersionMetadata(_ context.Context, _, _, _ string, _ map[string]string) error {
+	return fmt.Errorf("SetVersionMetadata not implemented for JFROG")
+}
+
+// GetNPMPackument fetches the packument through Artifactory's npm API
+func (a *adapter) GetNPMPackument(registry string, name string) (*npm.PackageMetadata, error) {
+	packumentURL := fmt.Sprintf("%s/artifactory/api/npm/%s/%s", strings.TrimSuffix(a.reg.Endpoint, "/"),
+		registry, util.EscapeNPMPackageName(name))
+	body, _, err := a.client.GetFile(registry, packumentURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get packument of %s: %w", name, err)
+	}
+	defer body.Close()
+
+	var packument npm.PackageMetadata
+	if err := json.NewDecoder(body).Decode(&packument); err != nil {
+		return nil, fmt.Errorf("failed to decode packument of %s: %w", name, err)
+	}
+	return &packument, nil
+}
+
+func (a *adapter) UpdateNPMPackument(_ string, _ string, _ *npm.PackageMetadata) error {
+	return fmt.Errorf("UpdateNPMPackument not implemented for JFROG")
+}
+
 type repomdData struct {
 	XMLName xml.Name `xml:"repomd"`
 	Data    []struct {
@@ -0,0 +1,48 @@
+// Package mock_harbor registers the MOCK_HARBOR registry type: the real Harbor
+// adapter pointed at an in-process server that answers the Harbor API,
+// ChartMuseum API and OCI registry API from embedded fixtures, so a migration
+// config can be rehearsed without a Harbor instance.
+package mock_harbor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	adp "github.com/harness/harness-cli/module/ar/migrate/adapter"
+	_ "github.com/harness/harness-cli/module/ar/migrate/adapter/harbor"
+	"github.com/harness/harness-cli/module/ar/migrate/types"
+)
+
+func init() {
+	adapterType := types.MOCK_HARBOR
+	if err := adp.RegisterFactory(adapterType, new(factory)); err != nil {
+		return
+	}
+}
+
+var (
+	startOnce sync.Once
+	serverURL string
+	startErr  error
+)
+
+type factory struct{}
+
+// Create starts the fixture server on first use and returns a Harbor adapter
+// whose endpoint is the server, whatever endpoint the config names
+func (f factory) Create(ctx context.Context, config types.RegistryConfig) (adp.Adapter, error) {
+	startOnce.Do(func() {
+		serverURL, startErr = startServer()
+	})
+	if startErr != nil {
+		return nil, fmt.Errorf("start mock Harbor server: %w", startErr)
+	}
+
+	harborFactory, err := adp.GetFactory(types.HARBOR)
+	if err != nil {
+		return nil, err
+	}
+	config.Endpoint = serverURL
+	return harborFactory.Create(ctx, config)
+}
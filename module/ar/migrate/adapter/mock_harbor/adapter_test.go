@@ -0,0 +1,117 @@
+package mock_harbor
+
+import (
+	"context"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+
+	adp "github.com/harness/harness-cli/module/ar/migrate/adapter"
+	"github.com/harness/harness-cli/module/ar/migrate/types"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+)
+
+func newTestAdapter(t *testing.T) adp.Adapter {
+	t.Helper()
+	a, err := adp.GetAdapter(context.Background(), types.RegistryConfig{
+		Type:        types.MOCK_HARBOR,
+		Endpoint:    "https://harbor.example.com",
+		Credentials: types.CredentialsConfig{Username: "admin", Password: "Harbor12345"},
+	})
+	if err != nil {
+		t.Fatalf("GetAdapter: %v", err)
+	}
+	return a
+}
+
+// TestDockerRepositories asserts images are enumerated, pullable from the
+// in-process registry and carry their labels and scan summary
+func TestDockerRepositories(t *testing.T) {
+	a := newTestAdapter(t)
+	if ok, err := a.ValidateCredentials(); !ok || err != nil {
+		t.Fatalf("ValidateCredentials: %v", err)
+	}
+
+	pkgs, err := a.GetPackages("library", types.DOCKER, nil)
+	if err != nil {
+		t.Fatalf("GetPackages: %v", err)
+	}
+	var names []string
+	for _, p := range pkgs {
+		names = append(names, p.Name)
+	}
+	sort.Strings(names)
+	if strings.Join(names, ",") != "nginx,team/api" {
+		t.Fatalf("packages = %v", names)
+	}
+
+	image, err := a.GetOCIImagePath("library", "", "nginx")
+	if err != nil {
+		t.Fatalf("GetOCIImagePath: %v", err)
+	}
+	tags, err := crane.ListTags(image)
+	if err != nil {
+		t.Fatalf("ListTags: %v", err)
+	}
+	sort.Strings(tags)
+	if strings.Join(tags, ",") != "1.24,1.25,latest" {
+		t.Errorf("tags = %v", tags)
+	}
+	d1, err := crane.Digest(image + ":1.25")
+	if err != nil {
+		t.Fatalf("Digest: %v", err)
+	}
+	if d2, _ := crane.Digest(image + ":latest"); d1 != d2 {
+		t.Errorf("tags of one artifact resolve to %s and %s", d1, d2)
+	}
+
+	md, err := a.GetVersionMetadata("library", types.Package{Name: "nginx"}, types.DOCKER)
+	if err != nil {
+		t.Fatalf("GetVersionMetadata: %v", err)
+	}
+	if md["latest"]["harbor.labels"] != "prod" || md["latest"]["harbor.vulnerability.severity"] != "High" {
+		t.Errorf("metadata of latest = %v", md["latest"])
+	}
+	if _, ok := md["1.24"]; ok {
+		t.Errorf("unlabelled, unscanned artifact must carry no metadata: %v", md["1.24"])
+	}
+}
+
+func TestOCIHelmChart(t *testing.T) {
+	a := newTestAdapter(t)
+	image, err := a.GetOCIImagePath("charts", "", "podinfo")
+	if err != nil {
+		t.Fatalf("GetOCIImagePath: %v", err)
+	}
+	manifest, err := crane.Manifest(image + ":6.5.0")
+	if err != nil {
+		t.Fatalf("Manifest: %v", err)
+	}
+	if !strings.Contains(string(manifest), "application/vnd.cncf.helm.config.v1+json") {
+		t.Errorf("manifest is not a Helm chart: %s", manifest)
+	}
+}
+
+func TestChartMuseumCharts(t *testing.T) {
+	a := newTestAdapter(t)
+	pkgs, err := a.GetPackages("charts", types.HELM_HTTP, nil)
+	if err != nil {
+		t.Fatalf("GetPackages: %v", err)
+	}
+	if len(pkgs) != 3 {
+		t.Fatalf("packages = %+v, want 3 chart versions", pkgs)
+	}
+	for _, p := range pkgs {
+		body, _, err := a.DownloadFile("charts", p.URL)
+		if err != nil {
+			t.Fatalf("DownloadFile %s: %v", p.URL, err)
+		}
+		data, _ := io.ReadAll(body)
+		body.Close()
+		if len(data) == 0 {
+			t.Errorf("%s: empty chart", p.URL)
+		}
+	}
+}
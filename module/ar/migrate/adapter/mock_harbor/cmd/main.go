@@ -0,0 +1,35 @@
+// Command generate-mock-data writes every MOCK_HARBOR ChartMuseum chart archive
+// into testdata/binary/ so it is embedded by the mock server at compile time,
+// where it can be inspected or replaced by real charts. OCI images and charts
+// are built in memory when the mock server starts.
+//
+// Usage:
+//
+//	go run ./module/ar/migrate/adapter/mock_harbor/cmd
+//
+// Or via Makefile:
+//
+//	make mock-init
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/harness/harness-cli/module/ar/migrate/adapter/mock_harbor"
+)
+
+var baseDir = filepath.Join("module", "ar", "migrate", "adapter", "mock_harbor", "testdata", "binary", "charts")
+
+func main() {
+	written, err := mock_harbor.WriteContent(baseDir)
+	for _, f := range written {
+		fmt.Printf("  wrote %s\n", f)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("mock-init: done")
+}
@@ -0,0 +1,305 @@
+package mock_harbor
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/harness/harness-cli/module/ar/migrate/adapter/harbor"
+	"github.com/harness/harness-cli/module/ar/migrate/adapter/mockdata"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+)
+
+//go:embed testdata
+var testdataFS embed.FS
+
+// binaryChartDir holds chart archives written by cmd, as
+// <project>/<name>-<version>.tgz; missing charts are generated in code
+const binaryChartDir = "testdata/binary/charts"
+
+// scanReportMimeType keys the scan overview of an artifact in Harbor responses
+const scanReportMimeType = "application/vnd.security.vulnerability.report; version=1.1"
+
+type projectFixture struct {
+	harbor.HarborProject
+	Repositories []repositoryFixture `json:"repositories"`
+	Charts       []chartFixture      `json:"charts"`
+}
+
+// repositoryFixture is an OCI repository; Helm marks a repository of OCI
+// Helm charts rather than container images
+type repositoryFixture struct {
+	Name      string            `json:"name"`
+	Helm      bool              `json:"helm"`
+	Artifacts []artifactFixture `json:"artifacts"`
+}
+
+type artifactFixture struct {
+	Tags         []string                   `json:"tags"`
+	Labels       []harbor.HarborLabel       `json:"labels"`
+	ScanOverview *harbor.HarborScanOverview `json:"scan_overview"`
+	digest       string
+}
+
+type chartFixture struct {
+	Name     string `json:"name"`
+	Versions []struct {
+		Version string               `json:"version"`
+		Labels  []harbor.HarborLabel `json:"labels"`
+	} `json:"versions"`
+}
+
+type server struct {
+	projects []projectFixture
+	charts   map[string][]byte // keyed by "project/charts/<name>-<version>.tgz"
+	registry http.Handler
+}
+
+// loadFixtures reads the embedded projects and resolves every ChartMuseum
+// chart archive
+func loadFixtures() (*server, error) {
+	s := &server{charts: make(map[string][]byte)}
+	data, err := testdataFS.ReadFile("testdata/projects.json")
+	if err != nil {
+		return nil, fmt.Errorf("read projects: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.projects); err != nil {
+		return nil, fmt.Errorf("parse projects: %w", err)
+	}
+	for _, p := range s.projects {
+		for _, c := range p.Charts {
+			for _, v := range c.Versions {
+				s.charts[p.Name+"/"+chartPath(c.Name, v.Version)] = chartContent(p.Name, c.Name, v.Version)
+			}
+		}
+	}
+	return s, nil
+}
+
+// chartPath is the archive path of a chart version relative to the project's
+// chart repository, as ChartMuseum reports it
+func chartPath(name, version string) string {
+	return fmt.Sprintf("charts/%s-%s.tgz", name, version)
+}
+
+func chartContent(project, name, version string) []byte {
+	file := fmt.Sprintf("%s/%s/%s-%s.tgz", binaryChartDir, project, name, version)
+	if data, err := fs.ReadFile(testdataFS, file); err == nil {
+		return data
+	}
+	return mockdata.HelmChartTgz(name, version)
+}
+
+// startServer serves the fixtures and pushes every artifact to the in-process
+// registry, recording the digests the artifact API reports
+func startServer() (string, error) {
+	s, err := loadFixtures()
+	if err != nil {
+		return "", err
+	}
+	s.registry = mockdata.NewRegistry()
+	baseURL, err := mockdata.Serve(s.handler())
+	if err != nil {
+		return "", err
+	}
+
+	host := strings.TrimPrefix(baseURL, "http://")
+	for _, p := range s.projects {
+		for _, repo := range p.Repositories {
+			for i := range repo.Artifacts {
+				art := &repo.Artifacts[i]
+				img, err := artifactImage(p.Name, repo, art.Tags[0])
+				if err != nil {
+					return "", err
+				}
+				for _, tag := range art.Tags {
+					if art.digest, err = mockdata.PushImage(host, p.Name+"/"+repo.Name, tag, img); err != nil {
+						return "", err
+					}
+				}
+			}
+		}
+	}
+	return baseURL, nil
+}
+
+// artifactImage builds the image of an artifact from its first tag, so every
+// tag of the artifact resolves to the same digest
+func artifactImage(project string, repo repositoryFixture, tag string) (v1.Image, error) {
+	if repo.Helm {
+		return mockdata.HelmChartImage(repo.Name, tag)
+	}
+	return mockdata.DockerImage(project+"/"+repo.Name, tag)
+}
+
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/v2/", s.registry)
+	mux.HandleFunc("GET /api/v2.0/health", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, map[string]string{"status": "healthy"})
+	})
+	mux.HandleFunc("GET /api/v2.0/projects/{project}", s.getProject)
+	mux.HandleFunc("GET /api/v2.0/projects/{project}/repositories", s.listRepositories)
+	mux.HandleFunc("GET /api/v2.0/projects/{project}/repositories/{repo}/artifacts", s.listArtifacts)
+	mux.HandleFunc("GET /api/chartrepo/{project}/charts", s.listCharts)
+	mux.HandleFunc("GET /api/chartrepo/{project}/charts/{chart}", s.listChartVersions)
+	mux.HandleFunc("GET /chartrepo/{project}/{path...}", s.getChart)
+	return mux
+}
+
+func (s *server) project(r *http.Request) (projectFixture, bool) {
+	for _, p := range s.projects {
+		if p.Name == r.PathValue("project") {
+			return p, true
+		}
+	}
+	return projectFixture{}, false
+}
+
+func (s *server) getProject(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.project(r)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	writeJSON(w, p.HarborProject)
+}
+
+func (s *server) listRepositories(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.project(r)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	repos := make([]harbor.HarborRepository, 0, len(p.Repositories))
+	for _, repo := range p.Repositories {
+		repos = append(repos, harbor.HarborRepository{
+			Name:          p.Name + "/" + repo.Name,
+			ArtifactCount: int64(len(repo.Artifacts)),
+		})
+	}
+	writeJSON(w, repos)
+}
+
+// listArtifacts answers with the tags, labels and scan overview of every
+// artifact. Harbor expects the repository name double-encoded, so the path
+// value is still escaped once.
+func (s *server) listArtifacts(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.project(r)
+	name, err := url.PathUnescape(r.PathValue("repo"))
+	if !ok || err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	for _, repo := range p.Repositories {
+		if repo.Name != name {
+			continue
+		}
+		artifacts := make([]harbor.HarborArtifact, 0, len(repo.Artifacts))
+		for _, art := range repo.Artifacts {
+			a := harbor.HarborArtifact{Digest: art.digest, Labels: art.Labels}
+			for _, tag := range art.Tags {
+				a.Tags = append(a.Tags, harbor.HarborTag{Name: tag})
+			}
+			if art.ScanOverview != nil {
+				a.ScanOverview = map[string]harbor.HarborScanOverview{scanReportMimeType: *art.ScanOverview}
+			}
+			artifacts = append(artifacts, a)
+		}
+		writeJSON(w, artifacts)
+		return
+	}
+	http.NotFound(w, r)
+}
+
+func (s *server) listCharts(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.project(r)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	charts := make([]harbor.HarborChart, 0, len(p.Charts))
+	for _, c := range p.Charts {
+		charts = append(charts, harbor.HarborChart{Name: c.Name, TotalVersions: len(c.Versions)})
+	}
+	writeJSON(w, charts)
+}
+
+func (s *server) listChartVersions(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.project(r)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	for _, c := range p.Charts {
+		if c.Name != r.PathValue("chart") {
+			continue
+		}
+		versions := make([]harbor.HarborChartVersion, 0, len(c.Versions))
+		for _, v := range c.Versions {
+			path := chartPath(c.Name, v.Version)
+			sum := sha256.Sum256(s.charts[p.Name+"/"+path])
+			versions = append(versions, harbor.HarborChartVersion{
+				Name:    c.Name,
+				Version: v.Version,
+				Created: "2024-01-01T00:00:00Z",
+				Digest:  hex.EncodeToString(sum[:]),
+				URLs:    []string{path},
+				Labels:  v.Labels,
+			})
+		}
+		writeJSON(w, versions)
+		return
+	}
+	http.NotFound(w, r)
+}
+
+func (s *server) getChart(w http.ResponseWriter, r *http.Request) {
+	content, ok := s.charts[r.PathValue("project")+"/"+r.PathValue("path")]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/gzip")
+	_, _ = w.Write(content)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// WriteContent generates every ChartMuseum chart archive of the fixtures into
+// <dir>/<project>/<name>-<version>.tgz, where the server picks it up once
+// embedded
+func WriteContent(dir string) ([]string, error) {
+	s, err := loadFixtures()
+	if err != nil {
+		return nil, err
+	}
+	var written []string
+	for _, p := range s.projects {
+		for _, c := range p.Charts {
+			for _, v := range c.Versions {
+				target := filepath.Join(dir, p.Name, fmt.Sprintf("%s-%s.tgz", c.Name, v.Version))
+				if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+					return written, err
+				}
+				if err := os.WriteFile(target, mockdata.HelmChartTgz(c.Name, v.Version), 0644); err != nil {
+					return written, err
+				}
+				written = append(written, target)
+			}
+		}
+	}
+	return written, nil
+}
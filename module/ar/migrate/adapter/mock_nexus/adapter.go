@@ -0,0 +1,48 @@
+// Package mock_nexus registers the MOCK_NEXUS registry type: the real Nexus
+// adapter pointed at an in-process server that answers the Nexus REST API,
+// repository content and Docker registry API from embedded fixtures, so a
+// migration config can be rehearsed without a Nexus instance.
+package mock_nexus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	adp "github.com/harness/harness-cli/module/ar/migrate/adapter"
+	_ "github.com/harness/harness-cli/module/ar/migrate/adapter/nexus"
+	"github.com/harness/harness-cli/module/ar/migrate/types"
+)
+
+func init() {
+	adapterType := types.MOCK_NEXUS
+	if err := adp.RegisterFactory(adapterType, new(factory)); err != nil {
+		return
+	}
+}
+
+var (
+	startOnce sync.Once
+	serverURL string
+	startErr  error
+)
+
+type factory struct{}
+
+// Create starts the fixture server on first use and returns a Nexus adapter
+// whose endpoint is the server, whatever endpoint the config names
+func (f factory) Create(ctx context.Context, config types.RegistryConfig) (adp.Adapter, error) {
+	startOnce.Do(func() {
+		serverURL, startErr = startServer()
+	})
+	if startErr != nil {
+		return nil, fmt.Errorf("start mock Nexus server: %w", startErr)
+	}
+
+	nexusFactory, err := adp.GetFactory(types.NEXUS)
+	if err != nil {
+		return nil, err
+	}
+	config.Endpoint = serverURL
+	return nexusFactory.Create(ctx, config)
+}
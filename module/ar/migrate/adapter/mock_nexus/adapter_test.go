@@ -0,0 +1,138 @@
+package mock_nexus
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	adp "github.com/harness/harness-cli/module/ar/migrate/adapter"
+	"github.com/harness/harness-cli/module/ar/migrate/tree"
+	"github.com/harness/harness-cli/module/ar/migrate/types"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+)
+
+func newTestAdapter(t *testing.T) adp.Adapter {
+	t.Helper()
+	a, err := adp.GetAdapter(context.Background(), types.RegistryConfig{
+		Type:        types.MOCK_NEXUS,
+		Endpoint:    "https://nexus.example.com",
+		Credentials: types.CredentialsConfig{Username: "admin", Password: "admin123"},
+	})
+	if err != nil {
+		t.Fatalf("GetAdapter: %v", err)
+	}
+	return a
+}
+
+// TestGroupRepositoryFiles asserts a group is expanded into its hosted members
+// through the mock repository API, skipping the proxy member
+func TestGroupRepositoryFiles(t *testing.T) {
+	a := newTestAdapter(t)
+	if ok, err := a.ValidateCredentials(); !ok || err != nil {
+		t.Fatalf("ValidateCredentials: %v", err)
+	}
+
+	files, err := a.GetFiles("maven-public")
+	if err != nil {
+		t.Fatalf("GetFiles: %v", err)
+	}
+	var uris []string
+	for _, f := range files {
+		if f.Size == 0 || f.SHA1 == "" {
+			t.Errorf("%s: size and checksum must be filled in, got %d %q", f.Uri, f.Size, f.SHA1)
+		}
+		uris = append(uris, f.Uri)
+	}
+	sort.Strings(uris)
+	if len(uris) != 6 || strings.Contains(strings.Join(uris, ","), "junit") {
+		t.Errorf("uris = %v, want the six maven-releases assets", uris)
+	}
+}
+
+// TestPackagesPerFormat asserts every format served by the mock enumerates
+func TestPackagesPerFormat(t *testing.T) {
+	a := newTestAdapter(t)
+	cases := []struct {
+		registry     string
+		artifactType types.ArtifactType
+		want         int
+	}{
+		{"maven-releases", types.MAVEN, 2},
+		{"npm-hosted", types.NPM, 2},
+		{"pypi-hosted", types.PYTHON, 2},
+		{"nuget-hosted", types.NUGET, 1},
+		{"helm-hosted", types.HELM_HTTP, 3},
+		{"raw-hosted", types.GENERIC, 2},
+		{"docker-hosted", types.DOCKER, 2},
+	}
+	for _, tc := range cases {
+		files, err := a.GetFiles(tc.registry)
+		if err != nil {
+			t.Fatalf("%s GetFiles: %v", tc.registry, err)
+		}
+		pkgs, err := a.GetPackages(tc.registry, tc.artifactType, tree.TransformToTree(files))
+		if err != nil {
+			t.Fatalf("%s GetPackages: %v", tc.registry, err)
+		}
+		if len(pkgs) != tc.want {
+			t.Errorf("%s: %d packages, want %d: %+v", tc.registry, len(pkgs), tc.want, pkgs)
+		}
+	}
+}
+
+func TestDownloadNPMPackage(t *testing.T) {
+	a := newTestAdapter(t)
+	body, _, err := a.DownloadFile("npm-hosted", "@acme/ui/-/ui-1.0.0.tgz")
+	if err != nil {
+		t.Fatalf("DownloadFile: %v", err)
+	}
+	defer body.Close()
+	gz, err := gzip.NewReader(body)
+	if err != nil {
+		t.Fatalf("gzip: %v", err)
+	}
+	hdr, err := tar.NewReader(gz).Next()
+	if err != nil || hdr.Name != "package/package.json" {
+		t.Fatalf("first entry = %v, %v", hdr, err)
+	}
+
+	packument, err := a.GetNPMPackument("npm-hosted", "@acme/ui")
+	if err != nil {
+		t.Fatalf("GetNPMPackument: %v", err)
+	}
+	if packument.DistTags["latest"] != "1.0.0" || packument.DistTags["next"] != "2.0.0-beta.1" {
+		t.Errorf("dist-tags = %v", packument.DistTags)
+	}
+	if len(packument.Versions) != 2 {
+		t.Errorf("versions = %v", packument.Versions)
+	}
+}
+
+// TestDockerImages asserts the image path resolves to the in-process registry
+// and that it serves the fixture tags
+func TestDockerImages(t *testing.T) {
+	a := newTestAdapter(t)
+	image, err := a.GetOCIImagePath("docker-hosted", "", "nginx")
+	if err != nil {
+		t.Fatalf("GetOCIImagePath: %v", err)
+	}
+	if !strings.HasSuffix(image, "/docker-hosted/nginx") {
+		t.Errorf("image = %s, want path-based routing", image)
+	}
+	tags, err := crane.ListTags(image)
+	if err != nil {
+		t.Fatalf("ListTags: %v", err)
+	}
+	sort.Strings(tags)
+	if strings.Join(tags, ",") != "1.25,latest" {
+		t.Errorf("tags = %v", tags)
+	}
+
+	if _, _, err := a.DownloadFile("docker-hosted", "missing"); err == nil {
+		t.Errorf("expected error for unknown asset")
+	}
+}
@@ -0,0 +1,35 @@
+// Command generate-mock-data writes the content of every MOCK_NEXUS fixture
+// asset (jars, POMs, npm/Helm tarballs, wheels, .nupkg files, raw files) into
+// testdata/binary/ so it is embedded by the mock server at compile time, where
+// it can be inspected or replaced by real artifacts.
+//
+// Usage:
+//
+//	go run ./module/ar/migrate/adapter/mock_nexus/cmd
+//
+// Or via Makefile:
+//
+//	make mock-init
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/harness/harness-cli/module/ar/migrate/adapter/mock_nexus"
+)
+
+var baseDir = filepath.Join("module", "ar", "migrate", "adapter", "mock_nexus", "testdata", "binary", "content")
+
+func main() {
+	written, err := mock_nexus.WriteContent(baseDir)
+	for _, f := range written {
+		fmt.Printf("  wrote %s\n", f)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("mock-init: done")
+}
@@ -0,0 +1,306 @@
+package mock_nexus
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/harness/harness-cli/module/ar/migrate/adapter/mockdata"
+	"github.com/harness/harness-cli/module/ar/migrate/adapter/nexus"
+	"github.com/harness/harness-cli/module/ar/migrate/types/npm"
+)
+
+//go:embed testdata
+var testdataFS embed.FS
+
+// binaryContentDir holds asset content written by cmd; when an asset has no
+// file there its content is generated in code
+const binaryContentDir = "testdata/binary/content"
+
+// repositoryFixture is a repository as listed by Nexus together with the
+// format-specific configuration returned by the repository management API
+type repositoryFixture struct {
+	nexus.NexusRepository
+	Docker *nexus.NexusDockerConfig `json:"docker,omitempty"`
+	Group  *nexus.NexusGroupConfig  `json:"group,omitempty"`
+}
+
+type server struct {
+	url        string
+	repos      []repositoryFixture
+	components map[string][]nexus.NexusComponent
+	content    map[string][]byte // keyed by "repository/path"
+	registry   http.Handler
+}
+
+// loadFixtures reads the embedded repositories and components and resolves
+// the content, size and checksums of every asset
+func loadFixtures() (*server, error) {
+	s := &server{
+		components: make(map[string][]nexus.NexusComponent),
+		content:    make(map[string][]byte),
+	}
+	data, err := testdataFS.ReadFile("testdata/repositories.json")
+	if err != nil {
+		return nil, fmt.Errorf("read repositories: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.repos); err != nil {
+		return nil, fmt.Errorf("parse repositories: %w", err)
+	}
+
+	for _, repo := range s.repos {
+		data, err := testdataFS.ReadFile("testdata/components/" + repo.Name + ".json")
+		if err != nil {
+			continue
+		}
+		var components []nexus.NexusComponent
+		if err := json.Unmarshal(data, &components); err != nil {
+			return nil, fmt.Errorf("parse components of %s: %w", repo.Name, err)
+		}
+		for i := range components {
+			c := &components[i]
+			c.Repository, c.Format = repo.Name, repo.Format
+			c.ID = fmt.Sprintf("%s:%s:%s:%s", repo.Name, c.Group, c.Name, c.Version)
+			for j := range c.Assets {
+				a := &c.Assets[j]
+				a.Repository, a.Format = repo.Name, repo.Format
+				a.ID = c.ID + ":" + a.Path
+				if repo.Format == "docker" {
+					continue
+				}
+				content := assetContent(repo.Format, *c, a.Path)
+				s.content[repo.Name+"/"+a.Path] = content
+				a.FileSize = int64(len(content))
+				a.Checksum = checksums(content)
+			}
+		}
+		s.components[repo.Name] = components
+	}
+	return s, nil
+}
+
+// assetContent returns the fixture file for an asset, or generates a package
+// archive valid enough for the destination to index
+func assetContent(format string, c nexus.NexusComponent, assetPath string) []byte {
+	if data, err := fs.ReadFile(testdataFS, binaryContentDir+"/"+c.Repository+"/"+assetPath); err == nil {
+		return data
+	}
+	return generateContent(format, c, assetPath)
+}
+
+func generateContent(format string, c nexus.NexusComponent, assetPath string) []byte {
+	switch {
+	case format == "maven2" && strings.HasSuffix(assetPath, ".pom"):
+		return mockdata.MavenPOM(c.Group, c.Name, c.Version, "jar")
+	case format == "maven2" && strings.HasSuffix(assetPath, ".jar"):
+		return mockdata.JAR(c.Name, c.Version)
+	case format == "npm":
+		return mockdata.NPMPackageTgz(npmName(c), c.Version, "Mock NPM package for migration testing")
+	case format == "pypi" && strings.HasSuffix(assetPath, ".whl"):
+		return mockdata.PythonWheel(c.Name, c.Version)
+	case format == "pypi":
+		return mockdata.PythonSdist(c.Name, c.Version)
+	case format == "nuget":
+		return mockdata.NuGetPackage(c.Name, c.Version)
+	case format == "helm":
+		return mockdata.HelmChartTgz(c.Name, c.Version)
+	default:
+		return []byte(fmt.Sprintf("mock content of %s/%s\n", c.Repository, assetPath))
+	}
+}
+
+// npmName is the npm package name of a component; Nexus stores the scope,
+// without "@", as the component group
+func npmName(c nexus.NexusComponent) string {
+	if c.Group != "" {
+		return "@" + c.Group + "/" + c.Name
+	}
+	return c.Name
+}
+
+func checksums(content []byte) map[string]string {
+	sum1 := sha1.Sum(content)
+	sum256 := sha256.Sum256(content)
+	sumMD5 := md5.Sum(content)
+	return map[string]string{
+		"sha1":   hex.EncodeToString(sum1[:]),
+		"sha256": hex.EncodeToString(sum256[:]),
+		"md5":    hex.EncodeToString(sumMD5[:]),
+	}
+}
+
+// startServer serves the fixtures and pushes an image for every Docker
+// component, nested under the repository name as Nexus path-based routing does
+func startServer() (string, error) {
+	s, err := loadFixtures()
+	if err != nil {
+		return "", err
+	}
+	s.registry = mockdata.NewRegistry()
+	s.url, err = mockdata.Serve(s.handler())
+	if err != nil {
+		return "", err
+	}
+
+	host := strings.TrimPrefix(s.url, "http://")
+	for _, repo := range s.repos {
+		if repo.Format != "docker" {
+			continue
+		}
+		for _, c := range s.components[repo.Name] {
+			img, err := mockdata.DockerImage(c.Name, c.Version)
+			if err != nil {
+				return "", err
+			}
+			if _, err := mockdata.PushImage(host, repo.Name+"/"+c.Name, c.Version, img); err != nil {
+				return "", err
+			}
+		}
+	}
+	return s.url, nil
+}
+
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/v2/", s.registry)
+	mux.HandleFunc("GET /service/rest/v1/repositories", s.listRepositories)
+	mux.HandleFunc("GET /service/rest/v1/repositories/{format}/{type}/{name}", s.getRepositoryDetails)
+	mux.HandleFunc("GET /service/rest/v1/search", s.search)
+	mux.HandleFunc("GET /repository/{repository}/{path...}", s.getAsset)
+	return mux
+}
+
+func (s *server) repository(name string) (repositoryFixture, bool) {
+	for _, repo := range s.repos {
+		if repo.Name == name {
+			return repo, true
+		}
+	}
+	return repositoryFixture{}, false
+}
+
+func (s *server) listRepositories(w http.ResponseWriter, _ *http.Request) {
+	repos := make([]nexus.NexusRepository, 0, len(s.repos))
+	for _, repo := range s.repos {
+		r := repo.NexusRepository
+		r.URL = s.url + "/repository/" + r.Name
+		repos = append(repos, r)
+	}
+	writeJSON(w, repos)
+}
+
+func (s *server) getRepositoryDetails(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.repository(r.PathValue("name"))
+	if !ok || repo.Type != r.PathValue("type") {
+		http.NotFound(w, r)
+		return
+	}
+	writeJSON(w, nexus.NexusRepositoryDetails{Name: repo.Name, Docker: repo.Docker, Group: repo.Group})
+}
+
+// search returns every component of the repository in a single page; Nexus
+// answers an unknown repository with an empty result
+func (s *server) search(w http.ResponseWriter, r *http.Request) {
+	items := s.components[r.URL.Query().Get("repository")]
+	if items == nil {
+		items = []nexus.NexusComponent{}
+	}
+	writeJSON(w, nexus.NexusSearchResponse{Items: items})
+}
+
+// getAsset serves asset content and, for npm repositories, the packument of a
+// package requested by its (escaped) name
+func (s *server) getAsset(w http.ResponseWriter, r *http.Request) {
+	repoName, assetPath := r.PathValue("repository"), r.PathValue("path")
+	if content, ok := s.content[repoName+"/"+assetPath]; ok {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write(content)
+		return
+	}
+	if repo, ok := s.repository(repoName); ok && repo.Format == "npm" {
+		name, err := url.PathUnescape(assetPath)
+		if err == nil {
+			if packument := s.packument(repoName, name); packument != nil {
+				writeJSON(w, packument)
+				return
+			}
+		}
+	}
+	http.NotFound(w, r)
+}
+
+// packument builds the npm metadata of a package from its components: the
+// last stable version is "latest" and the last prerelease is "next"
+func (s *server) packument(repoName, name string) *npm.PackageMetadata {
+	var packument *npm.PackageMetadata
+	for _, c := range s.components[repoName] {
+		if npmName(c) != name {
+			continue
+		}
+		if packument == nil {
+			packument = &npm.PackageMetadata{
+				Name:        name,
+				Description: "Mock NPM package for migration testing",
+				DistTags:    map[string]string{},
+				Versions:    map[string]*npm.PackageMetadataVersion{},
+			}
+		}
+		v := &npm.PackageMetadataVersion{Name: name, Version: c.Version}
+		if len(c.Assets) > 0 {
+			v.Dist = npm.PackageDistribution{
+				Tarball: s.url + "/repository/" + repoName + "/" + c.Assets[0].Path,
+				Shasum:  c.Assets[0].Checksum["sha1"],
+			}
+		}
+		packument.Versions[c.Version] = v
+		if strings.Contains(c.Version, "-") {
+			packument.DistTags["next"] = c.Version
+		} else {
+			packument.DistTags["latest"] = c.Version
+		}
+	}
+	return packument
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// WriteContent generates the content of every fixture asset into
+// <dir>/<repository>/<path>, where the server picks it up once embedded
+func WriteContent(dir string) ([]string, error) {
+	s, err := loadFixtures()
+	if err != nil {
+		return nil, err
+	}
+	var written []string
+	for _, repo := range s.repos {
+		for _, c := range s.components[repo.Name] {
+			for _, a := range c.Assets {
+				if repo.Format == "docker" {
+					continue
+				}
+				target := filepath.Join(dir, repo.Name, filepath.FromSlash(a.Path))
+				if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+					return written, err
+				}
+				if err := os.WriteFile(target, generateContent(repo.Format, c, a.Path), 0644); err != nil {
+					return written, err
+				}
+				written = append(written, target)
+			}
+		}
+	}
+	return written, nil
+}
@@ -0,0 +1,111 @@
+// Package mockdata holds the package archives, OCI images and in-process
+// server shared by the mock registry adapters (MOCK_NEXUS, MOCK_HARBOR), so
+// that fixtures are valid enough for the destination to index them.
+package mockdata
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"strings"
+)
+
+// NPMPackageTgz returns an npm tarball with a package/package.json
+func NPMPackageTgz(name, version, description string) []byte {
+	packageJSON := fmt.Sprintf(`{
+  "name": "%s",
+  "version": "%s",
+  "description": "%s",
+  "main": "index.js",
+  "license": "MIT"
+}`, name, version, description)
+	return tarGz(map[string]string{"package/package.json": packageJSON})
+}
+
+// HelmChartTgz returns a chart archive with a <name>/Chart.yaml
+func HelmChartTgz(name, version string) []byte {
+	chartYaml := fmt.Sprintf(
+		"apiVersion: v2\nname: %s\nversion: %s\ndescription: Mock Helm chart for migration testing\ntype: application\n",
+		name, version)
+	return tarGz(map[string]string{name + "/Chart.yaml": chartYaml})
+}
+
+// NuGetPackage returns a .nupkg with a <id>.nuspec
+func NuGetPackage(id, version string) []byte {
+	nuspec := fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
+<package xmlns="http://schemas.microsoft.com/packaging/2013/05/nuspec.xsd">
+  <metadata>
+    <id>%s</id>
+    <version>%s</version>
+    <authors>test</authors>
+    <description>Mock NuGet package for migration testing</description>
+  </metadata>
+</package>`, id, version)
+	return zipFile(map[string]string{id + ".nuspec": nuspec})
+}
+
+// PythonWheel returns a wheel with a <name>-<version>.dist-info/METADATA
+func PythonWheel(name, version string) []byte {
+	distInfo := fmt.Sprintf("%s-%s.dist-info/", strings.ReplaceAll(name, "-", "_"), version)
+	metadata := fmt.Sprintf("Metadata-Version: 2.1\nName: %s\nVersion: %s\nSummary: Mock Python package for migration testing\n",
+		name, version)
+	return zipFile(map[string]string{
+		distInfo + "METADATA": metadata,
+		distInfo + "WHEEL":    "Wheel-Version: 1.0\nGenerator: mock\nRoot-Is-Purelib: true\nTag: py3-none-any\n",
+	})
+}
+
+// PythonSdist returns a source distribution with a <name>-<version>/PKG-INFO
+func PythonSdist(name, version string) []byte {
+	pkgInfo := fmt.Sprintf("Metadata-Version: 2.1\nName: %s\nVersion: %s\nSummary: Mock Python package for migration testing\n",
+		name, version)
+	return tarGz(map[string]string{fmt.Sprintf("%s-%s/PKG-INFO", name, version): pkgInfo})
+}
+
+// MavenPOM returns a minimal POM for the given coordinates
+func MavenPOM(groupID, artifactID, version, packaging string) []byte {
+	return []byte(fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <modelVersion>4.0.0</modelVersion>
+  <groupId>%s</groupId>
+  <artifactId>%s</artifactId>
+  <version>%s</version>
+  <packaging>%s</packaging>
+</project>
+`, groupID, artifactID, version, packaging))
+}
+
+// JAR returns a jar holding only a manifest
+func JAR(artifactID, version string) []byte {
+	return zipFile(map[string]string{
+		"META-INF/MANIFEST.MF": fmt.Sprintf(
+			"Manifest-Version: 1.0\nImplementation-Title: %s\nImplementation-Version: %s\n", artifactID, version),
+	})
+}
+
+func tarGz(files map[string]string) []byte {
+	var buf bytes.Buffer
+	gzWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzWriter)
+	for _, name := range sortedNames(files) {
+		content := files[name]
+		_ = tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))})
+		_, _ = tarWriter.Write([]byte(content))
+	}
+	_ = tarWriter.Close()
+	_ = gzWriter.Close()
+	return buf.Bytes()
+}
+
+func zipFile(files map[string]string) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, name := range sortedNames(files) {
+		f, _ := w.Create(name)
+		_, _ = f.Write([]byte(files[name]))
+	}
+	_ = w.Close()
+	return buf.Bytes()
+}
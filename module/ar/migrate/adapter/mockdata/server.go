@@ -0,0 +1,86 @@
+package mockdata
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"sort"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	ggcrtypes "github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+const (
+	helmConfigMediaType ggcrtypes.MediaType = "application/vnd.cncf.helm.config.v1+json"
+	helmChartMediaType  ggcrtypes.MediaType = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+)
+
+// Serve starts an HTTP server for handler on a random loopback port and
+// returns its base URL. The server lives as long as the process: mock
+// adapters are created once per migration run. Loopback registries are spoken
+// to over plain HTTP by crane, so OCI copies need no insecure flag.
+func Serve(handler http.Handler) (string, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", fmt.Errorf("listen: %w", err)
+	}
+	go func() { _ = http.Serve(l, handler) }()
+	return "http://" + l.Addr().String(), nil
+}
+
+// NewRegistry returns an in-memory OCI distribution registry handler to mount
+// at /v2/
+func NewRegistry() http.Handler {
+	return registry.New(registry.Logger(log.New(io.Discard, "", 0)))
+}
+
+// DockerImage returns a deterministic single-layer image identifying repo:tag
+func DockerImage(repo, tag string) (v1.Image, error) {
+	return crane.Image(map[string][]byte{
+		"etc/mock-release": []byte(fmt.Sprintf("%s:%s\n", repo, tag)),
+	})
+}
+
+// HelmChartImage returns an OCI Helm chart artifact wrapping HelmChartTgz
+func HelmChartImage(name, version string) (v1.Image, error) {
+	img := mutate.MediaType(empty.Image, ggcrtypes.OCIManifestSchema1)
+	img = mutate.ConfigMediaType(img, helmConfigMediaType)
+	return mutate.Append(img, mutate.Addendum{
+		Layer:     static.NewLayer(HelmChartTgz(name, version), helmChartMediaType),
+		MediaType: helmChartMediaType,
+	})
+}
+
+// PushImage writes img to <host>/<repo>:<tag> and returns its digest
+func PushImage(host, repo, tag string, img v1.Image) (string, error) {
+	ref, err := name.ParseReference(fmt.Sprintf("%s/%s:%s", host, repo, tag))
+	if err != nil {
+		return "", fmt.Errorf("parse reference: %w", err)
+	}
+	if err := remote.Write(ref, img); err != nil {
+		return "", fmt.Errorf("push %s: %w", ref, err)
+	}
+	digest, err := img.Digest()
+	if err != nil {
+		return "", fmt.Errorf("digest %s: %w", ref, err)
+	}
+	return digest.String(), nil
+}
+
+func sortedNames(m map[string]string) []string {
+	names := make([]string, 0, len(m))
+	for n := range m {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -2,14 +2,18 @@ package nexus
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 
 	adp "github.com/harness/harness-cli/module/ar/migrate/adapter"
 	"github.com/harness/harness-cli/module/ar/migrate/types"
+	"github.com/harness/harness-cli/module/ar/migrate/types/npm"
 	"github.com/harness/harness-cli/module/ar/migrate/util"
 
 	"github.com/google/go-containerregistry/pkg/authn"
@@ -41,6 +45,11 @@ func (f factory) Create(ctx context.Context, config types.RegistryConfig) (adp.A
 type adapter struct {
 	client *client
 	reg    types.RegistryConfig
+
+	// reposMu guards the lazily loaded repository index and group expansions
+	reposMu sync.Mutex
+	repos   map[string]NexusRepository
+	members map[string][]string
 }
 
 func (a *adapter) SearchFiles(registry string) ([]types.SearchedFile, error) {
@@ -102,52 +111,42 @@ func (a *adapter) GetPackages(registry string, artifactType types.ArtifactType,
 	error,
 ) {
 	var packages []types.Package
-	continuationToken := ""
 
 	pkgNames := make(map[string][]string)
 	assetToVersion := make(map[string]string)
-	for {
-		searchResponse, err := a.client.searchComponents(registry, continuationToken)
-		if err != nil {
-			return nil, fmt.Errorf("failed to search components in registry %s: %w", registry, err)
-		}
-
-		for _, component := range searchResponse.Items {
-			if artifactType == types.HELM_LEGACY || artifactType == types.HELM_HTTP {
-				for _, asset := range component.Assets {
-					if asset.Format != "helm" {
-						continue
-					}
-					// For HELM_HTTP, enumerate only chart archives. Whether Nexus
-					// tags the provenance sidecar (.tgz.prov) as format "helm" is
-					// version/environment-dependent, so we filter it out here
-					// rather than relying on the tag — the .prov is migrated as a
-					// sibling of the chart by migrateHelmHTTPProv, never as its own
-					// package. HELM_LEGACY keeps its original (unfiltered) behavior.
-					if artifactType == types.HELM_HTTP && !util.IsHelmChartArchive(asset.Path) {
-						continue
-					}
-					if _, ok := pkgNames[component.Name]; !ok {
-						pkgNames[component.Name] = []string{}
-					}
-					pkgNames[component.Name] = append(pkgNames[component.Name], asset.Path)
-					assetToVersion[asset.Path] = component.Version
+	err := a.forEachComponent(registry, func(component NexusComponent) {
+		if artifactType == types.HELM_LEGACY || artifactType == types.HELM_HTTP {
+			for _, asset := range component.Assets {
+				if asset.Format != "helm" {
+					continue
 				}
-			} else {
-				var pkgName string
-				if component.Group != "" {
-					pkgName = fmt.Sprintf("%s/%s", component.Group, component.Name)
-				} else {
-					pkgName = component.Name
+				// For HELM_HTTP, enumerate only chart archives. Whether Nexus
+				// tags the provenance sidecar (.tgz.prov) as format "helm" is
+				// version/environment-dependent, so we filter it out here
+				// rather than relying on the tag — the .prov is migrated as a
+				// sibling of the chart by migrateHelmHTTPProv, never as its own
+				// package. HELM_LEGACY keeps its original (unfiltered) behavior.
+				if artifactType == types.HELM_HTTP && !util.IsHelmChartArchive(asset.Path) {
+					continue
+				}
+				if _, ok := pkgNames[component.Name]; !ok {
+					pkgNames[component.Name] = []string{}
 				}
-				pkgNames[pkgName] = []string{}
+				pkgNames[component.Name] = append(pkgNames[component.Name], asset.Path)
+				assetToVersion[asset.Path] = component.Version
 			}
+		} else {
+			var pkgName string
+			if component.Group != "" {
+				pkgName = fmt.Sprintf("%s/%s", component.Group, component.Name)
+			} else {
+				pkgName = component.Name
+			}
+			pkgNames[pkgName] = []string{}
 		}
-
-		if searchResponse.ContinuationToken == "" {
-			break
-		}
-		continuationToken = searchResponse.ContinuationToken
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search components in registry %s: %w", registry, err)
 	}
 
 	for pkgName, urls := range pkgNames {
@@ -224,71 +223,208 @@ func (a *adapter) GetVersions(
 	node *types.TreeNode, registry, pkg string, artifactType types.ArtifactType,
 ) ([]types.Version, error) {
 	var versions []types.Version
-	continuationToken := ""
 
-	for {
-		searchResponse, err := a.client.searchComponents(registry, continuationToken)
-		if err != nil {
-			return nil, fmt.Errorf("failed to search components: %w", err)
-		}
-
-		for _, component := range searchResponse.Items {
-			if artifactType == types.MAVEN {
-				if component.Group+"/"+component.Name == pkg {
-					version := types.Version{
-						Registry: registry,
-						Pkg:      pkg,
-						Name:     component.Version,
-						Path:     fmt.Sprintf("%s", component.Version),
-					}
-					// Calculate total size from all assets
-					totalSize := 0
-					for _, asset := range component.Assets {
-						totalSize += int(asset.FileSize)
-					}
-					version.Size = totalSize
-
-					versions = append(versions, version)
+	err := a.forEachComponent(registry, func(component NexusComponent) {
+		if artifactType == types.MAVEN {
+			if component.Group+"/"+component.Name == pkg {
+				version := types.Version{
+					Registry: registry,
+					Pkg:      pkg,
+					Name:     component.Version,
+					Path:     fmt.Sprintf("%s", component.Version),
 				}
-			} else {
-				if component.Name == pkg || strings.Contains(component.Name, pkg) {
-					version := types.Version{
-						Registry: registry,
-						Pkg:      pkg,
-						Name:     component.Version,
-						Path:     fmt.Sprintf("%s", component.Version),
-					}
-
-					// Calculate total size from all assets
-					totalSize := 0
-					for _, asset := range component.Assets {
-						totalSize += int(asset.FileSize)
-					}
-					version.Size = totalSize
-
-					versions = append(versions, version)
+				// Calculate total size from all assets
+				totalSize := 0
+				for _, asset := range component.Assets {
+					totalSize += int(asset.FileSize)
 				}
+				version.Size = totalSize
+
+				versions = append(versions, version)
 			}
-		}
+		} else {
+			if component.Name == pkg || strings.Contains(component.Name, pkg) {
+				version := types.Version{
+					Registry: registry,
+					Pkg:      pkg,
+					Name:     component.Version,
+					Path:     fmt.Sprintf("%s", component.Version),
+				}
+
+				// Calculate total size from all assets
+				totalSize := 0
+				for _, asset := range component.Assets {
+					totalSize += int(asset.FileSize)
+				}
+				version.Size = totalSize
 
-		if searchResponse.ContinuationToken == "" {
-			break
+				versions = append(versions, version)
+			}
 		}
-		continuationToken = searchResponse.ContinuationToken
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search components: %w", err)
 	}
 
 	return versions, nil
 }
 
+// GetFiles lists the files of a hosted repository, or of every hosted member of
+// a group repository. A path served by more than one member is listed once,
+// from the member Nexus would resolve it from.
 func (a *adapter) GetFiles(registry string) ([]types.File, error) {
-	repository, err := a.client.getRepository(registry)
+	members, err := a.memberRepositories(registry)
 	if err != nil {
-		return nil, fmt.Errorf("get repository: %w", err)
+		return nil, err
 	}
-	if repository.Type != "hosted" {
-		return nil, fmt.Errorf("repository %s is not a hosted repository", registry)
+
+	var files []types.File
+	seen := make(map[string]bool)
+	for _, member := range members {
+		repo, err := a.repository(member)
+		if err != nil {
+			return nil, err
+		}
+		memberFiles, err := a.client.getFiles(member, repo.Format)
+		if err != nil {
+			return nil, fmt.Errorf("get files of %s: %w", member, err)
+		}
+		for _, f := range memberFiles {
+			if seen[f.Uri] {
+				continue
+			}
+			seen[f.Uri] = true
+			files = append(files, f)
+		}
 	}
-	return a.client.getFiles(registry, repository.Format)
+	return files, nil
+}
+
+// repository returns a repository from the lazily loaded repository index
+func (a *adapter) repository(name string) (NexusRepository, error) {
+	a.reposMu.Lock()
+	defer a.reposMu.Unlock()
+	return a.repositoryLocked(name)
+}
+
+func (a *adapter) repositoryLocked(name string) (NexusRepository, error) {
+	if a.repos == nil {
+		repositories, err := a.client.getRepositories()
+		if err != nil {
+			return NexusRepository{}, fmt.Errorf("get repositories: %w", err)
+		}
+		a.repos = make(map[string]NexusRepository, len(repositories))
+		for _, repo := range repositories {
+			a.repos[repo.Name] = repo
+		}
+	}
+	repo, ok := a.repos[name]
+	if !ok {
+		return NexusRepository{}, fmt.Errorf("repository %s not found", name)
+	}
+	return repo, nil
+}
+
+// memberRepositories expands a repository into the hosted repositories that
+// hold its content. A hosted repository expands to itself; a group expands
+// depth-first in member order, so nested groups are flattened and a hosted
+// repository reachable through several groups is listed once. Proxy members
+// only cache remote content and are skipped.
+func (a *adapter) memberRepositories(registry string) ([]string, error) {
+	a.reposMu.Lock()
+	defer a.reposMu.Unlock()
+
+	if members, ok := a.members[registry]; ok {
+		return members, nil
+	}
+
+	repo, err := a.repositoryLocked(registry)
+	if err != nil {
+		return nil, err
+	}
+	if repo.Type != "hosted" && repo.Type != "group" {
+		return nil, fmt.Errorf("repository %s is not a hosted or group repository", registry)
+	}
+
+	var members []string
+	visited := make(map[string]bool)
+	var expand func(repo NexusRepository) error
+	expand = func(repo NexusRepository) error {
+		if visited[repo.Name] {
+			return nil
+		}
+		visited[repo.Name] = true
+
+		switch repo.Type {
+		case "hosted":
+			members = append(members, repo.Name)
+		case "group":
+			names, err := a.client.getGroupMembers(repo)
+			if err != nil {
+				return fmt.Errorf("get members of group %s: %w", repo.Name, err)
+			}
+			for _, name := range names {
+				member, err := a.repositoryLocked(name)
+				if err != nil {
+					return err
+				}
+				if err := expand(member); err != nil {
+					return err
+				}
+			}
+		default:
+			log.Debug().Msgf("Skipping %s member %s of group %s", repo.Type, repo.Name, registry)
+		}
+		return nil
+	}
+	if err := expand(repo); err != nil {
+		return nil, err
+	}
+	if repo.Type == "group" {
+		log.Info().Msgf("Expanded group repository %s into hosted members %v", registry, members)
+	}
+
+	if a.members == nil {
+		a.members = make(map[string][]string)
+	}
+	a.members[registry] = members
+	return members, nil
+}
+
+// forEachComponent calls fn for every component of a repository, searching
+// each hosted member of a group. A component (group, name and version) present
+// in several members is reported once, from the first member that has it.
+func (a *adapter) forEachComponent(registry string, fn func(NexusComponent)) error {
+	members, err := a.memberRepositories(registry)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	for _, member := range members {
+		continuationToken := ""
+		for {
+			searchResponse, err := a.client.searchComponents(member, continuationToken)
+			if err != nil {
+				return err
+			}
+
+			for _, component := range searchResponse.Items {
+				key := component.Group + "/" + component.Name + "/" + component.Version
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				fn(component)
+			}
+
+			if searchResponse.ContinuationToken == "" {
+				break
+			}
+			continuationToken = searchResponse.ContinuationToken
+		}
+	}
+	return nil
 }
 
 func (a *adapter) DownloadFile(registry string, uri string) (io.ReadCloser, http.Header, error) {
@@ -296,40 +432,55 @@ func (a *adapter) DownloadFile(registry string, uri string) (io.ReadCloser, http
 	return a.client.getAsset(downloadURL)
 }
 
+// GetOCIImagePath builds the image reference for a Docker repository. Nexus
+// serves a Docker repository either on its own connector port, on a subdomain
+// of the Nexus host, or path-based on the main connector
+// (<host>/<repository>/<image>); the routing is discovered from the repository
+// configuration. Group repositories are addressed through the group itself.
 func (a *adapter) GetOCIImagePath(registry string, _ string, image string) (string, error) {
-	var port int
-	var err error
-
-	// First, try to discover the port via API
-	port, err = a.client.getDockerPort(registry, a.GetConfig().Insecure)
+	repo, err := a.repository(registry)
+	if err != nil {
+		return "", fmt.Errorf("failed to get repository: %w", err)
+	}
+	docker, err := a.client.getDockerConfig(repo)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to discover Docker port via API, falling back to configuration")
-		return "", fmt.Errorf("failed to get Docker port: %w", err)
+		log.Error().Err(err).Msg("Failed to discover Docker routing via API")
+		return "", fmt.Errorf("failed to get Docker configuration: %w", err)
 	}
 
-	host, err := nexusDockerHost(a.reg.Endpoint, port)
+	host, pathPrefix, err := nexusDockerHost(a.reg.Endpoint, registry, docker, a.GetConfig().Insecure)
 	if err != nil {
 		return "", fmt.Errorf("failed to get OCI host: %w", err)
 	}
-	return util.GenOCIImagePath(host, image), nil
+	return util.GenOCIImagePath(host, append(pathPrefix, image)...), nil
 }
 
-func nexusDockerHost(endpoint string, port int) (string, error) {
+// nexusDockerHost returns the registry host serving a Docker repository and the
+// path prefix images are nested under. A dedicated connector port takes
+// precedence, then a subdomain connector; otherwise the repository is reached
+// by path-based routing on the Nexus endpoint itself.
+func nexusDockerHost(endpoint, registry string, docker *NexusDockerConfig, insecure bool) (string, []string, error) {
 	parsedURL, err := url.Parse(endpoint)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse endpoint URL: %w", err)
+		return "", nil, fmt.Errorf("failed to parse endpoint URL: %w", err)
 	}
 
-	// Replace or add the port to the host
 	host := parsedURL.Hostname()
 	if host == "" {
-		return "", fmt.Errorf("invalid endpoint URL: no hostname found")
+		return "", nil, fmt.Errorf("invalid endpoint URL: no hostname found")
 	}
 
-	// Create new host with the specified port
-	newHost := fmt.Sprintf("%s:%d", host, port)
-
-	return newHost, nil
+	if port := dockerPort(docker, insecure); port > 0 {
+		return fmt.Sprintf("%s:%d", host, port), nil, nil
+	}
+	if docker.Subdomain != "" {
+		subdomainHost := docker.Subdomain + "." + host
+		if port := parsedURL.Port(); port != "" {
+			subdomainHost = net.JoinHostPort(subdomainHost, port)
+		}
+		return subdomainHost, nil, nil
+	}
+	return parsedURL.Host, []string{registry}, nil
 }
 
 func (a *adapter) UploadFile(
@@ -432,6 +583,37 @@ func (a *adapter) BuildExistingIndex(
 	return nil, nil
 }
 
+func (a *adapter) GetVersionMetadata(
+	_ string,
+	_ types.Package,
+	_ types.ArtifactType,
+) (map[string]map[string]string, error) {
+	return nil, nil
+}
+
+func (a *adapter) SetVersionMetadata(_ context.Context, _, _, _ string, _ map[string]string) error {
+	return fmt.Errorf("SetVersionMetadata not implemented for NEXUS")
+}
+
+// GetNPMPackument fetches the packument from the repository's npm endpoint
+func (a *adapter) GetNPMPackument(registry string, name string) (*npm.PackageMetadata, error) {
+	body, _, err := a.client.getAsset(a.client.buildDownloadURL(registry, util.EscapeNPMPackageName(name)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get packument of %s: %w", name, err)
+	}
+	defer body.Close()
+
+	var packument npm.PackageMetadata
+	if err := json.NewDecoder(body).Decode(&packument); err != nil {
+		return nil, fmt.Errorf("failed to decode packument of %s: %w", name, err)
+	}
+	return &packument, nil
+}
+
+func (a *adapter) UpdateNPMPackument(_ string, _ string, _ *npm.PackageMetadata) error {
+	return fmt.Errorf("UpdateNPMPackument not implemented for NEXUS")
+}
+
 func (a *adapter) constructFilePath(pkg, version, fileName string, artifactType types.ArtifactType) string {
 	switch artifactType {
 	case types.MAVEN:
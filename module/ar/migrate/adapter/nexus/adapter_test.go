@@ -7,15 +7,25 @@ import (
 	"testing"
 
 	"github.com/harness/harness-cli/module/ar/migrate/types"
+	"github.com/harness/harness-cli/module/ar/migrate/util"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
 )
 
 // helmSearchServer spins up an httptest server that answers Nexus' component
-// search endpoint with the supplied response. It returns the server and the
-// number of times the search endpoint was hit (to confirm pagination behavior).
+// search endpoint with the supplied response, and lists "helm-hosted" as a
+// hosted repository. It returns the server and the number of times the search
+// endpoint was hit (to confirm pagination behavior).
 func helmSearchServer(t *testing.T, resp NexusSearchResponse) (*httptest.Server, *int) {
 	t.Helper()
 	hits := 0
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/service/rest/v1/repositories" {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode([]NexusRepository{{Name: "helm-hosted", Format: "helm", Type: "hosted"}})
+			return
+		}
 		if r.URL.Path != "/service/rest/v1/search" {
 			t.Errorf("unexpected request path: %s", r.URL.Path)
 			w.WriteHeader(http.StatusNotFound)
@@ -156,3 +166,147 @@ func TestGetPackagesHelmLegacyNexusUnfiltered(t *testing.T) {
 		t.Errorf("HELM_LEGACY should include both chart and prov, got URLs: %v", urls)
 	}
 }
+
+// groupServer serves a maven group "maven-public" whose members are a hosted
+// repository, a proxy, and a nested group that repeats the hosted repository
+// and adds a second one. Both hosted repositories carry the same component.
+func groupServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	writeJSON := func(w http.ResponseWriter, v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/service/rest/v1/repositories":
+			writeJSON(w, []NexusRepository{
+				{Name: "maven-public", Format: "maven2", Type: "group"},
+				{Name: "maven-nested", Format: "maven2", Type: "group"},
+				{Name: "maven-releases", Format: "maven2", Type: "hosted"},
+				{Name: "maven-snapshots", Format: "maven2", Type: "hosted"},
+				{Name: "maven-central", Format: "maven2", Type: "proxy"},
+			})
+		case "/service/rest/v1/repositories/maven/group/maven-public":
+			writeJSON(w, NexusRepositoryDetails{Name: "maven-public", Group: &NexusGroupConfig{
+				MemberNames: []string{"maven-releases", "maven-central", "maven-nested"},
+			}})
+		case "/service/rest/v1/repositories/maven/group/maven-nested":
+			writeJSON(w, NexusRepositoryDetails{Name: "maven-nested", Group: &NexusGroupConfig{
+				MemberNames: []string{"maven-releases", "maven-snapshots"},
+			}})
+		case "/service/rest/v1/search":
+			shared := NexusComponent{Group: "com.acme", Name: "app", Version: "1.0",
+				Assets: []NexusAsset{{Path: "com/acme/app/1.0/app-1.0.jar", FileSize: 4}}}
+			switch repo := r.URL.Query().Get("repository"); repo {
+			case "maven-releases":
+				writeJSON(w, NexusSearchResponse{Items: []NexusComponent{shared}})
+			case "maven-snapshots":
+				writeJSON(w, NexusSearchResponse{Items: []NexusComponent{shared, {
+					Group: "com.acme", Name: "app", Version: "1.1-SNAPSHOT",
+					Assets: []NexusAsset{{Path: "com/acme/app/1.1-SNAPSHOT/app-1.1-SNAPSHOT.jar", FileSize: 5}},
+				}}})
+			default:
+				t.Errorf("unexpected search of repository %q", repo)
+				writeJSON(w, NexusSearchResponse{})
+			}
+		default:
+			t.Errorf("unexpected request path: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// TestGroupExpansion asserts that a group is flattened into its hosted members
+// (nested groups expanded, proxies skipped, repeats visited once) and that
+// components and files served by several members are de-duplicated.
+func TestGroupExpansion(t *testing.T) {
+	a := newHelmAdapter(t, groupServer(t).URL)
+
+	members, err := a.memberRepositories("maven-public")
+	if err != nil {
+		t.Fatalf("memberRepositories: %v", err)
+	}
+	if len(members) != 2 || members[0] != "maven-releases" || members[1] != "maven-snapshots" {
+		t.Fatalf("members = %v, want [maven-releases maven-snapshots]", members)
+	}
+
+	files, err := a.GetFiles("maven-public")
+	if err != nil {
+		t.Fatalf("GetFiles: %v", err)
+	}
+	if len(files) != 2 {
+		t.Errorf("files = %+v, want 2 de-duplicated files", files)
+	}
+
+	pkgs, err := a.GetPackages("maven-public", types.MAVEN, nil)
+	if err != nil {
+		t.Fatalf("GetPackages: %v", err)
+	}
+	if len(pkgs) != 1 || pkgs[0].Name != "com.acme/app" {
+		t.Fatalf("packages = %+v", pkgs)
+	}
+
+	versions, err := a.GetVersions(pkgs[0], nil, "maven-public", pkgs[0].Name, types.MAVEN)
+	if err != nil {
+		t.Fatalf("GetVersions: %v", err)
+	}
+	if len(versions) != 2 {
+		t.Errorf("versions = %+v, want 1.0 once and 1.1-SNAPSHOT", versions)
+	}
+
+	if _, err := a.GetFiles("maven-central"); err == nil {
+		t.Errorf("expected proxy repository to be rejected")
+	}
+}
+
+func TestNexusDockerHost(t *testing.T) {
+	cases := []struct {
+		name     string
+		endpoint string
+		docker   NexusDockerConfig
+		insecure bool
+		want     string
+	}{
+		{"https connector", "https://nexus.acme.io", NexusDockerConfig{HttpPort: 8082, HttpsPort: 8443}, false,
+			"nexus.acme.io:8443/team/app"},
+		{"insecure prefers http", "https://nexus.acme.io", NexusDockerConfig{HttpPort: 8082, HttpsPort: 8443}, true,
+			"nexus.acme.io:8082/team/app"},
+		{"subdomain", "https://nexus.acme.io:8443", NexusDockerConfig{Subdomain: "docker"}, false,
+			"docker.nexus.acme.io:8443/team/app"},
+		{"path based", "https://nexus.acme.io:8443/", NexusDockerConfig{}, false,
+			"nexus.acme.io:8443/docker-hosted/team/app"},
+	}
+	for _, tc := range cases {
+		host, prefix, err := nexusDockerHost(tc.endpoint, "docker-hosted", &tc.docker, tc.insecure)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if got := util.GenOCIImagePath(host, append(prefix, "team/app")...); got != tc.want {
+			t.Errorf("%s: image path = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestKeychainMatchesSubdomain(t *testing.T) {
+	kc := NewNexusKeychain("user", "pass", "nexus.acme.io")
+	for host, wantAuth := range map[string]bool{
+		"nexus.acme.io:8443/app":   true,
+		"docker.nexus.acme.io/app": true,
+		"evil-nexus.acme.io/app":   false,
+		"registry.example.com/app": false,
+	} {
+		ref, err := name.ParseReference(host)
+		if err != nil {
+			t.Fatalf("parse %s: %v", host, err)
+		}
+		auth, err := kc.Resolve(ref.Context())
+		if err != nil {
+			t.Fatalf("resolve %s: %v", host, err)
+		}
+		if got := auth != authn.Anonymous; got != wantAuth {
+			t.Errorf("%s: authenticated = %v, want %v", host, got, wantAuth)
+		}
+	}
+}
@@ -88,6 +88,12 @@ type NexusSearchResponse struct {
 type NexusRepositoryDetails struct {
 	Name   string             `json:"name"`
 	Docker *NexusDockerConfig `json:"docker,omitempty"`
+	Group  *NexusGroupConfig  `json:"group,omitempty"`
+}
+
+// NexusGroupConfig represents the member list of a group repository
+type NexusGroupConfig struct {
+	MemberNames []string `json:"memberNames"`
 }
 
 // NexusDockerConfig represents Docker-specific configuration
@@ -146,10 +152,11 @@ func (c *client) getRepository(name string) (NexusRepository, error) {
 	return NexusRepository{}, fmt.Errorf("repository %s not found", name)
 }
 
-// getRepositoryDetails retrieves detailed repository configuration
-func (c *client) getRepositoryDetails(name, packageType string) (*NexusRepositoryDetails, error) {
-	url := fmt.Sprintf("%s/service/rest/v1/repositories/%s/hosted/%s", strings.TrimSuffix(c.url, "/"), packageType,
-		name)
+// getRepositoryDetails retrieves detailed repository configuration. repoType is
+// the repository type as reported by Nexus (hosted, proxy or group).
+func (c *client) getRepositoryDetails(name, packageType, repoType string) (*NexusRepositoryDetails, error) {
+	url := fmt.Sprintf("%s/service/rest/v1/repositories/%s/%s/%s", strings.TrimSuffix(c.url, "/"), packageType,
+		repoType, name)
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
@@ -334,32 +341,50 @@ func (c *client) buildDownloadURL(repository, path string) string {
 	return fmt.Sprintf("%s/repository/%s/%s", strings.TrimSuffix(c.url, "/"), repository, strings.TrimPrefix(path, "/"))
 }
 
-// getDockerPort attempts to discover the Docker HTTPS port for a repository
-func (c *client) getDockerPort(repositoryName string, insecure bool) (int, error) {
-	repoDetails, err := c.getRepositoryDetails(repositoryName, "docker")
+// getGroupMembers returns the member repository names of a group repository, in
+// the order Nexus resolves them
+func (c *client) getGroupMembers(repository NexusRepository) ([]string, error) {
+	repoDetails, err := c.getRepositoryDetails(repository.Name, formatAPIPath(repository.Format), repository.Type)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get repository details: %w", err)
+		return nil, fmt.Errorf("failed to get repository details: %w", err)
 	}
-
-	// Check for Docker configuration
-	if repoDetails.Docker == nil {
-		return 0, fmt.Errorf("no Docker configuration found for repository %s", repositoryName)
+	if repoDetails.Group == nil {
+		return nil, fmt.Errorf("no group configuration found for repository %s", repository.Name)
 	}
+	return repoDetails.Group.MemberNames, nil
+}
 
-	if insecure {
-		if repoDetails.Docker.HttpPort > 0 {
-			return repoDetails.Docker.HttpPort, nil
-		}
+// formatAPIPath maps a repository format to the path segment used by the
+// repository management API, which differs from the format name for maven
+func formatAPIPath(format string) string {
+	if format == "maven2" {
+		return "maven"
 	}
+	return format
+}
 
-	// Prefer HTTPS port, fallback to HTTP port
-	if repoDetails.Docker.HttpsPort > 0 {
-		return repoDetails.Docker.HttpsPort, nil
+// getDockerConfig retrieves the Docker connector configuration of a repository
+func (c *client) getDockerConfig(repository NexusRepository) (*NexusDockerConfig, error) {
+	repoDetails, err := c.getRepositoryDetails(repository.Name, "docker", repository.Type)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository details: %w", err)
+	}
+	if repoDetails.Docker == nil {
+		return nil, fmt.Errorf("no Docker configuration found for repository %s", repository.Name)
 	}
+	return repoDetails.Docker, nil
+}
 
-	if repoDetails.Docker.HttpPort > 0 {
-		return repoDetails.Docker.HttpPort, nil
+// dockerPort returns the connector port to use for a Docker repository, or 0
+// when the repository has no dedicated connector (path-based or subdomain routing)
+func dockerPort(docker *NexusDockerConfig, insecure bool) int {
+	if insecure && docker.HttpPort > 0 {
+		return docker.HttpPort
 	}
 
-	return 0, fmt.Errorf("no Docker connector port configured for repository %s", repositoryName)
+	// Prefer HTTPS port, fallback to HTTP port
+	if docker.HttpsPort > 0 {
+		return docker.HttpsPort
+	}
+	return docker.HttpPort
 }
@@ -31,7 +31,11 @@ func (n nexusKeychain) Resolve(r authn.Resource) (authn.Authenticator, error) {
 		return authn.Anonymous, nil
 	}
 
-	if strings.EqualFold(serverURL.Hostname(), n.hostname) {
+	// Docker repositories may be served from the Nexus host itself (connector
+	// port or path-based routing) or from a subdomain connector
+	host := strings.ToLower(serverURL.Hostname())
+	hostname := strings.ToLower(n.hostname)
+	if host == hostname || strings.HasSuffix(host, "."+hostname) {
 		return nexusAuthenticator{n.username, n.password}, nil
 	}
 	return authn.Anonymous, nil
@@ -0,0 +1,196 @@
+package migratable
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/harness/harness-cli/module/ar/migrate/tree"
+	"github.com/harness/harness-cli/module/ar/migrate/types/npm"
+	"github.com/harness/harness-cli/module/ar/migrate/util"
+
+	"github.com/pterm/pterm"
+	"github.com/rs/zerolog"
+)
+
+// npmDistTagURI is the npm registry API path that sets a dist-tag, relative to
+// the registry's npm root
+func npmDistTagURI(name, tag string) string {
+	return "/-/package/" + util.EscapeNPMPackageName(name) + "/dist-tags/" + url.PathEscape(tag)
+}
+
+// npmPackageNames returns the npm package names whose tarballs live under the
+// package job's node. Artifactory migrates a whole npm repository as a single
+// unnamed package, while Nexus runs one job per component, so names are taken
+// from the tarball layout and, when the job is named, narrowed to that package.
+func (r *Package) npmPackageNames() ([]string, error) {
+	files, err := tree.GetAllFiles(r.node)
+	if err != nil {
+		return nil, fmt.Errorf("get all files: %w", err)
+	}
+	seen := map[string]bool{}
+	var names []string
+	for _, f := range files {
+		name, ok := util.GetNPMPackageNameFromPath(f.Uri)
+		if !ok || seen[name] {
+			continue
+		}
+		// Nexus names scoped components "<scope>/<name>", without the "@"
+		if r.pkg.Name != "" && name != r.pkg.Name && strings.TrimPrefix(name, "@") != r.pkg.Name {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// migrateNPMPackuments reproduces, for every npm package of the job, the
+// source packument's package-level fields, per-version deprecations and
+// dist-tags at the destination once the versions have landed, then reports
+// any remaining dist-tag drift. It is best-effort: failures are logged and
+// never fail the job, since the tarballs themselves have already migrated.
+func (r *Package) migrateNPMPackuments(ctx context.Context, logger zerolog.Logger) {
+	names, err := r.npmPackageNames()
+	if err != nil {
+		logger.Warn().Err(err).Msg("Failed to list npm packages, skipping packument migration")
+		return
+	}
+	for _, name := range names {
+		if ctx.Err() != nil {
+			return
+		}
+		r.migrateNPMPackument(logger, name)
+	}
+}
+
+func (r *Package) migrateNPMPackument(logger zerolog.Logger, name string) {
+	src, err := r.srcAdapter.GetNPMPackument(r.srcRegistry, name)
+	if err != nil {
+		logger.Warn().Err(err).Msgf("Failed to get source packument of %s, skipping", name)
+		return
+	}
+	dest, err := r.destAdapter.GetNPMPackument(r.destRegistry, name)
+	if err != nil {
+		logger.Warn().Err(err).Msgf("Failed to get destination packument of %s, skipping", name)
+		return
+	}
+
+	if mergeNPMPackument(src, dest) {
+		if err := r.destAdapter.UpdateNPMPackument(r.destRegistry, name, dest); err != nil {
+			logger.Warn().Err(err).Msgf("Failed to update packument of %s", name)
+			pterm.Warning.Println(fmt.Sprintf("Failed to copy npm metadata and deprecations of %s: %v", name, err))
+		}
+	}
+
+	for _, tag := range sortedKeys(src.DistTags) {
+		version := src.DistTags[tag]
+		if dest.DistTags[tag] == version {
+			continue
+		}
+		if _, ok := dest.Versions[version]; !ok {
+			// Reported as drift below; the tagged version did not migrate
+			continue
+		}
+		if err := r.destAdapter.AddNPMTag(r.destRegistry, name, version, npmDistTagURI(name, tag)); err != nil {
+			logger.Warn().Err(err).Msgf("Failed to set dist-tag %s of %s to %s", tag, name, version)
+		}
+	}
+
+	after, err := r.destAdapter.GetNPMPackument(r.destRegistry, name)
+	if err != nil {
+		logger.Warn().Err(err).Msgf("Failed to re-read destination packument of %s, cannot check dist-tags", name)
+		return
+	}
+	drift := npmDistTagDrift(src.DistTags, after.DistTags)
+	for _, d := range drift {
+		logger.Warn().Msgf("npm package %s: %s", name, d)
+		pterm.Warning.Println(fmt.Sprintf("npm package %s: %s", name, d))
+	}
+	if len(drift) == 0 {
+		logger.Info().Msgf("npm package %s: %d dist-tag(s) in sync", name, len(src.DistTags))
+	}
+}
+
+// mergeNPMPackument copies the package-level fields (readme, description,
+// keywords, links, people) and per-version deprecations of src onto dest. Only
+// versions already present at dest are touched. It reports whether dest changed.
+func mergeNPMPackument(src, dest *npm.PackageMetadata) bool {
+	changed := false
+	setString := func(dst *string, v string) {
+		if v != "" && *dst != v {
+			*dst = v
+			changed = true
+		}
+	}
+	setValue := func(dst *interface{}, v interface{}) {
+		if v != nil && fmt.Sprint(*dst) != fmt.Sprint(v) {
+			*dst = v
+			changed = true
+		}
+	}
+
+	setString(&dest.Readme, src.Readme)
+	setString(&dest.ReadmeFilename, src.ReadmeFilename)
+	setValue(&dest.Description, src.Description)
+	setValue(&dest.Homepage, src.Homepage)
+	setValue(&dest.Repository, src.Repository)
+	setValue(&dest.Author, src.Author)
+	setValue(&dest.Bugs, src.Bugs)
+	setValue(&dest.License, src.License)
+	setValue(&dest.Contributors, src.Contributors)
+	if len(src.Keywords) > 0 && strings.Join(dest.Keywords, ",") != strings.Join(src.Keywords, ",") {
+		dest.Keywords = src.Keywords
+		changed = true
+	}
+	if len(src.Maintainers) > 0 && fmt.Sprint(dest.Maintainers) != fmt.Sprint(src.Maintainers) {
+		dest.Maintainers = src.Maintainers
+		changed = true
+	}
+
+	for version, srcVersion := range src.Versions {
+		destVersion, ok := dest.Versions[version]
+		if !ok || srcVersion == nil || destVersion == nil {
+			continue
+		}
+		// npm clears a deprecation with an empty message; carry only real ones
+		if msg, _ := srcVersion.Deprecated.(string); msg != "" && destVersion.Deprecated != msg {
+			destVersion.Deprecated = msg
+			changed = true
+		}
+	}
+	return changed
+}
+
+// npmDistTagDrift describes every difference between the source and
+// destination dist-tag sets, in tag order
+func npmDistTagDrift(src, dest map[string]string) []string {
+	var drift []string
+	for _, tag := range sortedKeys(src) {
+		switch destVersion, ok := dest[tag]; {
+		case !ok:
+			drift = append(drift, fmt.Sprintf("dist-tag %s (%s) missing at destination", tag, src[tag]))
+		case destVersion != src[tag]:
+			drift = append(drift, fmt.Sprintf("dist-tag %s is %s at destination, %s at source",
+				tag, destVersion, src[tag]))
+		}
+	}
+	for _, tag := range sortedKeys(dest) {
+		if _, ok := src[tag]; !ok {
+			drift = append(drift, fmt.Sprintf("dist-tag %s (%s) only exists at destination", tag, dest[tag]))
+		}
+	}
+	return drift
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
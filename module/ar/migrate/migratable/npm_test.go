@@ -0,0 +1,144 @@
+package migratable
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/harness/harness-cli/module/ar/migrate/tree"
+	"github.com/harness/harness-cli/module/ar/migrate/types"
+	"github.com/harness/harness-cli/module/ar/migrate/types/npm"
+
+	"github.com/rs/zerolog"
+)
+
+// fakeNPM serves packuments by package name and applies dist-tag and
+// packument writes to them, so a re-read observes the result like a registry.
+type fakeNPM struct {
+	noopAdapter
+	packuments map[string]*npm.PackageMetadata
+	updates    []string // package names passed to UpdateNPMPackument
+	tagURIs    []string // uri arguments passed to AddNPMTag
+}
+
+func (f *fakeNPM) GetNPMPackument(_ string, name string) (*npm.PackageMetadata, error) {
+	p, ok := f.packuments[name]
+	if !ok {
+		return nil, fmt.Errorf("packument %s not found", name)
+	}
+	return p, nil
+}
+
+func (f *fakeNPM) UpdateNPMPackument(_ string, name string, packument *npm.PackageMetadata) error {
+	f.updates = append(f.updates, name)
+	f.packuments[name] = packument
+	return nil
+}
+
+func (f *fakeNPM) AddNPMTag(_ string, name string, version string, uri string) error {
+	f.tagURIs = append(f.tagURIs, uri)
+	p := f.packuments[name]
+	if p.DistTags == nil {
+		p.DistTags = map[string]string{}
+	}
+	p.DistTags[uri[strings.LastIndex(uri, "/")+1:]] = version
+	return nil
+}
+
+func versions(vs ...string) map[string]*npm.PackageMetadataVersion {
+	m := map[string]*npm.PackageMetadataVersion{}
+	for _, v := range vs {
+		m[v] = &npm.PackageMetadataVersion{Version: v}
+	}
+	return m
+}
+
+func TestMigrateNPMPackument(t *testing.T) {
+	srcVersions := versions("1.0.0", "2.0.0-beta.1", "3.0.0")
+	srcVersions["1.0.0"].Deprecated = "use 2.x"
+	src := &fakeNPM{packuments: map[string]*npm.PackageMetadata{
+		"@acme/ui": {
+			Name:     "@acme/ui",
+			Readme:   "# UI",
+			Keywords: []string{"ui"},
+			DistTags: map[string]string{"latest": "1.0.0", "beta": "2.0.0-beta.1", "next": "3.0.0"},
+			Versions: srcVersions,
+		},
+	}}
+	// 3.0.0 did not migrate; latest was moved by publish order
+	dest := &fakeNPM{packuments: map[string]*npm.PackageMetadata{
+		"@acme/ui": {
+			Name:     "@acme/ui",
+			DistTags: map[string]string{"latest": "2.0.0-beta.1"},
+			Versions: versions("1.0.0", "2.0.0-beta.1"),
+		},
+	}}
+
+	job := &Package{
+		srcRegistry:  "src-reg",
+		destRegistry: "dst-reg",
+		srcAdapter:   src,
+		destAdapter:  dest,
+		artifactType: types.NPM,
+		logger:       zerolog.Nop(),
+		config:       &types.Config{},
+		node: tree.TransformToTree([]types.File{
+			{Name: "ui-1.0.0.tgz", Uri: "/@acme/ui/-/ui-1.0.0.tgz"},
+			{Name: "ui-2.0.0-beta.1.tgz", Uri: "/@acme/ui/-/ui-2.0.0-beta.1.tgz"},
+		}),
+	}
+	job.migrateNPMPackuments(context.Background(), zerolog.Nop())
+
+	got := dest.packuments["@acme/ui"]
+	if len(dest.updates) != 1 || got.Readme != "# UI" || strings.Join(got.Keywords, ",") != "ui" {
+		t.Errorf("package-level fields not copied: updates=%v packument=%+v", dest.updates, got)
+	}
+	if got.Versions["1.0.0"].Deprecated != "use 2.x" {
+		t.Errorf("deprecation not copied: %v", got.Versions["1.0.0"].Deprecated)
+	}
+	if _, ok := got.Versions["3.0.0"]; ok {
+		t.Errorf("packument update must not add versions")
+	}
+	wantTags := "/-/package/@acme%2Fui/dist-tags/beta,/-/package/@acme%2Fui/dist-tags/latest"
+	if strings.Join(dest.tagURIs, ",") != wantTags {
+		t.Errorf("tag uris = %v, want %s", dest.tagURIs, wantTags)
+	}
+	if got.DistTags["latest"] != "1.0.0" || got.DistTags["beta"] != "2.0.0-beta.1" {
+		t.Errorf("dist-tags = %v", got.DistTags)
+	}
+}
+
+func TestNPMDistTagDrift(t *testing.T) {
+	drift := npmDistTagDrift(
+		map[string]string{"latest": "1.0.0", "next": "2.0.0", "beta": "2.0.0-beta.1"},
+		map[string]string{"latest": "1.0.0", "beta": "2.0.0-beta.2", "canary": "0.0.1"},
+	)
+	want := []string{
+		"dist-tag beta is 2.0.0-beta.2 at destination, 2.0.0-beta.1 at source",
+		"dist-tag next (2.0.0) missing at destination",
+		"dist-tag canary (0.0.1) only exists at destination",
+	}
+	if strings.Join(drift, "\n") != strings.Join(want, "\n") {
+		t.Errorf("drift =\n%s\nwant\n%s", strings.Join(drift, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+// TestNPMPackageNamesNexusScope asserts a job named after a Nexus component
+// ("<scope>/<name>", no "@") only picks its own package out of the tree.
+func TestNPMPackageNamesNexusScope(t *testing.T) {
+	job := &Package{
+		pkg: types.Package{Name: "acme/ui"},
+		node: tree.TransformToTree([]types.File{
+			{Name: "ui-1.0.0.tgz", Uri: "/@acme/ui/-/ui-1.0.0.tgz"},
+			{Name: "lodash-4.0.0.tgz", Uri: "/lodash/-/lodash-4.0.0.tgz"},
+		}),
+	}
+	names, err := job.npmPackageNames()
+	if err != nil {
+		t.Fatalf("npmPackageNames: %v", err)
+	}
+	if strings.Join(names, ",") != "@acme/ui" {
+		t.Errorf("names = %v", names)
+	}
+}
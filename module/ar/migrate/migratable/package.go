@@ -3,7 +3,6 @@ package migratable
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -13,7 +12,6 @@ import (
 	"strings"
 	"sync"
 	"time"
-	"unicode/utf8"
 
 	"github.com/harness/harness-cli/config"
 	"github.com/harness/harness-cli/module/ar/migrate/adapter"
@@ -27,13 +25,8 @@ import (
 
 	"github.com/google/go-containerregistry/pkg/crane"
 	"github.com/google/go-containerregistry/pkg/name"
-	v1 "github.com/google/go-containerregistry/pkg/v1"
-	"github.com/google/go-containerregistry/pkg/v1/empty"
-	"github.com/google/go-containerregistry/pkg/v1/mutate"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
-	"github.com/google/go-containerregistry/pkg/v1/static"
-	types2 "github.com/google/go-containerregistry/pkg/v1/types"
 	"github.com/google/uuid"
 	"github.com/pterm/pterm"
 	"github.com/rs/zerolog"
@@ -1292,8 +1285,6 @@ func (r *Package) migrateConan(ctx context.Context) error {
 	return nil
 }
 
-const labelMaxBytes = 1024
-
 func readChartMeta(path string) (*chart.Metadata, error) {
 	ch, err := loader.Load(path) // understands .tgz & directories
 	if err != nil {
@@ -1305,56 +1296,6 @@ func readChartMeta(path string) (*chart.Metadata, error) {
 	return ch.Metadata, nil
 }
 
-func truncate(s string) string {
-	_max := labelMaxBytes
-	if len(s) <= _max {
-		return s
-	}
-	// walk backwards until we’re on a rune boundary
-	for _max > 0 && !utf8.RuneStart(s[_max]) {
-		_max--
-	}
-	return s[:_max-1] + "…"
-}
-
-func chartLabels(meta *chart.Metadata) map[string]string {
-	lbl := map[string]string{
-		"helm.sh/chart":     truncate(meta.Name + "-" + meta.Version),
-		"chart.name":        truncate(meta.Name),
-		"chart.home":        truncate(meta.Home),
-		"chart.sources":     truncate(strings.Join(meta.Sources, ",")),
-		"chart.version":     truncate(meta.Version),
-		"chart.description": truncate(meta.Description),
-		"chart.keywords":    truncate(strings.Join(meta.Keywords, ",")),
-		"chart.icon":        truncate(meta.Icon),
-		"chart.apiVersion":  truncate(meta.APIVersion),
-		"chart.condition":   truncate(meta.Condition),
-		"chart.tags":        truncate(meta.Tags),
-		"chart.appVersion":  truncate(meta.AppVersion),
-		"chart.kubeVersion": truncate(meta.KubeVersion),
-		"chart.type":        truncate(meta.Type),
-	}
-	// objects & complex lists → JSON
-	if meta.Maintainers != nil {
-		if b, _ := json.Marshal(meta.Maintainers); len(b) > 0 {
-			lbl["chart.maintainers"] = truncate(string(b))
-		}
-	}
-
-	if meta.Dependencies != nil {
-		if b, _ := json.Marshal(meta.Dependencies); len(b) > 0 {
-			lbl["chart.dependencies"] = truncate(string(b))
-		}
-	}
-
-	if meta.Annotations != nil {
-		if b, _ := json.Marshal(meta.Annotations); len(b) > 0 {
-			lbl["chart.annotations"] = truncate(string(b))
-		}
-	}
-	return lbl
-}
-
 // pushChart uploads chart.tar.gz --> oci://<dstRef>
 func (r *Package) pushChart(ctx context.Context, chartPath string, dstRef string) error {
 	meta, err := readChartMeta(chartPath)
@@ -1362,47 +1303,21 @@ func (r *Package) pushChart(ctx context.Context, chartPath string, dstRef string
 		log.Error().Msgf("Failed to read chart metadata from %s", chartPath)
 		return errors.New("failed to read chart metadata from chartPath")
 	}
-	labels := chartLabels(meta)
 	ref, err := name.ParseReference(dstRef, name.WeakValidation)
-	//check(err, "parsing reference")
-
-	chartData, err := os.ReadFile(chartPath)
-	check(err, "reading chart file")
-
-	layer := static.NewLayer(chartData, "application/vnd.cncf.helm.chart.content.v1.tar+gzip")
-
-	img, err := mutate.AppendLayers(empty.Image, layer)
-	check(err, "appending layer")
-
-	cfg := v1.Config{
-		Labels: labels,
-	}
-	cfg.Labels = map[string]string{}
-
-	img, err = mutate.Config(img, cfg)
-
-	check(err, "adding config JSON")
-
-	img = mutate.ConfigMediaType(img, "application/vnd.cncf.helm.config.v1+json")
-	img = mutate.MediaType(img, types2.OCIManifestSchema1)
-
-	annotations := map[string]string{
-		"org.opencontainers.image.title":       truncate(meta.Name),
-		"org.opencontainers.image.description": truncate(meta.Description),
-		"org.opencontainers.image.version":     truncate(meta.Version),
-		"org.opencontainers.image.created":     time.Now().UTC().Format(time.RFC3339),
-		"org.opencontainers.artifactType":      "application/vnd.cncf.helm.chart.layer.v1.tar+gzip",
+	if err != nil {
+		return fmt.Errorf("invalid chart reference %q: %w", dstRef, err)
 	}
 
-	if strings.Contains(meta.Version, "+") {
-		return errors.New("chart version cannot contain +")
+	chartData, err := os.ReadFile(chartPath)
+	if err != nil {
+		return fmt.Errorf("reading chart file: %w", err)
 	}
 
-	for k, v := range meta.Annotations {
-		annotations[k] = truncate(v)
+	img, err := util.MigratedChartImage(meta, chartData)
+	if err != nil {
+		return err
 	}
 
-	img = mutate.Annotations(img, annotations).(v1.Image)
 	keyChain, err := lib.CreateCraneKeychain(r.srcAdapter, r.destAdapter, r.sourcePackageHostname)
 	if err != nil {
 		log.Error().Ctx(ctx).Err(err).Msgf("Failed to create keyChain: %v", err)
@@ -1444,10 +1359,38 @@ func (r *Package) Post(ctx context.Context) error {
 	logger.Info().Msg("Starting package post-migration step")
 
 	startTime := time.Now()
-	// Your post-migration code here
+
+	if !r.config.DryRun && !r.skipMigration {
+		r.migrateVersionMetadata(ctx, logger)
+		if r.artifactType == types.NPM {
+			r.migrateNPMPackuments(ctx, logger)
+		}
+	}
 
 	logger.Info().
 		Dur("duration", time.Since(startTime)).
 		Msg("Completed registry post-migration step")
 	return nil
 }
+
+// migrateVersionMetadata copies the source's per-version metadata (e.g. Harbor
+// labels and vulnerability summaries) onto the migrated versions. It is
+// best-effort: a version that did not make it to the destination, or a
+// metadata write the destination rejects, is logged and never fails the job.
+func (r *Package) migrateVersionMetadata(ctx context.Context, logger zerolog.Logger) {
+	metadata, err := r.srcAdapter.GetVersionMetadata(r.srcRegistry, r.pkg, r.artifactType)
+	if err != nil {
+		logger.Warn().Err(err).Msgf("Failed to get metadata for package %s, skipping", r.pkg.Name)
+		return
+	}
+	for version, md := range metadata {
+		if len(md) == 0 {
+			continue
+		}
+		if err := r.destAdapter.SetVersionMetadata(ctx, r.destRegistry, r.pkg.Name, version, md); err != nil {
+			logger.Warn().Err(err).Msgf("Failed to set metadata on %s:%s", r.pkg.Name, version)
+			continue
+		}
+		logger.Debug().Msgf("Copied %d metadata entries to %s:%s", len(md), r.pkg.Name, version)
+	}
+}
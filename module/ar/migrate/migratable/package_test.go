@@ -10,6 +10,7 @@ import (
 
 	adp "github.com/harness/harness-cli/module/ar/migrate/adapter"
 	"github.com/harness/harness-cli/module/ar/migrate/types"
+	"github.com/harness/harness-cli/module/ar/migrate/types/npm"
 
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
@@ -59,6 +60,18 @@ func (noopAdapter) BuildExistingIndex(context.Context, string, int) (*types.Exis
 func (noopAdapter) CreateVersion(string, string, string, types.ArtifactType, []*types.PackageFiles, map[string]interface{}) error {
 	return nil
 }
+func (noopAdapter) GetVersionMetadata(string, types.Package, types.ArtifactType) (map[string]map[string]string, error) {
+	return nil, nil
+}
+func (noopAdapter) SetVersionMetadata(context.Context, string, string, string, map[string]string) error {
+	return nil
+}
+func (noopAdapter) GetNPMPackument(string, string) (*npm.PackageMetadata, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+func (noopAdapter) UpdateNPMPackument(string, string, *npm.PackageMetadata) error {
+	return fmt.Errorf("not implemented")
+}
 
 // fakeSrc serves chart/prov bytes keyed by URI. A URI absent from content
 // produces a download error (used to model a missing .prov or an unreachable
@@ -13,11 +13,14 @@ import (
 type RegistryType string
 
 var (
-	HAR        RegistryType = "HAR"
-	JFROG      RegistryType = "JFROG"
-	MOCK_JFROG RegistryType = "MOCK_JFROG"
-	NEXUS      RegistryType = "NEXUS"
-	HARBOR     RegistryType = "HARBOR"
+	HAR         RegistryType = "HAR"
+	JFROG       RegistryType = "JFROG"
+	MOCK_JFROG  RegistryType = "MOCK_JFROG"
+	NEXUS       RegistryType = "NEXUS"
+	MOCK_NEXUS  RegistryType = "MOCK_NEXUS"
+	HARBOR      RegistryType = "HARBOR"
+	MOCK_HARBOR RegistryType = "MOCK_HARBOR"
+	GITLAB      RegistryType = "GITLAB"
 )
 
 type ArtifactType string
@@ -189,7 +192,7 @@ func validateCredentials(registry RegistryConfig) error {
 
 	// Check supported registry types
 	switch registry.Type {
-	case HAR, JFROG, NEXUS, HARBOR, MOCK_JFROG:
+	case HAR, JFROG, NEXUS, HARBOR, GITLAB, MOCK_JFROG, MOCK_NEXUS, MOCK_HARBOR:
 		// These are supported
 	default:
 		return fmt.Errorf("unsupported registry type: %s", registry.Type)
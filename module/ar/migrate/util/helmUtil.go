@@ -1,10 +1,24 @@
 package util
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"path"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/Masterminds/semver/v3"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"helm.sh/helm/v3/pkg/chart"
 )
 
 // helmChartExt is the canonical Helm chart archive extension.
@@ -81,3 +95,181 @@ func GetChartProvFileName(name, version string) string {
 func IsHelmChartArchive(name string) bool {
 	return strings.HasSuffix(name, helmChartExt) && !strings.HasSuffix(name, helmChartExt+helmProvExt)
 }
+
+// Media types of a Helm chart stored in an OCI registry, as written by
+// `helm push`.
+const (
+	HelmChartConfigMediaType     = "application/vnd.cncf.helm.config.v1+json"
+	HelmChartContentMediaType    = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+	HelmChartProvenanceMediaType = "application/vnd.cncf.helm.chart.provenance.v1.prov"
+)
+
+const labelMaxBytes = 1024
+
+func truncate(s string) string {
+	_max := labelMaxBytes
+	if len(s) <= _max {
+		return s
+	}
+	// walk backwards until we’re on a rune boundary
+	for _max > 0 && !utf8.RuneStart(s[_max]) {
+		_max--
+	}
+	return s[:_max-1] + "…"
+}
+
+// ChartLabels flattens chart metadata into the config labels HAR indexes a
+// Helm OCI artifact by. Values are truncated to 1KiB; objects and lists of
+// objects are stored as JSON.
+func ChartLabels(meta *chart.Metadata) map[string]string {
+	lbl := map[string]string{
+		"helm.sh/chart":     truncate(meta.Name + "-" + meta.Version),
+		"chart.name":        truncate(meta.Name),
+		"chart.home":        truncate(meta.Home),
+		"chart.sources":     truncate(strings.Join(meta.Sources, ",")),
+		"chart.version":     truncate(meta.Version),
+		"chart.description": truncate(meta.Description),
+		"chart.keywords":    truncate(strings.Join(meta.Keywords, ",")),
+		"chart.icon":        truncate(meta.Icon),
+		"chart.apiVersion":  truncate(meta.APIVersion),
+		"chart.condition":   truncate(meta.Condition),
+		"chart.tags":        truncate(meta.Tags),
+		"chart.appVersion":  truncate(meta.AppVersion),
+		"chart.kubeVersion": truncate(meta.KubeVersion),
+		"chart.type":        truncate(meta.Type),
+	}
+	// objects & complex lists → JSON
+	if meta.Maintainers != nil {
+		if b, _ := json.Marshal(meta.Maintainers); len(b) > 0 {
+			lbl["chart.maintainers"] = truncate(string(b))
+		}
+	}
+
+	if meta.Dependencies != nil {
+		if b, _ := json.Marshal(meta.Dependencies); len(b) > 0 {
+			lbl["chart.dependencies"] = truncate(string(b))
+		}
+	}
+
+	if meta.Annotations != nil {
+		if b, _ := json.Marshal(meta.Annotations); len(b) > 0 {
+			lbl["chart.annotations"] = truncate(string(b))
+		}
+	}
+	return lbl
+}
+
+// ChartImage builds the OCI artifact `helm push` writes for a packaged
+// chart: the chart archive as its layer, followed by the provenance file when
+// provData is not empty, with the Chart.yaml metadata as JSON in the config.
+// The manifest carries the chart labels HAR indexes the chart by alongside
+// the chart's annotations.
+func ChartImage(meta *chart.Metadata, chartData, provData []byte) (v1.Image, error) {
+	// OCI tags cannot hold "+", which helm would otherwise translate to "_"
+	if strings.Contains(meta.Version, "+") {
+		return nil, errors.New("chart version cannot contain +")
+	}
+	config, err := json.Marshal(meta)
+	if err != nil {
+		return nil, fmt.Errorf("encoding chart config: %w", err)
+	}
+
+	img := &helmChartImage{config: config, layers: map[v1.Hash]v1.Layer{}}
+	manifest := v1.Manifest{
+		SchemaVersion: 2,
+		MediaType:     types.OCIManifestSchema1,
+		Config: v1.Descriptor{
+			MediaType: HelmChartConfigMediaType,
+			Size:      int64(len(config)),
+			Digest:    sha256Hash(config),
+		},
+		Annotations: ChartLabels(meta),
+	}
+	for k, v := range chartAnnotations(meta) {
+		manifest.Annotations[k] = v
+	}
+	blobs := []struct {
+		data      []byte
+		mediaType types.MediaType
+	}{{chartData, HelmChartContentMediaType}, {provData, HelmChartProvenanceMediaType}}
+	for _, b := range blobs {
+		if len(b.data) == 0 {
+			continue
+		}
+		layer := static.NewLayer(b.data, b.mediaType)
+		digest := sha256Hash(b.data)
+		img.layers[digest] = layer
+		manifest.Layers = append(manifest.Layers, v1.Descriptor{
+			MediaType: b.mediaType,
+			Size:      int64(len(b.data)),
+			Digest:    digest,
+		})
+	}
+	if img.manifest, err = json.Marshal(manifest); err != nil {
+		return nil, fmt.Errorf("encoding chart manifest: %w", err)
+	}
+	return partial.CompressedToImage(img)
+}
+
+// MigratedChartImage builds the OCI artifact the migration writes for a
+// chart: the chart archive as its only layer with an empty image config.
+// Charts already migrated were written this way, so it must not change.
+func MigratedChartImage(meta *chart.Metadata, chartData []byte) (v1.Image, error) {
+	layer := static.NewLayer(chartData, HelmChartContentMediaType)
+	img, err := mutate.AppendLayers(empty.Image, layer)
+	if err != nil {
+		return nil, fmt.Errorf("appending layer: %w", err)
+	}
+
+	img, err = mutate.Config(img, v1.Config{Labels: map[string]string{}})
+	if err != nil {
+		return nil, fmt.Errorf("adding config JSON: %w", err)
+	}
+	img = mutate.ConfigMediaType(img, HelmChartConfigMediaType)
+	img = mutate.MediaType(img, types.OCIManifestSchema1)
+
+	if strings.Contains(meta.Version, "+") {
+		return nil, errors.New("chart version cannot contain +")
+	}
+	return mutate.Annotations(img, chartAnnotations(meta)).(v1.Image), nil
+}
+
+func chartAnnotations(meta *chart.Metadata) map[string]string {
+	annotations := map[string]string{
+		"org.opencontainers.image.title":       truncate(meta.Name),
+		"org.opencontainers.image.description": truncate(meta.Description),
+		"org.opencontainers.image.version":     truncate(meta.Version),
+		"org.opencontainers.image.created":     time.Now().UTC().Format(time.RFC3339),
+		"org.opencontainers.artifactType":      "application/vnd.cncf.helm.chart.layer.v1.tar+gzip",
+	}
+	for k, v := range meta.Annotations {
+		annotations[k] = truncate(v)
+	}
+	return annotations
+}
+
+func sha256Hash(data []byte) v1.Hash {
+	sum := sha256.Sum256(data)
+	return v1.Hash{Algorithm: "sha256", Hex: hex.EncodeToString(sum[:])}
+}
+
+// helmChartImage is a chart artifact whose config blob is the chart metadata
+// rather than an image config, which the mutate package cannot build
+type helmChartImage struct {
+	config   []byte
+	manifest []byte
+	layers   map[v1.Hash]v1.Layer
+}
+
+func (i *helmChartImage) RawConfigFile() ([]byte, error) { return i.config, nil }
+
+func (i *helmChartImage) MediaType() (types.MediaType, error) { return types.OCIManifestSchema1, nil }
+
+func (i *helmChartImage) RawManifest() ([]byte, error) { return i.manifest, nil }
+
+func (i *helmChartImage) LayerByDigest(h v1.Hash) (partial.CompressedLayer, error) {
+	if l, ok := i.layers[h]; ok {
+		return l, nil
+	}
+	return nil, fmt.Errorf("unknown blob %s", h)
+}
@@ -1,6 +1,11 @@
 package util
 
-import "testing"
+import (
+	"encoding/json"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
 
 func TestParseChartFileName(t *testing.T) {
 	tests := []struct {
@@ -162,3 +167,77 @@ func TestIsHelmChartArchive(t *testing.T) {
 		})
 	}
 }
+
+func TestChartImage(t *testing.T) {
+	meta := &chart.Metadata{Name: "demo", Version: "0.1.0", AppVersion: "1.2.3",
+		Annotations: map[string]string{"team": "infra"}}
+
+	img, err := ChartImage(meta, []byte("chart"), []byte("prov"))
+	if err != nil {
+		t.Fatalf("ChartImage() error = %v", err)
+	}
+	raw, err := img.RawConfigFile()
+	if err != nil {
+		t.Fatalf("RawConfigFile() error = %v", err)
+	}
+	var cfg chart.Metadata
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		t.Fatalf("config is not chart metadata: %v", err)
+	}
+	if cfg.Name != "demo" || cfg.AppVersion != "1.2.3" {
+		t.Errorf("config = %s, want the chart metadata", raw)
+	}
+	manifest, err := img.Manifest()
+	if err != nil {
+		t.Fatalf("Manifest() error = %v", err)
+	}
+	if got := string(manifest.Config.MediaType); got != HelmChartConfigMediaType {
+		t.Errorf("config media type = %q, want %q", got, HelmChartConfigMediaType)
+	}
+	if len(manifest.Layers) != 2 || string(manifest.Layers[1].MediaType) != HelmChartProvenanceMediaType {
+		t.Errorf("layers = %v, want the chart and its provenance", manifest.Layers)
+	}
+	if got := manifest.Annotations["team"]; got != "infra" {
+		t.Errorf("team annotation = %q, want %q", got, "infra")
+	}
+	for k, v := range ChartLabels(meta) {
+		if got := manifest.Annotations[k]; got != v {
+			t.Errorf("label %s = %q, want %q", k, got, v)
+		}
+	}
+	if _, err := img.Digest(); err != nil {
+		t.Errorf("Digest() error = %v", err)
+	}
+
+	meta.Version = "0.1.0+build.1"
+	if _, err := ChartImage(meta, []byte("chart"), nil); err == nil {
+		t.Error("ChartImage() accepted a version with build metadata")
+	}
+}
+
+func TestMigratedChartImage(t *testing.T) {
+	meta := &chart.Metadata{Name: "demo", Version: "0.1.0", AppVersion: "1.2.3"}
+
+	img, err := MigratedChartImage(meta, []byte("chart"))
+	if err != nil {
+		t.Fatalf("MigratedChartImage() error = %v", err)
+	}
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		t.Fatalf("ConfigFile() error = %v", err)
+	}
+	// the migration has always written an empty config
+	if len(cfg.Config.Labels) != 0 {
+		t.Errorf("config labels = %v, want none", cfg.Config.Labels)
+	}
+	manifest, err := img.Manifest()
+	if err != nil {
+		t.Fatalf("Manifest() error = %v", err)
+	}
+	if got := string(manifest.Config.MediaType); got != HelmChartConfigMediaType {
+		t.Errorf("config media type = %q, want %q", got, HelmChartConfigMediaType)
+	}
+	if len(manifest.Layers) != 1 {
+		t.Errorf("layers = %v, want the chart only", manifest.Layers)
+	}
+}
@@ -0,0 +1,32 @@
+package util
+
+import (
+	"net/url"
+	"strings"
+)
+
+// npmTarballSeparator separates the package name from the tarball file name in
+// the conventional npm registry layout "<name>/-/<name>-<version>.tgz".
+const npmTarballSeparator = "/-/"
+
+// GetNPMPackageNameFromPath returns the npm package name (including its
+// "@scope/" prefix, if any) a tarball path belongs to. ok is false when the
+// path does not follow the "<name>/-/<file>.tgz" layout shared by Artifactory
+// and Nexus npm repositories.
+func GetNPMPackageNameFromPath(uri string) (name string, ok bool) {
+	uri = strings.TrimPrefix(uri, "/")
+	if !strings.HasSuffix(uri, ".tgz") {
+		return "", false
+	}
+	idx := strings.LastIndex(uri, npmTarballSeparator)
+	if idx <= 0 {
+		return "", false
+	}
+	return uri[:idx], true
+}
+
+// EscapeNPMPackageName escapes a package name for use as a single path
+// segment of the npm registry API, e.g. "@scope/pkg" -> "@scope%2Fpkg".
+func EscapeNPMPackageName(name string) string {
+	return url.PathEscape(name)
+}
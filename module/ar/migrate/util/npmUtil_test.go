@@ -0,0 +1,34 @@
+package util
+
+import "testing"
+
+func TestGetNPMPackageNameFromPath(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		want   string
+		wantOK bool
+	}{
+		{"unscoped", "lodash/-/lodash-4.17.21.tgz", "lodash", true},
+		{"scoped", "/@acme/ui/-/ui-2.1.0.tgz", "@acme/ui", true},
+		{"not a tarball", "lodash/-/lodash-4.17.21.tgz.sha1", "", false},
+		{"no separator", "lodash-4.17.21.tgz", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := GetNPMPackageNameFromPath(tt.in)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("GetNPMPackageNameFromPath(%q) = %q, %v; want %q, %v", tt.in, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestEscapeNPMPackageName(t *testing.T) {
+	if got := EscapeNPMPackageName("@acme/ui"); got != "@acme%2Fui" {
+		t.Errorf("EscapeNPMPackageName(scoped) = %q", got)
+	}
+	if got := EscapeNPMPackageName("lodash"); got != "lodash" {
+		t.Errorf("EscapeNPMPackageName(unscoped) = %q", got)
+	}
+}
@@ -0,0 +1,148 @@
+// Package pagination fetches every page of paginated listings
+package pagination
+
+import (
+	"context"
+	"sync"
+
+	"github.com/harness/harness-cli/config"
+	"github.com/harness/harness-cli/util/common/printer"
+)
+
+const (
+	// DefaultConcurrency is the default number of pages fetched at once
+	DefaultConcurrency = 4
+	// DefaultPageSize is the page size used to fetch every page
+	DefaultPageSize = 100
+)
+
+// Page is one page of a listing
+type Page[T any] struct {
+	Items []T
+	// PageCount is the total number of pages of the listing
+	PageCount int64
+}
+
+// Fetcher fetches a page (zero-indexed) of a listing
+type Fetcher[T any] func(ctx context.Context, page int64) (Page[T], error)
+
+// Options configures how pages are fetched
+type Options struct {
+	// Concurrency is the maximum number of pages fetched or waiting to be
+	// emitted at once
+	Concurrency int
+	// Limit caps the number of items; zero for all
+	Limit int
+}
+
+// All fetches every page of a listing and passes the items of each page to
+// emit, in page order. The first page is fetched alone to learn the page
+// count; the others are fetched concurrently. Fetching stops at the first
+// error, including one returned by emit, or once Limit items were emitted.
+func All[T any](ctx context.Context, fetch Fetcher[T], opts Options, emit func([]T) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	// on return the pending requests are cancelled and waited for, so no
+	// fetch outlives the call
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+	concurrency := max(opts.Concurrency, 1)
+
+	remaining := opts.Limit
+	// send emits the items and reports whether the limit is reached
+	send := func(items []T) (bool, error) {
+		if opts.Limit > 0 && len(items) >= remaining {
+			return true, emit(items[:remaining])
+		}
+		remaining -= len(items)
+		return false, emit(items)
+	}
+
+	first, err := fetch(ctx, 0)
+	if err != nil {
+		return err
+	}
+	if done, err := send(first.Items); done || err != nil {
+		return err
+	}
+	if first.PageCount <= 1 {
+		return nil
+	}
+
+	type result struct {
+		page Page[T]
+		err  error
+	}
+	results := make([]chan result, first.PageCount)
+	for i := range results {
+		results[i] = make(chan result, 1)
+	}
+	// a slot is taken when a page is requested and released once the page
+	// is emitted, bounding both the requests in flight and the pages held
+	slots := make(chan struct{}, concurrency)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for page := int64(1); page < first.PageCount; page++ {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			wg.Add(1)
+			go func(page int64) {
+				defer wg.Done()
+				p, err := fetch(ctx, page)
+				results[page] <- result{page: p, err: err}
+			}(page)
+		}
+	}()
+
+	for page := int64(1); page < first.PageCount; page++ {
+		var r result
+		// once ctx is cancelled the page may never be requested
+		select {
+		case r = <-results[page]:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		<-slots
+		if r.err != nil {
+			return r.err
+		}
+		if done, err := send(r.page.Items); done || err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Collect fetches every page of a listing and returns the items in page order
+func Collect[T any](ctx context.Context, fetch Fetcher[T], opts Options) ([]T, error) {
+	all := []T{}
+	if err := All(ctx, fetch, opts, func(items []T) error {
+		all = append(all, items...)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// Print fetches every page of a listing and prints the items: with the
+// ndjson format each page is printed as soon as it is fetched, otherwise a
+// single table or JSON document is printed once every page is fetched
+func Print[T any](ctx context.Context, fetch Fetcher[T], opts Options, mappings [][]string) error {
+	if config.Global.Format == printer.FormatNDJSON {
+		return All(ctx, fetch, opts, func(items []T) error {
+			return printer.PrintNDJSON(items, nil)
+		})
+	}
+	all, err := Collect(ctx, fetch, opts)
+	if err != nil {
+		return err
+	}
+	return printer.Print(all, 0, 1, int64(len(all)), false, mappings)
+}
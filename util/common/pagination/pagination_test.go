@@ -0,0 +1,156 @@
+package pagination
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// numbers is a listing of the integers [0, total) in pages of size items;
+// later pages answer faster so out of order completion is exercised
+type numbers struct {
+	total, size int
+	inFlight    atomic.Int32
+	maxInFlight atomic.Int32
+	mu          sync.Mutex
+	requested   []int64
+}
+
+func (n *numbers) fetch(ctx context.Context, page int64) (Page[int], error) {
+	inFlight := n.inFlight.Add(1)
+	defer n.inFlight.Add(-1)
+	for {
+		m := n.maxInFlight.Load()
+		if inFlight <= m || n.maxInFlight.CompareAndSwap(m, inFlight) {
+			break
+		}
+	}
+	n.mu.Lock()
+	n.requested = append(n.requested, page)
+	n.mu.Unlock()
+
+	pageCount := int64((n.total + n.size - 1) / n.size)
+	time.Sleep(time.Duration(pageCount-page) * time.Millisecond)
+	var items []int
+	for i := int(page) * n.size; i < min(int(page+1)*n.size, n.total); i++ {
+		items = append(items, i)
+	}
+	return Page[int]{Items: items, PageCount: pageCount}, ctx.Err()
+}
+
+func collect(t *testing.T, fetch Fetcher[int], opts Options) ([]int, error) {
+	t.Helper()
+	var items []int
+	err := All(t.Context(), fetch, opts, func(page []int) error {
+		items = append(items, page...)
+		return nil
+	})
+	return items, err
+}
+
+func TestAllEmitsEveryPageInOrder(t *testing.T) {
+	n := &numbers{total: 95, size: 10}
+	items, err := collect(t, n.fetch, Options{Concurrency: 3})
+	require.NoError(t, err)
+
+	require.Len(t, items, 95)
+	for i, item := range items {
+		require.Equal(t, i, item)
+	}
+	assert.Len(t, n.requested, 10)
+	assert.LessOrEqual(t, n.maxInFlight.Load(), int32(3))
+}
+
+func TestAllStopsAtLimit(t *testing.T) {
+	n := &numbers{total: 1000, size: 10}
+	items, err := collect(t, n.fetch, Options{Concurrency: 2, Limit: 25})
+	require.NoError(t, err)
+
+	assert.Equal(t, 25, len(items))
+	assert.Equal(t, 24, items[24])
+	// the pages after the limit are not all requested, and none is still
+	// in flight once All returns
+	assert.Less(t, len(n.requested), 10)
+	assert.Zero(t, n.inFlight.Load())
+}
+
+func TestAllReturnsFetchErrors(t *testing.T) {
+	n := &numbers{total: 50, size: 10}
+	boom := errors.New("boom")
+	var emitted int
+	err := All(t.Context(), func(ctx context.Context, page int64) (Page[int], error) {
+		if page == 2 {
+			return Page[int]{}, boom
+		}
+		return n.fetch(ctx, page)
+	}, Options{Concurrency: 4}, func(items []int) error {
+		emitted += len(items)
+		return nil
+	})
+	require.ErrorIs(t, err, boom)
+	assert.Equal(t, 20, emitted)
+}
+
+func TestAllStopsWhenCancelled(t *testing.T) {
+	n := &numbers{total: 100, size: 10}
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	var emitted int
+	errCh := make(chan error, 1)
+	go func() {
+		// pages fetched before the cancellation still succeed
+		fetch := func(ctx context.Context, page int64) (Page[int], error) {
+			p, _ := n.fetch(ctx, page)
+			return p, nil
+		}
+		errCh <- All(ctx, fetch, Options{Concurrency: 1}, func(items []int) error {
+			emitted += len(items)
+			if emitted == 20 {
+				cancel()
+			}
+			return nil
+		})
+	}()
+
+	select {
+	case err := <-errCh:
+		require.ErrorIs(t, err, context.Canceled)
+	case <-time.After(5 * time.Second):
+		t.Fatal("All did not return once its context was cancelled")
+	}
+	// the pages after the cancellation are not all requested
+	assert.Less(t, len(n.requested), 10)
+	assert.Zero(t, n.inFlight.Load())
+}
+
+func TestAllSinglePage(t *testing.T) {
+	calls := 0
+	items, err := collect(t, func(ctx context.Context, page int64) (Page[int], error) {
+		calls++
+		// a listing without a page count is a single page
+		return Page[int]{Items: []int{1, 2}}, nil
+	}, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, items)
+	assert.Equal(t, 1, calls)
+}
+
+func TestCollect(t *testing.T) {
+	n := &numbers{total: 25, size: 10}
+	items, err := Collect(t.Context(), n.fetch, Options{Concurrency: 2, Limit: 12})
+	require.NoError(t, err)
+	assert.Len(t, items, 12)
+	assert.Equal(t, 11, items[11])
+
+	// an empty listing is an empty slice, so it prints as []
+	items, err = Collect(t.Context(), (&numbers{total: 0, size: 10}).fetch, Options{})
+	require.NoError(t, err)
+	assert.NotNil(t, items)
+	assert.Empty(t, items)
+}
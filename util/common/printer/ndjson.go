@@ -0,0 +1,36 @@
+// Package printer provides output formatting utilities for the CLI
+package printer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+)
+
+// FormatNDJSON is the format printing one JSON object per line, which can be
+// streamed page by page
+const FormatNDJSON = "ndjson"
+
+// PrintNDJSON prints every element of a slice as a JSON object on its own
+// line; any other value is printed as a single line
+func PrintNDJSON(res any, writer io.Writer) error {
+	if writer == nil {
+		writer = os.Stdout
+	}
+	encoder := json.NewEncoder(writer)
+	v := reflect.ValueOf(res)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		if err := encoder.Encode(res); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		return nil
+	}
+	for i := 0; i < v.Len(); i++ {
+		if err := encoder.Encode(v.Index(i).Interface()); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+	}
+	return nil
+}
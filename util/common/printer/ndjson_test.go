@@ -0,0 +1,34 @@
+package printer
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPrintNDJSON(t *testing.T) {
+	type row struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+
+	tests := []struct {
+		name string
+		res  any
+		want string
+	}{
+		{"slice", []row{{"a", 1}, {"b", 2}}, "{\"name\":\"a\",\"count\":1}\n{\"name\":\"b\",\"count\":2}\n"},
+		{"empty slice", []row{}, ""},
+		{"single value", row{"a", 1}, "{\"name\":\"a\",\"count\":1}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := PrintNDJSON(tt.res, &buf); err != nil {
+				t.Fatalf("PrintNDJSON() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("PrintNDJSON() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -11,7 +11,7 @@ import (
 
 // PrintOptions combines options for both JSON and table output
 type PrintOptions struct {
-	// Format specifies the output format ("json" or "table")
+	// Format specifies the output format ("json", "ndjson" or "table")
 	Format string
 	// Writer is the output destination (defaults to os.Stdout if nil)
 	Writer io.Writer
@@ -57,7 +57,9 @@ func Print(res any, pageIndex, pageCount, itemCount int64, showPagination bool,
 func PrintWithOptions(res any, options PrintOptions) error {
 	var err error
 
-	if options.Format == "json" {
+	if options.Format == FormatNDJSON {
+		err = PrintNDJSON(res, options.Writer)
+	} else if options.Format == "json" {
 		// Convert to JsonOptions
 		jsonOpts := DefaultJsonOptions()
 		jsonOpts.Writer = options.Writer
@@ -42,7 +42,11 @@ func formatArtifactKey(val interface{}) string {
 // jsonToTableWithMapping converts JSON string to a table with custom column mapping
 func jsonToTableWithMapping(jsonStr string, mapping ColumnMapping) error {
 	var rows []map[string]interface{}
-	if err := json.Unmarshal([]byte(jsonStr), &rows); err != nil {
+	// numbers keep their JSON text, as float64 large integers would print in
+	// e-notation
+	dec := json.NewDecoder(strings.NewReader(jsonStr))
+	dec.UseNumber()
+	if err := dec.Decode(&rows); err != nil {
 		return fmt.Errorf("parse json: %w", err)
 	}
 	if len(rows) == 0 {
@@ -1,9 +1,12 @@
 package printer
 
 import (
+	"bytes"
 	"encoding/json"
 	"strings"
 	"testing"
+
+	"github.com/pterm/pterm"
 )
 
 func TestFormatArtifactKey(t *testing.T) {
@@ -542,3 +545,26 @@ func TestFormatArtifactKey_Integration(t *testing.T) {
 		})
 	}
 }
+
+func TestJsonToTableWithMapping_LargeIntegers(t *testing.T) {
+	var buf bytes.Buffer
+	orig := pterm.DefaultTable
+	pterm.DefaultTable = *pterm.DefaultTable.WithWriter(&buf)
+	defer func() { pterm.DefaultTable = orig }()
+
+	err := jsonToTableWithMapping(`[{"name": "layer", "size": 52428800, "ratio": 0.5}]`, ColumnMapping{
+		{"name", "Name"},
+		{"size", "Size"},
+		{"ratio", "Ratio"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "52428800") || strings.Contains(out, "e+07") {
+		t.Errorf("expected the integer as written, got:\n%s", out)
+	}
+	if !strings.Contains(out, "0.5") {
+		t.Errorf("expected the float as written, got:\n%s", out)
+	}
+}